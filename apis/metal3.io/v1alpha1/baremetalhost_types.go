@@ -51,6 +51,11 @@ const (
 	// from the status annotation.
 	StatusAnnotation = "baremetalhost.metal3.io/status"
 
+	// BMCEventAnnotation is set by the BMC event receiver to the time of
+	// the last event that may have changed the power state or the health
+	// of the host, so that the host controller reconciles it right away.
+	BMCEventAnnotation = "baremetalhost.metal3.io/bmc-event"
+
 	// RebootAnnotationPrefix is the annotation which tells the host which mode to use
 	// when rebooting - hard/soft.
 	RebootAnnotationPrefix = "reboot.metal3.io"
@@ -219,6 +224,39 @@ const (
 	WarningHealthReason = "Warning"
 	// CriticalHealthReason is the reason used when BMC reports critical errors.
 	CriticalHealthReason = "CriticalError"
+
+	// BMCEventCondition documents the last event received from the BMC
+	// through a BMCEventSubscription. It is false for the events of the
+	// Warning and Critical severities, using the health reasons.
+	BMCEventCondition = "BMCEvent"
+
+	// ServicingScheduledCondition documents the servicing held until the
+	// HostUpdatePolicy of the BareMetalHost allows it.
+	ServicingScheduledCondition = "ServicingScheduled"
+	// OutsideMaintenanceWindowReason is the reason used when servicing waits
+	// for the next maintenance window.
+	OutsideMaintenanceWindowReason = "OutsideMaintenanceWindow"
+	// MaxConcurrentServicingReason is the reason used when servicing waits
+	// for other hosts of its group to finish servicing.
+	MaxConcurrentServicingReason = "MaxConcurrentServicing"
+	// InvalidMaintenanceWindowReason is the reason used when servicing is
+	// held because a maintenance window cannot be parsed.
+	InvalidMaintenanceWindowReason = "InvalidMaintenanceWindow"
+
+	// CabledCondition documents whether the NICs of the inspected
+	// BareMetalHost are cabled to the BareMetalSwitches of its namespace,
+	// according to their LLDP data. It is only set in the namespaces with
+	// BareMetalSwitches.
+	CabledCondition = "Cabled"
+	// CabledReason is the reason used when the boot NIC and the NICs
+	// reporting LLDP data are all cabled to BareMetalSwitches.
+	CabledReason = "Cabled"
+	// BootNICNotCabledReason is the reason used when the boot NIC is not
+	// cabled to any BareMetalSwitch.
+	BootNICNotCabledReason = "BootNICNotCabled"
+	// UnknownSwitchReason is the reason used when NICs are cabled to
+	// switches that have no BareMetalSwitch.
+	UnknownSwitchReason = "UnknownSwitch"
 )
 
 // OperationalStatus represents the state of the host.
@@ -419,6 +457,66 @@ type SoftwareRAIDVolume struct {
 	PhysicalDisks []RootDeviceHints `json:"physicalDisks,omitempty"`
 }
 
+// RAIDDiskSelection tells which disks a RAID volume intent picks among the
+// matching ones.
+type RAIDDiskSelection string
+
+const (
+	// RAIDDiskSelectionSmallest picks the smallest matching disks.
+	RAIDDiskSelectionSmallest RAIDDiskSelection = "Smallest"
+
+	// RAIDDiskSelectionLargest picks the largest matching disks.
+	RAIDDiskSelectionLargest RAIDDiskSelection = "Largest"
+)
+
+// RAIDIntentType tells how the volumes of a RAID intent are created.
+type RAIDIntentType string
+
+const (
+	// RAIDIntentSoftware plans software RAID volumes on the inspected
+	// disks of the host.
+	RAIDIntentSoftware RAIDIntentType = "software"
+
+	// RAIDIntentHardware plans hardware RAID volumes, the RAID controller
+	// picks the disks of the requested type.
+	RAIDIntentHardware RAIDIntentType = "hardware"
+)
+
+// RAIDVolumeIntent describes a RAID volume in terms of the disks of the
+// host.
+type RAIDVolumeIntent struct {
+	// Name of the volume, used to report the plan.
+	// +kubebuilder:validation:MaxLength=64
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// RAID level for the logical disk. The following levels are supported:
+	// 0, 1 and 1+0.
+	// +kubebuilder:validation:Enum="0";"1";"1+0"
+	Level string `json:"level"`
+
+	// Type of the disks to use, one of HDD, SSD or NVME. By default, disks
+	// of any type can be picked.
+	// +kubebuilder:validation:Enum=HDD;SSD;NVME
+	// +optional
+	DiskType DiskType `json:"diskType,omitempty"`
+
+	// Number of disks to use. By default, all the matching disks not used
+	// by a previous volume are picked for software RAID, and the minimum
+	// number of disks of the level is used for hardware RAID.
+	// +kubebuilder:validation:Minimum=2
+	// +optional
+	DiskCount *int `json:"diskCount,omitempty"`
+
+	// Which disks to pick when more of them match than needed. Only the
+	// smallest disks can be picked for hardware RAID, the RAID controller
+	// chooses them.
+	// +kubebuilder:validation:Enum=Smallest;Largest
+	// +kubebuilder:default=Smallest
+	// +optional
+	Select RAIDDiskSelection `json:"select,omitempty"`
+}
+
 // RAIDConfig contains the configuration that are required to config RAID in Bare Metal server.
 type RAIDConfig struct {
 	// The list of logical disks for hardware RAID, if rootDeviceHints isn't used, first volume is root volume.
@@ -439,6 +537,52 @@ type RAIDConfig struct {
 	// +optional
 	// +nullable
 	SoftwareRAIDVolumes []SoftwareRAIDVolume `json:"softwareRAIDVolumes"`
+
+	// The RAID volumes to create, described by the disks they use instead
+	// of device hints. The operator plans them when preparing the host and
+	// records the resulting softwareRAIDVolumes or hardwareRAIDVolumes in
+	// the provisioning status. The same rules as for these volumes apply,
+	// and they cannot be set together with this field.
+	// +kubebuilder:validation:MaxItems=2
+	// +optional
+	Intent []RAIDVolumeIntent `json:"intent,omitempty"`
+
+	// IntentType tells whether the intent is planned as software RAID
+	// volumes from the inspected disks, or as hardware RAID volumes of the
+	// RAID controller. Defaults to software.
+	// +kubebuilder:validation:Enum=software;hardware
+	// +optional
+	IntentType RAIDIntentType `json:"intentType,omitempty"`
+}
+
+// ValidateIntent checks the RAID intent against the rules of the RAID
+// volumes it is planned into.
+func (raid *RAIDConfig) ValidateIntent() []error {
+	var errs []error
+	if len(raid.Intent) == 0 {
+		if raid.IntentType != "" {
+			errs = append(errs, errors.New("intentType can only be set together with intent"))
+		}
+		return errs
+	}
+
+	// check if the intent is combined with explicit volumes
+	if len(raid.HardwareRAIDVolumes) > 0 || len(raid.SoftwareRAIDVolumes) > 0 {
+		errs = append(errs, errors.New("intent can not be set together with hardwareRAIDVolumes or softwareRAIDVolumes"))
+	}
+	// the first volume is the deployment device of software RAID
+	if raid.IntentType != RAIDIntentHardware && raid.Intent[0].Level != "1" {
+		errs = append(errs, errors.New("the level of the first volume of the RAID intent must be 1"))
+	}
+	for index, volume := range raid.Intent {
+		if volume.Level == "1+0" && volume.DiskCount != nil && *volume.DiskCount < 4 {
+			errs = append(errs, fmt.Errorf("RAID intent volume %d needs at least 4 disks for level 1+0", index))
+		}
+		if raid.IntentType == RAIDIntentHardware && volume.Select == RAIDDiskSelectionLargest {
+			errs = append(errs, fmt.Errorf("RAID intent volume %d cannot pick the largest disks with hardware RAID", index))
+		}
+	}
+	return errs
 }
 
 // FirmwareConfig contains the configuration that you want to configure BIOS settings in Bare metal server.
@@ -569,7 +713,9 @@ type BareMetalHostSpec struct {
 	ExternallyProvisioned bool `json:"externallyProvisioned,omitempty"`
 
 	// When set to disabled, automated cleaning will be skipped
-	// during provisioning and deprovisioning.
+	// during provisioning and deprovisioning. When set to full, secure or
+	// crypto, the disks of the host are also erased with the matching
+	// method after the metadata cleaning of deprovisioning.
 	// +optional
 	// +kubebuilder:default:=metadata
 	// +kubebuilder:validation:Optional
@@ -598,18 +744,165 @@ type BareMetalHostSpec struct {
 	// +optional
 	// +kubebuilder:validation:Enum=disabled;agent
 	InspectionMode InspectionMode `json:"inspectionMode,omitempty"`
+
+	// Network attaches the NICs of the host to tenant networks while it
+	// is provisioned. The switch ports are moved to the tenant networks
+	// once the image is deployed, and back to the provisioning VLAN when
+	// the host starts deprovisioning.
+	// +optional
+	Network *HostNetwork `json:"network,omitempty"`
+}
+
+// SwitchPortMode is the VLAN mode of a switch port.
+type SwitchPortMode string
+
+const (
+	// SwitchPortModeAccess carries a single untagged VLAN.
+	SwitchPortModeAccess SwitchPortMode = "access"
+
+	// SwitchPortModeTrunk carries tagged VLANs, and optionally a native
+	// untagged one.
+	SwitchPortModeTrunk SwitchPortMode = "trunk"
+)
+
+// SwitchPortConfig is the VLAN configuration of a switch port.
+type SwitchPortConfig struct {
+	// Mode of the switch port, access by default.
+	// +kubebuilder:validation:Enum=access;trunk
+	// +optional
+	Mode SwitchPortMode `json:"mode,omitempty"`
+
+	// VLAN is the VLAN of an access port, or the native VLAN of a trunk.
+	// +optional
+	VLAN *VLANID `json:"vlan,omitempty"`
+
+	// AllowedVLANs are the tagged VLANs of a trunk.
+	// +kubebuilder:validation:MaxItems=128
+	// +optional
+	AllowedVLANs []VLANID `json:"allowedVLANs,omitempty"`
+}
+
+// NetworkInterface attaches a NIC of the host to a switch port.
+type NetworkInterface struct {
+	// MACAddress of the NIC.
+	// +kubebuilder:validation:Pattern=`^[0-9a-fA-F]{2}(:[0-9a-fA-F]{2}){5}$`
+	MACAddress string `json:"macAddress"`
+
+	// Switch is the name of the BareMetalSwitch the NIC is connected to.
+	// By default, it is found from the LLDP data of the inspected NIC.
+	// +optional
+	Switch string `json:"switch,omitempty"`
+
+	// Port is the switch port the NIC is connected to. By default, it is
+	// taken from the LLDP data of the inspected NIC.
+	// +optional
+	Port string `json:"port,omitempty"`
+
+	// The VLAN configuration of the switch port. It cannot be set for the
+	// NICs of a bond, which use the configuration of the bond.
+	SwitchPortConfig `json:",inline"`
+}
+
+// NetworkBond aggregates two NICs of the host with LACP (802.3ad).
+type NetworkBond struct {
+	// Name of the bond.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=32
+	Name string `json:"name"`
+
+	// MACAddresses of the NICs of the bond. The switch and port of a NIC
+	// can be given in the interfaces, they are found from the LLDP data of
+	// the inspected NIC otherwise.
+	// +kubebuilder:validation:MinItems=2
+	// +kubebuilder:validation:MaxItems=2
+	MACAddresses []string `json:"macAddresses"`
+
+	// The VLAN configuration of the switch ports of the bond.
+	SwitchPortConfig `json:",inline"`
+}
+
+// HostNetwork describes the tenant networks of the NICs of a host.
+type HostNetwork struct {
+	// ProvisioningVLAN is the VLAN the switch ports are moved back to when
+	// the host starts deprovisioning. By default, the tenant configuration
+	// is removed and the switch applies its own default.
+	// +optional
+	ProvisioningVLAN *VLANID `json:"provisioningVLAN,omitempty"`
+
+	// Interfaces attach NICs of the host to switch ports.
+	// +kubebuilder:validation:MaxItems=16
+	// +optional
+	Interfaces []NetworkInterface `json:"interfaces,omitempty"`
+
+	// Bonds aggregate pairs of NICs of the host.
+	// +kubebuilder:validation:MaxItems=8
+	// +optional
+	Bonds []NetworkBond `json:"bonds,omitempty"`
 }
 
 // AutomatedCleaningMode is the interface to enable/disable automated cleaning
-// +kubebuilder:validation:Enum:=metadata;disabled
+// +kubebuilder:validation:Enum:=metadata;disabled;full;secure;crypto
 type AutomatedCleaningMode string
 
 // Allowed automated cleaning modes.
 const (
 	CleaningModeDisabled AutomatedCleaningMode = "disabled"
 	CleaningModeMetadata AutomatedCleaningMode = "metadata"
+
+	// CleaningModeFull erases the whole disks by overwriting them.
+	CleaningModeFull AutomatedCleaningMode = "full"
+
+	// CleaningModeSecure erases the disks with the ATA or NVMe secure
+	// erase of the hardware. Hosts with rotational disks cannot use it.
+	CleaningModeSecure AutomatedCleaningMode = "secure"
+
+	// CleaningModeCrypto erases the disks by discarding their encryption
+	// keys. Only hosts whose disks are all NVMe drives can use it.
+	CleaningModeCrypto AutomatedCleaningMode = "crypto"
 )
 
+// ErasesDisks returns whether the cleaning mode erases the disks.
+func (mode AutomatedCleaningMode) ErasesDisks() bool {
+	switch mode {
+	case CleaningModeFull, CleaningModeSecure, CleaningModeCrypto:
+		return true
+	default:
+		return false
+	}
+}
+
+// DiskErasure records an erasure of the disks of a host.
+type DiskErasure struct {
+	// Method is the cleaning mode used to erase the disks.
+	Method AutomatedCleaningMode `json:"method"`
+
+	// Steps are the cleaning steps the provisioner completed to erase the
+	// disks.
+	// +optional
+	Steps []string `json:"steps,omitempty"`
+
+	// Disks are the disks erased, as inspected when the erasure started.
+	// +optional
+	Disks []ErasedDisk `json:"disks,omitempty"`
+
+	// CompletedAt is when the provisioner completed the erasure.
+	CompletedAt metav1.Time `json:"completedAt"`
+}
+
+// ErasedDisk is a disk erased by a DiskErasure.
+type ErasedDisk struct {
+	// Name is the Linux device name of the disk.
+	Name string `json:"name"`
+
+	// Type is the type of the disk, one of HDD, SSD and NVME.
+	// +optional
+	Type DiskType `json:"type,omitempty"`
+
+	// SerialNumber is the serial number of the disk.
+	// +optional
+	SerialNumber string `json:"serialNumber,omitempty"`
+}
+
 // ChecksumType holds the algorithm name for the checksum
 // +kubebuilder:validation:Enum=md5;sha256;sha512;auto
 type ChecksumType string
@@ -816,6 +1109,12 @@ type BareMetalHostStatus struct {
 	// +listMapKey=type
 	// +kubebuilder:validation:MaxItems=32
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// DiskErasures records the last erasures of the disks of the host,
+	// the most recent last.
+	// +optional
+	// +kubebuilder:validation:MaxItems=10
+	DiskErasures []DiskErasure `json:"diskErasures,omitempty"`
 }
 
 // ProvisionStatus holds the state information for a single target.
@@ -73,8 +73,32 @@ const (
 	// SwitchConditionValid indicates whether the switch has been
 	// successfully reconciled into the switch config secret.
 	SwitchConditionValid SwitchConditionType = "Valid"
+
+	// SwitchConditionReachable indicates whether the management address
+	// and port of the switch answered the last connectivity probe.
+	SwitchConditionReachable SwitchConditionType = "Reachable"
 )
 
+// SwitchPortLink is a NIC of a BareMetalHost cabled to a switch port.
+type SwitchPortLink struct {
+	// Port is the switch port ID reported by LLDP.
+	Port string `json:"port"`
+
+	// Host is the name of the BareMetalHost.
+	Host string `json:"host"`
+
+	// NIC is the name of the NIC of the host.
+	// +optional
+	NIC string `json:"nic,omitempty"`
+
+	// MACAddress of the NIC of the host.
+	MACAddress string `json:"macAddress"`
+
+	// Boot tells whether the NIC is the boot NIC of the host.
+	// +optional
+	Boot bool `json:"boot,omitempty"`
+}
+
 // BareMetalSwitchStatus defines the observed state of BareMetalSwitch.
 type BareMetalSwitchStatus struct {
 	// Conditions describes the state of the BareMetalSwitch resource.
@@ -84,6 +108,21 @@ type BareMetalSwitchStatus struct {
 	// +listMapKey=type
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// Ports lists the NICs of the inspected BareMetalHosts of the namespace
+	// cabled to the switch, according to their LLDP data.
+	// +optional
+	Ports []SwitchPortLink `json:"ports,omitempty"`
+
+	// LastCheckTime is the time of the last connectivity probe of the
+	// management address and port of the switch.
+	// +optional
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+
+	// Latency is the time taken by the last successful connectivity probe
+	// to connect and receive the SSH banner or complete the TLS handshake.
+	// +optional
+	Latency *metav1.Duration `json:"latency,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -93,6 +132,7 @@ type BareMetalSwitchStatus struct {
 // +kubebuilder:printcolumn:name="Device Type",type="string",JSONPath=".spec.deviceType",description="Switch device type"
 // +kubebuilder:printcolumn:name="Address",type="string",JSONPath=".spec.address",description="Switch address"
 // +kubebuilder:printcolumn:name="Valid",type="string",JSONPath=".status.conditions[?(@.type==\"Valid\")].status",description="Valid"
+// +kubebuilder:printcolumn:name="Reachable",type="string",JSONPath=".status.conditions[?(@.type==\"Reachable\")].status",description="Reachable"
 
 // BareMetalSwitch represents a Top-of-Rack switch managed by Ironic Networking.
 type BareMetalSwitch struct {
@@ -44,9 +44,49 @@ type BMCEventSubscriptionSpec struct {
 	HTTPHeadersRef *corev1.SecretReference `json:"httpHeadersRef,omitempty"`
 }
 
+// BMCEventSubscriptionConditionType is the type of the conditions of a
+// BMCEventSubscription.
+type BMCEventSubscriptionConditionType string
+
+const (
+	// BMCEventSubscriptionReady is true when the subscription exists on
+	// the BMC with the current destination, context and HTTP headers.
+	BMCEventSubscriptionReady BMCEventSubscriptionConditionType = "Ready"
+
+	// BMCEventSubscriptionError is true when the last attempt to create,
+	// verify or remove the subscription failed.
+	BMCEventSubscriptionError BMCEventSubscriptionConditionType = "Error"
+)
+
 type BMCEventSubscriptionStatus struct {
 	SubscriptionID string `json:"subscriptionID,omitempty"`
-	Error          string `json:"error,omitempty"`
+
+	// Error is the most recent error message. Deprecated: use the Error
+	// condition instead.
+	Error string `json:"error,omitempty"`
+
+	// Conditions describe the state of the subscription.
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// ObservedGeneration is the generation of the spec the subscription
+	// was created from.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// HTTPHeadersVersion is a digest of the content of the httpHeadersRef
+	// secret the subscription was created with.
+	// +optional
+	HTTPHeadersVersion string `json:"httpHeadersVersion,omitempty"`
+
+	// LastCheckTime is the time the subscription was last found on the
+	// BMC.
+	// +optional
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -55,6 +95,7 @@ type BMCEventSubscriptionStatus struct {
 // +k8s:openapi-gen=true
 // +kubebuilder:resource:shortName=bes;bmcevent
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="Whether the subscription exists on the BMC"
 // +kubebuilder:printcolumn:name="Error",type="string",JSONPath=".status.error",description="The most recent error message"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of BMCEventSubscription"
 // +kubebuilder:object:root=true
@@ -75,6 +116,16 @@ type BMCEventSubscriptionList struct {
 	Items           []BMCEventSubscription `json:"items"`
 }
 
+// GetConditions returns the set of conditions for this object.
+func (s *BMCEventSubscription) GetConditions() []metav1.Condition {
+	return s.Status.Conditions
+}
+
+// SetConditions sets conditions for this object.
+func (s *BMCEventSubscription) SetConditions(conditions []metav1.Condition) {
+	s.Status.Conditions = conditions
+}
+
 func init() {
 	SchemeBuilder.Register(&BMCEventSubscription{}, &BMCEventSubscriptionList{})
 }
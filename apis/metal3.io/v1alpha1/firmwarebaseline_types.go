@@ -0,0 +1,175 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FirmwareVersionMatch tells how the version of a component is compared
+// with the one of the baseline.
+// +kubebuilder:validation:Enum=Minimum;Exact
+type FirmwareVersionMatch string
+
+const (
+	// FirmwareVersionMinimum accepts the version of the baseline and any
+	// later one.
+	FirmwareVersionMinimum FirmwareVersionMatch = "Minimum"
+	// FirmwareVersionExact only accepts the version of the baseline.
+	FirmwareVersionExact FirmwareVersionMatch = "Exact"
+)
+
+// FirmwareBaselineComponent is the expected version of a firmware component.
+type FirmwareBaselineComponent struct {
+	// Component is the name of the component as reported by the
+	// HostFirmwareComponents, i.e. bmc, bios or a name starting with nic:.
+	// +kubebuilder:validation:Pattern=`^(bmc|bios|nic:.+)$`
+	Component string `json:"component"`
+
+	// Version is the expected version of the component.
+	// +kubebuilder:validation:MinLength=1
+	Version string `json:"version"`
+
+	// Match tells whether later versions than the expected one are
+	// compliant.
+	// +kubebuilder:default=Minimum
+	// +optional
+	Match FirmwareVersionMatch `json:"match,omitempty"`
+
+	// URL of the firmware image of the expected version, used to update
+	// the hosts that are not compliant.
+	// +optional
+	URL string `json:"url,omitempty"`
+}
+
+// FirmwareBaselineModel is the expected firmware of the hosts of a vendor
+// and model.
+type FirmwareBaselineModel struct {
+	// Vendor must be contained in the system manufacturer of the host. The
+	// match is case insensitive.
+	// +optional
+	Vendor string `json:"vendor,omitempty"`
+
+	// Product must be contained in the system product name of the host.
+	// The match is case insensitive.
+	// +optional
+	Product string `json:"product,omitempty"`
+
+	// Components lists the expected versions of the firmware components.
+	// +kubebuilder:validation:MinItems=1
+	Components []FirmwareBaselineComponent `json:"components"`
+}
+
+// FirmwareBaselineSpec defines the desired state of FirmwareBaseline.
+type FirmwareBaselineSpec struct {
+	// Models lists the expected firmware per vendor and model. A host is
+	// checked against the first model matching its system vendor.
+	// +kubebuilder:validation:MinItems=1
+	Models []FirmwareBaselineModel `json:"models"`
+
+	// ApplyUpdates writes the updates of the components that are not
+	// compliant and have a URL to the HostFirmwareComponents of the
+	// hosts. They are applied on the next servicing or provisioning.
+	// The hosts being updated by a FirmwareUpdateCampaign are skipped.
+	// +optional
+	ApplyUpdates bool `json:"applyUpdates,omitempty"`
+}
+
+// FirmwareComponentCompliance compares the version of a component of a
+// host with the baseline.
+type FirmwareComponentCompliance struct {
+	// Component is the name of the firmware component.
+	Component string `json:"component"`
+
+	// CurrentVersion is the version reported by the
+	// HostFirmwareComponents, empty when the component is not reported.
+	// +optional
+	CurrentVersion string `json:"currentVersion,omitempty"`
+
+	// ExpectedVersion is the version of the baseline.
+	ExpectedVersion string `json:"expectedVersion"`
+
+	// Compliant tells whether the current version matches the baseline.
+	Compliant bool `json:"compliant"`
+}
+
+// FirmwareBaselineHostStatus is the compliance of a host with the baseline.
+type FirmwareBaselineHostStatus struct {
+	// Name of the BareMetalHost.
+	Name string `json:"name"`
+
+	// Compliant tells whether all the components of the host match the
+	// baseline.
+	Compliant bool `json:"compliant"`
+
+	// Components compares each component of the baseline with the host.
+	// +optional
+	Components []FirmwareComponentCompliance `json:"components,omitempty"`
+}
+
+// FirmwareBaselineStatus defines the observed state of FirmwareBaseline.
+type FirmwareBaselineStatus struct {
+	// Hosts is the compliance of the hosts of the namespace matching one
+	// of the models. Hosts that have not been inspected yet are not
+	// listed.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Hosts []FirmwareBaselineHostStatus `json:"hosts,omitempty"`
+
+	// CompliantHosts is the number of hosts matching the baseline.
+	// +optional
+	CompliantHosts int `json:"compliantHosts,omitempty"`
+
+	// NonCompliantHosts is the number of hosts with at least one component
+	// not matching the baseline.
+	// +optional
+	NonCompliantHosts int `json:"nonCompliantHosts,omitempty"`
+
+	// LastUpdated identifies when this status was last observed.
+	// +optional
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Compliant",type="integer",JSONPath=".status.compliantHosts",description="Number of compliant hosts"
+//+kubebuilder:printcolumn:name="NonCompliant",type="integer",JSONPath=".status.nonCompliantHosts",description="Number of hosts that are not compliant"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of FirmwareBaseline"
+
+// FirmwareBaseline declares the expected firmware versions of the
+// BareMetalHosts of its namespace and reports their compliance.
+type FirmwareBaseline struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FirmwareBaselineSpec   `json:"spec,omitempty"`
+	Status FirmwareBaselineStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// FirmwareBaselineList contains a list of FirmwareBaseline.
+type FirmwareBaselineList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FirmwareBaseline `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FirmwareBaseline{}, &FirmwareBaselineList{})
+}
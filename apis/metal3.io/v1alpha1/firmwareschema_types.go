@@ -60,6 +60,11 @@ type SettingSchema struct {
 	// Whether or not this setting's value is unique to this node, e.g.
 	// a serial number.
 	Unique *bool `json:"unique,omitempty"`
+
+	// Whether or not the host must be reset for a change of this setting
+	// to take effect.
+	//nolint:tagliatelle
+	ResetRequired *bool `json:"reset_required,omitempty"`
 }
 
 type SchemaSettingError struct {
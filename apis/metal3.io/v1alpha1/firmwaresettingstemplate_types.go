@@ -0,0 +1,126 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// SettingsValidCondition is true when the settings of a
+	// FirmwareSettingsTemplate are valid for all the hosts it matches.
+	SettingsValidCondition = "SettingsValid"
+	// NoConflictsCondition is true when no other FirmwareSettingsTemplate
+	// sets a different value for the same setting of a host, and the
+	// HostFirmwareSettings of the hosts do not override the settings.
+	NoConflictsCondition = "NoConflicts"
+
+	// InvalidSettingsReason is used when some settings are unknown or have
+	// values not allowed by the FirmwareSchema of a host.
+	InvalidSettingsReason = "InvalidSettings"
+	// ConflictingTemplatesReason is used when templates set different
+	// values for the same setting of a host.
+	ConflictingTemplatesReason = "ConflictingTemplates"
+	// OverriddenSettingsReason is used when the HostFirmwareSettings of a
+	// host set a different value for a setting of the template.
+	OverriddenSettingsReason = "OverriddenSettings"
+	// TemplateRenderedReason is used when the settings have been rendered
+	// without any problem.
+	TemplateRenderedReason = "Rendered"
+
+	// FirmwareSettingsTemplateFinalizer is the name of the finalizer
+	// blocking the deletion of a template until its settings are removed
+	// from the HostFirmwareSettings of the hosts.
+	FirmwareSettingsTemplateFinalizer = "firmwaresettingstemplate.metal3.io"
+	// RenderedSettingsAnnotation records on the HostFirmwareSettings the
+	// settings rendered by each template, as a JSON object mapping the
+	// names of the templates to their settings and values.
+	RenderedSettingsAnnotation = "firmwaresettingstemplate.metal3.io/rendered"
+)
+
+// FirmwareSettingsTemplateSpec defines the desired state of FirmwareSettingsTemplate.
+type FirmwareSettingsTemplateSpec struct {
+	// Selector chooses the BareMetalHosts of the namespace by their labels.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// Vendor must be contained in the hardware vendor of the FirmwareSchema
+	// of the host. The match is case insensitive.
+	// +optional
+	Vendor string `json:"vendor,omitempty"`
+
+	// Model must be contained in the hardware model of the FirmwareSchema
+	// of the host. The match is case insensitive.
+	// +optional
+	Model string `json:"model,omitempty"`
+
+	// Settings are written to the HostFirmwareSettings of the matching
+	// hosts. Other settings of the hosts are left unchanged. The settings
+	// removed from the template, or rendered for a host that no longer
+	// matches, are removed from the HostFirmwareSettings unless they were
+	// changed there. A different value set in the HostFirmwareSettings
+	// overrides the template for that host.
+	Settings DesiredSettingsMap `json:"settings"`
+}
+
+// FirmwareSettingsTemplateStatus defines the observed state of FirmwareSettingsTemplate.
+type FirmwareSettingsTemplateStatus struct {
+	// Hosts lists the names of the BareMetalHosts matching the template.
+	// +optional
+	Hosts []string `json:"hosts,omitempty"`
+
+	// Conditions report the settings that are not valid for a host, the
+	// ones conflicting with another template and the ones overridden for a
+	// host. Such settings are not written to the HostFirmwareSettings of
+	// the host.
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Valid",type="string",JSONPath=".status.conditions[?(@.type==\"SettingsValid\")].status",description="Whether the settings are valid for all the hosts"
+//+kubebuilder:printcolumn:name="NoConflicts",type="string",JSONPath=".status.conditions[?(@.type==\"NoConflicts\")].status",description="Whether the template conflicts with another one"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of FirmwareSettingsTemplate"
+
+// FirmwareSettingsTemplate holds firmware settings shared by the
+// BareMetalHosts matching it, which are rendered into their
+// HostFirmwareSettings.
+type FirmwareSettingsTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FirmwareSettingsTemplateSpec   `json:"spec,omitempty"`
+	Status FirmwareSettingsTemplateStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// FirmwareSettingsTemplateList contains a list of FirmwareSettingsTemplate.
+type FirmwareSettingsTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FirmwareSettingsTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FirmwareSettingsTemplate{}, &FirmwareSettingsTemplateList{})
+}
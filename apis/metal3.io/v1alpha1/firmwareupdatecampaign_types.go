@@ -0,0 +1,179 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// FirmwareUpdateCampaignPausedCondition is true when the campaign does
+	// not start updating new hosts.
+	FirmwareUpdateCampaignPausedCondition = "Paused"
+	// FirmwareUpdateCampaignCompletedCondition is true when all the hosts
+	// of the campaign have been updated.
+	FirmwareUpdateCampaignCompletedCondition = "Completed"
+
+	// PausedByUserReason is used when the campaign is paused in its spec.
+	PausedByUserReason = "PausedByUser"
+	// FailureBudgetExceededReason is used when more hosts than allowed by
+	// the failure budget are in a servicing error.
+	FailureBudgetExceededReason = "FailureBudgetExceeded"
+	// InvalidCampaignReason is used when the selector or the updates of
+	// the campaign are not valid.
+	InvalidCampaignReason = "InvalidCampaign"
+	// RollingOutReason is used when the campaign is updating hosts.
+	RollingOutReason = "RollingOut"
+	// AllHostsUpdatedReason is used when all the hosts have been updated.
+	AllHostsUpdatedReason = "AllHostsUpdated"
+
+	// FirmwareUpdateCampaignAnnotation is set on the HostFirmwareComponents
+	// of a host by the campaign updating it. Other campaigns and the
+	// FirmwareBaselines leave the host alone until the campaign removes the
+	// annotation once the host is updated, or until the campaign is deleted.
+	FirmwareUpdateCampaignAnnotation = "firmwareupdatecampaign.metal3.io/name"
+)
+
+// FirmwareUpdateCampaignHostPhase is the progress of a host in a campaign.
+type FirmwareUpdateCampaignHostPhase string
+
+const (
+	// FirmwareUpdateCampaignHostPending is used for the hosts waiting for
+	// their wave.
+	FirmwareUpdateCampaignHostPending FirmwareUpdateCampaignHostPhase = "Pending"
+	// FirmwareUpdateCampaignHostUpdating is used for the hosts rebooting
+	// or servicing to apply the updates.
+	FirmwareUpdateCampaignHostUpdating FirmwareUpdateCampaignHostPhase = "Updating"
+	// FirmwareUpdateCampaignHostUpdated is used for the hosts that applied
+	// the updates.
+	FirmwareUpdateCampaignHostUpdated FirmwareUpdateCampaignHostPhase = "Updated"
+	// FirmwareUpdateCampaignHostFailed is used for the hosts in a
+	// servicing error.
+	FirmwareUpdateCampaignHostFailed FirmwareUpdateCampaignHostPhase = "Failed"
+)
+
+// FirmwareUpdateCampaignSpec defines the desired state of FirmwareUpdateCampaign.
+type FirmwareUpdateCampaignSpec struct {
+	// Selector chooses the BareMetalHosts of the namespace to update.
+	Selector metav1.LabelSelector `json:"selector"`
+
+	// Updates are written to the HostFirmwareComponents of each host
+	// before it is rebooted into servicing, replacing the updates of the
+	// same components and keeping the others. The hosts must have a
+	// HostUpdatePolicy allowing firmware updates on reboot.
+	// +kubebuilder:validation:MinItems=1
+	Updates []FirmwareUpdate `json:"updates"`
+
+	// BatchSize is the number of hosts updated in each wave. A wave starts
+	// when all the hosts of the previous one are updated or failed.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +optional
+	BatchSize int `json:"batchSize,omitempty"`
+
+	// MaxFailures is the number of hosts that may be in a servicing error
+	// before the campaign pauses itself.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxFailures int `json:"maxFailures,omitempty"`
+
+	// Paused stops the campaign from starting new waves. The hosts already
+	// updating are not interrupted.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+// FirmwareUpdateCampaignHostStatus is the progress of a host in a campaign.
+type FirmwareUpdateCampaignHostStatus struct {
+	// Name of the BareMetalHost.
+	Name string `json:"name"`
+
+	// Phase of the host in the campaign.
+	Phase FirmwareUpdateCampaignHostPhase `json:"phase"`
+
+	// Message explains why the host is waiting or failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Components are the versions reported by the HostFirmwareComponents
+	// of the host for the components updated by the campaign.
+	// +optional
+	Components []FirmwareComponentStatus `json:"components,omitempty"`
+}
+
+// FirmwareUpdateCampaignStatus defines the observed state of FirmwareUpdateCampaign.
+type FirmwareUpdateCampaignStatus struct {
+	// Hosts is the progress of each host selected by the campaign.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Hosts []FirmwareUpdateCampaignHostStatus `json:"hosts,omitempty"`
+
+	// Pending is the number of hosts waiting for their wave.
+	// +optional
+	Pending int `json:"pending,omitempty"`
+
+	// Updating is the number of hosts of the current wave.
+	// +optional
+	Updating int `json:"updating,omitempty"`
+
+	// Updated is the number of hosts that applied the updates.
+	// +optional
+	Updated int `json:"updated,omitempty"`
+
+	// Failed is the number of hosts in a servicing error.
+	// +optional
+	Failed int `json:"failed,omitempty"`
+
+	// Conditions tell whether the campaign is paused or completed.
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Updated",type="integer",JSONPath=".status.updated",description="Number of hosts updated"
+//+kubebuilder:printcolumn:name="Updating",type="integer",JSONPath=".status.updating",description="Number of hosts updating"
+//+kubebuilder:printcolumn:name="Failed",type="integer",JSONPath=".status.failed",description="Number of hosts in a servicing error"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of FirmwareUpdateCampaign"
+
+// FirmwareUpdateCampaign rolls firmware updates out to the BareMetalHosts
+// matching a label selector, a few hosts at a time.
+type FirmwareUpdateCampaign struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FirmwareUpdateCampaignSpec   `json:"spec,omitempty"`
+	Status FirmwareUpdateCampaignStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// FirmwareUpdateCampaignList contains a list of FirmwareUpdateCampaign.
+type FirmwareUpdateCampaignList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FirmwareUpdateCampaign `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FirmwareUpdateCampaign{}, &FirmwareUpdateCampaignList{})
+}
@@ -0,0 +1,84 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HardwareProfileSpec defines the hosts a HardwareProfile applies to and
+// the defaults it sets for them.
+type HardwareProfileSpec struct {
+	// Match lists the requirements the inspected hardware of a host must
+	// meet for the profile to be chosen automatically, e.g. the vendor, the
+	// product name and the disks. A profile without requirements is only
+	// used by the hosts naming it in their hardwareProfile field.
+	// +optional
+	Match *HardwareRequirements `json:"match,omitempty"`
+
+	// Priority decides between the profiles matching the same host. The
+	// profile with the highest priority is chosen, then the first one in
+	// alphabetical order.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// RootDeviceHints are used for the hosts that do not set their own.
+	// +optional
+	RootDeviceHints *RootDeviceHints `json:"rootDeviceHints,omitempty"`
+
+	// RAID is the RAID configuration used for the hosts that do not set
+	// their own.
+	// +optional
+	RAID *RAIDConfig `json:"raid,omitempty"`
+
+	// Firmware holds the BIOS settings used for the hosts that do not set
+	// their own.
+	// +optional
+	Firmware *FirmwareConfig `json:"firmware,omitempty"`
+
+	// BootMode is used for the hosts that do not set their own.
+	// +optional
+	BootMode BootMode `json:"bootMode,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster,shortName=hwp
+//+kubebuilder:printcolumn:name="Priority",type="integer",JSONPath=".spec.priority",description="Priority of the profile"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of HardwareProfile"
+
+// HardwareProfile holds the default settings of a class of hardware. The
+// name of the profile chosen for a host is recorded in the
+// hardwareProfile field of its status.
+type HardwareProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HardwareProfileSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// HardwareProfileList contains a list of HardwareProfile.
+type HardwareProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HardwareProfile `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HardwareProfile{}, &HardwareProfileList{})
+}
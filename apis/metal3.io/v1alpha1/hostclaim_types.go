@@ -35,6 +35,9 @@ const (
 	MissingBareMetalHostReason = "MissingBareMetalHost"
 	// NoBareMetalHostReason is a reason used when no BareMetalHost matching the constraints is found.
 	NoBareMetalHostReason = "NoBareMetalHost"
+	// QuotaExceededReason is a reason used when the BareMetalHosts matching the constraints cannot
+	// be bound without exceeding the quota of the HostDeployPolicies.
+	QuotaExceededReason = "QuotaExceeded"
 	// BadBareMetalHostStatusReason is a reason used when the status of the associated BareMetalHost cannot be marshalled.
 	BadBareMetalHostStatusReason = "BadBareMetalHostStatus"
 	// HostClaimAnnotationNotSetReason is a reason used when the annotation on the hostclaim cannot be set.
@@ -65,6 +68,13 @@ const (
 	// BareMetalHostNotSynchronizedReason is the reason used when the synchronization of BareMetalHost state
 	// is not successful.
 	BareMetalHostNotSynchronizedReason = "BareMetalHostNotSynchronized"
+
+	// HostScoredCondition documents the score of the BareMetalHost chosen for the HostClaim.
+	// The message of the condition contains the breakdown of the score per scoring plugin.
+	HostScoredCondition = "HostScored"
+
+	// HostScoredReason is the reason used when the BareMetalHost was chosen by ranking the candidates.
+	HostScoredReason = "HostScored"
 )
 
 // HostClaimSpec defines the desired state of HostClaim.
@@ -117,6 +127,38 @@ type HostClaimSpec struct {
 	// infrastructure.cluster.x-k8s.io/failure-domain set to the value of
 	// the field.
 	FailureDomain string `json:"failureDomain,omitempty"`
+
+	// Scoring configures how the BareMetalHosts matching the HostClaim are
+	// ranked before one of them is chosen.
+	// +optional
+	Scoring *HostScoring `json:"scoring,omitempty"`
+}
+
+// HostScoring configures the ranking of the BareMetalHosts that can be
+// bound to a HostClaim.
+type HostScoring struct {
+	// Profile is the name of the scoring profile used to rank the
+	// candidate BareMetalHosts. The built-in profiles are "default" which
+	// combines all the scoring plugins, "binpack" which favors the hosts
+	// with the least RAM and CPUs in excess of the hardware requirements,
+	// "spread" which favors the failure domains
+	// with the fewest hosts bound to HostClaims of the same namespace and
+	// "random" which picks a host at random. If not specified, the
+	// "default" profile is used.
+	// +optional
+	Profile string `json:"profile,omitempty"`
+
+	// PreferredVendors is a list of system manufacturers (as reported by
+	// the inspection of the hosts) that should be preferred. The match is
+	// a case insensitive substring match.
+	// +optional
+	PreferredVendors []string `json:"preferredVendors,omitempty"`
+
+	// PreferredProducts is a list of system product names (as reported by
+	// the inspection of the hosts) that should be preferred. The match is
+	// a case insensitive substring match.
+	// +optional
+	PreferredProducts []string `json:"preferredProducts,omitempty"`
 }
 
 // HostSelector specifies matching criteria for labels on BareMetalHosts.
@@ -136,6 +178,87 @@ type HostSelector struct {
 	// namespaces with a compliant policy.
 	// +optional
 	InNamespace string `json:"inNamespace,omitempty"`
+
+	// Hardware lists requirements on the hardware details found during the
+	// inspection of the BareMetalHosts. Hosts without HardwareData are not
+	// considered when requirements are set.
+	// +optional
+	Hardware *HardwareRequirements `json:"hardware,omitempty"`
+}
+
+// HardwareRequirements specifies the minimal hardware a BareMetalHost must
+// have to be claimed. All the requirements must be met.
+type HardwareRequirements struct {
+	// MinRAMMebibytes is the minimal amount of memory of the host.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinRAMMebibytes int `json:"minRAMMebibytes,omitempty"`
+
+	// MinCPUCount is the minimal number of CPUs of the host.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinCPUCount int `json:"minCPUCount,omitempty"`
+
+	// CPUArchitecture is the architecture of the CPUs, e.g. "x86_64".
+	// +optional
+	CPUArchitecture string `json:"cpuArchitecture,omitempty"`
+
+	// CPUFlags lists the flags the CPUs must all support, e.g. "vmx".
+	// +optional
+	CPUFlags []string `json:"cpuFlags,omitempty"`
+
+	// Disks lists requirements on the storage devices of the host. Each
+	// requirement is evaluated independently.
+	// +optional
+	Disks []DiskRequirement `json:"disks,omitempty"`
+
+	// NICs specifies requirements on the network interfaces of the host.
+	// +optional
+	NICs *NICRequirement `json:"nics,omitempty"`
+
+	// Vendor must be contained in the system manufacturer of the host. The
+	// match is case insensitive.
+	// +optional
+	Vendor string `json:"vendor,omitempty"`
+
+	// Product must be contained in the system product name of the host.
+	// The match is case insensitive.
+	// +optional
+	Product string `json:"product,omitempty"`
+}
+
+// DiskRequirement specifies a number of storage devices of a given type
+// and size that a host must have.
+type DiskRequirement struct {
+	// Type restricts the requirement to the devices of this type. If not
+	// specified, devices of any type are counted.
+	// +kubebuilder:validation:Enum=HDD;SSD;NVME
+	// +optional
+	Type DiskType `json:"type,omitempty"`
+
+	// MinCount is the minimal number of matching devices. Defaults to 1.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinCount int `json:"minCount,omitempty"`
+
+	// MinSizeBytes is the minimal size of each matching device.
+	// +optional
+	MinSizeBytes Capacity `json:"minSizeBytes,omitempty"`
+}
+
+// NICRequirement specifies a number of network interfaces with a given
+// speed that a host must have.
+type NICRequirement struct {
+	// MinCount is the minimal number of matching interfaces. Defaults to 1.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinCount int `json:"minCount,omitempty"`
+
+	// MinSpeedGbps is the minimal speed of each matching interface in
+	// Gigabits per second.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinSpeedGbps int `json:"minSpeedGbps,omitempty"`
 }
 
 type HostSelectorRequirement struct {
@@ -0,0 +1,124 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// HostClaimSetLabel is the label of the HostClaims giving the name of the
+	// HostClaimSet in the same namespace they belong to.
+	HostClaimSetLabel = "hostclaim.metal3.io/set"
+
+	// WaitingForHostClaimSetReason is a reason used when the HostClaimSet of
+	// the HostClaim does not have enough members yet.
+	WaitingForHostClaimSetReason = "WaitingForHostClaimSet"
+	// MissingHostClaimSetReason is a reason used when the HostClaimSet of the
+	// HostClaim does not exist.
+	MissingHostClaimSetReason = "MissingHostClaimSet"
+	// HostClaimSetNotSatisfiableReason is a reason used when hosts cannot be
+	// reserved for all the members of the HostClaimSet.
+	HostClaimSetNotSatisfiableReason = "HostClaimSetNotSatisfiable"
+
+	// HostClaimSetBoundCondition is true when all the members of the
+	// HostClaimSet are bound to a BareMetalHost.
+	HostClaimSetBoundCondition = "Bound"
+	// HostClaimSetBoundReason is a reason used when all the members of the
+	// HostClaimSet are bound.
+	HostClaimSetBoundReason = "MembersBound"
+)
+
+// FailureDomainSpread defines how the members of a HostClaimSet are spread
+// across failure domains.
+// +kubebuilder:validation:Enum=None;Preferred;Required
+type FailureDomainSpread string
+
+const (
+	// FailureDomainSpreadNone does not take failure domains into account.
+	FailureDomainSpreadNone FailureDomainSpread = "None"
+	// FailureDomainSpreadPreferred chooses hosts in the failure domains
+	// with the fewest members of the set when possible.
+	FailureDomainSpreadPreferred FailureDomainSpread = "Preferred"
+	// FailureDomainSpreadRequired binds each member of the set in a
+	// different failure domain.
+	FailureDomainSpreadRequired FailureDomainSpread = "Required"
+)
+
+// HostClaimSetSpec defines the desired state of HostClaimSet.
+type HostClaimSetSpec struct {
+	// MinMembers is the number of HostClaims labelled with the set that must
+	// exist before any of them is bound. Hosts are reserved for all the
+	// unbound members at once or for none of them.
+	// +kubebuilder:validation:Minimum=1
+	MinMembers int `json:"minMembers"`
+
+	// FailureDomainSpread defines how the members are spread across the
+	// failure domains given by the infrastructure.cluster.x-k8s.io/failure-domain
+	// label of the BareMetalHosts.
+	// +kubebuilder:default=None
+	// +optional
+	FailureDomainSpread FailureDomainSpread `json:"failureDomainSpread,omitempty"`
+}
+
+// HostClaimSetStatus defines the observed state of HostClaimSet.
+type HostClaimSetStatus struct {
+	// Members is the number of HostClaims labelled with the set.
+	// +optional
+	Members int `json:"members,omitempty"`
+
+	// BoundMembers is the number of members bound to a BareMetalHost.
+	// +optional
+	BoundMembers int `json:"boundMembers,omitempty"`
+
+	// Conditions describe why the members are not bound yet.
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Members",type="integer",JSONPath=".status.members",description="Number of members"
+//+kubebuilder:printcolumn:name="Bound",type="integer",JSONPath=".status.boundMembers",description="Number of members bound to a host"
+//+kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type=='Bound')].reason",description="Why the members are bound or not"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of HostClaimSet"
+
+// HostClaimSet groups HostClaims that must be bound all together.
+type HostClaimSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HostClaimSetSpec   `json:"spec,omitempty"`
+	Status HostClaimSetStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// HostClaimSetList contains a list of HostClaimSet.
+type HostClaimSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HostClaimSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HostClaimSet{}, &HostClaimSetList{})
+}
@@ -25,6 +25,42 @@ type HostDeployPolicySpec struct {
 	// HostClaimNamespaces constrains the namespaces of the HostClaims allowed
 	// to bind the BareMetalHosts in the same namespace as the HostDeployPolicy
 	HostClaimNamespaces *HostClaimNamespaces `json:"hostClaimNamespaces,omitempty"`
+
+	// Quota limits the resources that the HostClaims of a single namespace
+	// can bind in the namespace of the HostDeployPolicy. When several
+	// HostDeployPolicies accept the namespace of a HostClaim, the quotas of
+	// all of them apply.
+	// +optional
+	Quota *HostClaimQuota `json:"quota,omitempty"`
+
+	// AutomatedCleaningMode erases the disks of the BareMetalHosts in the
+	// same namespace as the HostDeployPolicy when they are deprovisioned,
+	// unless a host sets a cleaning mode erasing its disks itself.
+	// +kubebuilder:validation:Enum=full;secure;crypto
+	// +optional
+	AutomatedCleaningMode AutomatedCleaningMode `json:"automatedCleaningMode,omitempty"`
+}
+
+// HostClaimQuota limits the BareMetalHosts bound by the HostClaims of each
+// namespace. A zero value means no limit.
+type HostClaimQuota struct {
+	// MaxHosts is the maximum number of BareMetalHosts bound by the
+	// HostClaims of a namespace.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxHosts int `json:"maxHosts,omitempty"`
+
+	// MaxRAMMebibytes is the maximum amount of memory of the BareMetalHosts
+	// bound by the HostClaims of a namespace.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxRAMMebibytes int `json:"maxRAMMebibytes,omitempty"`
+
+	// MaxCPUs is the maximum number of logical CPUs of the BareMetalHosts
+	// bound by the HostClaims of a namespace.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxCPUs int `json:"maxCPUs,omitempty"`
 }
 
 type HostClaimNamespaces struct {
@@ -46,8 +82,48 @@ type NameValuePair struct {
 	Value string `json:"value,omitempty"`
 }
 
+// HostResources is an amount of BareMetalHosts with their memory and CPUs as
+// reported by their HardwareData.
+type HostResources struct {
+	// Hosts is the number of BareMetalHosts.
+	Hosts int `json:"hosts"`
+
+	// RAMMebibytes is the memory of the BareMetalHosts.
+	RAMMebibytes int `json:"ramMebibytes"`
+
+	// CPUs is the number of logical CPUs of the BareMetalHosts.
+	CPUs int `json:"cpus"`
+}
+
+// NamespaceUsage is the resources bound by the HostClaims of a namespace.
+type NamespaceUsage struct {
+	// Namespace of the HostClaims.
+	Namespace string `json:"namespace"`
+
+	HostResources `json:",inline"`
+}
+
 // HostDeployPolicyStatus defines the observed state of HostDeployPolicy.
 type HostDeployPolicyStatus struct {
+	// Capacity is the total of the BareMetalHosts in the namespace of the
+	// HostDeployPolicy.
+	// +optional
+	Capacity HostResources `json:"capacity,omitempty"`
+
+	// Free is the total of the BareMetalHosts in the namespace of the
+	// HostDeployPolicy that are not consumed.
+	// +optional
+	Free HostResources `json:"free,omitempty"`
+
+	// Usage lists the resources bound by the HostClaims of each namespace.
+	// +listType=map
+	// +listMapKey=namespace
+	// +optional
+	Usage []NamespaceUsage `json:"usage,omitempty"`
+
+	// LastUpdated identifies when this status was last observed.
+	// +optional
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -41,8 +41,47 @@ const (
 
 	// Indicates if the settings are valid and can be configured on the host.
 	FirmwareSettingsValid SettingsConditionType = "Valid"
+
+	// Indicates that the changes are held until they are approved.
+	FirmwareSettingsHeld SettingsConditionType = "Held"
 )
 
+const (
+	// FirmwareSettingsHoldAnnotation holds the changes of the settings until
+	// they are approved. Its value is ignored.
+	FirmwareSettingsHoldAnnotation = "hostfirmwaresettings.metal3.io/hold"
+
+	// FirmwareSettingsApprovedAnnotation approves the changes of the
+	// settings held by FirmwareSettingsHoldAnnotation. Its value must be
+	// the pendingChangesDigest of the status.
+	FirmwareSettingsApprovedAnnotation = "hostfirmwaresettings.metal3.io/approved"
+)
+
+// FirmwareSettingChange describes how a setting will change once the spec
+// is applied.
+type FirmwareSettingChange struct {
+	// Name of the setting.
+	Name string `json:"name"`
+
+	// CurrentValue is the value reported by the host, empty when the host
+	// does not have this setting.
+	// +optional
+	CurrentValue string `json:"currentValue,omitempty"`
+
+	// NewValue is the value of the spec.
+	NewValue string `json:"newValue"`
+
+	// RequiresReboot is true when the FirmwareSchema tells that the host
+	// must be reset for the change to take effect.
+	// +optional
+	RequiresReboot bool `json:"requiresReboot,omitempty"`
+
+	// Problem explains why the change cannot be applied, e.g. because the
+	// setting is read-only or the value is out of range.
+	// +optional
+	Problem string `json:"problem,omitempty"`
+}
+
 // HostFirmwareSettingsSpec defines the desired state of HostFirmwareSettings.
 type HostFirmwareSettingsSpec struct {
 
@@ -61,6 +100,19 @@ type HostFirmwareSettingsStatus struct {
 	// Settings are the firmware settings stored as name/value pairs
 	Settings SettingsMap `json:"settings" required:"true"`
 
+	// PendingChanges lists the settings of the spec that differ from the
+	// ones of the host.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	PendingChanges []FirmwareSettingChange `json:"pendingChanges,omitempty"`
+
+	// PendingChangesDigest identifies the pending changes. Held changes
+	// are approved by setting the hostfirmwaresettings.metal3.io/approved
+	// annotation to this value.
+	// +optional
+	PendingChangesDigest string `json:"pendingChangesDigest,omitempty"`
+
 	// Time that the status was last updated
 	// +optional
 	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
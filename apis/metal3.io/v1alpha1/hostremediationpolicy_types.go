@@ -0,0 +1,255 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// RemediationAnnotation is set on the hosts being remediated, with
+	// the name of the HostRemediationPolicy as value. The hosts with this
+	// annotation count against the maximum of concurrent remediations of
+	// their namespace.
+	RemediationAnnotation = "remediation.metal3.io/policy"
+
+	// RemediationAttemptAnnotation records the remediation of the host as
+	// a JSON HostRemediationAttempt. It is written together with each
+	// action, and kept once all the actions have been taken until the host
+	// is healthy again.
+	RemediationAttemptAnnotation = "remediation.metal3.io/attempt"
+
+	// ReplaceAnnotation marks a host to be replaced, its value explains
+	// why. HostClaims do not select the hosts with this annotation.
+	ReplaceAnnotation = "remediation.metal3.io/replace"
+
+	// CordonTaintKey is the key of the taint added to the cordoned hosts.
+	// HostClaims do not select the hosts with this taint.
+	CordonTaintKey = "remediation.metal3.io/cordoned"
+)
+
+// HostRemediationAction is an action taken on an unhealthy host.
+type HostRemediationAction string
+
+const (
+	// HostRemediationCordon taints the host and keeps HostClaims from
+	// selecting it.
+	HostRemediationCordon HostRemediationAction = "Cordon"
+	// HostRemediationReboot reboots the host.
+	HostRemediationReboot HostRemediationAction = "Reboot"
+	// HostRemediationInspect inspects the host again once it is
+	// available.
+	HostRemediationInspect HostRemediationAction = "Inspect"
+	// HostRemediationReplace marks the host to be replaced.
+	HostRemediationReplace HostRemediationAction = "Replace"
+)
+
+// HostRemediationReason tells why a host is remediated.
+type HostRemediationReason string
+
+const (
+	// CriticalHealthRemediationReason is used when the BMC has reported a
+	// critical health for longer than the criticalHealthTimeout.
+	CriticalHealthRemediationReason HostRemediationReason = "CriticalHealth"
+	// PowerFailureRemediationReason is used when the host has had more
+	// power failures than the powerFailureThreshold.
+	PowerFailureRemediationReason HostRemediationReason = "PowerFailure"
+)
+
+// HostRemediationPolicySpec defines the desired state of HostRemediationPolicy.
+type HostRemediationPolicySpec struct {
+	// Selector chooses the BareMetalHosts of the namespace to remediate.
+	// All the hosts of the namespace are selected when it is empty.
+	// +optional
+	Selector metav1.LabelSelector `json:"selector,omitempty"`
+
+	// CriticalHealthTimeout is how long the BMC must report a critical
+	// health before the host is remediated.
+	// +kubebuilder:default="10m"
+	// +optional
+	CriticalHealthTimeout metav1.Duration `json:"criticalHealthTimeout,omitempty"`
+
+	// PowerFailureThreshold is the number of power failures after which
+	// the host is remediated.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=3
+	// +optional
+	PowerFailureThreshold int `json:"powerFailureThreshold,omitempty"`
+
+	// PowerFailureWindow is how long a power failure counts towards the
+	// powerFailureThreshold.
+	// +kubebuilder:default="1h"
+	// +optional
+	PowerFailureWindow metav1.Duration `json:"powerFailureWindow,omitempty"`
+
+	// Actions are taken one after the other while the host stays
+	// unhealthy, waiting for the backoff between two of them.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=4
+	// +kubebuilder:validation:items:Enum=Cordon;Reboot;Inspect;Replace
+	Actions []HostRemediationAction `json:"actions"`
+
+	// Backoff is how long an action is given to fix the host before the
+	// next one is taken. It doubles after each action.
+	// +kubebuilder:default="5m"
+	// +optional
+	Backoff metav1.Duration `json:"backoff,omitempty"`
+
+	// MaxBackoff caps the backoff between two actions.
+	// +kubebuilder:default="1h"
+	// +optional
+	MaxBackoff metav1.Duration `json:"maxBackoff,omitempty"`
+
+	// MaxConcurrentRemediations limits the number of hosts of the
+	// namespace remediated at the same time, whichever policy remediates
+	// them.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +optional
+	MaxConcurrentRemediations int `json:"maxConcurrentRemediations,omitempty"`
+}
+
+// HostRemediationStatus is the remediation of a host.
+type HostRemediationStatus struct {
+	// Name of the BareMetalHost.
+	Name string `json:"name"`
+
+	// Reason tells why the host is remediated.
+	// +optional
+	Reason HostRemediationReason `json:"reason,omitempty"`
+
+	// PowerFailures is the number of power failures of the host within
+	// the powerFailureWindow, since the last action.
+	// +optional
+	PowerFailures int `json:"powerFailures,omitempty"`
+
+	// PowerFailureTimes are the times of these power failures.
+	// +optional
+	PowerFailureTimes []metav1.Time `json:"powerFailureTimes,omitempty"`
+
+	// PowerFailing tells whether the host had a power failure when it was
+	// last observed.
+	// +optional
+	PowerFailing bool `json:"powerFailing,omitempty"`
+
+	// Attempts is the number of actions taken.
+	// +optional
+	Attempts int `json:"attempts,omitempty"`
+
+	// LastAction is the last action taken.
+	// +optional
+	LastAction HostRemediationAction `json:"lastAction,omitempty"`
+
+	// LastActionTime is when the last action was taken.
+	// +optional
+	LastActionTime *metav1.Time `json:"lastActionTime,omitempty"`
+
+	// Message explains why the host is waiting.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// HostRemediationAttempt is the value of the RemediationAttemptAnnotation.
+type HostRemediationAttempt struct {
+	// Policy is the name of the HostRemediationPolicy remediating the host.
+	Policy string `json:"policy"`
+
+	// Reason tells why the host is remediated.
+	Reason HostRemediationReason `json:"reason"`
+
+	// StartTime is when the host reserved a remediation slot. It orders
+	// the hosts reserving the last slots of the namespace at the same
+	// time.
+	StartTime metav1.MicroTime `json:"startTime"`
+
+	// Attempts is the number of actions taken.
+	// +optional
+	Attempts int `json:"attempts,omitempty"`
+
+	// LastAction is the last action taken.
+	// +optional
+	LastAction HostRemediationAction `json:"lastAction,omitempty"`
+
+	// LastActionTime is when the last action was taken.
+	// +optional
+	LastActionTime *metav1.Time `json:"lastActionTime,omitempty"`
+}
+
+// HostRemediationPolicyStatus defines the observed state of HostRemediationPolicy.
+type HostRemediationPolicyStatus struct {
+	// Hosts lists the selected hosts that are unhealthy or being
+	// remediated.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Hosts []HostRemediationStatus `json:"hosts,omitempty"`
+
+	// Remediating is the number of hosts being remediated by the policy.
+	// +optional
+	Remediating int `json:"remediating,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Remediating",type="integer",JSONPath=".status.remediating",description="Number of hosts being remediated"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of HostRemediationPolicy"
+
+// HostRemediationPolicy takes actions on the BareMetalHosts of its
+// namespace that report a critical health or repeated power failures.
+type HostRemediationPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HostRemediationPolicySpec   `json:"spec,omitempty"`
+	Status HostRemediationPolicyStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// HostRemediationPolicyList contains a list of HostRemediationPolicy.
+type HostRemediationPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HostRemediationPolicy `json:"items"`
+}
+
+// IsCordoned tells whether a remediation cordoned the host or marked it
+// to be replaced.
+func (host *BareMetalHost) IsCordoned() bool {
+	if _, replace := host.Annotations[ReplaceAnnotation]; replace {
+		return true
+	}
+	for _, taint := range host.Spec.Taints {
+		if taint.Key == CordonTaintKey {
+			return true
+		}
+	}
+	return false
+}
+
+// CordonTaint is the taint added to the cordoned hosts.
+func CordonTaint() corev1.Taint {
+	return corev1.Taint{
+		Key:    CordonTaintKey,
+		Effect: corev1.TaintEffectNoSchedule,
+	}
+}
+
+func init() {
+	SchemeBuilder.Register(&HostRemediationPolicy{}, &HostRemediationPolicyList{})
+}
@@ -26,6 +26,11 @@ const (
 	HostUpdatePolicyOnReboot    UpdatePolicy = "onReboot"
 )
 
+// ServicingReservedAnnotation is set on a host holding one of the slots
+// allowed by the maxConcurrentServicing of its HostUpdatePolicy, with the
+// time the slot was reserved as value. It is removed once servicing ends.
+const ServicingReservedAnnotation = "servicing.metal3.io/reserved"
+
 // HostUpdatePolicySpec defines the desired state of HostUpdatePolicy.
 type HostUpdatePolicySpec struct {
 	// Defines policy for changing firmware settings
@@ -37,6 +42,44 @@ type HostUpdatePolicySpec struct {
 	// +optional
 	// +kubebuilder:validation:Enum="onPreparing";"onReboot"
 	FirmwareUpdates UpdatePolicy `json:"firmwareUpdates,omitempty"`
+
+	// Lists the maintenance windows in which servicing may start. Changes
+	// found on a reboot outside of them are held, and the host is rebooted
+	// again to apply them once a window opens. Servicing may start at any
+	// time when no window is set.
+	// +optional
+	// +kubebuilder:validation:MaxItems=16
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// Limits the number of hosts servicing at the same time among the
+	// hosts with the same value of the servicingGroupLabel label, e.g. to
+	// keep the quorum of the services running in a rack.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrentServicing *int32 `json:"maxConcurrentServicing,omitempty"`
+
+	// The label grouping the hosts counted by maxConcurrentServicing.
+	// All the hosts are counted together when it is not set.
+	// +optional
+	ServicingGroupLabel string `json:"servicingGroupLabel,omitempty"`
+}
+
+// MaintenanceWindow defines recurring periods of time in which servicing
+// may start.
+type MaintenanceWindow struct {
+	// The start of the window in cron format, i.e. the minute, hour, day of
+	// month, month and day of week fields, e.g. "0 2 * * 6" for 2am every
+	// Saturday.
+	// +kubebuilder:validation:MinLength=9
+	Schedule string `json:"schedule"`
+
+	// The length of the window, e.g. "4h".
+	Duration metav1.Duration `json:"duration"`
+
+	// The IANA time zone of the schedule, e.g. "Europe/Paris". Defaults to
+	// UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
 }
 
 // HostUpdatePolicyStatus defines the observed state of HostUpdatePolicy.
@@ -23,6 +23,15 @@ type Profile struct {
 	// RootDeviceHints holds the suggestions for placing the storage
 	// for the root filesystem.
 	RootDeviceHints metal3api.RootDeviceHints
+
+	// RAID holds the default RAID configuration.
+	RAID *metal3api.RAIDConfig
+
+	// Firmware holds the default BIOS settings.
+	Firmware *metal3api.FirmwareConfig
+
+	// BootMode holds the default boot mode.
+	BootMode metal3api.BootMode
 }
 
 var profiles = make(map[string]Profile)
@@ -68,7 +77,8 @@ func init() {
 	}
 }
 
-// GetProfile returns the named profile.
+// GetProfile returns the named built-in profile. HardwareProfile resources
+// are looked up by the controller before the built-in profiles.
 func GetProfile(name string) (Profile, error) {
 	profile, ok := profiles[name]
 	if !ok {
@@ -76,3 +86,17 @@ func GetProfile(name string) (Profile, error) {
 	}
 	return profile, nil
 }
+
+// FromHardwareProfile returns the settings of a HardwareProfile resource.
+func FromHardwareProfile(hwProfile *metal3api.HardwareProfile) Profile {
+	result := Profile{
+		Name:     hwProfile.Name,
+		RAID:     hwProfile.Spec.RAID.DeepCopy(),
+		Firmware: hwProfile.Spec.Firmware.DeepCopy(),
+		BootMode: hwProfile.Spec.BootMode,
+	}
+	if hwProfile.Spec.RootDeviceHints != nil {
+		result.RootDeviceHints = *hwProfile.Spec.RootDeviceHints.DeepCopy()
+	}
+	return result
+}
@@ -77,7 +77,7 @@ func (in *BMCEventSubscription) DeepCopyInto(out *BMCEventSubscription) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BMCEventSubscription.
@@ -153,6 +153,17 @@ func (in *BMCEventSubscriptionSpec) DeepCopy() *BMCEventSubscriptionSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BMCEventSubscriptionStatus) DeepCopyInto(out *BMCEventSubscriptionStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BMCEventSubscriptionStatus.
@@ -280,6 +291,11 @@ func (in *BareMetalHostSpec) DeepCopyInto(out *BareMetalHostSpec) {
 		*out = new(CustomDeploy)
 		**out = **in
 	}
+	if in.Network != nil {
+		in, out := &in.Network, &out.Network
+		*out = new(HostNetwork)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BareMetalHostSpec.
@@ -315,6 +331,13 @@ func (in *BareMetalHostStatus) DeepCopyInto(out *BareMetalHostStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DiskErasures != nil {
+		in, out := &in.DiskErasures, &out.DiskErasures
+		*out = make([]DiskErasure, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BareMetalHostStatus.
@@ -421,6 +444,20 @@ func (in *BareMetalSwitchStatus) DeepCopyInto(out *BareMetalSwitchStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]SwitchPortLink, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Latency != nil {
+		in, out := &in.Latency, &out.Latency
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BareMetalSwitchStatus.
@@ -634,6 +671,62 @@ func (in *DetachedAnnotationArguments) DeepCopy() *DetachedAnnotationArguments {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskErasure) DeepCopyInto(out *DiskErasure) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]ErasedDisk, len(*in))
+		copy(*out, *in)
+	}
+	in.CompletedAt.DeepCopyInto(&out.CompletedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskErasure.
+func (in *DiskErasure) DeepCopy() *DiskErasure {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskErasure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskRequirement) DeepCopyInto(out *DiskRequirement) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskRequirement.
+func (in *DiskRequirement) DeepCopy() *DiskRequirement {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskRequirement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ErasedDisk) DeepCopyInto(out *ErasedDisk) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ErasedDisk.
+func (in *ErasedDisk) DeepCopy() *ErasedDisk {
+	if in == nil {
+		return nil
+	}
+	out := new(ErasedDisk)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Firmware) DeepCopyInto(out *Firmware) {
 	*out = *in
@@ -650,6 +743,183 @@ func (in *Firmware) DeepCopy() *Firmware {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirmwareBaseline) DeepCopyInto(out *FirmwareBaseline) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareBaseline.
+func (in *FirmwareBaseline) DeepCopy() *FirmwareBaseline {
+	if in == nil {
+		return nil
+	}
+	out := new(FirmwareBaseline)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FirmwareBaseline) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirmwareBaselineComponent) DeepCopyInto(out *FirmwareBaselineComponent) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareBaselineComponent.
+func (in *FirmwareBaselineComponent) DeepCopy() *FirmwareBaselineComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(FirmwareBaselineComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirmwareBaselineHostStatus) DeepCopyInto(out *FirmwareBaselineHostStatus) {
+	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]FirmwareComponentCompliance, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareBaselineHostStatus.
+func (in *FirmwareBaselineHostStatus) DeepCopy() *FirmwareBaselineHostStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FirmwareBaselineHostStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirmwareBaselineList) DeepCopyInto(out *FirmwareBaselineList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FirmwareBaseline, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareBaselineList.
+func (in *FirmwareBaselineList) DeepCopy() *FirmwareBaselineList {
+	if in == nil {
+		return nil
+	}
+	out := new(FirmwareBaselineList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FirmwareBaselineList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirmwareBaselineModel) DeepCopyInto(out *FirmwareBaselineModel) {
+	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]FirmwareBaselineComponent, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareBaselineModel.
+func (in *FirmwareBaselineModel) DeepCopy() *FirmwareBaselineModel {
+	if in == nil {
+		return nil
+	}
+	out := new(FirmwareBaselineModel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirmwareBaselineSpec) DeepCopyInto(out *FirmwareBaselineSpec) {
+	*out = *in
+	if in.Models != nil {
+		in, out := &in.Models, &out.Models
+		*out = make([]FirmwareBaselineModel, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareBaselineSpec.
+func (in *FirmwareBaselineSpec) DeepCopy() *FirmwareBaselineSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FirmwareBaselineSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirmwareBaselineStatus) DeepCopyInto(out *FirmwareBaselineStatus) {
+	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]FirmwareBaselineHostStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareBaselineStatus.
+func (in *FirmwareBaselineStatus) DeepCopy() *FirmwareBaselineStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FirmwareBaselineStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirmwareComponentCompliance) DeepCopyInto(out *FirmwareComponentCompliance) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareComponentCompliance.
+func (in *FirmwareComponentCompliance) DeepCopy() *FirmwareComponentCompliance {
+	if in == nil {
+		return nil
+	}
+	out := new(FirmwareComponentCompliance)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FirmwareComponentStatus) DeepCopyInto(out *FirmwareComponentStatus) {
 	*out = *in
@@ -777,40 +1047,41 @@ func (in *FirmwareSchemaSpec) DeepCopy() *FirmwareSchemaSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FirmwareUpdate) DeepCopyInto(out *FirmwareUpdate) {
+func (in *FirmwareSettingChange) DeepCopyInto(out *FirmwareSettingChange) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareUpdate.
-func (in *FirmwareUpdate) DeepCopy() *FirmwareUpdate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareSettingChange.
+func (in *FirmwareSettingChange) DeepCopy() *FirmwareSettingChange {
 	if in == nil {
 		return nil
 	}
-	out := new(FirmwareUpdate)
+	out := new(FirmwareSettingChange)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HardwareData) DeepCopyInto(out *HardwareData) {
+func (in *FirmwareSettingsTemplate) DeepCopyInto(out *FirmwareSettingsTemplate) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareData.
-func (in *HardwareData) DeepCopy() *HardwareData {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareSettingsTemplate.
+func (in *FirmwareSettingsTemplate) DeepCopy() *FirmwareSettingsTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(HardwareData)
+	out := new(FirmwareSettingsTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HardwareData) DeepCopyObject() runtime.Object {
+func (in *FirmwareSettingsTemplate) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -818,31 +1089,31 @@ func (in *HardwareData) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HardwareDataList) DeepCopyInto(out *HardwareDataList) {
+func (in *FirmwareSettingsTemplateList) DeepCopyInto(out *FirmwareSettingsTemplateList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]HardwareData, len(*in))
+		*out = make([]FirmwareSettingsTemplate, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareDataList.
-func (in *HardwareDataList) DeepCopy() *HardwareDataList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareSettingsTemplateList.
+func (in *FirmwareSettingsTemplateList) DeepCopy() *FirmwareSettingsTemplateList {
 	if in == nil {
 		return nil
 	}
-	out := new(HardwareDataList)
+	out := new(FirmwareSettingsTemplateList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HardwareDataList) DeepCopyObject() runtime.Object {
+func (in *FirmwareSettingsTemplateList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -850,109 +1121,882 @@ func (in *HardwareDataList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HardwareDataSpec) DeepCopyInto(out *HardwareDataSpec) {
+func (in *FirmwareSettingsTemplateSpec) DeepCopyInto(out *FirmwareSettingsTemplateSpec) {
 	*out = *in
-	if in.HardwareDetails != nil {
-		in, out := &in.HardwareDetails, &out.HardwareDetails
-		*out = new(HardwareDetails)
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = make(DesiredSettingsMap, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareDataSpec.
-func (in *HardwareDataSpec) DeepCopy() *HardwareDataSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareSettingsTemplateSpec.
+func (in *FirmwareSettingsTemplateSpec) DeepCopy() *FirmwareSettingsTemplateSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(HardwareDataSpec)
+	out := new(FirmwareSettingsTemplateSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HardwareDetails) DeepCopyInto(out *HardwareDetails) {
+func (in *FirmwareSettingsTemplateStatus) DeepCopyInto(out *FirmwareSettingsTemplateStatus) {
 	*out = *in
-	out.SystemVendor = in.SystemVendor
-	out.Firmware = in.Firmware
-	if in.NIC != nil {
-		in, out := &in.NIC, &out.NIC
-		*out = make([]NIC, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.Storage != nil {
-		in, out := &in.Storage, &out.Storage
-		*out = make([]Storage, len(*in))
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	in.CPU.DeepCopyInto(&out.CPU)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareDetails.
-func (in *HardwareDetails) DeepCopy() *HardwareDetails {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareSettingsTemplateStatus.
+func (in *FirmwareSettingsTemplateStatus) DeepCopy() *FirmwareSettingsTemplateStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(HardwareDetails)
+	out := new(FirmwareSettingsTemplateStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HardwareRAIDVolume) DeepCopyInto(out *HardwareRAIDVolume) {
+func (in *FirmwareUpdate) DeepCopyInto(out *FirmwareUpdate) {
 	*out = *in
-	if in.SizeGibibytes != nil {
-		in, out := &in.SizeGibibytes, &out.SizeGibibytes
-		*out = new(int)
-		**out = **in
-	}
-	if in.Rotational != nil {
-		in, out := &in.Rotational, &out.Rotational
-		*out = new(bool)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareUpdate.
+func (in *FirmwareUpdate) DeepCopy() *FirmwareUpdate {
+	if in == nil {
+		return nil
+	}
+	out := new(FirmwareUpdate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirmwareUpdateCampaign) DeepCopyInto(out *FirmwareUpdateCampaign) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareUpdateCampaign.
+func (in *FirmwareUpdateCampaign) DeepCopy() *FirmwareUpdateCampaign {
+	if in == nil {
+		return nil
+	}
+	out := new(FirmwareUpdateCampaign)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FirmwareUpdateCampaign) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirmwareUpdateCampaignHostStatus) DeepCopyInto(out *FirmwareUpdateCampaignHostStatus) {
+	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]FirmwareComponentStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareUpdateCampaignHostStatus.
+func (in *FirmwareUpdateCampaignHostStatus) DeepCopy() *FirmwareUpdateCampaignHostStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FirmwareUpdateCampaignHostStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirmwareUpdateCampaignList) DeepCopyInto(out *FirmwareUpdateCampaignList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FirmwareUpdateCampaign, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareUpdateCampaignList.
+func (in *FirmwareUpdateCampaignList) DeepCopy() *FirmwareUpdateCampaignList {
+	if in == nil {
+		return nil
+	}
+	out := new(FirmwareUpdateCampaignList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FirmwareUpdateCampaignList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirmwareUpdateCampaignSpec) DeepCopyInto(out *FirmwareUpdateCampaignSpec) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+	if in.Updates != nil {
+		in, out := &in.Updates, &out.Updates
+		*out = make([]FirmwareUpdate, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareUpdateCampaignSpec.
+func (in *FirmwareUpdateCampaignSpec) DeepCopy() *FirmwareUpdateCampaignSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FirmwareUpdateCampaignSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirmwareUpdateCampaignStatus) DeepCopyInto(out *FirmwareUpdateCampaignStatus) {
+	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]FirmwareUpdateCampaignHostStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareUpdateCampaignStatus.
+func (in *FirmwareUpdateCampaignStatus) DeepCopy() *FirmwareUpdateCampaignStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FirmwareUpdateCampaignStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareData) DeepCopyInto(out *HardwareData) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareData.
+func (in *HardwareData) DeepCopy() *HardwareData {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareData)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HardwareData) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareDataList) DeepCopyInto(out *HardwareDataList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HardwareData, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareDataList.
+func (in *HardwareDataList) DeepCopy() *HardwareDataList {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareDataList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HardwareDataList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareDataSpec) DeepCopyInto(out *HardwareDataSpec) {
+	*out = *in
+	if in.HardwareDetails != nil {
+		in, out := &in.HardwareDetails, &out.HardwareDetails
+		*out = new(HardwareDetails)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareDataSpec.
+func (in *HardwareDataSpec) DeepCopy() *HardwareDataSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareDataSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareDetails) DeepCopyInto(out *HardwareDetails) {
+	*out = *in
+	out.SystemVendor = in.SystemVendor
+	out.Firmware = in.Firmware
+	if in.NIC != nil {
+		in, out := &in.NIC, &out.NIC
+		*out = make([]NIC, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = make([]Storage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.CPU.DeepCopyInto(&out.CPU)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareDetails.
+func (in *HardwareDetails) DeepCopy() *HardwareDetails {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareDetails)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareProfile) DeepCopyInto(out *HardwareProfile) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareProfile.
+func (in *HardwareProfile) DeepCopy() *HardwareProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HardwareProfile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareProfileList) DeepCopyInto(out *HardwareProfileList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HardwareProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareProfileList.
+func (in *HardwareProfileList) DeepCopy() *HardwareProfileList {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareProfileList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HardwareProfileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareProfileSpec) DeepCopyInto(out *HardwareProfileSpec) {
+	*out = *in
+	if in.Match != nil {
+		in, out := &in.Match, &out.Match
+		*out = new(HardwareRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RootDeviceHints != nil {
+		in, out := &in.RootDeviceHints, &out.RootDeviceHints
+		*out = new(RootDeviceHints)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RAID != nil {
+		in, out := &in.RAID, &out.RAID
+		*out = new(RAIDConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Firmware != nil {
+		in, out := &in.Firmware, &out.Firmware
+		*out = new(FirmwareConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareProfileSpec.
+func (in *HardwareProfileSpec) DeepCopy() *HardwareProfileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareProfileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareRAIDVolume) DeepCopyInto(out *HardwareRAIDVolume) {
+	*out = *in
+	if in.SizeGibibytes != nil {
+		in, out := &in.SizeGibibytes, &out.SizeGibibytes
+		*out = new(int)
+		**out = **in
+	}
+	if in.Rotational != nil {
+		in, out := &in.Rotational, &out.Rotational
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NumberOfPhysicalDisks != nil {
+		in, out := &in.NumberOfPhysicalDisks, &out.NumberOfPhysicalDisks
+		*out = new(int)
+		**out = **in
+	}
+	if in.PhysicalDisks != nil {
+		in, out := &in.PhysicalDisks, &out.PhysicalDisks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareRAIDVolume.
+func (in *HardwareRAIDVolume) DeepCopy() *HardwareRAIDVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareRAIDVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareRequirements) DeepCopyInto(out *HardwareRequirements) {
+	*out = *in
+	if in.CPUFlags != nil {
+		in, out := &in.CPUFlags, &out.CPUFlags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]DiskRequirement, len(*in))
+		copy(*out, *in)
+	}
+	if in.NICs != nil {
+		in, out := &in.NICs, &out.NICs
+		*out = new(NICRequirement)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareRequirements.
+func (in *HardwareRequirements) DeepCopy() *HardwareRequirements {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareRequirements)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareSystemVendor) DeepCopyInto(out *HardwareSystemVendor) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareSystemVendor.
+func (in *HardwareSystemVendor) DeepCopy() *HardwareSystemVendor {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareSystemVendor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostClaim) DeepCopyInto(out *HostClaim) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostClaim.
+func (in *HostClaim) DeepCopy() *HostClaim {
+	if in == nil {
+		return nil
+	}
+	out := new(HostClaim)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HostClaim) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostClaimList) DeepCopyInto(out *HostClaimList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HostClaim, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostClaimList.
+func (in *HostClaimList) DeepCopy() *HostClaimList {
+	if in == nil {
+		return nil
+	}
+	out := new(HostClaimList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HostClaimList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostClaimNamespaces) DeepCopyInto(out *HostClaimNamespaces) {
+	*out = *in
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HasLabels != nil {
+		in, out := &in.HasLabels, &out.HasLabels
+		*out = make([]NameValuePair, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostClaimNamespaces.
+func (in *HostClaimNamespaces) DeepCopy() *HostClaimNamespaces {
+	if in == nil {
+		return nil
+	}
+	out := new(HostClaimNamespaces)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostClaimQuota) DeepCopyInto(out *HostClaimQuota) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostClaimQuota.
+func (in *HostClaimQuota) DeepCopy() *HostClaimQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(HostClaimQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostClaimSet) DeepCopyInto(out *HostClaimSet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostClaimSet.
+func (in *HostClaimSet) DeepCopy() *HostClaimSet {
+	if in == nil {
+		return nil
+	}
+	out := new(HostClaimSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HostClaimSet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostClaimSetList) DeepCopyInto(out *HostClaimSetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HostClaimSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostClaimSetList.
+func (in *HostClaimSetList) DeepCopy() *HostClaimSetList {
+	if in == nil {
+		return nil
+	}
+	out := new(HostClaimSetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HostClaimSetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostClaimSetSpec) DeepCopyInto(out *HostClaimSetSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostClaimSetSpec.
+func (in *HostClaimSetSpec) DeepCopy() *HostClaimSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HostClaimSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostClaimSetStatus) DeepCopyInto(out *HostClaimSetStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostClaimSetStatus.
+func (in *HostClaimSetStatus) DeepCopy() *HostClaimSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HostClaimSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostClaimSpec) DeepCopyInto(out *HostClaimSpec) {
+	*out = *in
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(Image)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UserData != nil {
+		in, out := &in.UserData, &out.UserData
+		*out = new(v1.SecretReference)
+		**out = **in
+	}
+	if in.NetworkData != nil {
+		in, out := &in.NetworkData, &out.NetworkData
+		*out = new(v1.SecretReference)
+		**out = **in
+	}
+	if in.MetaData != nil {
+		in, out := &in.MetaData, &out.MetaData
+		*out = new(v1.SecretReference)
+		**out = **in
+	}
+	if in.CustomDeploy != nil {
+		in, out := &in.CustomDeploy, &out.CustomDeploy
+		*out = new(CustomDeploy)
+		**out = **in
+	}
+	in.HostSelector.DeepCopyInto(&out.HostSelector)
+	if in.ConsumerRef != nil {
+		in, out := &in.ConsumerRef, &out.ConsumerRef
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+	if in.Scoring != nil {
+		in, out := &in.Scoring, &out.Scoring
+		*out = new(HostScoring)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostClaimSpec.
+func (in *HostClaimSpec) DeepCopy() *HostClaimSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HostClaimSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostClaimStatus) DeepCopyInto(out *HostClaimStatus) {
+	*out = *in
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BareMetalHost != nil {
+		in, out := &in.BareMetalHost, &out.BareMetalHost
+		*out = new(ObjectReference)
+		**out = **in
+	}
+	if in.HardwareData != nil {
+		in, out := &in.HardwareData, &out.HardwareData
+		*out = new(ObjectReference)
 		**out = **in
 	}
-	if in.NumberOfPhysicalDisks != nil {
-		in, out := &in.NumberOfPhysicalDisks, &out.NumberOfPhysicalDisks
-		*out = new(int)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostClaimStatus.
+func (in *HostClaimStatus) DeepCopy() *HostClaimStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HostClaimStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostDeployPolicy) DeepCopyInto(out *HostDeployPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostDeployPolicy.
+func (in *HostDeployPolicy) DeepCopy() *HostDeployPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(HostDeployPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HostDeployPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostDeployPolicyList) DeepCopyInto(out *HostDeployPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HostDeployPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostDeployPolicyList.
+func (in *HostDeployPolicyList) DeepCopy() *HostDeployPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(HostDeployPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HostDeployPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	if in.PhysicalDisks != nil {
-		in, out := &in.PhysicalDisks, &out.PhysicalDisks
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostDeployPolicySpec) DeepCopyInto(out *HostDeployPolicySpec) {
+	*out = *in
+	if in.HostClaimNamespaces != nil {
+		in, out := &in.HostClaimNamespaces, &out.HostClaimNamespaces
+		*out = new(HostClaimNamespaces)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = new(HostClaimQuota)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareRAIDVolume.
-func (in *HardwareRAIDVolume) DeepCopy() *HardwareRAIDVolume {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostDeployPolicySpec.
+func (in *HostDeployPolicySpec) DeepCopy() *HostDeployPolicySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(HardwareRAIDVolume)
+	out := new(HostDeployPolicySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HardwareSystemVendor) DeepCopyInto(out *HardwareSystemVendor) {
+func (in *HostDeployPolicyStatus) DeepCopyInto(out *HostDeployPolicyStatus) {
 	*out = *in
+	out.Capacity = in.Capacity
+	out.Free = in.Free
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = make([]NamespaceUsage, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareSystemVendor.
-func (in *HardwareSystemVendor) DeepCopy() *HardwareSystemVendor {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostDeployPolicyStatus.
+func (in *HostDeployPolicyStatus) DeepCopy() *HostDeployPolicyStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(HardwareSystemVendor)
+	out := new(HostDeployPolicyStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostClaim) DeepCopyInto(out *HostClaim) {
+func (in *HostFirmwareComponents) DeepCopyInto(out *HostFirmwareComponents) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -960,18 +2004,18 @@ func (in *HostClaim) DeepCopyInto(out *HostClaim) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostClaim.
-func (in *HostClaim) DeepCopy() *HostClaim {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareComponents.
+func (in *HostFirmwareComponents) DeepCopy() *HostFirmwareComponents {
 	if in == nil {
 		return nil
 	}
-	out := new(HostClaim)
+	out := new(HostFirmwareComponents)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HostClaim) DeepCopyObject() runtime.Object {
+func (in *HostFirmwareComponents) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -979,31 +2023,31 @@ func (in *HostClaim) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostClaimList) DeepCopyInto(out *HostClaimList) {
+func (in *HostFirmwareComponentsList) DeepCopyInto(out *HostFirmwareComponentsList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]HostClaim, len(*in))
+		*out = make([]HostFirmwareComponents, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostClaimList.
-func (in *HostClaimList) DeepCopy() *HostClaimList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareComponentsList.
+func (in *HostFirmwareComponentsList) DeepCopy() *HostFirmwareComponentsList {
 	if in == nil {
 		return nil
 	}
-	out := new(HostClaimList)
+	out := new(HostFirmwareComponentsList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HostClaimList) DeepCopyObject() runtime.Object {
+func (in *HostFirmwareComponentsList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1011,79 +2055,40 @@ func (in *HostClaimList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostClaimNamespaces) DeepCopyInto(out *HostClaimNamespaces) {
+func (in *HostFirmwareComponentsSpec) DeepCopyInto(out *HostFirmwareComponentsSpec) {
 	*out = *in
-	if in.Names != nil {
-		in, out := &in.Names, &out.Names
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.HasLabels != nil {
-		in, out := &in.HasLabels, &out.HasLabels
-		*out = make([]NameValuePair, len(*in))
+	if in.Updates != nil {
+		in, out := &in.Updates, &out.Updates
+		*out = make([]FirmwareUpdate, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostClaimNamespaces.
-func (in *HostClaimNamespaces) DeepCopy() *HostClaimNamespaces {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareComponentsSpec.
+func (in *HostFirmwareComponentsSpec) DeepCopy() *HostFirmwareComponentsSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(HostClaimNamespaces)
+	out := new(HostFirmwareComponentsSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostClaimSpec) DeepCopyInto(out *HostClaimSpec) {
+func (in *HostFirmwareComponentsStatus) DeepCopyInto(out *HostFirmwareComponentsStatus) {
 	*out = *in
-	if in.Image != nil {
-		in, out := &in.Image, &out.Image
-		*out = new(Image)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.UserData != nil {
-		in, out := &in.UserData, &out.UserData
-		*out = new(v1.SecretReference)
-		**out = **in
-	}
-	if in.NetworkData != nil {
-		in, out := &in.NetworkData, &out.NetworkData
-		*out = new(v1.SecretReference)
-		**out = **in
-	}
-	if in.MetaData != nil {
-		in, out := &in.MetaData, &out.MetaData
-		*out = new(v1.SecretReference)
-		**out = **in
-	}
-	if in.CustomDeploy != nil {
-		in, out := &in.CustomDeploy, &out.CustomDeploy
-		*out = new(CustomDeploy)
-		**out = **in
-	}
-	in.HostSelector.DeepCopyInto(&out.HostSelector)
-	if in.ConsumerRef != nil {
-		in, out := &in.ConsumerRef, &out.ConsumerRef
-		*out = new(v1.ObjectReference)
-		**out = **in
+	if in.Updates != nil {
+		in, out := &in.Updates, &out.Updates
+		*out = make([]FirmwareUpdate, len(*in))
+		copy(*out, *in)
 	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostClaimSpec.
-func (in *HostClaimSpec) DeepCopy() *HostClaimSpec {
-	if in == nil {
-		return nil
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]FirmwareComponentStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	out := new(HostClaimSpec)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostClaimStatus) DeepCopyInto(out *HostClaimStatus) {
-	*out = *in
 	if in.LastUpdated != nil {
 		in, out := &in.LastUpdated, &out.LastUpdated
 		*out = (*in).DeepCopy()
@@ -1095,49 +2100,39 @@ func (in *HostClaimStatus) DeepCopyInto(out *HostClaimStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.BareMetalHost != nil {
-		in, out := &in.BareMetalHost, &out.BareMetalHost
-		*out = new(ObjectReference)
-		**out = **in
-	}
-	if in.HardwareData != nil {
-		in, out := &in.HardwareData, &out.HardwareData
-		*out = new(ObjectReference)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostClaimStatus.
-func (in *HostClaimStatus) DeepCopy() *HostClaimStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareComponentsStatus.
+func (in *HostFirmwareComponentsStatus) DeepCopy() *HostFirmwareComponentsStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(HostClaimStatus)
+	out := new(HostFirmwareComponentsStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostDeployPolicy) DeepCopyInto(out *HostDeployPolicy) {
+func (in *HostFirmwareSettings) DeepCopyInto(out *HostFirmwareSettings) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostDeployPolicy.
-func (in *HostDeployPolicy) DeepCopy() *HostDeployPolicy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareSettings.
+func (in *HostFirmwareSettings) DeepCopy() *HostFirmwareSettings {
 	if in == nil {
 		return nil
 	}
-	out := new(HostDeployPolicy)
+	out := new(HostFirmwareSettings)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HostDeployPolicy) DeepCopyObject() runtime.Object {
+func (in *HostFirmwareSettings) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1145,31 +2140,31 @@ func (in *HostDeployPolicy) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostDeployPolicyList) DeepCopyInto(out *HostDeployPolicyList) {
+func (in *HostFirmwareSettingsList) DeepCopyInto(out *HostFirmwareSettingsList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]HostDeployPolicy, len(*in))
+		*out = make([]HostFirmwareSettings, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostDeployPolicyList.
-func (in *HostDeployPolicyList) DeepCopy() *HostDeployPolicyList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareSettingsList.
+func (in *HostFirmwareSettingsList) DeepCopy() *HostFirmwareSettingsList {
 	if in == nil {
 		return nil
 	}
-	out := new(HostDeployPolicyList)
+	out := new(HostFirmwareSettingsList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HostDeployPolicyList) DeepCopyObject() runtime.Object {
+func (in *HostFirmwareSettingsList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1177,42 +2172,126 @@ func (in *HostDeployPolicyList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostDeployPolicySpec) DeepCopyInto(out *HostDeployPolicySpec) {
+func (in *HostFirmwareSettingsSpec) DeepCopyInto(out *HostFirmwareSettingsSpec) {
 	*out = *in
-	if in.HostClaimNamespaces != nil {
-		in, out := &in.HostClaimNamespaces, &out.HostClaimNamespaces
-		*out = new(HostClaimNamespaces)
-		(*in).DeepCopyInto(*out)
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = make(DesiredSettingsMap, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareSettingsSpec.
+func (in *HostFirmwareSettingsSpec) DeepCopy() *HostFirmwareSettingsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HostFirmwareSettingsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostFirmwareSettingsStatus) DeepCopyInto(out *HostFirmwareSettingsStatus) {
+	*out = *in
+	if in.FirmwareSchema != nil {
+		in, out := &in.FirmwareSchema, &out.FirmwareSchema
+		*out = new(SchemaReference)
+		**out = **in
+	}
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = make(SettingsMap, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PendingChanges != nil {
+		in, out := &in.PendingChanges, &out.PendingChanges
+		*out = make([]FirmwareSettingChange, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareSettingsStatus.
+func (in *HostFirmwareSettingsStatus) DeepCopy() *HostFirmwareSettingsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HostFirmwareSettingsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostNetwork) DeepCopyInto(out *HostNetwork) {
+	*out = *in
+	if in.ProvisioningVLAN != nil {
+		in, out := &in.ProvisioningVLAN, &out.ProvisioningVLAN
+		*out = new(VLANID)
+		**out = **in
+	}
+	if in.Interfaces != nil {
+		in, out := &in.Interfaces, &out.Interfaces
+		*out = make([]NetworkInterface, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Bonds != nil {
+		in, out := &in.Bonds, &out.Bonds
+		*out = make([]NetworkBond, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostDeployPolicySpec.
-func (in *HostDeployPolicySpec) DeepCopy() *HostDeployPolicySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostNetwork.
+func (in *HostNetwork) DeepCopy() *HostNetwork {
 	if in == nil {
 		return nil
 	}
-	out := new(HostDeployPolicySpec)
+	out := new(HostNetwork)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostDeployPolicyStatus) DeepCopyInto(out *HostDeployPolicyStatus) {
+func (in *HostRemediationAttempt) DeepCopyInto(out *HostRemediationAttempt) {
 	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.LastActionTime != nil {
+		in, out := &in.LastActionTime, &out.LastActionTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostDeployPolicyStatus.
-func (in *HostDeployPolicyStatus) DeepCopy() *HostDeployPolicyStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostRemediationAttempt.
+func (in *HostRemediationAttempt) DeepCopy() *HostRemediationAttempt {
 	if in == nil {
 		return nil
 	}
-	out := new(HostDeployPolicyStatus)
+	out := new(HostRemediationAttempt)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostFirmwareComponents) DeepCopyInto(out *HostFirmwareComponents) {
+func (in *HostRemediationPolicy) DeepCopyInto(out *HostRemediationPolicy) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -1220,18 +2299,18 @@ func (in *HostFirmwareComponents) DeepCopyInto(out *HostFirmwareComponents) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareComponents.
-func (in *HostFirmwareComponents) DeepCopy() *HostFirmwareComponents {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostRemediationPolicy.
+func (in *HostRemediationPolicy) DeepCopy() *HostRemediationPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(HostFirmwareComponents)
+	out := new(HostRemediationPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HostFirmwareComponents) DeepCopyObject() runtime.Object {
+func (in *HostRemediationPolicy) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1239,31 +2318,31 @@ func (in *HostFirmwareComponents) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostFirmwareComponentsList) DeepCopyInto(out *HostFirmwareComponentsList) {
+func (in *HostRemediationPolicyList) DeepCopyInto(out *HostRemediationPolicyList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]HostFirmwareComponents, len(*in))
+		*out = make([]HostRemediationPolicy, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareComponentsList.
-func (in *HostFirmwareComponentsList) DeepCopy() *HostFirmwareComponentsList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostRemediationPolicyList.
+func (in *HostRemediationPolicyList) DeepCopy() *HostRemediationPolicyList {
 	if in == nil {
 		return nil
 	}
-	out := new(HostFirmwareComponentsList)
+	out := new(HostRemediationPolicyList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HostFirmwareComponentsList) DeepCopyObject() runtime.Object {
+func (in *HostRemediationPolicyList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1271,178 +2350,114 @@ func (in *HostFirmwareComponentsList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostFirmwareComponentsSpec) DeepCopyInto(out *HostFirmwareComponentsSpec) {
+func (in *HostRemediationPolicySpec) DeepCopyInto(out *HostRemediationPolicySpec) {
 	*out = *in
-	if in.Updates != nil {
-		in, out := &in.Updates, &out.Updates
-		*out = make([]FirmwareUpdate, len(*in))
+	in.Selector.DeepCopyInto(&out.Selector)
+	out.CriticalHealthTimeout = in.CriticalHealthTimeout
+	out.PowerFailureWindow = in.PowerFailureWindow
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]HostRemediationAction, len(*in))
 		copy(*out, *in)
 	}
+	out.Backoff = in.Backoff
+	out.MaxBackoff = in.MaxBackoff
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareComponentsSpec.
-func (in *HostFirmwareComponentsSpec) DeepCopy() *HostFirmwareComponentsSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostRemediationPolicySpec.
+func (in *HostRemediationPolicySpec) DeepCopy() *HostRemediationPolicySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(HostFirmwareComponentsSpec)
+	out := new(HostRemediationPolicySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostFirmwareComponentsStatus) DeepCopyInto(out *HostFirmwareComponentsStatus) {
+func (in *HostRemediationPolicyStatus) DeepCopyInto(out *HostRemediationPolicyStatus) {
 	*out = *in
-	if in.Updates != nil {
-		in, out := &in.Updates, &out.Updates
-		*out = make([]FirmwareUpdate, len(*in))
-		copy(*out, *in)
-	}
-	if in.Components != nil {
-		in, out := &in.Components, &out.Components
-		*out = make([]FirmwareComponentStatus, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.LastUpdated != nil {
-		in, out := &in.LastUpdated, &out.LastUpdated
-		*out = (*in).DeepCopy()
-	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]HostRemediationStatus, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareComponentsStatus.
-func (in *HostFirmwareComponentsStatus) DeepCopy() *HostFirmwareComponentsStatus {
-	if in == nil {
-		return nil
-	}
-	out := new(HostFirmwareComponentsStatus)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostFirmwareSettings) DeepCopyInto(out *HostFirmwareSettings) {
-	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareSettings.
-func (in *HostFirmwareSettings) DeepCopy() *HostFirmwareSettings {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostRemediationPolicyStatus.
+func (in *HostRemediationPolicyStatus) DeepCopy() *HostRemediationPolicyStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(HostFirmwareSettings)
+	out := new(HostRemediationPolicyStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HostFirmwareSettings) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostFirmwareSettingsList) DeepCopyInto(out *HostFirmwareSettingsList) {
+func (in *HostRemediationStatus) DeepCopyInto(out *HostRemediationStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]HostFirmwareSettings, len(*in))
+	if in.PowerFailureTimes != nil {
+		in, out := &in.PowerFailureTimes, &out.PowerFailureTimes
+		*out = make([]metav1.Time, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LastActionTime != nil {
+		in, out := &in.LastActionTime, &out.LastActionTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareSettingsList.
-func (in *HostFirmwareSettingsList) DeepCopy() *HostFirmwareSettingsList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostRemediationStatus.
+func (in *HostRemediationStatus) DeepCopy() *HostRemediationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(HostFirmwareSettingsList)
+	out := new(HostRemediationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HostFirmwareSettingsList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostFirmwareSettingsSpec) DeepCopyInto(out *HostFirmwareSettingsSpec) {
+func (in *HostResources) DeepCopyInto(out *HostResources) {
 	*out = *in
-	if in.Settings != nil {
-		in, out := &in.Settings, &out.Settings
-		*out = make(DesiredSettingsMap, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareSettingsSpec.
-func (in *HostFirmwareSettingsSpec) DeepCopy() *HostFirmwareSettingsSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostResources.
+func (in *HostResources) DeepCopy() *HostResources {
 	if in == nil {
 		return nil
 	}
-	out := new(HostFirmwareSettingsSpec)
+	out := new(HostResources)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostFirmwareSettingsStatus) DeepCopyInto(out *HostFirmwareSettingsStatus) {
+func (in *HostScoring) DeepCopyInto(out *HostScoring) {
 	*out = *in
-	if in.FirmwareSchema != nil {
-		in, out := &in.FirmwareSchema, &out.FirmwareSchema
-		*out = new(SchemaReference)
-		**out = **in
-	}
-	if in.Settings != nil {
-		in, out := &in.Settings, &out.Settings
-		*out = make(SettingsMap, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.LastUpdated != nil {
-		in, out := &in.LastUpdated, &out.LastUpdated
-		*out = (*in).DeepCopy()
+	if in.PreferredVendors != nil {
+		in, out := &in.PreferredVendors, &out.PreferredVendors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.PreferredProducts != nil {
+		in, out := &in.PreferredProducts, &out.PreferredProducts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareSettingsStatus.
-func (in *HostFirmwareSettingsStatus) DeepCopy() *HostFirmwareSettingsStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostScoring.
+func (in *HostScoring) DeepCopy() *HostScoring {
 	if in == nil {
 		return nil
 	}
-	out := new(HostFirmwareSettingsStatus)
+	out := new(HostScoring)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1464,6 +2479,11 @@ func (in *HostSelector) DeepCopyInto(out *HostSelector) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Hardware != nil {
+		in, out := &in.Hardware, &out.Hardware
+		*out = new(HardwareRequirements)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostSelector.
@@ -1501,7 +2521,7 @@ func (in *HostUpdatePolicy) DeepCopyInto(out *HostUpdatePolicy) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	out.Status = in.Status
 }
 
@@ -1558,6 +2578,16 @@ func (in *HostUpdatePolicyList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostUpdatePolicySpec) DeepCopyInto(out *HostUpdatePolicySpec) {
 	*out = *in
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxConcurrentServicing != nil {
+		in, out := &in.MaxConcurrentServicing, &out.MaxConcurrentServicing
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostUpdatePolicySpec.
@@ -1625,6 +2655,22 @@ func (in *LLDP) DeepCopy() *LLDP {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NIC) DeepCopyInto(out *NIC) {
 	*out = *in
@@ -1650,6 +2696,21 @@ func (in *NIC) DeepCopy() *NIC {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NICRequirement) DeepCopyInto(out *NICRequirement) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NICRequirement.
+func (in *NICRequirement) DeepCopy() *NICRequirement {
+	if in == nil {
+		return nil
+	}
+	out := new(NICRequirement)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NameValuePair) DeepCopyInto(out *NameValuePair) {
 	*out = *in
@@ -1665,6 +2726,59 @@ func (in *NameValuePair) DeepCopy() *NameValuePair {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceUsage) DeepCopyInto(out *NamespaceUsage) {
+	*out = *in
+	out.HostResources = in.HostResources
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceUsage.
+func (in *NamespaceUsage) DeepCopy() *NamespaceUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkBond) DeepCopyInto(out *NetworkBond) {
+	*out = *in
+	if in.MACAddresses != nil {
+		in, out := &in.MACAddresses, &out.MACAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.SwitchPortConfig.DeepCopyInto(&out.SwitchPortConfig)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkBond.
+func (in *NetworkBond) DeepCopy() *NetworkBond {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkBond)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkInterface) DeepCopyInto(out *NetworkInterface) {
+	*out = *in
+	in.SwitchPortConfig.DeepCopyInto(&out.SwitchPortConfig)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkInterface.
+func (in *NetworkInterface) DeepCopy() *NetworkInterface {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkInterface)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectReference) DeepCopyInto(out *ObjectReference) {
 	*out = *in
@@ -1871,6 +2985,13 @@ func (in *RAIDConfig) DeepCopyInto(out *RAIDConfig) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Intent != nil {
+		in, out := &in.Intent, &out.Intent
+		*out = make([]RAIDVolumeIntent, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RAIDConfig.
@@ -1883,6 +3004,26 @@ func (in *RAIDConfig) DeepCopy() *RAIDConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RAIDVolumeIntent) DeepCopyInto(out *RAIDVolumeIntent) {
+	*out = *in
+	if in.DiskCount != nil {
+		in, out := &in.DiskCount, &out.DiskCount
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RAIDVolumeIntent.
+func (in *RAIDVolumeIntent) DeepCopy() *RAIDVolumeIntent {
+	if in == nil {
+		return nil
+	}
+	out := new(RAIDVolumeIntent)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RebootAnnotationArguments) DeepCopyInto(out *RebootAnnotationArguments) {
 	*out = *in
@@ -2001,6 +3142,11 @@ func (in *SettingSchema) DeepCopyInto(out *SettingSchema) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.ResetRequired != nil {
+		in, out := &in.ResetRequired, &out.ResetRequired
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SettingSchema.
@@ -2081,6 +3227,46 @@ func (in *Storage) DeepCopy() *Storage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwitchPortConfig) DeepCopyInto(out *SwitchPortConfig) {
+	*out = *in
+	if in.VLAN != nil {
+		in, out := &in.VLAN, &out.VLAN
+		*out = new(VLANID)
+		**out = **in
+	}
+	if in.AllowedVLANs != nil {
+		in, out := &in.AllowedVLANs, &out.AllowedVLANs
+		*out = make([]VLANID, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchPortConfig.
+func (in *SwitchPortConfig) DeepCopy() *SwitchPortConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SwitchPortConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwitchPortLink) DeepCopyInto(out *SwitchPortLink) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchPortLink.
+func (in *SwitchPortLink) DeepCopy() *SwitchPortLink {
+	if in == nil {
+		return nil
+	}
+	out := new(SwitchPortLink)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VLAN) DeepCopyInto(out *VLAN) {
 	*out = *in
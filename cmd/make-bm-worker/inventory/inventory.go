@@ -0,0 +1,350 @@
+package inventory
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	webhooks "github.com/metal3-io/baremetal-operator/internal/webhooks/metal3.io/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	bootModes    = []string{string(metal3api.UEFI), string(metal3api.UEFISecureBoot), string(metal3api.Legacy)}
+	imageFormats = []string{"raw", "qcow2", "vdi", "vmdk", "live-iso"}
+)
+
+// Host is one entry of the inventory.
+type Host struct {
+	Name                           string                     `json:"name"`
+	BMCAddress                     string                     `json:"bmcAddress"`
+	Username                       string                     `json:"username"`
+	Password                       string                     `json:"password"` //nolint:gosec
+	DisableCertificateVerification bool                       `json:"disableCertificateVerification,omitempty"`
+	BootMACAddress                 string                     `json:"bootMACAddress,omitempty"`
+	BootMode                       string                     `json:"bootMode,omitempty"`
+	Labels                         map[string]string          `json:"labels,omitempty"`
+	RootDeviceHints                *metal3api.RootDeviceHints `json:"rootDeviceHints,omitempty"`
+	RAID                           *metal3api.RAIDConfig      `json:"raid,omitempty"`
+	Image                          *metal3api.Image           `json:"image,omitempty"`
+}
+
+// Row is a host read from the inventory, with the error met while
+// reading it if any.
+type Row struct {
+	// Number is the position of the row in the inventory, starting at 1
+	// and not counting the CSV header.
+	Number int
+	Host   Host
+	Err    error
+}
+
+// RowError reports the problems found in one row of the inventory.
+type RowError struct {
+	Number int
+	Name   string
+	Errors []error
+}
+
+func (e RowError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Sprintf("row %d (%s): %s", e.Number, e.Name, strings.Join(messages, "; "))
+}
+
+// Load reads the inventory from a CSV file, or from a YAML or JSON file
+// holding a list of hosts, depending on the extension of the file.
+func Load(path string) ([]Row, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return ParseCSV(bytes.NewReader(data))
+	case ".yaml", ".yml", ".json":
+		return ParseYAML(data)
+	default:
+		return nil, fmt.Errorf("unknown inventory format for %s, use a .csv, .yaml or .json file", path)
+	}
+}
+
+// ParseYAML reads a list of hosts. An entry that can not be decoded is
+// returned with its error so the other entries can still be used.
+func ParseYAML(data []byte) ([]Row, error) {
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid inventory: %w", err)
+	}
+	entries := []json.RawMessage{}
+	if err = json.Unmarshal(jsonData, &entries); err != nil {
+		return nil, fmt.Errorf("the inventory must be a list of hosts: %w", err)
+	}
+
+	rows := make([]Row, 0, len(entries))
+	for i, entry := range entries {
+		row := Row{Number: i + 1}
+		decoder := json.NewDecoder(bytes.NewReader(entry))
+		decoder.DisallowUnknownFields()
+		row.Err = decoder.Decode(&row.Host)
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// ParseCSV reads hosts from CSV data with a header naming the columns.
+// The columns are the fields of Host, except for the image which uses the
+// imageURL, imageChecksum, imageChecksumType and imageFormat columns.
+// The labels, rootDeviceHints and raid columns hold YAML flow mappings,
+// e.g. "{rack: r1, row: b}".
+func ParseCSV(r io.Reader) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the inventory header: %w", err)
+	}
+
+	columns := make([]string, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if _, known := csvColumns[strings.ToLower(name)]; !known {
+			return nil, fmt.Errorf("unknown inventory column %q", name)
+		}
+		columns[i] = strings.ToLower(name)
+	}
+
+	rows := []Row{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		row := Row{Number: len(rows) + 1}
+		if err != nil {
+			row.Err = err
+			rows = append(rows, row)
+			continue
+		}
+		for i, value := range record {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+			if err := csvColumns[columns[i]](&row.Host, value); err != nil {
+				row.Err = fmt.Errorf("invalid %s: %w", header[i], err)
+				break
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+var csvColumns = map[string]func(*Host, string) error{
+	"name":           func(h *Host, v string) error { h.Name = v; return nil },
+	"bmcaddress":     func(h *Host, v string) error { h.BMCAddress = v; return nil },
+	"username":       func(h *Host, v string) error { h.Username = v; return nil },
+	"password":       func(h *Host, v string) error { h.Password = v; return nil },
+	"bootmacaddress": func(h *Host, v string) error { h.BootMACAddress = v; return nil },
+	"bootmode":       func(h *Host, v string) error { h.BootMode = v; return nil },
+	"disablecertificateverification": func(h *Host, v string) (err error) {
+		h.DisableCertificateVerification, err = strconv.ParseBool(v)
+		return err
+	},
+	"labels":          func(h *Host, v string) error { return yaml.UnmarshalStrict([]byte(v), &h.Labels) },
+	"rootdevicehints": func(h *Host, v string) error { return yaml.UnmarshalStrict([]byte(v), &h.RootDeviceHints) },
+	"raid":            func(h *Host, v string) error { return yaml.UnmarshalStrict([]byte(v), &h.RAID) },
+	"imageurl":        func(h *Host, v string) error { image(h).URL = v; return nil },
+	"imagechecksum":   func(h *Host, v string) error { image(h).Checksum = v; return nil },
+	"imagechecksumtype": func(h *Host, v string) error {
+		image(h).ChecksumType = metal3api.ChecksumType(v)
+		return nil
+	},
+	"imageformat": func(h *Host, v string) error { image(h).DiskFormat = &v; return nil },
+}
+
+func image(h *Host) *metal3api.Image {
+	if h.Image == nil {
+		h.Image = &metal3api.Image{}
+	}
+	return h.Image
+}
+
+// Generate validates the rows and returns a multi-document manifest with
+// a BareMetalHost for each valid row. Hosts sharing the same credentials
+// share a single Secret, named after the first of these hosts. The rows
+// that are not valid are left out of the manifest and reported.
+func Generate(rows []Row) (string, []RowError, error) {
+	var manifest strings.Builder
+	var report []RowError
+	names := map[string]int{}
+	macs := map[string]int{}
+	secrets := map[[2]string]string{}
+
+	for _, row := range rows {
+		host := hostFromRow(row.Host)
+
+		var errs []error
+		if row.Err != nil {
+			errs = append(errs, row.Err)
+		} else {
+			errs = validateRow(row.Host, host)
+			if other, found := names[host.Name]; found {
+				errs = append(errs, fmt.Errorf("name %s is already used by row %d", host.Name, other))
+			}
+			mac := strings.ToLower(host.Spec.BootMACAddress)
+			if other, found := macs[mac]; found && mac != "" {
+				errs = append(errs, fmt.Errorf("boot MAC address %s is already used by row %d", host.Spec.BootMACAddress, other))
+			}
+		}
+		if len(errs) > 0 {
+			report = append(report, RowError{Number: row.Number, Name: row.Host.Name, Errors: errs})
+			continue
+		}
+		names[host.Name] = row.Number
+		if host.Spec.BootMACAddress != "" {
+			macs[strings.ToLower(host.Spec.BootMACAddress)] = row.Number
+		}
+
+		credentials := [2]string{row.Host.Username, row.Host.Password}
+		secretName, found := secrets[credentials]
+		if !found {
+			secretName = host.Name + "-bmc-secret"
+			secrets[credentials] = secretName
+			if err := writeDocument(&manifest, credentialsSecret(secretName, row.Host)); err != nil {
+				return "", nil, err
+			}
+		}
+		host.Spec.BMC.CredentialsName = secretName
+		if err := writeDocument(&manifest, host); err != nil {
+			return "", nil, err
+		}
+	}
+
+	return manifest.String(), report, nil
+}
+
+func validateRow(row Host, host *metal3api.BareMetalHost) (errs []error) {
+	if row.Name == "" {
+		errs = append(errs, errors.New("missing name"))
+	} else if msgs := validation.IsDNS1123Subdomain(row.Name); len(msgs) > 0 {
+		errs = append(errs, fmt.Errorf("invalid name %q: %s", row.Name, strings.Join(msgs, ", ")))
+	}
+	if row.BMCAddress == "" {
+		errs = append(errs, errors.New("missing BMC address"))
+	}
+	if row.Username == "" {
+		errs = append(errs, errors.New("missing username"))
+	}
+	if row.Password == "" {
+		errs = append(errs, errors.New("missing password"))
+	}
+	if row.BootMode != "" && !slices.Contains(bootModes, row.BootMode) {
+		errs = append(errs, fmt.Errorf("invalid boot mode %q, use one of %v", row.BootMode, bootModes))
+	}
+	if row.Image != nil && row.Image.DiskFormat != nil && !slices.Contains(imageFormats, *row.Image.DiskFormat) {
+		errs = append(errs, fmt.Errorf("invalid image format %q, use one of %v", *row.Image.DiskFormat, imageFormats))
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return webhooks.ValidateBareMetalHost(host)
+}
+
+func hostFromRow(row Host) *metal3api.BareMetalHost {
+	return &metal3api.BareMetalHost{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: metal3api.GroupVersion.String(),
+			Kind:       "BareMetalHost",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   row.Name,
+			Labels: row.Labels,
+		},
+		Spec: metal3api.BareMetalHostSpec{
+			Online: true,
+			BMC: metal3api.BMCDetails{
+				Address:                        row.BMCAddress,
+				DisableCertificateVerification: row.DisableCertificateVerification,
+			},
+			BootMACAddress:  row.BootMACAddress,
+			BootMode:        metal3api.BootMode(row.BootMode),
+			RootDeviceHints: row.RootDeviceHints,
+			RAID:            row.RAID,
+			Image:           row.Image,
+		},
+	}
+}
+
+func credentialsSecret(name string, row Host) *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			"username": []byte(row.Username),
+			"password": []byte(row.Password),
+		},
+	}
+}
+
+// writeDocument appends the object to the manifest, without the status,
+// the fields only set by the API server and the null fields.
+func writeDocument(manifest *strings.Builder, obj interface{}) error {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	doc := map[string]interface{}{}
+	if err = yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	delete(doc, "status")
+	if metadata, ok := doc["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "creationTimestamp")
+	}
+	pruneNulls(doc)
+	if data, err = yaml.Marshal(doc); err != nil {
+		return err
+	}
+	manifest.WriteString("---\n")
+	manifest.Write(data)
+	return nil
+}
+
+func pruneNulls(value interface{}) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			if item == nil {
+				delete(value, key)
+				continue
+			}
+			pruneNulls(item)
+		}
+	case []interface{}:
+		for _, item := range value {
+			pruneNulls(item)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package inventory
+
+import (
+	"strings"
+	"testing"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestParseCSV(t *testing.T) {
+	data := `name, bmcAddress, username, password, bootMACAddress, labels, rootDeviceHints, raid, imageURL, imageFormat
+worker-0,ipmi://192.168.122.1,admin,secret,00:11:22:33:44:55,"{rack: r1}",{deviceName: /dev/sda},"{hardwareRAIDVolumes: [{level: ""1""}]}",http://example.com/image.iso,live-iso
+worker-1,ipmi://192.168.122.2,admin,secret,,"[r1, r2]",,,,
+`
+	rows, err := ParseCSV(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+
+	host := rows[0].Host
+	if rows[0].Err != nil {
+		t.Fatalf("unexpected row error: %v", rows[0].Err)
+	}
+	if host.Name != "worker-0" || host.BMCAddress != "ipmi://192.168.122.1" || host.BootMACAddress != "00:11:22:33:44:55" {
+		t.Errorf("unexpected host %+v", host)
+	}
+	if host.Labels["rack"] != "r1" {
+		t.Errorf("unexpected labels %v", host.Labels)
+	}
+	if host.RootDeviceHints == nil || host.RootDeviceHints.DeviceName != "/dev/sda" {
+		t.Errorf("unexpected root device hints %v", host.RootDeviceHints)
+	}
+	if host.RAID == nil || len(host.RAID.HardwareRAIDVolumes) != 1 || host.RAID.HardwareRAIDVolumes[0].Level != "1" {
+		t.Errorf("unexpected RAID %v", host.RAID)
+	}
+	if host.Image == nil || host.Image.URL != "http://example.com/image.iso" || *host.Image.DiskFormat != "live-iso" {
+		t.Errorf("unexpected image %v", host.Image)
+	}
+
+	if rows[1].Err == nil || !strings.Contains(rows[1].Err.Error(), "invalid labels") {
+		t.Errorf("expected a labels error, got %v", rows[1].Err)
+	}
+}
+
+func TestParseCSVUnknownColumn(t *testing.T) {
+	_, err := ParseCSV(strings.NewReader("name,bmc\nworker-0,ipmi://192.168.122.1\n"))
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestParseYAML(t *testing.T) {
+	data := `
+- name: worker-0
+  bmcAddress: ipmi://192.168.122.1
+  username: admin
+  password: secret
+  rootDeviceHints:
+    minSizeGigabytes: 100
+- name: worker-1
+  bmcAdress: ipmi://192.168.122.2
+`
+	rows, err := ParseYAML([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+	if rows[0].Err != nil || rows[0].Host.RootDeviceHints.MinSizeGigabytes != 100 {
+		t.Errorf("unexpected first row %+v", rows[0])
+	}
+	if rows[1].Err == nil || rows[1].Number != 2 {
+		t.Errorf("expected an unknown field error in row 2, got %+v", rows[1])
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	valid := func(name, address, mac, username string) Row {
+		return Row{Host: Host{
+			Name: name, BMCAddress: address, BootMACAddress: mac, Username: username, Password: "secret",
+		}}
+	}
+	rows := []Row{
+		valid("worker-0", "ipmi://192.168.122.1", "00:11:22:33:44:00", "admin"),
+		valid("worker-1", "redfish://192.168.122.2", "00:11:22:33:44:01", "admin"),
+		valid("worker-2", "redfish://192.168.122.3", "00:11:22:33:44:02", "root"),
+		valid("worker-0", "ipmi://192.168.122.4", "00:11:22:33:44:04", "admin"),
+		valid("worker-4", "ipmi://192.168.122.5", "00:11:22:33:44:01", "admin"),
+		valid("worker-5", "redfish://192.168.122.6", "", "admin"),
+		valid("worker-6", "foo://192.168.122.7", "00:11:22:33:44:06", "admin"),
+		{Host: Host{Name: "worker-7", BMCAddress: "ipmi://192.168.122.8"}},
+		valid("worker_8", "redfish://192.168.122.9", "00:11:22:33:44:08", "admin"),
+	}
+	for i := range rows {
+		rows[i].Number = i + 1
+	}
+
+	manifest, report, err := Generate(rows)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedErrors := map[int]string{
+		4: "name worker-0 is already used by row 1",
+		5: "boot MAC address 00:11:22:33:44:01 is already used by row 2",
+		6: "requires a BootMACAddress value",
+		7: "Unknown BMC type 'foo'",
+		8: "missing username",
+		9: `invalid name "worker_8"`,
+	}
+	if len(report) != len(expectedErrors) {
+		t.Fatalf("unexpected report %v", report)
+	}
+	for _, rowErr := range report {
+		if !strings.Contains(rowErr.Error(), expectedErrors[rowErr.Number]) {
+			t.Errorf("row %d: expected an error containing %q, got %q",
+				rowErr.Number, expectedErrors[rowErr.Number], rowErr.Error())
+		}
+	}
+
+	secrets := map[string]bool{}
+	credentials := map[string]string{}
+	for _, doc := range strings.Split(manifest, "---\n")[1:] {
+		obj := struct {
+			Kind     string
+			Metadata struct{ Name string }
+			Spec     metal3api.BareMetalHostSpec
+		}{}
+		if err = yaml.Unmarshal([]byte(doc), &obj); err != nil {
+			t.Fatalf("invalid document %q: %v", doc, err)
+		}
+		switch obj.Kind {
+		case "Secret":
+			secrets[obj.Metadata.Name] = true
+		case "BareMetalHost":
+			credentials[obj.Metadata.Name] = obj.Spec.BMC.CredentialsName
+		}
+	}
+
+	expectedCredentials := map[string]string{
+		"worker-0": "worker-0-bmc-secret",
+		"worker-1": "worker-0-bmc-secret",
+		"worker-2": "worker-2-bmc-secret",
+	}
+	if len(secrets) != 2 || !secrets["worker-0-bmc-secret"] || !secrets["worker-2-bmc-secret"] {
+		t.Errorf("unexpected secrets %v", secrets)
+	}
+	if len(credentials) != len(expectedCredentials) {
+		t.Fatalf("unexpected hosts %v", credentials)
+	}
+	for name, secret := range expectedCredentials {
+		if credentials[name] != secret {
+			t.Errorf("host %s uses secret %q, expected %q", name, credentials[name], secret)
+		}
+	}
+}
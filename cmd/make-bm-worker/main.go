@@ -1,14 +1,20 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/metal3-io/baremetal-operator/cmd/make-bm-worker/inventory"
+	"github.com/metal3-io/baremetal-operator/cmd/make-bm-worker/probe"
 	"github.com/metal3-io/baremetal-operator/cmd/make-bm-worker/templates"
 )
 
+const probeTimeout = 60 * time.Second
+
 func main() {
 	var username = flag.String("user", "", "username for BMC")
 	var password = flag.String("password", "", "password for BMC")
@@ -18,12 +24,15 @@ func main() {
 	var macAddress = flag.String("boot-mac", "", "boot-mac for bootMACAddress")
 	var bootMode = flag.String("boot-mode", "", "boot-mode for host (UEFI, UEFISecureBoot or legacy)")
 	var verbose = flag.Bool("v", false, "turn on verbose output")
+	var probeBMC = flag.Bool(
+		"probe", false, "probe the Redfish service of the BMC at -address (an IP, host name or http(s) URL) "+
+			"to detect the BMC type, boot MAC and boot mode")
 	var consumer = flag.String(
 		"consumer", "", "specify name of a related, existing, consumer to link")
 	var consumerNamespace = flag.String(
 		"consumer-namespace", "", "specify namespace of a related, existing, consumer to link")
 	var automatedCleaningMode = flag.String(
-		"automatedCleaningMode", "", "automatic cleaning mode for host (metadata or disabled)")
+		"automatedCleaningMode", "", "automatic cleaning mode for host (metadata, disabled, full, secure or crypto)")
 	var imageURL = flag.String("image-url", "", "url for the image")
 	var imageChecksum = flag.String("image-checksum", "", "checksum for the image")
 	var imageChecksumType = flag.String(
@@ -31,8 +40,15 @@ func main() {
 	var imageFormat = flag.String(
 		"image-format", "", "format of the image (raw, qcow2, vdi, vmdk, or live-iso)")
 
+	var inventoryFile = flag.String(
+		"inventory", "", "generate the hosts listed in a CSV, YAML or JSON inventory file instead of a single host")
+
 	flag.Parse()
 
+	if *inventoryFile != "" {
+		os.Exit(generateInventory(*inventoryFile))
+	}
+
 	hostName := flag.Arg(0)
 	if hostName == "" {
 		fmt.Fprintf(os.Stderr, "Missing name argument\n")
@@ -56,8 +72,10 @@ func main() {
 		os.Exit(1)
 	}
 
-	if *automatedCleaningMode != "" && *automatedCleaningMode != "metadata" && *automatedCleaningMode != "disabled" {
-		fmt.Fprintf(os.Stderr, "Invalid automatic cleaning mode %q, use \"metadata\" or \"disabled\"\n", *automatedCleaningMode)
+	switch *automatedCleaningMode {
+	case "", "metadata", "disabled", "full", "secure", "crypto":
+	default:
+		fmt.Fprintf(os.Stderr, "Invalid automatic cleaning mode %q, use \"metadata\", \"disabled\", \"full\", \"secure\" or \"crypto\"\n", *automatedCleaningMode)
 		os.Exit(1)
 	}
 
@@ -94,6 +112,31 @@ func main() {
 	if bootMode != nil {
 		template.BootMode = *bootMode
 	}
+	if *probeBMC {
+		ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+		result, err := probe.Probe(ctx, probe.Options{
+			Address:                        *bmcAddress,
+			Username:                       *username,
+			Password:                       *password,
+			DisableCertificateVerification: *disableCertificateVerification,
+		})
+		cancel()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: failed to probe the BMC: %s\n", err)
+			os.Exit(1)
+		}
+		if *verbose {
+			fmt.Fprintf(os.Stderr, "vendor: %q, manufacturer: %q, model: %q, MAC addresses: %v, boot modes: %v\n",
+				result.Vendor, result.Manufacturer, result.Model, result.MACAddresses, result.BootModes)
+		}
+		template.BMCAddress = result.BMCAddress
+		if template.BootMacAddress == "" {
+			template.BootMacAddress = result.BootMACAddress
+		}
+		if template.BootMode == "" {
+			template.BootMode = result.PreferredBootMode()
+		}
+	}
 	if *verbose {
 		fmt.Fprintf(os.Stderr, "%v", template)
 	}
@@ -106,3 +149,29 @@ func main() {
 
 	_, _ = fmt.Fprint(os.Stdout, result)
 }
+
+// generateInventory prints the manifest of the valid hosts of the
+// inventory, reports the invalid ones and returns the exit code.
+func generateInventory(path string) int {
+	rows, err := inventory.Load(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %s\n", err)
+		return 1
+	}
+
+	result, report, err := inventory.Generate(rows)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %s\n", err)
+		return 1
+	}
+	_, _ = fmt.Fprint(os.Stdout, result)
+
+	for _, rowErr := range report {
+		fmt.Fprintf(os.Stderr, "ERROR: %s\n", rowErr)
+	}
+	if len(report) > 0 {
+		fmt.Fprintf(os.Stderr, "%d of %d hosts were not generated\n", len(report), len(rows))
+		return 1
+	}
+	return 0
+}
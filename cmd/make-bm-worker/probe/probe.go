@@ -0,0 +1,375 @@
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/metal3-io/baremetal-operator/pkg/hardwareutils/bmc"
+)
+
+const serviceRootPath = "/redfish/v1/"
+
+// Boot modes, using the values of the BareMetalHost API.
+const (
+	BootModeUEFI           = "UEFI"
+	BootModeUEFISecureBoot = "UEFISecureBoot"
+	BootModeLegacy         = "legacy"
+)
+
+// Vendors recognized when choosing the BMC access type.
+const (
+	VendorDell    = "Dell"
+	VendorHPE     = "HPE"
+	VendorLenovo  = "Lenovo"
+	VendorUnknown = ""
+)
+
+// vendorSchemes gives the access type that works best for each vendor.
+// Other vendors use the generic Redfish virtual media access type.
+var vendorSchemes = map[string]string{
+	VendorDell:   "idrac-virtualmedia",
+	VendorHPE:    "ilo5-virtualmedia",
+	VendorLenovo: "xcc-virtualmedia",
+}
+
+const defaultScheme = "redfish-virtualmedia"
+
+// iLO4Scheme is used instead of the HPE default for the older iLO 4 BMCs,
+// which lack the Redfish features of the ilo5-virtualmedia access type.
+const iLO4Scheme = "ilo4-virtualmedia"
+
+// Options holds the parameters needed to connect to the BMC.
+type Options struct {
+	// Address is either a host name or IP, optionally with a port, or
+	// an http or https URL. HTTPS is used when no scheme is given.
+	Address                        string
+	Username                       string
+	Password                       string //nolint:gosec
+	DisableCertificateVerification bool
+	// Transport overrides the HTTP transport, mostly for testing.
+	Transport http.RoundTripper
+}
+
+// Result holds what was learnt about the host from its BMC.
+type Result struct {
+	Vendor       string
+	Manufacturer string
+	Model        string
+	// BMCAddress is the address to use in the BareMetalHost, including
+	// the access type matching the vendor.
+	BMCAddress string
+	// MACAddresses lists the MAC addresses of the system NICs in the
+	// order returned by the BMC.
+	MACAddresses []string
+	// BootMACAddress is the MAC address of the first NIC with a link,
+	// or of the first NIC if the link status is not reported.
+	BootMACAddress string
+	// BootModes lists the supported boot modes.
+	BootModes []string
+}
+
+// PreferredBootMode returns UEFI when it is supported, legacy otherwise,
+// or an empty string when the BMC does not report the boot modes.
+func (r *Result) PreferredBootMode() string {
+	for _, mode := range []string{BootModeUEFI, BootModeLegacy} {
+		for _, supported := range r.BootModes {
+			if supported == mode {
+				return mode
+			}
+		}
+	}
+	return ""
+}
+
+type odataID struct {
+	ID string `json:"@odata.id"`
+}
+
+type serviceRoot struct {
+	Vendor  string
+	Product string
+	Oem     map[string]json.RawMessage
+	Systems odataID
+}
+
+type collection struct {
+	Members []odataID
+}
+
+type computerSystem struct {
+	Manufacturer string
+	Model        string
+	Boot         struct {
+		BootSourceOverrideMode          string
+		BootSourceOverrideModeAllowable []string `json:"BootSourceOverrideMode@Redfish.AllowableValues"`
+	}
+	SecureBoot         odataID
+	EthernetInterfaces odataID
+	Links              struct {
+		ManagedBy []odataID
+	}
+}
+
+type manager struct {
+	Model           string
+	FirmwareVersion string
+}
+
+type ethernetInterface struct {
+	MACAddress          string
+	PermanentMACAddress string
+	LinkStatus          string
+}
+
+type prober struct {
+	client   *http.Client
+	endpoint *url.URL
+	opts     Options
+}
+
+// Probe connects to the Redfish service of the BMC and collects the
+// values needed to enroll the host.
+func Probe(ctx context.Context, opts Options) (*Result, error) {
+	endpoint, err := parseAddress(opts.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := opts.Transport
+	if transport == nil {
+		transport = &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: opts.DisableCertificateVerification, // #nosec G402 Requested by the user
+			},
+		}
+	}
+	p := prober{
+		client:   &http.Client{Transport: transport},
+		endpoint: endpoint,
+		opts:     opts,
+	}
+
+	root := serviceRoot{}
+	if err = p.get(ctx, serviceRootPath, &root); err != nil {
+		return nil, fmt.Errorf("failed to read the Redfish service root: %w", err)
+	}
+	if root.Systems.ID == "" {
+		return nil, errors.New("the Redfish service does not expose any system")
+	}
+
+	systems := collection{}
+	if err = p.get(ctx, root.Systems.ID, &systems); err != nil {
+		return nil, fmt.Errorf("failed to list the systems: %w", err)
+	}
+	switch len(systems.Members) {
+	case 0:
+		return nil, errors.New("the Redfish service does not expose any system")
+	case 1:
+	default:
+		return nil, fmt.Errorf("the BMC manages %d systems, give the address of one of them", len(systems.Members))
+	}
+	systemID := systems.Members[0].ID
+
+	system := computerSystem{}
+	if err = p.get(ctx, systemID, &system); err != nil {
+		return nil, fmt.Errorf("failed to read system %s: %w", systemID, err)
+	}
+
+	result := &Result{
+		Vendor:       detectVendor(&root, &system),
+		Manufacturer: system.Manufacturer,
+		Model:        system.Model,
+		BootModes:    bootModes(&system),
+	}
+
+	scheme, ok := vendorSchemes[result.Vendor]
+	if !ok {
+		scheme = defaultScheme
+	}
+	if result.Vendor == VendorHPE && p.isILO4(ctx, &system) {
+		scheme = iLO4Scheme
+	}
+	result.BMCAddress = bmcAddress(scheme, endpoint, systemID)
+	if _, err = bmc.NewAccessDetails(result.BMCAddress, opts.DisableCertificateVerification); err != nil {
+		return nil, fmt.Errorf("failed to build the BMC address: %w", err)
+	}
+
+	if system.EthernetInterfaces.ID != "" {
+		if err = p.readNICs(ctx, system.EthernetInterfaces.ID, result); err != nil {
+			return nil, fmt.Errorf("failed to read the network interfaces: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// parseAddress turns the address given by the user into the URL of the
+// Redfish service.
+func parseAddress(address string) (*url.URL, error) {
+	if address == "" {
+		return nil, errors.New("missing BMC address")
+	}
+	if !strings.Contains(address, "://") {
+		address = "https://" + address
+	}
+	endpoint, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid BMC address: %w", err)
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, fmt.Errorf("invalid BMC address scheme %q, use http or https", endpoint.Scheme)
+	}
+	if endpoint.Hostname() == "" {
+		return nil, fmt.Errorf("missing host in BMC address %q", address)
+	}
+	return &url.URL{Scheme: endpoint.Scheme, Host: endpoint.Host}, nil
+}
+
+func (p *prober) get(ctx context.Context, path string, into interface{}) error {
+	target := p.endpoint.ResolveReference(&url.URL{Path: path})
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(p.opts.Username, p.opts.Password)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned %s", path, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(body, into); err != nil {
+		return fmt.Errorf("invalid response to GET %s: %w", path, err)
+	}
+	return nil
+}
+
+func (p *prober) readNICs(ctx context.Context, path string, result *Result) error {
+	nics := collection{}
+	if err := p.get(ctx, path, &nics); err != nil {
+		return err
+	}
+
+	for _, member := range nics.Members {
+		nic := ethernetInterface{}
+		if err := p.get(ctx, member.ID, &nic); err != nil {
+			return err
+		}
+		mac := nic.PermanentMACAddress
+		if mac == "" {
+			mac = nic.MACAddress
+		}
+		if mac == "" {
+			continue
+		}
+		mac = strings.ToLower(mac)
+		result.MACAddresses = append(result.MACAddresses, mac)
+		if result.BootMACAddress == "" && nic.LinkStatus == "LinkUp" {
+			result.BootMACAddress = mac
+		}
+	}
+
+	if result.BootMACAddress == "" && len(result.MACAddresses) > 0 {
+		result.BootMACAddress = result.MACAddresses[0]
+	}
+	return nil
+}
+
+// isILO4 reads the model and firmware version of the manager of the system
+// to tell iLO 4 from the later generations. A manager that cannot be read
+// is assumed to be a later generation.
+func (p *prober) isILO4(ctx context.Context, system *computerSystem) bool {
+	if len(system.Links.ManagedBy) == 0 {
+		return false
+	}
+	mgr := manager{}
+	if err := p.get(ctx, system.Links.ManagedBy[0].ID, &mgr); err != nil {
+		return false
+	}
+	for _, value := range []string{mgr.Model, mgr.FirmwareVersion} {
+		if strings.HasPrefix(strings.ToLower(strings.ReplaceAll(value, " ", "")), "ilo4") {
+			return true
+		}
+	}
+	return false
+}
+
+// detectVendor looks for the vendor in the service root first, then in
+// its OEM extensions, in the order of their names so that the result is
+// stable, and finally in the manufacturer of the system.
+func detectVendor(root *serviceRoot, system *computerSystem) string {
+	candidates := []string{root.Vendor}
+	candidates = append(candidates, slices.Sorted(maps.Keys(root.Oem))...)
+	candidates = append(candidates, system.Manufacturer)
+
+	for _, candidate := range candidates {
+		candidate = strings.ToLower(candidate)
+		switch {
+		case strings.Contains(candidate, "dell"):
+			return VendorDell
+		case candidate == "hpe", candidate == "hp", strings.Contains(candidate, "hewlett"):
+			return VendorHPE
+		case strings.Contains(candidate, "lenovo"):
+			return VendorLenovo
+		}
+	}
+	return VendorUnknown
+}
+
+func bootModes(system *computerSystem) (modes []string) {
+	allowable := system.Boot.BootSourceOverrideModeAllowable
+	if len(allowable) == 0 && system.Boot.BootSourceOverrideMode != "" {
+		allowable = []string{system.Boot.BootSourceOverrideMode}
+	}
+
+	uefi := false
+	legacy := false
+	for _, mode := range allowable {
+		switch mode {
+		case "UEFI":
+			uefi = true
+		case "Legacy":
+			legacy = true
+		}
+	}
+
+	if uefi {
+		modes = append(modes, BootModeUEFI)
+		if system.SecureBoot.ID != "" {
+			modes = append(modes, BootModeUEFISecureBoot)
+		}
+	}
+	if legacy {
+		modes = append(modes, BootModeLegacy)
+	}
+	return modes
+}
+
+func bmcAddress(scheme string, endpoint *url.URL, systemID string) string {
+	if scheme == iLO4Scheme {
+		// The native iLO driver only needs the address of the BMC
+		return fmt.Sprintf("%s://%s", scheme, endpoint.Host)
+	}
+	if endpoint.Scheme == "http" {
+		scheme += "+http"
+	}
+	return fmt.Sprintf("%s://%s%s", scheme, endpoint.Host, systemID)
+}
@@ -0,0 +1,265 @@
+package probe
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeRedfish serves the given resources, keyed by path, to clients
+// using the admin/password credentials.
+func fakeRedfish(t *testing.T, resources map[string]interface{}) *httptest.Server {
+	t.Helper()
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "admin" || password != "password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		resource, ok := resources[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resource)
+	}))
+}
+
+func link(path string) map[string]string {
+	return map[string]string{"@odata.id": path}
+}
+
+func members(paths ...string) map[string]interface{} {
+	links := []map[string]string{}
+	for _, path := range paths {
+		links = append(links, link(path))
+	}
+	return map[string]interface{}{"Members": links}
+}
+
+func redfishResources(root map[string]interface{}, systemID string, system map[string]interface{}) map[string]interface{} {
+	root["Systems"] = link("/redfish/v1/Systems")
+	system["EthernetInterfaces"] = link(systemID + "/EthernetInterfaces")
+	return map[string]interface{}{
+		"/redfish/v1/":        root,
+		"/redfish/v1/Systems": members(systemID),
+		systemID:              system,
+		systemID + "/EthernetInterfaces": members(
+			systemID+"/EthernetInterfaces/1", systemID+"/EthernetInterfaces/2"),
+		systemID + "/EthernetInterfaces/1": map[string]interface{}{
+			"MACAddress": "0C:C4:7A:00:00:01", "LinkStatus": "LinkDown",
+		},
+		systemID + "/EthernetInterfaces/2": map[string]interface{}{
+			"MACAddress": "0c:c4:7a:00:00:02", "PermanentMACAddress": "0C:C4:7A:00:00:03", "LinkStatus": "LinkUp",
+		},
+	}
+}
+
+func withResource(resources map[string]interface{}, path string, resource interface{}) map[string]interface{} {
+	resources[path] = resource
+	return resources
+}
+
+func TestProbe(t *testing.T) {
+	uefiAndLegacy := map[string]interface{}{
+		"BootSourceOverrideMode":                         "UEFI",
+		"BootSourceOverrideMode@Redfish.AllowableValues": []string{"Legacy", "UEFI"},
+	}
+
+	for _, tc := range []struct {
+		Scenario          string
+		Resources         map[string]interface{}
+		Username          string
+		ExpectedVendor    string
+		ExpectedAddress   string
+		ExpectedBootModes []string
+		ExpectedBootMode  string
+		ExpectedError     string
+	}{
+		{
+			Scenario: "dell from service root vendor",
+			Resources: redfishResources(
+				map[string]interface{}{"Vendor": "Dell"},
+				"/redfish/v1/Systems/System.Embedded.1",
+				map[string]interface{}{
+					"Manufacturer": "Dell Inc.", "Boot": uefiAndLegacy,
+					"SecureBoot": link("/redfish/v1/Systems/System.Embedded.1/SecureBoot"),
+				}),
+			ExpectedVendor:    VendorDell,
+			ExpectedAddress:   "idrac-virtualmedia://%s/redfish/v1/Systems/System.Embedded.1",
+			ExpectedBootModes: []string{BootModeUEFI, BootModeUEFISecureBoot, BootModeLegacy},
+			ExpectedBootMode:  BootModeUEFI,
+		},
+		{
+			Scenario: "hpe from oem extension",
+			Resources: redfishResources(
+				map[string]interface{}{"Oem": map[string]interface{}{"Hpe": map[string]string{}}},
+				"/redfish/v1/Systems/1",
+				map[string]interface{}{"Boot": map[string]interface{}{"BootSourceOverrideMode": "Legacy"}}),
+			ExpectedVendor:    VendorHPE,
+			ExpectedAddress:   "ilo5-virtualmedia://%s/redfish/v1/Systems/1",
+			ExpectedBootModes: []string{BootModeLegacy},
+			ExpectedBootMode:  BootModeLegacy,
+		},
+		{
+			Scenario: "hpe ilo 4 from manager firmware",
+			Resources: withResource(redfishResources(
+				map[string]interface{}{"Oem": map[string]interface{}{"Hp": map[string]string{}}},
+				"/redfish/v1/Systems/1",
+				map[string]interface{}{
+					"Boot":  map[string]interface{}{"BootSourceOverrideMode": "Legacy"},
+					"Links": map[string]interface{}{"ManagedBy": []map[string]string{link("/redfish/v1/Managers/1")}},
+				}),
+				"/redfish/v1/Managers/1", map[string]interface{}{"Model": "iLO 4", "FirmwareVersion": "iLO 4 v2.80"}),
+			ExpectedVendor:    VendorHPE,
+			ExpectedAddress:   "ilo4-virtualmedia://%s",
+			ExpectedBootModes: []string{BootModeLegacy},
+			ExpectedBootMode:  BootModeLegacy,
+		},
+		{
+			Scenario: "hpe ilo 5 from manager firmware",
+			Resources: withResource(redfishResources(
+				map[string]interface{}{"Vendor": "HPE"},
+				"/redfish/v1/Systems/1",
+				map[string]interface{}{
+					"Boot":  uefiAndLegacy,
+					"Links": map[string]interface{}{"ManagedBy": []map[string]string{link("/redfish/v1/Managers/1")}},
+				}),
+				"/redfish/v1/Managers/1", map[string]interface{}{"Model": "iLO 5", "FirmwareVersion": "iLO 5 v2.72"}),
+			ExpectedVendor:    VendorHPE,
+			ExpectedAddress:   "ilo5-virtualmedia://%s/redfish/v1/Systems/1",
+			ExpectedBootModes: []string{BootModeUEFI, BootModeLegacy},
+			ExpectedBootMode:  BootModeUEFI,
+		},
+		{
+			Scenario: "vendor from the first oem extension by name",
+			Resources: redfishResources(
+				map[string]interface{}{"Oem": map[string]interface{}{
+					"Lenovo": map[string]string{}, "Dell": map[string]string{}, "Hpe": map[string]string{},
+				}},
+				"/redfish/v1/Systems/1",
+				map[string]interface{}{"Boot": uefiAndLegacy}),
+			ExpectedVendor:    VendorDell,
+			ExpectedAddress:   "idrac-virtualmedia://%s/redfish/v1/Systems/1",
+			ExpectedBootModes: []string{BootModeUEFI, BootModeLegacy},
+			ExpectedBootMode:  BootModeUEFI,
+		},
+		{
+			Scenario: "lenovo from system manufacturer",
+			Resources: redfishResources(
+				map[string]interface{}{},
+				"/redfish/v1/Systems/1",
+				map[string]interface{}{"Manufacturer": "Lenovo", "Boot": uefiAndLegacy}),
+			ExpectedVendor:    VendorLenovo,
+			ExpectedAddress:   "xcc-virtualmedia://%s/redfish/v1/Systems/1",
+			ExpectedBootModes: []string{BootModeUEFI, BootModeLegacy},
+			ExpectedBootMode:  BootModeUEFI,
+		},
+		{
+			Scenario: "unknown vendor uses generic redfish",
+			Resources: redfishResources(
+				map[string]interface{}{"Vendor": "Supermicro"},
+				"/redfish/v1/Systems/1",
+				map[string]interface{}{"Manufacturer": "Supermicro"}),
+			ExpectedVendor:  VendorUnknown,
+			ExpectedAddress: "redfish-virtualmedia://%s/redfish/v1/Systems/1",
+		},
+		{
+			Scenario: "several systems",
+			Resources: map[string]interface{}{
+				"/redfish/v1/":        map[string]interface{}{"Systems": link("/redfish/v1/Systems")},
+				"/redfish/v1/Systems": members("/redfish/v1/Systems/1", "/redfish/v1/Systems/2"),
+			},
+			ExpectedError: "the BMC manages 2 systems",
+		},
+		{
+			Scenario:      "wrong credentials",
+			Resources:     map[string]interface{}{},
+			Username:      "root",
+			ExpectedError: "401 Unauthorized",
+		},
+	} {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			server := fakeRedfish(t, tc.Resources)
+			defer server.Close()
+
+			username := tc.Username
+			if username == "" {
+				username = "admin"
+			}
+			host := server.Listener.Addr().String()
+			result, err := Probe(context.Background(), Options{
+				Address:                        host,
+				Username:                       username,
+				Password:                       "password",
+				DisableCertificateVerification: true,
+			})
+
+			if tc.ExpectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.ExpectedError) {
+					t.Fatalf("expected error containing %q, got %v", tc.ExpectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Vendor != tc.ExpectedVendor {
+				t.Errorf("unexpected vendor %q, expected %q", result.Vendor, tc.ExpectedVendor)
+			}
+			expectedAddress := strings.Replace(tc.ExpectedAddress, "%s", host, 1)
+			if result.BMCAddress != expectedAddress {
+				t.Errorf("unexpected address %q, expected %q", result.BMCAddress, expectedAddress)
+			}
+			expectedMACs := []string{"0c:c4:7a:00:00:01", "0c:c4:7a:00:00:03"}
+			if !reflect.DeepEqual(result.MACAddresses, expectedMACs) {
+				t.Errorf("unexpected MAC addresses %v, expected %v", result.MACAddresses, expectedMACs)
+			}
+			if result.BootMACAddress != "0c:c4:7a:00:00:03" {
+				t.Errorf("unexpected boot MAC address %q", result.BootMACAddress)
+			}
+			if !reflect.DeepEqual(result.BootModes, tc.ExpectedBootModes) {
+				t.Errorf("unexpected boot modes %v, expected %v", result.BootModes, tc.ExpectedBootModes)
+			}
+			if result.PreferredBootMode() != tc.ExpectedBootMode {
+				t.Errorf("unexpected boot mode %q, expected %q", result.PreferredBootMode(), tc.ExpectedBootMode)
+			}
+		})
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	for _, tc := range []struct {
+		Address     string
+		Expected    string
+		ExpectError bool
+	}{
+		{Address: "192.168.122.1", Expected: "https://192.168.122.1"},
+		{Address: "bmc.example.com:8443", Expected: "https://bmc.example.com:8443"},
+		{Address: "http://192.168.122.1/redfish/v1", Expected: "http://192.168.122.1"},
+		{Address: "[fe80::1]:443", Expected: "https://[fe80::1]:443"},
+		{Address: "ipmi://192.168.122.1", ExpectError: true},
+		{Address: "", ExpectError: true},
+	} {
+		t.Run(tc.Address, func(t *testing.T) {
+			endpoint, err := parseAddress(tc.Address)
+			if tc.ExpectError {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", endpoint)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if endpoint.String() != tc.Expected {
+				t.Fatalf("unexpected endpoint %q, expected %q", endpoint, tc.Expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,436 @@
+/*
+Copyright 2026 The Metal3 Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bmcevents implements an HTTPS receiver for the Redfish events
+// sent by the BMCs to the destinations of BMCEventSubscriptions.
+package bmcevents
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/secretutils"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// PathPrefix is the path of the receiver, followed by the namespace
+	// and the name of the BMCEventSubscription, e.g.
+	// https://<receiver>/bmcevents/<namespace>/<name>.
+	PathPrefix = "/bmcevents/"
+
+	// maxPayloadSize bounds the size of the accepted payloads.
+	maxPayloadSize = 1 << 20
+
+	// statusUpdateAttempts bounds the retries of the host status updates
+	// conflicting with the BareMetalHost controller.
+	statusUpdateAttempts = 3
+
+	readHeaderTimeout = 10 * time.Second
+	shutdownTimeout   = 10 * time.Second
+)
+
+// Redfish event severities.
+const (
+	severityOK       = "OK"
+	severityWarning  = "Warning"
+	severityCritical = "Critical"
+)
+
+// EventReason is the reason of the Kubernetes Events recording the BMC
+// events on the hosts.
+const EventReason = "BMCEvent"
+
+// redfishEvent is the payload of the Redfish Event resource.
+type redfishEvent struct {
+	Context string
+	Events  []redfishEventRecord
+}
+
+type redfishEventRecord struct {
+	EventType         string
+	EventID           string `json:"EventId"`
+	MessageID         string `json:"MessageId"`
+	Message           string
+	Severity          string
+	MessageSeverity   string
+	Context           string
+	OriginOfCondition struct {
+		ID string `json:"@odata.id"`
+	}
+}
+
+// severity returns the severity of the record, MessageSeverity replacing
+// the deprecated Severity in recent Redfish versions.
+func (rec *redfishEventRecord) severity() string {
+	if rec.MessageSeverity != "" {
+		return rec.MessageSeverity
+	}
+	if rec.Severity != "" {
+		return rec.Severity
+	}
+	return severityOK
+}
+
+// changesHost tells whether the record may report a change of the power
+// state or of the health of the host.
+func (rec *redfishEventRecord) changesHost() bool {
+	switch rec.EventType {
+	case "StatusChange", "Alert":
+		return true
+	}
+	if rec.severity() != severityOK {
+		return true
+	}
+	messageID := strings.ToLower(rec.MessageID)
+	for _, keyword := range []string{"power", "statuschange", "health"} {
+		if strings.Contains(messageID, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func (rec *redfishEventRecord) message() string {
+	message := rec.Message
+	if message == "" {
+		message = rec.EventType
+	}
+	if rec.MessageID != "" {
+		message = fmt.Sprintf("%s: %s", rec.MessageID, message)
+	}
+	if rec.OriginOfCondition.ID != "" {
+		message = fmt.Sprintf("%s (%s)", message, rec.OriginOfCondition.ID)
+	}
+	return message
+}
+
+// Receiver is an HTTPS server accepting the Redfish events of the
+// BMCEventSubscriptions whose destination points at it. The events are
+// recorded on the host of the subscription, and the hosts whose power state
+// or health may have changed get their BMCEventAnnotation updated to be
+// reconciled.
+type Receiver struct {
+	Client    client.Client
+	APIReader client.Reader
+	Log       logr.Logger
+	Recorder  record.EventRecorder
+
+	// BindAddress is the address the receiver listens on.
+	BindAddress string
+
+	// CertDir is the directory holding the tls.crt and tls.key files of
+	// the serving certificate.
+	CertDir string
+
+	// TLSOpts customize the TLS configuration of the server.
+	TLSOpts []func(*tls.Config)
+}
+
+// NeedLeaderElection runs the receiver on all the replicas, as the BMCs
+// reach any of them through the service. The hosts are reconciled by the
+// leader through the change of their BMCEventAnnotation.
+func (r *Receiver) NeedLeaderElection() bool {
+	return false
+}
+
+// Start serves the events until the context is cancelled.
+func (r *Receiver) Start(ctx context.Context) error {
+	watcher, err := certwatcher.New(filepath.Join(r.CertDir, "tls.crt"), filepath.Join(r.CertDir, "tls.key"))
+	if err != nil {
+		return fmt.Errorf("failed to load the BMC event receiver certificate: %w", err)
+	}
+	go func() {
+		if err := watcher.Start(ctx); err != nil {
+			r.Log.Error(err, "failed to watch the BMC event receiver certificate")
+		}
+	}()
+
+	tlsConfig := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: watcher.GetCertificate,
+	}
+	for _, opt := range r.TLSOpts {
+		opt(tlsConfig)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(PathPrefix, r)
+	server := &http.Server{
+		Addr:              r.BindAddress,
+		Handler:           mux,
+		TLSConfig:         tlsConfig,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil { //nolint:contextcheck
+			r.Log.Error(err, "failed to stop the BMC event receiver")
+		}
+	}()
+
+	r.Log.Info("starting the BMC event receiver", "address", r.BindAddress)
+	if err := server.ListenAndServeTLS("", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("the BMC event receiver failed: %w", err)
+	}
+	return nil
+}
+
+// receiverError is an error answered to the BMC with an HTTP status.
+type receiverError struct {
+	status  int
+	message string
+}
+
+func (e *receiverError) Error() string {
+	return e.message
+}
+
+func newReceiverError(status int, format string, args ...any) *receiverError {
+	return &receiverError{status: status, message: fmt.Sprintf(format, args...)}
+}
+
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	err := r.handleEvent(req)
+	if err == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	var recvErr *receiverError
+	if !errors.As(err, &recvErr) {
+		r.Log.Error(err, "failed to handle a BMC event", "path", req.URL.Path)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	r.Log.Info("rejected a BMC event", "path", req.URL.Path, "status", recvErr.status, "reason", recvErr.message)
+	http.Error(w, recvErr.message, recvErr.status)
+}
+
+func (r *Receiver) handleEvent(req *http.Request) error {
+	ctx := req.Context()
+
+	key, ok := subscriptionKey(req.URL.Path)
+	if !ok {
+		return newReceiverError(http.StatusNotFound, "the path must be %s<namespace>/<name>", PathPrefix)
+	}
+
+	payload := redfishEvent{}
+	if err := json.NewDecoder(http.MaxBytesReader(nil, req.Body, maxPayloadSize)).Decode(&payload); err != nil {
+		return newReceiverError(http.StatusBadRequest, "invalid Redfish event: %s", err)
+	}
+
+	subscription := &metal3api.BMCEventSubscription{}
+	if err := r.Client.Get(ctx, key, subscription); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return newReceiverError(http.StatusNotFound, "BMCEventSubscription %s not found", key)
+		}
+		return fmt.Errorf("could not load BMCEventSubscription %s: %w", key, err)
+	}
+
+	if err := r.authenticate(ctx, req, subscription); err != nil {
+		return err
+	}
+	if err := checkContext(&payload, subscription); err != nil {
+		return err
+	}
+
+	host := &metal3api.BareMetalHost{}
+	hostKey := types.NamespacedName{Namespace: subscription.Namespace, Name: subscription.Spec.HostName}
+	if err := r.Client.Get(ctx, hostKey, host); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return newReceiverError(http.StatusNotFound, "BareMetalHost %s not found", hostKey)
+		}
+		return fmt.Errorf("could not load BareMetalHost %s: %w", hostKey, err)
+	}
+
+	return r.recordEvents(ctx, host, payload.Events)
+}
+
+// subscriptionKey returns the subscription of the path of the request.
+func subscriptionKey(path string) (types.NamespacedName, bool) {
+	parts := strings.Split(strings.TrimPrefix(path, PathPrefix), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: parts[0], Name: parts[1]}, true
+}
+
+// authenticate checks that the request carries the HTTP headers of the
+// secret of the subscription, which the BMC sends with each event. The
+// subscriptions without headers are rejected, their context not being a
+// secret.
+func (r *Receiver) authenticate(ctx context.Context, req *http.Request, subscription *metal3api.BMCEventSubscription) error {
+	ref := subscription.Spec.HTTPHeadersRef
+	if ref == nil {
+		return newReceiverError(http.StatusForbidden, "BMCEventSubscription %s has no httpHeadersRef to authenticate its events", client.ObjectKeyFromObject(subscription))
+	}
+	if ref.Namespace != subscription.Namespace {
+		return newReceiverError(http.StatusForbidden, "the httpHeadersRef secret of BMCEventSubscription %s must be in its namespace", client.ObjectKeyFromObject(subscription))
+	}
+
+	secretManager := secretutils.NewSecretManager(r.Log, r.Client, r.APIReader)
+	secret, err := secretManager.ObtainSecret(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return newReceiverError(http.StatusForbidden, "the httpHeadersRef secret of BMCEventSubscription %s was not found", client.ObjectKeyFromObject(subscription))
+		}
+		return fmt.Errorf("could not load the httpHeadersRef secret: %w", err)
+	}
+	if len(secret.Data) == 0 {
+		return newReceiverError(http.StatusForbidden, "the httpHeadersRef secret of BMCEventSubscription %s is empty", client.ObjectKeyFromObject(subscription))
+	}
+
+	for name, value := range secret.Data {
+		if subtle.ConstantTimeCompare([]byte(req.Header.Get(name)), value) != 1 {
+			return newReceiverError(http.StatusUnauthorized, "missing or invalid %s header", name)
+		}
+	}
+	return nil
+}
+
+// checkContext checks that the context of the event is the one of the
+// subscription, older Redfish services setting it on each record.
+func checkContext(payload *redfishEvent, subscription *metal3api.BMCEventSubscription) error {
+	if subscription.Spec.Context == "" {
+		return nil
+	}
+	if payload.Context != "" {
+		if payload.Context != subscription.Spec.Context {
+			return newReceiverError(http.StatusForbidden, "the event context does not match BMCEventSubscription %s", client.ObjectKeyFromObject(subscription))
+		}
+		return nil
+	}
+	if len(payload.Events) == 0 {
+		return newReceiverError(http.StatusForbidden, "the event has no context")
+	}
+	for _, rec := range payload.Events {
+		if rec.Context != subscription.Spec.Context {
+			return newReceiverError(http.StatusForbidden, "the event context does not match BMCEventSubscription %s", client.ObjectKeyFromObject(subscription))
+		}
+	}
+	return nil
+}
+
+// recordEvents records the events on the host as Kubernetes Events and in
+// its BMCEvent condition, then asks for the host to be reconciled if its
+// power state or health may have changed.
+func (r *Receiver) recordEvents(ctx context.Context, host *metal3api.BareMetalHost, records []redfishEventRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	reconcile := false
+	for i := range records {
+		rec := &records[i]
+		eventType := corev1.EventTypeNormal
+		if rec.severity() != severityOK {
+			eventType = corev1.EventTypeWarning
+		}
+		r.Recorder.Event(host, eventType, EventReason, rec.message())
+		reconcile = reconcile || rec.changesHost()
+	}
+
+	if err := r.setCondition(ctx, host, &records[len(records)-1]); err != nil {
+		return err
+	}
+
+	if reconcile {
+		return r.requestReconcile(ctx, host)
+	}
+	return nil
+}
+
+// requestReconcile sets the BMCEventAnnotation of the host to the current
+// time. The host controller ignores the status updates such as the one of
+// the BMCEvent condition, but reconciles the hosts whose annotations
+// change, on the leader whichever replica received the event.
+func (r *Receiver) requestReconcile(ctx context.Context, host *metal3api.BareMetalHost) error {
+	patch := client.MergeFrom(host.DeepCopy())
+	if host.Annotations == nil {
+		host.Annotations = make(map[string]string)
+	}
+	host.Annotations[metal3api.BMCEventAnnotation] = time.Now().UTC().Format(time.RFC3339Nano)
+	if err := r.Client.Patch(ctx, host, patch); err != nil {
+		return fmt.Errorf("failed to annotate BareMetalHost %s: %w", client.ObjectKeyFromObject(host), err)
+	}
+	return nil
+}
+
+// setCondition sets the BMCEvent condition of the host from the last event
+// received.
+func (r *Receiver) setCondition(ctx context.Context, host *metal3api.BareMetalHost, rec *redfishEventRecord) error {
+	cond := metav1.Condition{
+		Type:    metal3api.BMCEventCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  metal3api.HealthyReason,
+		Message: rec.message(),
+	}
+	switch rec.severity() {
+	case severityOK:
+	case severityCritical:
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = metal3api.CriticalHealthReason
+	default:
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = metal3api.WarningHealthReason
+	}
+
+	var err error
+	for range statusUpdateAttempts {
+		cond.ObservedGeneration = host.Generation
+		if !meta.SetStatusCondition(&host.Status.Conditions, cond) {
+			return nil
+		}
+		err = r.Client.Status().Update(ctx, host)
+		if !k8serrors.IsConflict(err) {
+			break
+		}
+		if err = r.Client.Get(ctx, client.ObjectKeyFromObject(host), host); err != nil {
+			break
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update the %s condition of BareMetalHost %s: %w", metal3api.BMCEventCondition, client.ObjectKeyFromObject(host), err)
+	}
+	return nil
+}
@@ -0,0 +1,302 @@
+/*
+Copyright 2026 The Metal3 Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bmcevents
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testNamespace = "test-ns"
+	testToken     = "Bearer s3cr3t"
+)
+
+const criticalEvent = `{
+	"@odata.type": "#Event.v1_7_0.Event",
+	"Context": "host-0-events",
+	"Events": [{
+		"EventId": "1",
+		"MessageId": "ResourceEvent.1.0.ResourceStatusChangedCritical",
+		"Message": "The health of PowerSupply 1 changed to Critical",
+		"MessageSeverity": "Critical",
+		"OriginOfCondition": {"@odata.id": "/redfish/v1/Chassis/1/Power"}
+	}]
+}`
+
+const legacyEvent = `{
+	"Events": [{
+		"EventType": "ResourceAdded",
+		"EventId": "2",
+		"MessageId": "ResourceEvent.1.0.ResourceCreated",
+		"Message": "A virtual disk was created",
+		"Severity": "OK",
+		"Context": "host-0-events"
+	}]
+}`
+
+func newTestReceiver(t *testing.T, subscription *metal3api.BMCEventSubscription) (*Receiver, client.Client, *record.FakeRecorder) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, metal3api.AddToScheme(scheme))
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			subscription,
+			&metal3api.BareMetalHost{
+				ObjectMeta: metav1.ObjectMeta{Name: "host-0", Namespace: testNamespace},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "event-headers", Namespace: testNamespace},
+				Data:       map[string][]byte{"Authorization": []byte(testToken)},
+			},
+		).
+		WithStatusSubresource(&metal3api.BareMetalHost{}).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	return &Receiver{
+		Client:    c,
+		APIReader: c,
+		Log:       logr.Discard(),
+		Recorder:  recorder,
+	}, c, recorder
+}
+
+func testSubscription() *metal3api.BMCEventSubscription {
+	return &metal3api.BMCEventSubscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "host-0-events", Namespace: testNamespace},
+		Spec: metal3api.BMCEventSubscriptionSpec{
+			HostName:       "host-0",
+			Destination:    "https://metal3.example.com/bmcevents/test-ns/host-0-events",
+			Context:        "host-0-events",
+			HTTPHeadersRef: &corev1.SecretReference{Name: "event-headers", Namespace: testNamespace},
+		},
+	}
+}
+
+func TestReceiverRejections(t *testing.T) {
+	testCases := []struct {
+		Scenario       string
+		Method         string
+		Path           string
+		Token          string
+		Body           string
+		Subscription   func(*metal3api.BMCEventSubscription)
+		ExpectedStatus int
+		ExpectedError  string
+	}{
+		{
+			Scenario:       "wrong method",
+			Method:         http.MethodGet,
+			ExpectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			Scenario:       "no subscription in path",
+			Path:           PathPrefix + testNamespace,
+			ExpectedStatus: http.StatusNotFound,
+			ExpectedError:  "the path must be /bmcevents/<namespace>/<name>",
+		},
+		{
+			Scenario:       "invalid payload",
+			Body:           "not json",
+			ExpectedStatus: http.StatusBadRequest,
+			ExpectedError:  "invalid Redfish event",
+		},
+		{
+			Scenario:       "unknown subscription",
+			Path:           PathPrefix + testNamespace + "/other",
+			ExpectedStatus: http.StatusNotFound,
+			ExpectedError:  "BMCEventSubscription test-ns/other not found",
+		},
+		{
+			Scenario: "no headers secret",
+			Subscription: func(subscription *metal3api.BMCEventSubscription) {
+				subscription.Spec.HTTPHeadersRef = nil
+			},
+			ExpectedStatus: http.StatusForbidden,
+			ExpectedError:  "BMCEventSubscription test-ns/host-0-events has no httpHeadersRef to authenticate its events",
+		},
+		{
+			Scenario: "missing headers secret",
+			Subscription: func(subscription *metal3api.BMCEventSubscription) {
+				subscription.Spec.HTTPHeadersRef.Name = "missing"
+			},
+			ExpectedStatus: http.StatusForbidden,
+			ExpectedError:  "the httpHeadersRef secret of BMCEventSubscription test-ns/host-0-events was not found",
+		},
+		{
+			Scenario:       "missing token",
+			Token:          "none",
+			ExpectedStatus: http.StatusUnauthorized,
+			ExpectedError:  "missing or invalid Authorization header",
+		},
+		{
+			Scenario:       "wrong token",
+			Token:          "Bearer wrong",
+			ExpectedStatus: http.StatusUnauthorized,
+			ExpectedError:  "missing or invalid Authorization header",
+		},
+		{
+			Scenario: "wrong context",
+			Subscription: func(subscription *metal3api.BMCEventSubscription) {
+				subscription.Spec.Context = "other"
+			},
+			ExpectedStatus: http.StatusForbidden,
+			ExpectedError:  "the event context does not match BMCEventSubscription test-ns/host-0-events",
+		},
+		{
+			Scenario:       "wrong context of a legacy event",
+			Body:           strings.Replace(legacyEvent, "host-0-events", "other", 1),
+			ExpectedStatus: http.StatusForbidden,
+			ExpectedError:  "the event context does not match BMCEventSubscription test-ns/host-0-events",
+		},
+		{
+			Scenario: "unknown host",
+			Subscription: func(subscription *metal3api.BMCEventSubscription) {
+				subscription.Spec.HostName = "host-1"
+			},
+			ExpectedStatus: http.StatusNotFound,
+			ExpectedError:  "BareMetalHost test-ns/host-1 not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			subscription := testSubscription()
+			if tc.Subscription != nil {
+				tc.Subscription(subscription)
+			}
+			receiver, c, recorder := newTestReceiver(t, subscription)
+
+			method := tc.Method
+			if method == "" {
+				method = http.MethodPost
+			}
+			path := tc.Path
+			if path == "" {
+				path = PathPrefix + testNamespace + "/host-0-events"
+			}
+			body := tc.Body
+			if body == "" {
+				body = criticalEvent
+			}
+			req := httptest.NewRequest(method, path, strings.NewReader(body))
+			switch tc.Token {
+			case "":
+				req.Header.Set("Authorization", testToken)
+			case "none":
+			default:
+				req.Header.Set("Authorization", tc.Token)
+			}
+			resp := httptest.NewRecorder()
+
+			receiver.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.ExpectedStatus, resp.Code)
+			assert.Contains(t, resp.Body.String(), tc.ExpectedError)
+			assert.Empty(t, recorder.Events)
+
+			host := &metal3api.BareMetalHost{}
+			require.NoError(t, c.Get(t.Context(), client.ObjectKey{Namespace: testNamespace, Name: "host-0"}, host))
+			assert.NotContains(t, host.Annotations, metal3api.BMCEventAnnotation)
+		})
+	}
+}
+
+func TestReceiverRecordsEvents(t *testing.T) {
+	testCases := []struct {
+		Scenario          string
+		Body              string
+		ExpectedEvent     string
+		ExpectedStatus    metav1.ConditionStatus
+		ExpectedReason    string
+		ExpectedReconcile bool
+	}{
+		{
+			Scenario:          "critical health change",
+			Body:              criticalEvent,
+			ExpectedEvent:     "Warning BMCEvent ResourceEvent.1.0.ResourceStatusChangedCritical: The health of PowerSupply 1 changed to Critical (/redfish/v1/Chassis/1/Power)",
+			ExpectedStatus:    metav1.ConditionFalse,
+			ExpectedReason:    metal3api.CriticalHealthReason,
+			ExpectedReconcile: true,
+		},
+		{
+			Scenario:       "legacy event",
+			Body:           legacyEvent,
+			ExpectedEvent:  "Normal BMCEvent ResourceEvent.1.0.ResourceCreated: A virtual disk was created",
+			ExpectedStatus: metav1.ConditionTrue,
+			ExpectedReason: metal3api.HealthyReason,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			receiver, c, recorder := newTestReceiver(t, testSubscription())
+
+			req := httptest.NewRequest(http.MethodPost, PathPrefix+testNamespace+"/host-0-events", strings.NewReader(tc.Body))
+			req.Header.Set("Authorization", testToken)
+			resp := httptest.NewRecorder()
+
+			receiver.ServeHTTP(resp, req)
+
+			require.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+			require.Len(t, recorder.Events, 1)
+			assert.Equal(t, tc.ExpectedEvent, <-recorder.Events)
+
+			host := &metal3api.BareMetalHost{}
+			require.NoError(t, c.Get(t.Context(), client.ObjectKey{Namespace: testNamespace, Name: "host-0"}, host))
+			cond := meta.FindStatusCondition(host.Status.Conditions, metal3api.BMCEventCondition)
+			require.NotNil(t, cond)
+			assert.Equal(t, tc.ExpectedStatus, cond.Status)
+			assert.Equal(t, tc.ExpectedReason, cond.Reason)
+
+			if tc.ExpectedReconcile {
+				assert.Contains(t, host.Annotations, metal3api.BMCEventAnnotation)
+			} else {
+				assert.NotContains(t, host.Annotations, metal3api.BMCEventAnnotation)
+			}
+		})
+	}
+}
+
+func TestSubscriptionKey(t *testing.T) {
+	key, ok := subscriptionKey("/bmcevents/test-ns/host-0-events")
+	assert.True(t, ok)
+	assert.Equal(t, client.ObjectKey{Namespace: "test-ns", Name: "host-0-events"}, key)
+
+	for _, path := range []string{"/bmcevents/", "/bmcevents/test-ns", "/bmcevents/test-ns/", "/bmcevents/a/b/c"} {
+		_, ok = subscriptionKey(path)
+		assert.False(t, ok, path)
+	}
+}
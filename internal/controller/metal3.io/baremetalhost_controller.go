@@ -48,6 +48,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
 
@@ -69,6 +70,9 @@ type BareMetalHostReconciler struct {
 	ProvisionerFactory provisioner.Factory
 	APIReader          client.Reader
 	Recorder           record.EventRecorder
+	// ProvisioningGroupLimits limit the hosts (de)provisioned at the same
+	// time in each bucket, on top of the limit of the provisioner.
+	ProvisioningGroupLimits []ProvisioningGroupLimit
 }
 
 // Instead of passing a zillion arguments to the action of a phase,
@@ -81,6 +85,9 @@ type reconcileInfo struct {
 	preprovisioningNetworkDataSecret *corev1.Secret
 	events                           []corev1.Event
 	postSaveCallbacks                []func()
+	// profileLookup finds the hardware profiles, the built-in profiles
+	// are used when it is not set.
+	profileLookup func(name string) (profile.Profile, error)
 }
 
 // match the provisioner.EventPublisher interface.
@@ -88,6 +95,14 @@ func (info *reconcileInfo) publishEvent(reason, message string) {
 	info.events = append(info.events, info.host.NewEvent(reason, message))
 }
 
+// hardwareProfile returns the named hardware profile.
+func (info *reconcileInfo) hardwareProfile(name string) (profile.Profile, error) {
+	if info.profileLookup != nil {
+		return info.profileLookup(name)
+	}
+	return builtinProfile(name)
+}
+
 // +kubebuilder:rbac:groups=metal3.io,resources=baremetalhosts,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=metal3.io,resources=baremetalhosts/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=metal3.io,resources=baremetalhosts/finalizers,verbs=update
@@ -110,6 +125,9 @@ func (info *reconcileInfo) publishEvent(reason, message string) {
 // Allow for updating hostupdatepolicies
 // +kubebuilder:rbac:groups=metal3.io,resources=hostupdatepolicies,verbs=get;list;watch;update;patch
 
+// Allow for resolving the switches of the host network
+// +kubebuilder:rbac:groups=metal3.io,resources=baremetalswitches,verbs=get;list;watch
+
 // Allow reading Ironic resources
 // +kubebuilder:rbac:groups=ironic.metal3.io,resources=ironics,verbs=get;list;watch
 
@@ -232,6 +250,9 @@ func (r *BareMetalHostReconciler) Reconcile(ctx context.Context, request ctrl.Re
 		request:                          request,
 		bmcCredsSecret:                   bmcCredsSecret,
 		preprovisioningNetworkDataSecret: preprovisioningNetworkDataSecret,
+		profileLookup: func(name string) (profile.Profile, error) {
+			return r.getHardwareProfile(ctx, name)
+		},
 	}
 
 	prov, err := r.ProvisionerFactory.NewProvisioner(ctx, provisioner.BuildHostData(*host, *bmcCreds), info.publishEvent)
@@ -267,6 +288,11 @@ func (r *BareMetalHostReconciler) Reconcile(ctx context.Context, request ctrl.Re
 	conditionsBefore := slices.Clone(host.GetConditions())
 	computeConditions(ctx, host, prov)
 	conditionsChanged := !reflect.DeepEqual(conditionsBefore, host.GetConditions())
+	cablingChanged, err := r.updateCabledCondition(ctx, host)
+	if err != nil {
+		info.log.Error(err, "failed to check the cabling of the host")
+	}
+	conditionsChanged = conditionsChanged || cablingChanged
 
 	// Only save status when we're told to, otherwise we
 	// introduce an infinite loop reconciling the same object over and
@@ -380,13 +406,14 @@ func recordActionFailure(info *reconcileInfo, errorType metal3api.ErrorType, err
 	return actionFailed{dirty: true, ErrorType: errorType, errorCount: info.host.Status.ErrorCount}
 }
 
-func recordActionDelayed(info *reconcileInfo, state metal3api.ProvisioningState) actionResult {
+func recordActionDelayed(info *reconcileInfo, state metal3api.ProvisioningState, bucket, bucketValue string) actionResult {
 	var counter prometheus.Counter
 
+	labels := delayedMetricLabels(info.request, bucket, bucketValue)
 	if state == metal3api.StateDeprovisioning {
-		counter = delayedDeprovisioningHostCounters.With(hostMetricLabels(info.request))
+		counter = delayedDeprovisioningHostCounters.With(labels)
 	} else {
-		counter = delayedProvisioningHostCounters.With(hostMetricLabels(info.request))
+		counter = delayedProvisioningHostCounters.With(labels)
 	}
 
 	info.postSaveCallbacks = append(info.postSaveCallbacks, counter.Inc)
@@ -866,6 +893,11 @@ func (r *BareMetalHostReconciler) registerHost(ctx context.Context, prov provisi
 		return actionError{err}
 	}
 
+	cleaningMode, err := r.automatedCleaningMode(ctx, info.host)
+	if err != nil {
+		return actionError{err}
+	}
+
 	switch info.host.Status.Provisioning.State {
 	case metal3api.StateRegistering, metal3api.StateDeleting, metal3api.StatePoweringOffBeforeDelete:
 		// No need to create PreprovisioningImage if host is not yet registered
@@ -877,7 +909,7 @@ func (r *BareMetalHostReconciler) registerHost(ctx context.Context, prov provisi
 		}
 	case metal3api.StateDeprovisioning:
 		// PreprovisioningImage is not required for deprovisioning when cleaning is disabled
-		if info.host.Spec.AutomatedCleaningMode == metal3api.CleaningModeDisabled {
+		if cleaningMode == metal3api.CleaningModeDisabled {
 			preprovImgFormats = nil
 		}
 	default:
@@ -907,7 +939,7 @@ func (r *BareMetalHostReconciler) registerHost(ctx context.Context, prov provisi
 		ctx,
 		provisioner.ManagementAccessData{
 			BootMode:                   info.host.Status.Provisioning.BootMode,
-			AutomatedCleaningMode:      info.host.Spec.AutomatedCleaningMode,
+			AutomatedCleaningMode:      cleaningMode,
 			State:                      info.host.Status.Provisioning.State,
 			OperationalStatus:          info.host.Status.OperationalStatus,
 			CurrentImage:               getCurrentImage(info.host),
@@ -1025,7 +1057,7 @@ func updateRootDeviceHints(host *metal3api.BareMetalHost, info *reconcileInfo) (
 	// precedence. Otherwise use the values from the hardware profile.
 	hintSource := host.Spec.RootDeviceHints
 	if hintSource == nil {
-		hwProf, err := profile.GetProfile(host.HardwareProfile())
+		hwProf, err := hostProfile(host, info)
 		if err != nil {
 			return false, fmt.Errorf("failed to update root device hints: %w", err)
 		}
@@ -1104,6 +1136,10 @@ func (r *BareMetalHostReconciler) actionInspecting(ctx context.Context, prov pro
 	clearError(info.host)
 	info.host.Status.HardwareDetails = details
 
+	if _, err = r.matchInspectedProfile(ctx, info, details); err != nil {
+		return actionError{err}
+	}
+
 	// Create HardwareData with the same name and namesapce as BareMetalHost
 	hardwareData := &metal3api.HardwareData{}
 	hardwareDataKey := client.ObjectKey{
@@ -1169,20 +1205,14 @@ func getHardwareProfileName(host *metal3api.BareMetalHost) string {
 		// Profile name supplied by user
 		return host.Spec.HardwareProfile
 	}
-
-	// FIXME(dhellmann): Insert more robust logic to match
-	// hardware profiles here.
-	if strings.HasPrefix(host.Spec.BMC.Address, "libvirt") {
-		return "libvirt"
-	}
-	return profile.DefaultProfileName
+	return defaultHardwareProfileName(host)
 }
 
 func (r *BareMetalHostReconciler) matchProfile(info *reconcileInfo) (dirty bool, err error) {
 	hardwareProfile := getHardwareProfileName(info.host)
 	info.log.V(1).Info("using hardware profile", "profile", hardwareProfile)
 
-	_, err = profile.GetProfile(hardwareProfile)
+	_, err = info.hardwareProfile(hardwareProfile)
 	if err != nil {
 		info.log.Info("invalid hardware profile", "profile", hardwareProfile)
 		return
@@ -1339,7 +1369,7 @@ func (r *BareMetalHostReconciler) actionProvisioning(ctx context.Context, prov p
 	}
 	info.log.Info("provisioning")
 
-	hwProf, err := profile.GetProfile(info.host.HardwareProfile())
+	hwProf, err := hostProfile(info.host, info)
 	if err != nil {
 		return actionError{fmt.Errorf(" could not start provisioning with bad hardware profile %s: %w",
 			info.host.HardwareProfile(), err)}
@@ -1358,6 +1388,18 @@ func (r *BareMetalHostReconciler) actionProvisioning(ctx context.Context, prov p
 		return recordActionFailure(info, metal3api.ProvisioningError, err.Error())
 	}
 
+	var network *provisioner.NetworkData
+	if info.host.Spec.Network != nil {
+		switches := &metal3api.BareMetalSwitchList{}
+		if err = r.List(ctx, switches, client.InNamespace(info.host.Namespace)); err != nil {
+			return actionError{fmt.Errorf("failed to list switches: %w", err)}
+		}
+		network, err = resolveHostNetwork(info.host.Spec.Network, info.host.Status.HardwareDetails, switches.Items)
+		if err != nil {
+			return recordActionFailure(info, metal3api.ProvisioningError, err.Error())
+		}
+	}
+
 	provResult, err := prov.Provision(ctx, provisioner.ProvisionData{
 		Image:           image,
 		CustomDeploy:    info.host.Spec.CustomDeploy.DeepCopy(),
@@ -1366,6 +1408,7 @@ func (r *BareMetalHostReconciler) actionProvisioning(ctx context.Context, prov p
 		HardwareProfile: hwProf,
 		RootDeviceHints: info.host.Status.Provisioning.RootDeviceHints.DeepCopy(),
 		ImagePullSecret: authSecret,
+		Network:         network,
 	}, forceReboot)
 	if err != nil {
 		return actionError{fmt.Errorf("failed to provision: %w", err)}
@@ -1447,10 +1490,15 @@ func (r *BareMetalHostReconciler) actionDeprovisioning(ctx context.Context, prov
 
 	info.log.Info("deprovisioning")
 
+	cleaningMode, err := r.automatedCleaningMode(ctx, info.host)
+	if err != nil {
+		return actionError{err}
+	}
+
 	provResult, err := prov.Deprovision(
 		ctx,
 		info.host.Status.ErrorType == metal3api.ProvisioningError,
-		info.host.Spec.AutomatedCleaningMode)
+		cleaningMode)
 	if err != nil {
 		return actionError{fmt.Errorf("failed to deprovision: %w", err)}
 	}
@@ -1474,6 +1522,14 @@ func (r *BareMetalHostReconciler) actionDeprovisioning(ctx context.Context, prov
 		return actionContinue{}
 	}
 
+	if cleaningMode.ErasesDisks() {
+		erasure, err := prov.GetDiskErasure(ctx)
+		if err != nil {
+			return actionError{fmt.Errorf("failed to get the disk erasure: %w", err)}
+		}
+		recordDiskErasure(info.host, erasure)
+	}
+
 	// After the provisioner is done, clear the provisioning settings
 	// so we transition to the next state.
 	info.host.Status.Provisioning.Image = metal3api.Image{}
@@ -1500,17 +1556,23 @@ func (r *BareMetalHostReconciler) doServiceIfNeeded(ctx context.Context, prov pr
 		liveFirmwareUpdatesAllowed = (hup.Spec.FirmwareUpdates == metal3api.HostUpdatePolicyOnReboot)
 	}
 
+	var firmware *metal3api.FirmwareConfig
 	if liveFirmwareSettingsAllowed {
+		var err error
+		firmware, err = targetFirmware(info.host, info)
+		if err != nil {
+			return actionError{fmt.Errorf("could not determine the firmware settings: %w", err)}
+		}
+
 		// handling pre-HFS FirmwareSettings here
-		if !reflect.DeepEqual(info.host.Status.Provisioning.Firmware, info.host.Spec.Firmware) {
-			servicingData.FirmwareConfig = info.host.Spec.Firmware
+		if !reflect.DeepEqual(info.host.Status.Provisioning.Firmware, firmware) {
+			servicingData.FirmwareConfig = firmware
 			fwDirty = true
 		}
-		servicingData.HasFirmwareSpec = fwDirty && info.host.Spec.Firmware != nil
+		servicingData.HasFirmwareSpec = fwDirty && firmware != nil
 
 		// handling HFS based FirmwareSettings here
 		var hfs *metal3api.HostFirmwareSettings
-		var err error
 		hfsDirty, hfs, err = r.getHostFirmwareSettings(ctx, info)
 		if err != nil {
 			return actionError{fmt.Errorf("could not determine updated settings: %w", err)}
@@ -1546,9 +1608,39 @@ func (r *BareMetalHostReconciler) doServiceIfNeeded(ctx context.Context, prov pr
 	// Even if settings are clean, we need to check the result of the current servicing.
 	if !hasChanges && info.host.Status.OperationalStatus != metal3api.OperationalStatusServicing && info.host.Status.ErrorType != metal3api.ServicingError {
 		// If nothing is going on, return control to the power management.
+		if err := r.releaseServicingSlot(ctx, info); err != nil {
+			return actionError{err}
+		}
+		if setServicingScheduled(info.host, "", "") {
+			return actionUpdate{}
+		}
 		return nil
 	}
 
+	// Servicing that has not started yet waits for the maintenance windows
+	// and the concurrency limit of the policy, the host powers on meanwhile
+	// and is rebooted once servicing may start.
+	if info.host.Status.OperationalStatus != metal3api.OperationalStatusServicing && info.host.Status.ErrorType != metal3api.ServicingError {
+		reason, message, err := r.servicingHold(ctx, info, hup, time.Now())
+		if err != nil {
+			return actionError{fmt.Errorf("could not check the servicing schedule: %w", err)}
+		}
+		if reason == "" && info.host.Status.PoweredOn {
+			return r.rebootForServicing(ctx, info)
+		}
+		if setServicingScheduled(info.host, reason, message) {
+			if reason != "" {
+				info.log.Info("servicing held", "reason", reason, "message", message)
+				info.publishEvent("ServicingScheduled", message)
+			}
+			return actionUpdate{}
+		}
+		if reason != "" {
+			// The power management requeues the held host on a delay.
+			return nil
+		}
+	}
+
 	// FIXME(janders/dtantsur): this implementation may lead to a scenario where if we never actually
 	// succeed before leaving this state (e.g. by deprovisioning) we lose the signal that the
 	// update didn't actually happen. This is deemed an acceptable risk for the moment since it is only
@@ -1580,7 +1672,7 @@ func (r *BareMetalHostReconciler) doServiceIfNeeded(ctx context.Context, prov pr
 	dirty := clearErrorWithStatus(info.host, metal3api.OperationalStatusServicing)
 
 	if started && fwDirty {
-		info.host.Status.Provisioning.Firmware = info.host.Spec.Firmware.DeepCopy()
+		info.host.Status.Provisioning.Firmware = firmware.DeepCopy()
 		dirty = true
 	}
 
@@ -1605,6 +1697,9 @@ func (r *BareMetalHostReconciler) doServiceIfNeeded(ctx context.Context, prov pr
 	}
 
 	// Servicing is finished at this point, clean up operational status
+	if err := r.releaseServicingSlot(ctx, info); err != nil {
+		return actionError{err}
+	}
 	if clearErrorWithStatus(info.host, metal3api.OperationalStatusOK) {
 		// FIXME(janders/dtantsur): this can be racy. We should consider
 		// using a generation number to decide if we start servicing or not.
@@ -1613,6 +1708,24 @@ func (r *BareMetalHostReconciler) doServiceIfNeeded(ctx context.Context, prov pr
 	return nil
 }
 
+// rebootForServicing reboots a host whose servicing was held while it
+// powered on, so that servicing starts now that the policy allows it.
+func (r *BareMetalHostReconciler) rebootForServicing(ctx context.Context, info *reconcileInfo) actionResult {
+	if _, found := info.host.Annotations[metal3api.RebootAnnotationPrefix]; found {
+		return nil
+	}
+	info.log.Info("rebooting to start the held servicing")
+	info.publishEvent("ServicingReboot", "Rebooting to start the held servicing")
+	if info.host.Annotations == nil {
+		info.host.Annotations = map[string]string{}
+	}
+	info.host.Annotations[metal3api.RebootAnnotationPrefix] = ""
+	if err := r.Update(ctx, info.host); err != nil {
+		return actionError{fmt.Errorf("failed to add reboot annotation to host: %w", err)}
+	}
+	return actionContinue{}
+}
+
 // Check the current power status against the desired power status.
 func (r *BareMetalHostReconciler) manageHostPower(ctx context.Context, prov provisioner.Provisioner, info *reconcileInfo) actionResult {
 	var provResult provisioner.Result
@@ -1664,7 +1777,10 @@ func (r *BareMetalHostReconciler) manageHostPower(ctx context.Context, prov prov
 	}
 
 	servicingAllowed := isProvisioned && !info.host.Status.PoweredOn && desiredPowerOnState
-	if servicingAllowed || info.host.Status.OperationalStatus == metal3api.OperationalStatusServicing || info.host.Status.ErrorType == metal3api.ServicingError {
+	// Servicing held while the host powered on is checked until it may start
+	servicingScheduled := isProvisioned && desiredPowerOnState &&
+		meta.IsStatusConditionTrue(info.host.Status.Conditions, metal3api.ServicingScheduledCondition)
+	if servicingAllowed || servicingScheduled || info.host.Status.OperationalStatus == metal3api.OperationalStatusServicing || info.host.Status.ErrorType == metal3api.ServicingError {
 		var hup *metal3api.HostUpdatePolicy
 		hup, err = r.acquireHostUpdatePolicy(ctx, info)
 		if err != nil {
@@ -1969,6 +2085,15 @@ func (r *BareMetalHostReconciler) actionManageSteadyState(ctx context.Context, p
 // use Adopt() because we don't want Ironic to treat the host as
 // having been provisioned. Then we monitor its power status.
 func (r *BareMetalHostReconciler) actionManageAvailable(ctx context.Context, prov provisioner.Provisioner, info *reconcileInfo) actionResult {
+	// Hardware profiles may have changed since the host was inspected
+	dirty, err := r.matchInspectedProfile(ctx, info, info.host.Status.HardwareDetails)
+	if err != nil {
+		return actionError{err}
+	}
+	if dirty {
+		return actionUpdate{}
+	}
+
 	if info.host.NeedsProvisioning() {
 		clearError(info.host)
 		return actionComplete{}
@@ -1996,8 +2121,21 @@ func saveHostProvisioningSettings(host *metal3api.BareMetalHost, info *reconcile
 		return dirty, err
 	}
 
-	// Copy RAID settings
+	hwProf, err := hostProfile(host, info)
+	if err != nil {
+		return dirty, err
+	}
+
+	// Copy RAID settings, the hardware profile gives the default ones
 	specRAID := host.Spec.RAID
+	if specRAID == nil {
+		specRAID = hwProf.RAID.DeepCopy()
+	}
+	// An intent is turned into concrete volumes from the inspected disks
+	specRAID, err = planRAIDIntent(specRAID, host.Status.Provisioning.RAID, host.Status.HardwareDetails)
+	if err != nil {
+		return dirty, err
+	}
 	// If RAID configure is nil or empty, means that we need to keep the current hardware RAID configuration
 	// or clear current software RAID configuration
 	if specRAID == nil || reflect.DeepEqual(specRAID, &metal3api.RAIDConfig{}) {
@@ -2022,8 +2160,12 @@ func saveHostProvisioningSettings(host *metal3api.BareMetalHost, info *reconcile
 	}
 
 	// Copy BIOS settings
-	if !reflect.DeepEqual(host.Status.Provisioning.Firmware, host.Spec.Firmware) {
-		host.Status.Provisioning.Firmware = host.Spec.Firmware
+	firmware := host.Spec.Firmware
+	if firmware == nil {
+		firmware = hwProf.Firmware.DeepCopy()
+	}
+	if !reflect.DeepEqual(host.Status.Provisioning.Firmware, firmware) {
+		host.Status.Provisioning.Firmware = firmware
 		info.log.Info("Firmware settings have changed")
 		dirty = true
 	}
@@ -2227,6 +2369,12 @@ func (r *BareMetalHostReconciler) getHostFirmwareSettings(ctx context.Context, i
 			return false, nil, errors.New("host firmware status settings not available")
 		}
 
+		if firmwareSettingsHeld(hfs, &hfs.Status) {
+			info.log.Info("hostFirmwareSettings changes held until approved", "namespacename", info.request.NamespacedName,
+				"digest", hfs.Status.PendingChangesDigest)
+			return false, hfs, nil
+		}
+
 		info.log.Info("hostFirmwareSettings indicating ChangeDetected", "namespacename", info.request.NamespacedName)
 		return true, hfs, nil
 	}
@@ -2581,7 +2729,11 @@ func (r *BareMetalHostReconciler) SetupWithManager(mgr ctrl.Manager, preprovImgE
 				UpdateFunc: r.updateEventHandler,
 			}).
 		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentReconcile}).
-		Owns(&corev1.Secret{}, builder.MatchEveryOwner)
+		Owns(&corev1.Secret{}, builder.MatchEveryOwner).
+		Watches(&metal3api.HardwareProfile{}, handler.EnqueueRequestsFromMapFunc(r.hardwareProfileToHosts)).
+		Watches(&metal3api.BareMetalSwitch{},
+			handler.EnqueueRequestsFromMapFunc(namespaceMapper[metal3api.BareMetalHostList](r.Client, r.Log)),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}))
 
 	if preprovImgEnable {
 		// We use SetControllerReference() to set the owner reference, so no
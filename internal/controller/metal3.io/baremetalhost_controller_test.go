@@ -3050,9 +3050,10 @@ func TestGetUpdatesDifference(t *testing.T) {
 // can be detected as it will be used to set state to Preparing.
 func TestHostFirmwareSettings(t *testing.T) {
 	testCases := []struct {
-		Scenario   string
-		Conditions []metav1.Condition
-		Dirty      bool
+		Scenario    string
+		Conditions  []metav1.Condition
+		Annotations map[string]string
+		Dirty       bool
 	}{
 		{
 			Scenario: "spec and status the same",
@@ -3077,6 +3078,29 @@ func TestHostFirmwareSettings(t *testing.T) {
 			},
 			Dirty: false,
 		},
+		{
+			Scenario: "spec changed and held",
+			Conditions: []metav1.Condition{
+				{Type: "ChangeDetected", Status: "True", Reason: "Success"},
+				{Type: "Valid", Status: "True", Reason: "Success"},
+			},
+			Annotations: map[string]string{
+				metal3api.FirmwareSettingsHoldAnnotation: "",
+			},
+			Dirty: false,
+		},
+		{
+			Scenario: "spec changed and approved",
+			Conditions: []metav1.Condition{
+				{Type: "ChangeDetected", Status: "True", Reason: "Success"},
+				{Type: "Valid", Status: "True", Reason: "Success"},
+			},
+			Annotations: map[string]string{
+				metal3api.FirmwareSettingsHoldAnnotation:     "",
+				metal3api.FirmwareSettingsApprovedAnnotation: "0123456789abcdef",
+			},
+			Dirty: true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -3087,6 +3111,11 @@ func TestHostFirmwareSettings(t *testing.T) {
 			i.request = newRequest(host)
 
 			hfs := newHostFirmwareSettings(host, tc.Conditions)
+			hfs.Annotations = tc.Annotations
+			hfs.Status.PendingChanges = []metal3api.FirmwareSettingChange{
+				{Name: "ProcVirtualization", CurrentValue: "Disabled", NewValue: "Enabled", RequiresReboot: true},
+			}
+			hfs.Status.PendingChangesDigest = "0123456789abcdef"
 			err := r.Create(t.Context(), hfs)
 			require.NoError(t, err)
 
@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -29,10 +30,14 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	meta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
 
 const credentialErrorRequeueDelay = 10 * time.Second
@@ -56,11 +61,23 @@ type BareMetalSwitchReconciler struct {
 	// is mounted in the ironic-networking pod (from IRONIC_SWITCH_CREDENTIALS_PATH).
 	// Used to construct key_file= paths in the switch config INI.
 	SwitchCredentialPath string
+
+	// ProbeInterval is how often the management address and port of each
+	// switch are probed (from IRONIC_SWITCH_PROBE_INTERVAL). Zero disables
+	// the probes.
+	ProbeInterval time.Duration
+
+	// ProbeTimeout bounds each probe, DefaultSwitchProbeTimeout if zero.
+	ProbeTimeout time.Duration
+
+	Recorder record.EventRecorder
 }
 
 //+kubebuilder:rbac:groups=metal3.io,resources=baremetalswitches,verbs=get;list;watch
 //+kubebuilder:rbac:groups=metal3.io,resources=baremetalswitches/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;update
+//+kubebuilder:rbac:groups=metal3.io,resources=baremetalhosts,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;update;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -90,6 +107,16 @@ func (r *BareMetalSwitchReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, fmt.Errorf("failed to update switch config: %w", err)
 	}
 
+	// Publish the hosts cabled to the switch
+	topologyChanged, err := r.updateSwitchTopology(ctx, bmSwitch)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Probe the management address of the switch
+	reachabilityChanged, probeRequeue := r.updateSwitchReachability(ctx, bmSwitch)
+	statusChanged := topologyChanged || reachabilityChanged
+
 	// Set the Reconciled condition based on whether this switch had a credential error
 	if credErr, hasErr := result.credentialErrors[bmSwitch.Name]; hasErr {
 		if meta.SetStatusCondition(&bmSwitch.Status.Conditions, metav1.Condition{
@@ -98,7 +125,7 @@ func (r *BareMetalSwitchReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 			ObservedGeneration: bmSwitch.Generation,
 			Reason:             "CredentialError",
 			Message:            credErr.Error(),
-		}) {
+		}) || statusChanged {
 			if statusErr := r.Status().Update(ctx, bmSwitch); statusErr != nil {
 				return ctrl.Result{}, fmt.Errorf("failed to update BareMetalSwitch status: %w", statusErr)
 			}
@@ -107,12 +134,12 @@ func (r *BareMetalSwitchReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 			// The secret doesn't exist yet so the Owns() watch cannot detect
 			// its creation (no owner reference). Requeue periodically.
 			logger.Info("BareMetalSwitch credential secret missing, requeueing", "error", credErr)
-			return ctrl.Result{RequeueAfter: credentialErrorRequeueDelay}, nil
+			return ctrl.Result{RequeueAfter: minRequeueDelay(credentialErrorRequeueDelay, probeRequeue)}, nil
 		}
 		// The secret exists but is misconfigured. The Owns() watch will
 		// trigger a reconcile when the user fixes it, so no requeue needed.
 		logger.Info("BareMetalSwitch has credential error", "error", credErr)
-		return ctrl.Result{}, nil
+		return ctrl.Result{RequeueAfter: probeRequeue}, nil
 	}
 
 	if meta.SetStatusCondition(&bmSwitch.Status.Conditions, metav1.Condition{
@@ -121,21 +148,53 @@ func (r *BareMetalSwitchReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		ObservedGeneration: bmSwitch.Generation,
 		Reason:             "Reconciled",
 		Message:            "Switch configuration has been successfully reconciled into the config secret",
-	}) {
+	}) || statusChanged {
 		if err := r.Status().Update(ctx, bmSwitch); err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed to update BareMetalSwitch status: %w", err)
 		}
 	}
 
 	logger.Info("BareMetalSwitch reconciled")
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: probeRequeue}, nil
+}
+
+// minRequeueDelay returns the shortest of two requeue delays, zero meaning
+// no requeue.
+func minRequeueDelay(a, b time.Duration) time.Duration {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+// hostCablingChanged filters the host updates changing the inspected NICs
+// or the boot NIC.
+func hostCablingChanged(e event.UpdateEvent) bool {
+	oldHost, oldOK := e.ObjectOld.(*metal3api.BareMetalHost)
+	newHost, newOK := e.ObjectNew.(*metal3api.BareMetalHost)
+	if !oldOK || !newOK {
+		return true
+	}
+	if oldHost.Spec.BootMACAddress != newHost.Spec.BootMACAddress {
+		return true
+	}
+	if (oldHost.Status.HardwareDetails == nil) != (newHost.Status.HardwareDetails == nil) {
+		return true
+	}
+	return newHost.Status.HardwareDetails != nil &&
+		!reflect.DeepEqual(oldHost.Status.HardwareDetails.NIC, newHost.Status.HardwareDetails.NIC)
 }
 
 // SetupWithManager registers the reconciler to be run by the manager.
 func (r *BareMetalSwitchReconciler) SetupWithManager(mgr ctrl.Manager, maxConcurrentReconcile int) error {
+	r.Recorder = mgr.GetEventRecorderFor("baremetalswitch-controller")
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&metal3api.BareMetalSwitch{}).
 		Owns(&corev1.Secret{}, builder.MatchEveryOwner).
+		Watches(&metal3api.BareMetalHost{},
+			handler.EnqueueRequestsFromMapFunc(namespaceMapper[metal3api.BareMetalSwitchList](r.Client, r.Log)),
+			builder.WithPredicates(predicate.Funcs{UpdateFunc: hostCablingChanged})).
 		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentReconcile}).
 		Complete(r)
 }
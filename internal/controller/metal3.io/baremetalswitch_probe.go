@@ -0,0 +1,165 @@
+/*
+Copyright 2026 The Metal3 Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// defaultSwitchProbePort is probed when the switch has no port, SSH
+	// being the default of the networking-generic-switch device types.
+	defaultSwitchProbePort = 22
+
+	// DefaultSwitchProbeTimeout bounds each connectivity probe.
+	DefaultSwitchProbeTimeout = 10 * time.Second
+)
+
+// probeSwitch connects to the management address and port of the switch
+// and returns the time taken to receive an SSH banner or, failing that, to
+// complete a TLS handshake. The credentials of the switch are not tried.
+func probeSwitch(ctx context.Context, spec *metal3api.BareMetalSwitchSpec, timeout time.Duration) (time.Duration, error) {
+	port := defaultSwitchProbePort
+	if spec.Port != nil {
+		port = int(*spec.Port)
+	}
+	address := net.JoinHostPort(spec.Address, strconv.Itoa(port))
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// SSH servers send their banner right away, while HTTPS servers wait
+	// for the client, so only half of the time is spent on the banner.
+	start := time.Now()
+	banner, err := readSSHBanner(ctx, address, timeout/2)
+	if err != nil {
+		return 0, err
+	}
+	if strings.HasPrefix(banner, "SSH-") {
+		return time.Since(start), nil
+	}
+
+	start = time.Now()
+	dialer := &tls.Dialer{
+		Config: &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: spec.DisableCertificateVerification, // #nosec G402 Requested by the user
+		},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return 0, fmt.Errorf("no SSH banner received from %s and the TLS handshake failed: %w", address, err)
+	}
+	conn.Close()
+	return time.Since(start), nil
+}
+
+// readSSHBanner connects to the address and returns the first line sent by
+// the server within the wait time, or an empty string if none was sent.
+func readSSHBanner(ctx context.Context, address string, wait time.Duration) (string, error) {
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(wait)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err = conn.SetReadDeadline(deadline); err != nil {
+		return "", fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+	// Errors mean that nothing usable was sent, the TLS handshake tells
+	// whether the server can be used.
+	line, _ := bufio.NewReader(conn).ReadString('\n')
+	return line, nil
+}
+
+// switchProbeDue returns how long to wait before probing the switch again,
+// zero meaning that a probe is due now. Changes to the spec are probed
+// right away.
+func switchProbeDue(bmSwitch *metal3api.BareMetalSwitch, interval time.Duration, now time.Time) time.Duration {
+	if bmSwitch.Status.LastCheckTime == nil {
+		return 0
+	}
+	cond := meta.FindStatusCondition(bmSwitch.Status.Conditions, string(metal3api.SwitchConditionReachable))
+	if cond == nil || cond.ObservedGeneration != bmSwitch.Generation {
+		return 0
+	}
+	return max(bmSwitch.Status.LastCheckTime.Add(interval).Sub(now), 0)
+}
+
+// updateSwitchReachability probes the switch if due, records the result in
+// its status and raises an event when the Reachable condition changes. It
+// returns whether the status changed and when to probe again.
+func (r *BareMetalSwitchReconciler) updateSwitchReachability(ctx context.Context, bmSwitch *metal3api.BareMetalSwitch) (changed bool, requeueAfter time.Duration) {
+	if r.ProbeInterval <= 0 {
+		return false, 0
+	}
+	now := time.Now()
+	if wait := switchProbeDue(bmSwitch, r.ProbeInterval, now); wait > 0 {
+		return false, wait
+	}
+
+	timeout := r.ProbeTimeout
+	if timeout <= 0 {
+		timeout = DefaultSwitchProbeTimeout
+	}
+	latency, err := probeSwitch(ctx, &bmSwitch.Spec, timeout)
+
+	cond := metav1.Condition{
+		Type:               string(metal3api.SwitchConditionReachable),
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: bmSwitch.Generation,
+		Reason:             "Reachable",
+		Message:            "The switch management address answered the connectivity probe",
+	}
+	bmSwitch.Status.Latency = &metav1.Duration{Duration: latency.Round(time.Millisecond)}
+	if err != nil {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "Unreachable"
+		cond.Message = err.Error()
+		bmSwitch.Status.Latency = nil
+	}
+	bmSwitch.Status.LastCheckTime = &metav1.Time{Time: now}
+
+	previous := meta.FindStatusCondition(bmSwitch.Status.Conditions, cond.Type)
+	if previous == nil || previous.Status != cond.Status {
+		if err != nil {
+			r.Recorder.Event(bmSwitch, corev1.EventTypeWarning, "SwitchUnreachable", cond.Message)
+		} else {
+			r.Recorder.Event(bmSwitch, corev1.EventTypeNormal, "SwitchReachable", cond.Message)
+		}
+	}
+	meta.SetStatusCondition(&bmSwitch.Status.Conditions, cond)
+
+	return true, r.ProbeInterval
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2026 The Metal3 Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testProbeTimeout = time.Second
+
+// fakeSSHServer listens on a local port and sends an SSH banner to each
+// connection.
+func fakeSSHServer(t *testing.T) net.Listener {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = conn.Write([]byte("SSH-2.0-OpenSSH_9.6\r\n"))
+			conn.Close()
+		}
+	}()
+	return listener
+}
+
+// closedPort returns a local port nothing listens on.
+func closedPort(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+	return addr
+}
+
+func probeSwitchSpec(t *testing.T, addr string) metal3api.BareMetalSwitchSpec {
+	t.Helper()
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	portNum, err := strconv.ParseInt(port, 10, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return metal3api.BareMetalSwitchSpec{
+		Address:    host,
+		MACAddress: "00:00:5e:00:53:01",
+		DeviceType: "netmiko_cisco_ios",
+		Port:       ptr.To(int32(portNum)),
+	}
+}
+
+func TestProbeSwitch(t *testing.T) {
+	sshServer := fakeSSHServer(t)
+	httpsServer := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	// The probes close the connections right after the handshake
+	httpsServer.Config.ErrorLog = log.New(io.Discard, "", 0)
+	httpsServer.StartTLS()
+	t.Cleanup(httpsServer.Close)
+	httpsAddr := httpsServer.Listener.Addr().String()
+
+	testCases := []struct {
+		Scenario      string
+		Addr          string
+		Insecure      bool
+		ExpectedError string
+	}{
+		{
+			Scenario: "SSH banner",
+			Addr:     sshServer.Addr().String(),
+		},
+		{
+			Scenario: "HTTPS without certificate verification",
+			Addr:     httpsAddr,
+			Insecure: true,
+		},
+		{
+			Scenario:      "HTTPS with an untrusted certificate",
+			Addr:          httpsAddr,
+			ExpectedError: "no SSH banner received from " + httpsAddr + " and the TLS handshake failed",
+		},
+		{
+			Scenario:      "closed port",
+			Addr:          closedPort(t),
+			ExpectedError: "failed to connect to",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			g := NewWithT(t)
+			spec := probeSwitchSpec(t, tc.Addr)
+			spec.DisableCertificateVerification = tc.Insecure
+
+			latency, err := probeSwitch(t.Context(), &spec, testProbeTimeout)
+			if tc.ExpectedError != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.ExpectedError)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(latency).To(BeNumerically("<", testProbeTimeout))
+		})
+	}
+}
+
+func TestSwitchProbeDue(t *testing.T) {
+	g := NewWithT(t)
+	now := time.Now()
+
+	bmSwitch := &metal3api.BareMetalSwitch{ObjectMeta: metav1.ObjectMeta{Generation: 2}}
+	g.Expect(switchProbeDue(bmSwitch, time.Minute, now)).To(BeZero())
+
+	bmSwitch.Status.LastCheckTime = &metav1.Time{Time: now.Add(-20 * time.Second)}
+	bmSwitch.Status.Conditions = []metav1.Condition{{
+		Type:               string(metal3api.SwitchConditionReachable),
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: 2,
+	}}
+	g.Expect(switchProbeDue(bmSwitch, time.Minute, now)).To(Equal(40 * time.Second))
+	g.Expect(switchProbeDue(bmSwitch, 10*time.Second, now)).To(BeZero())
+
+	bmSwitch.Generation = 3
+	g.Expect(switchProbeDue(bmSwitch, time.Minute, now)).To(BeZero())
+}
+
+func TestReconcileSwitchReachability(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(metal3api.AddToScheme(scheme)).To(Succeed())
+
+	sshServer := fakeSSHServer(t)
+	bmSwitch := &metal3api.BareMetalSwitch{
+		ObjectMeta: metav1.ObjectMeta{Name: "tor-1", Namespace: "test-ns"},
+		Spec:       probeSwitchSpec(t, sshServer.Addr().String()),
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			bmSwitch,
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: testSwitchConfigsSecretName, Namespace: "test-ns"}},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: testSwitchCredentialSecretName, Namespace: "test-ns"}},
+		).
+		WithStatusSubresource(&metal3api.BareMetalSwitch{}).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	r := &BareMetalSwitchReconciler{
+		Client:                     c,
+		Log:                        logr.Discard(),
+		APIReader:                  c,
+		SwitchConfigsSecretName:    testSwitchConfigsSecretName,
+		SwitchCredentialSecretName: testSwitchCredentialSecretName,
+		SwitchCredentialPath:       testSwitchCredentialPath,
+		ProbeInterval:              time.Minute,
+		ProbeTimeout:               testProbeTimeout,
+		Recorder:                   recorder,
+	}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(bmSwitch)}
+	updated := &metal3api.BareMetalSwitch{}
+
+	// The first probe finds the switch reachable
+	result, err := r.Reconcile(t.Context(), req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(Equal(time.Minute))
+
+	g.Expect(c.Get(t.Context(), req.NamespacedName, updated)).To(Succeed())
+	cond := meta.FindStatusCondition(updated.Status.Conditions, string(metal3api.SwitchConditionReachable))
+	g.Expect(cond).ToNot(BeNil())
+	g.Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+	g.Expect(cond.Reason).To(Equal("Reachable"))
+	g.Expect(updated.Status.LastCheckTime).ToNot(BeNil())
+	g.Expect(updated.Status.Latency).ToNot(BeNil())
+	g.Expect(recorder.Events).To(Receive(HavePrefix("Normal SwitchReachable")))
+
+	// No probe until the interval has passed
+	result, err = r.Reconcile(t.Context(), req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(And(BeNumerically(">", 0), BeNumerically("<=", time.Minute)))
+	g.Expect(recorder.Events).ToNot(Receive())
+
+	// The switch stops answering
+	sshServer.Close()
+	g.Expect(c.Get(t.Context(), req.NamespacedName, updated)).To(Succeed())
+	updated.Status.LastCheckTime = &metav1.Time{Time: time.Now().Add(-2 * time.Minute)}
+	g.Expect(c.Status().Update(t.Context(), updated)).To(Succeed())
+
+	_, err = r.Reconcile(t.Context(), req)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(c.Get(t.Context(), req.NamespacedName, updated)).To(Succeed())
+	cond = meta.FindStatusCondition(updated.Status.Conditions, string(metal3api.SwitchConditionReachable))
+	g.Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(cond.Reason).To(Equal("Unreachable"))
+	g.Expect(cond.Message).To(ContainSubstring("failed to connect to"))
+	g.Expect(updated.Status.Latency).To(BeNil())
+	g.Expect(recorder.Events).To(Receive(HavePrefix("Warning SwitchUnreachable")))
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2026 The Metal3 Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// switchTopology is the cabling of the hosts of a namespace to its switches,
+// as reported by the LLDP data of their NICs.
+type switchTopology struct {
+	// ports maps the name of each switch to the NICs cabled to it.
+	ports map[string][]metal3api.SwitchPortLink
+	// unknownSwitchLinks maps the name of each host to its NICs cabled to
+	// switches that have no BareMetalSwitch.
+	unknownSwitchLinks map[string][]string
+	// uncabledHosts maps the name of each host whose boot NIC is not cabled
+	// to any switch to what the inspection found about the boot NIC.
+	uncabledHosts map[string]string
+}
+
+// buildSwitchTopology cross-references the LLDP data of the inspected hosts
+// with the MAC addresses of the switches. The hosts without inspection data
+// are ignored.
+func buildSwitchTopology(switches []metal3api.BareMetalSwitch, hosts []metal3api.BareMetalHost) switchTopology {
+	topology := switchTopology{
+		ports:              make(map[string][]metal3api.SwitchPortLink, len(switches)),
+		unknownSwitchLinks: make(map[string][]string),
+		uncabledHosts:      make(map[string]string),
+	}
+
+	switchNames := make(map[string]string, len(switches))
+	for _, sw := range switches {
+		switchNames[strings.ToLower(sw.Spec.MACAddress)] = sw.Name
+	}
+
+	hosts = slices.Clone(hosts)
+	slices.SortFunc(hosts, func(a, b metal3api.BareMetalHost) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	for _, host := range hosts {
+		if host.Status.HardwareDetails == nil {
+			continue
+		}
+		bootMAC := host.Spec.BootMACAddress
+		bootCabled := false
+		bootMessage := "the boot NIC was not found by the inspection"
+
+		for _, nic := range host.Status.HardwareDetails.NIC {
+			boot := bootMAC != "" && strings.EqualFold(nic.MAC, bootMAC)
+			if nic.LLDP == nil || nic.LLDP.SwitchID == "" {
+				if boot {
+					bootMessage = "the boot NIC reports no LLDP data"
+				}
+				continue
+			}
+
+			name, known := switchNames[strings.ToLower(nic.LLDP.SwitchID)]
+			if !known {
+				link := fmt.Sprintf("%s to port %s of switch %s", nic.Name, nic.LLDP.PortID, nic.LLDP.SwitchID)
+				if nic.LLDP.SwitchSystemName != "" {
+					link += fmt.Sprintf(" (%s)", nic.LLDP.SwitchSystemName)
+				}
+				topology.unknownSwitchLinks[host.Name] = append(topology.unknownSwitchLinks[host.Name], link)
+				if boot {
+					bootMessage = fmt.Sprintf("the boot NIC is cabled to the unknown switch %s", nic.LLDP.SwitchID)
+				}
+				continue
+			}
+
+			topology.ports[name] = append(topology.ports[name], metal3api.SwitchPortLink{
+				Port:       nic.LLDP.PortID,
+				Host:       host.Name,
+				NIC:        nic.Name,
+				MACAddress: strings.ToLower(nic.MAC),
+				Boot:       boot,
+			})
+			bootCabled = bootCabled || boot
+		}
+
+		if bootMAC != "" && !bootCabled {
+			topology.uncabledHosts[host.Name] = bootMessage
+		}
+	}
+
+	for _, links := range topology.ports {
+		slices.SortStableFunc(links, func(a, b metal3api.SwitchPortLink) int {
+			return cmp.Compare(a.Port, b.Port)
+		})
+	}
+
+	return topology
+}
+
+// updateSwitchTopology sets the NICs of the hosts of the namespace cabled to
+// the switch in its status, and returns whether it changed.
+func (r *BareMetalSwitchReconciler) updateSwitchTopology(ctx context.Context, bmSwitch *metal3api.BareMetalSwitch) (bool, error) {
+	switches := &metal3api.BareMetalSwitchList{}
+	if err := r.List(ctx, switches, client.InNamespace(bmSwitch.Namespace)); err != nil {
+		return false, fmt.Errorf("failed to list BareMetalSwitch resources: %w", err)
+	}
+	hosts := &metal3api.BareMetalHostList{}
+	if err := r.List(ctx, hosts, client.InNamespace(bmSwitch.Namespace)); err != nil {
+		return false, fmt.Errorf("failed to list BareMetalHost resources: %w", err)
+	}
+
+	ports := buildSwitchTopology(switches.Items, hosts.Items).ports[bmSwitch.Name]
+	if reflect.DeepEqual(ports, bmSwitch.Status.Ports) {
+		return false, nil
+	}
+	bmSwitch.Status.Ports = ports
+	return true, nil
+}
+
+// setCabledCondition reports whether the boot NIC and the NICs reporting
+// LLDP data of the host are cabled to the switches of its namespace, and
+// whether the host changed. The condition is removed from the hosts that
+// have not been inspected and in the namespaces without switches.
+func setCabledCondition(host *metal3api.BareMetalHost, switches []metal3api.BareMetalSwitch) bool {
+	if host.Status.HardwareDetails == nil || len(switches) == 0 {
+		return meta.RemoveStatusCondition(&host.Status.Conditions, metal3api.CabledCondition)
+	}
+
+	topology := buildSwitchTopology(switches, []metal3api.BareMetalHost{*host})
+	condition := metav1.Condition{
+		Type:               metal3api.CabledCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             metal3api.CabledReason,
+		ObservedGeneration: host.Generation,
+	}
+	if message, uncabled := topology.uncabledHosts[host.Name]; uncabled {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = metal3api.BootNICNotCabledReason
+		condition.Message = message
+	} else if links := topology.unknownSwitchLinks[host.Name]; len(links) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = metal3api.UnknownSwitchReason
+		condition.Message = "NICs cabled to switches without BareMetalSwitch: " + strings.Join(links, ", ")
+	}
+	return meta.SetStatusCondition(&host.Status.Conditions, condition)
+}
+
+// updateCabledCondition sets the Cabled condition of the host from the
+// switches of its namespace, and returns whether it changed.
+func (r *BareMetalHostReconciler) updateCabledCondition(ctx context.Context, host *metal3api.BareMetalHost) (bool, error) {
+	switches := &metal3api.BareMetalSwitchList{}
+	if err := r.List(ctx, switches, client.InNamespace(host.Namespace)); err != nil {
+		return false, fmt.Errorf("failed to list BareMetalSwitch resources: %w", err)
+	}
+	return setCabledCondition(host, switches.Items), nil
+}
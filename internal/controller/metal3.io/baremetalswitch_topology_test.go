@@ -0,0 +1,279 @@
+/*
+Copyright 2026 The Metal3 Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func topologySwitch(name, mac string) metal3api.BareMetalSwitch {
+	return metal3api.BareMetalSwitch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "test-ns",
+		},
+		Spec: metal3api.BareMetalSwitchSpec{
+			Address:    "192.168.1.1",
+			MACAddress: mac,
+			DeviceType: "cisco_ios",
+		},
+	}
+}
+
+func topologyHost(name, bootMAC string, nics ...metal3api.NIC) metal3api.BareMetalHost {
+	host := metal3api.BareMetalHost{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "test-ns",
+		},
+		Spec: metal3api.BareMetalHostSpec{
+			BootMACAddress: bootMAC,
+		},
+	}
+	if nics != nil {
+		host.Status.HardwareDetails = &metal3api.HardwareDetails{NIC: nics}
+	}
+	return host
+}
+
+func topologyNIC(name, mac, switchID, port string) metal3api.NIC {
+	nic := metal3api.NIC{Name: name, MAC: mac}
+	if switchID != "" {
+		nic.LLDP = &metal3api.LLDP{SwitchID: switchID, PortID: port, SwitchSystemName: "sw-" + port}
+	}
+	return nic
+}
+
+func TestBuildSwitchTopology(t *testing.T) {
+	g := NewWithT(t)
+
+	switches := []metal3api.BareMetalSwitch{
+		topologySwitch("tor-1", "00:00:5E:00:53:01"),
+		topologySwitch("tor-2", "00:00:5e:00:53:02"),
+	}
+	hosts := []metal3api.BareMetalHost{
+		topologyHost("host-1", "00:00:00:00:01:01",
+			topologyNIC("eno1", "00:00:00:00:01:01", "00:00:5e:00:53:01", "Ethernet1/2"),
+			topologyNIC("eno2", "00:00:00:00:01:02", "00:00:5e:00:53:02", "Ethernet1/2"),
+		),
+		topologyHost("host-0", "00:00:00:00:00:01",
+			topologyNIC("eno1", "00:00:00:00:00:01", "00:00:5e:00:53:01", "Ethernet1/1"),
+			topologyNIC("eno2", "00:00:00:00:00:02", "00:00:5e:00:53:99", "Ethernet1/1"),
+		),
+		topologyHost("host-2", "00:00:00:00:02:01",
+			topologyNIC("eno1", "00:00:00:00:02:01", "00:00:5e:00:53:99", "Ethernet1/2"),
+		),
+		topologyHost("host-3", "00:00:00:00:03:01",
+			topologyNIC("eno1", "00:00:00:00:03:01", "", ""),
+		),
+		topologyHost("host-4", "00:00:00:00:04:01",
+			topologyNIC("eno1", "00:00:00:00:04:02", "00:00:5e:00:53:02", "Ethernet1/4"),
+		),
+		topologyHost("not-inspected", "00:00:00:00:05:01"),
+	}
+
+	topology := buildSwitchTopology(switches, hosts)
+
+	g.Expect(topology.ports).To(Equal(map[string][]metal3api.SwitchPortLink{
+		"tor-1": {
+			{Port: "Ethernet1/1", Host: "host-0", NIC: "eno1", MACAddress: "00:00:00:00:00:01", Boot: true},
+			{Port: "Ethernet1/2", Host: "host-1", NIC: "eno1", MACAddress: "00:00:00:00:01:01", Boot: true},
+		},
+		"tor-2": {
+			{Port: "Ethernet1/2", Host: "host-1", NIC: "eno2", MACAddress: "00:00:00:00:01:02"},
+			{Port: "Ethernet1/4", Host: "host-4", NIC: "eno1", MACAddress: "00:00:00:00:04:02"},
+		},
+	}))
+	g.Expect(topology.unknownSwitchLinks).To(Equal(map[string][]string{
+		"host-0": {"eno2 to port Ethernet1/1 of switch 00:00:5e:00:53:99 (sw-Ethernet1/1)"},
+		"host-2": {"eno1 to port Ethernet1/2 of switch 00:00:5e:00:53:99 (sw-Ethernet1/2)"},
+	}))
+	g.Expect(topology.uncabledHosts).To(Equal(map[string]string{
+		"host-2": "the boot NIC is cabled to the unknown switch 00:00:5e:00:53:99",
+		"host-3": "the boot NIC reports no LLDP data",
+		"host-4": "the boot NIC was not found by the inspection",
+	}))
+}
+
+func TestSetCabledCondition(t *testing.T) {
+	switches := []metal3api.BareMetalSwitch{
+		topologySwitch("tor-1", "00:00:5e:00:53:01"),
+	}
+
+	testCases := []struct {
+		name            string
+		host            metal3api.BareMetalHost
+		switches        []metal3api.BareMetalSwitch
+		expectedStatus  metav1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name: "cabled",
+			host: topologyHost("host-0", "00:00:00:00:00:01",
+				topologyNIC("eno1", "00:00:00:00:00:01", "00:00:5e:00:53:01", "Ethernet1/1"),
+				topologyNIC("eno2", "00:00:00:00:00:02", "", ""),
+			),
+			switches:       switches,
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: metal3api.CabledReason,
+		},
+		{
+			name: "boot NIC not cabled",
+			host: topologyHost("host-0", "00:00:00:00:00:01",
+				topologyNIC("eno1", "00:00:00:00:00:01", "", ""),
+			),
+			switches:        switches,
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  metal3api.BootNICNotCabledReason,
+			expectedMessage: "the boot NIC reports no LLDP data",
+		},
+		{
+			name: "unknown switch",
+			host: topologyHost("host-0", "00:00:00:00:00:01",
+				topologyNIC("eno1", "00:00:00:00:00:01", "00:00:5e:00:53:01", "Ethernet1/1"),
+				topologyNIC("eno2", "00:00:00:00:00:02", "00:00:5e:00:53:99", "Ethernet1/1"),
+			),
+			switches:        switches,
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  metal3api.UnknownSwitchReason,
+			expectedMessage: "NICs cabled to switches without BareMetalSwitch: eno2 to port Ethernet1/1 of switch 00:00:5e:00:53:99 (sw-Ethernet1/1)",
+		},
+		{
+			name: "no switch in the namespace",
+			host: topologyHost("host-0", "00:00:00:00:00:01",
+				topologyNIC("eno1", "00:00:00:00:00:01", "00:00:5e:00:53:99", "Ethernet1/1"),
+			),
+		},
+		{
+			name:     "not inspected",
+			host:     topologyHost("host-0", "00:00:00:00:00:01"),
+			switches: switches,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			host := tc.host.DeepCopy()
+			host.Status.Conditions = []metav1.Condition{
+				{Type: metal3api.CabledCondition, Status: metav1.ConditionUnknown, Reason: "Stale"},
+			}
+
+			g.Expect(setCabledCondition(host, tc.switches)).To(BeTrue())
+
+			condition := meta.FindStatusCondition(host.Status.Conditions, metal3api.CabledCondition)
+			if tc.expectedReason == "" {
+				g.Expect(condition).To(BeNil())
+				return
+			}
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(condition.Status).To(Equal(tc.expectedStatus))
+			g.Expect(condition.Reason).To(Equal(tc.expectedReason))
+			g.Expect(condition.Message).To(Equal(tc.expectedMessage))
+
+			g.Expect(setCabledCondition(host, tc.switches)).To(BeFalse())
+		})
+	}
+}
+
+func TestReconcileSwitchTopology(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(metal3api.AddToScheme(scheme)).To(Succeed())
+
+	bmSwitch := topologySwitch("tor-1", "00:00:5e:00:53:01")
+	bmSwitch.Spec.Credentials = &corev1.SecretReference{Name: "creds"}
+	host := topologyHost("host-0", "00:00:00:00:00:01",
+		topologyNIC("eno1", "00:00:00:00:00:01", "00:00:5e:00:53:01", "Ethernet1/1"),
+	)
+	uncabled := topologyHost("host-1", "00:00:00:00:01:01",
+		topologyNIC("eno1", "00:00:00:00:01:01", "", ""),
+	)
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			&bmSwitch, &host, &uncabled,
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "test-ns"},
+				Data: map[string][]byte{
+					"username": []byte("admin"),
+					"password": []byte("secret"),
+				},
+			},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: testSwitchConfigsSecretName, Namespace: "test-ns"}},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: testSwitchCredentialSecretName, Namespace: "test-ns"}},
+		).
+		WithStatusSubresource(&metal3api.BareMetalSwitch{}).
+		Build()
+
+	r := &BareMetalSwitchReconciler{
+		Client:                     c,
+		Log:                        logr.Discard(),
+		APIReader:                  c,
+		SwitchConfigsSecretName:    testSwitchConfigsSecretName,
+		SwitchCredentialSecretName: testSwitchCredentialSecretName,
+		SwitchCredentialPath:       testSwitchCredentialPath,
+	}
+
+	_, err := r.Reconcile(t.Context(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&bmSwitch)})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	updated := &metal3api.BareMetalSwitch{}
+	g.Expect(c.Get(t.Context(), client.ObjectKeyFromObject(&bmSwitch), updated)).To(Succeed())
+	g.Expect(updated.Status.Ports).To(Equal([]metal3api.SwitchPortLink{
+		{Port: "Ethernet1/1", Host: "host-0", NIC: "eno1", MACAddress: "00:00:00:00:00:01", Boot: true},
+	}))
+
+	requests := namespaceMapper[metal3api.BareMetalSwitchList](r.Client, r.Log)(t.Context(), &host)
+	g.Expect(requests).To(HaveLen(1))
+	g.Expect(requests[0].Name).To(Equal("tor-1"))
+}
+
+func TestHostCablingChanged(t *testing.T) {
+	g := NewWithT(t)
+
+	host := topologyHost("host-0", "00:00:00:00:00:01",
+		topologyNIC("eno1", "00:00:00:00:00:01", "00:00:5e:00:53:01", "Ethernet1/1"),
+	)
+
+	unchanged := host.DeepCopy()
+	unchanged.Status.PoweredOn = true
+	g.Expect(hostCablingChanged(event.UpdateEvent{ObjectOld: &host, ObjectNew: unchanged})).To(BeFalse())
+
+	recabled := host.DeepCopy()
+	recabled.Status.HardwareDetails.NIC[0].LLDP.PortID = "Ethernet1/2"
+	g.Expect(hostCablingChanged(event.UpdateEvent{ObjectOld: &host, ObjectNew: recabled})).To(BeTrue())
+
+	bootChanged := host.DeepCopy()
+	bootChanged.Spec.BootMACAddress = "00:00:00:00:00:02"
+	g.Expect(hostCablingChanged(event.UpdateEvent{ObjectOld: &host, ObjectNew: bootChanged})).To(BeTrue())
+}
@@ -17,6 +17,8 @@ package controllers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"reflect"
@@ -26,8 +28,11 @@ import (
 	"github.com/go-logr/logr"
 	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
 	"github.com/metal3-io/baremetal-operator/pkg/provisioner"
+	"github.com/metal3-io/baremetal-operator/pkg/secretutils"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -37,10 +42,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 const (
-	subscriptionRetryDelay = time.Minute * 10
+	subscriptionRetryDelay    = time.Minute * 10
+	subscriptionCheckInterval = time.Minute * 5
 )
 
 type BMCEventSubscriptionReconciler struct {
@@ -52,6 +59,7 @@ type BMCEventSubscriptionReconciler struct {
 
 //+kubebuilder:rbac:groups=metal3.io,resources=bmceventsubscriptions,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=metal3.io,resources=bmceventsubscriptions/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;update
 
 func (r *BMCEventSubscriptionReconciler) Reconcile(ctx context.Context, request ctrl.Request) (result ctrl.Result, err error) {
 	reqLogger := r.Log.WithValues("bmceventsubscription", request.NamespacedName)
@@ -86,8 +94,8 @@ func (r *BMCEventSubscriptionReconciler) Reconcile(ctx context.Context, request
 		if k8serrors.IsNotFound(err) {
 			reqLogger.Error(err, "baremetalhost not found", "host", subscription.Spec.HostName)
 
-			message := fmt.Sprintf("baremetal host %q", subscription.Status.Error)
-			return r.handleError(ctx, subscription, err, message, true)
+			message := fmt.Sprintf("baremetal host %q not found", subscription.Spec.HostName)
+			return r.handleError(ctx, subscription, err, "HostNotFound", message, true)
 		}
 		// Error reading the object - requeue the request.
 		return ctrl.Result{}, fmt.Errorf("could not load host data: %w", err)
@@ -112,25 +120,112 @@ func (r *BMCEventSubscriptionReconciler) Reconcile(ctx context.Context, request
 		return ctrl.Result{RequeueAfter: provisionerNotReadyRetryDelay}, nil
 	}
 
-	if subscription.DeletionTimestamp.IsZero() {
-		// Not being deleted
-		if err := r.createSubscription(ctx, prov, subscription); err != nil {
-			return r.handleError(ctx, subscription, err, "failed to create a subscription", false)
-		}
-	} else {
+	if !subscription.DeletionTimestamp.IsZero() {
 		// Being deleted
 		if err := r.deleteSubscription(ctx, prov, subscription); err != nil {
-			return r.handleError(ctx, subscription, err, "failed to delete a subscription", false)
+			return r.handleError(ctx, subscription, err, "RemovalFailed", "failed to delete a subscription", false)
 		}
 
 		return ctrl.Result{}, nil
 	}
 
-	return result, nil
+	return r.syncSubscription(ctx, prov, subscription)
 }
 
-func (r *BMCEventSubscriptionReconciler) handleError(ctx context.Context, subscription *metal3api.BMCEventSubscription, e error, message string, requeue bool) (ctrl.Result, error) {
+// syncSubscription creates the subscription, recreates it when the spec or
+// the HTTP headers secret changed, and periodically verifies that the BMC
+// did not drop it.
+func (r *BMCEventSubscriptionReconciler) syncSubscription(ctx context.Context, prov provisioner.Provisioner, subscription *metal3api.BMCEventSubscription) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("bmceventsubscription", client.ObjectKeyFromObject(subscription))
+
+	headers, headersVersion, err := r.getHTTPHeaders(ctx, *subscription)
+	if err != nil {
+		reqLogger.Error(err, "failed to get http headers")
+		return r.handleError(ctx, subscription, err, "HTTPHeadersError", "failed to retrieve HTTP headers secret", false)
+	}
+
+	if subscription.Status.SubscriptionID != "" {
+		if subscription.Status.ObservedGeneration == 0 {
+			// Created before the spec was recorded, assume it is current
+			subscription.Status.ObservedGeneration = subscription.Generation
+			subscription.Status.HTTPHeadersVersion = headersVersion
+		}
+
+		switch {
+		case subscription.Status.ObservedGeneration != subscription.Generation || subscription.Status.HTTPHeadersVersion != headersVersion:
+			reqLogger.Info("subscription changed, recreating it")
+			// The BMC may have already dropped it, and fails to remove it then
+			exists, err := prov.CheckBMCEventSubscriptionForNode(ctx, *subscription)
+			if err != nil {
+				return r.handleError(ctx, subscription, err, "CheckFailed", "failed to verify the subscription", false)
+			}
+			if exists {
+				if _, err := prov.RemoveBMCEventSubscriptionForNode(ctx, *subscription); err != nil {
+					return r.handleError(ctx, subscription, err, "RemovalFailed", "failed to remove the outdated subscription", false)
+				}
+			}
+		case subscription.Status.LastCheckTime == nil || time.Since(subscription.Status.LastCheckTime.Time) >= subscriptionCheckInterval:
+			exists, err := prov.CheckBMCEventSubscriptionForNode(ctx, *subscription)
+			if err != nil {
+				return r.handleError(ctx, subscription, err, "CheckFailed", "failed to verify the subscription", false)
+			}
+			if exists {
+				return r.subscriptionReady(ctx, subscription)
+			}
+			reqLogger.Info("subscription not found on the BMC, recreating it", "subscriptionID", subscription.Status.SubscriptionID)
+		default:
+			return ctrl.Result{RequeueAfter: subscriptionCheckInterval - time.Since(subscription.Status.LastCheckTime.Time)}, nil
+		}
+		subscription.Status.SubscriptionID = ""
+	}
+
+	if _, err := prov.AddBMCEventSubscriptionForNode(ctx, subscription, headers); err != nil {
+		return r.handleError(ctx, subscription, err, "SubscriptionFailed", "failed to create a subscription", false)
+	}
+	subscription.Status.ObservedGeneration = subscription.Generation
+	subscription.Status.HTTPHeadersVersion = headersVersion
+	return r.subscriptionReady(ctx, subscription)
+}
+
+// subscriptionReady records that the subscription exists on the BMC.
+func (r *BMCEventSubscriptionReconciler) subscriptionReady(ctx context.Context, subscription *metal3api.BMCEventSubscription) (ctrl.Result, error) {
+	subscription.Status.Error = ""
+	subscription.Status.LastCheckTime = &metav1.Time{Time: time.Now()}
+	meta.SetStatusCondition(&subscription.Status.Conditions, metav1.Condition{
+		Type:               string(metal3api.BMCEventSubscriptionReady),
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: subscription.Generation,
+		Reason:             "Subscribed",
+		Message:            "The subscription exists on the BMC",
+	})
+	meta.SetStatusCondition(&subscription.Status.Conditions, metav1.Condition{
+		Type:               string(metal3api.BMCEventSubscriptionError),
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: subscription.Generation,
+		Reason:             "Subscribed",
+	})
+	if err := r.Status().Update(ctx, subscription); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update subscription status: %w", err)
+	}
+	return ctrl.Result{RequeueAfter: subscriptionCheckInterval}, nil
+}
+
+func (r *BMCEventSubscriptionReconciler) handleError(ctx context.Context, subscription *metal3api.BMCEventSubscription, e error, reason, message string, requeue bool) (ctrl.Result, error) {
 	subscription.Status.Error = message
+	meta.SetStatusCondition(&subscription.Status.Conditions, metav1.Condition{
+		Type:               string(metal3api.BMCEventSubscriptionReady),
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: subscription.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+	meta.SetStatusCondition(&subscription.Status.Conditions, metav1.Condition{
+		Type:               string(metal3api.BMCEventSubscriptionError),
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: subscription.Generation,
+		Reason:             reason,
+		Message:            fmt.Sprintf("%s: %s", message, e),
+	})
 	err := r.Status().Update(ctx, subscription)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to update subscription status: %w", err)
@@ -165,33 +260,6 @@ func (r *BMCEventSubscriptionReconciler) addFinalizer(ctx context.Context, subsc
 	return nil
 }
 
-func (r *BMCEventSubscriptionReconciler) createSubscription(ctx context.Context, prov provisioner.Provisioner, subscription *metal3api.BMCEventSubscription) error {
-	reqLogger := r.Log.WithName("bmceventsubscription")
-
-	if subscription.Status.SubscriptionID != "" {
-		reqLogger.Info("subscription already exists")
-		return nil
-	}
-
-	headers, err := r.getHTTPHeaders(ctx, *subscription)
-
-	if err != nil {
-		reqLogger.Error(err, "failed to get http headers")
-		subscription.Status.Error = "failed to retrieve HTTP headers secret"
-		updateErr := r.Status().Update(ctx, subscription)
-		if updateErr != nil {
-			return fmt.Errorf("failed to update subscription status: %w", updateErr)
-		}
-		return err
-	}
-
-	if _, err := prov.AddBMCEventSubscriptionForNode(ctx, subscription, headers); err != nil {
-		return fmt.Errorf("failed to create subscription: %w", err)
-	}
-
-	return r.Status().Update(ctx, subscription)
-}
-
 func (r *BMCEventSubscriptionReconciler) deleteSubscription(ctx context.Context, prov provisioner.Provisioner, subscription *metal3api.BMCEventSubscription) error {
 	reqLogger := r.Log.WithName("bmceventsubscription")
 	reqLogger.Info("deleting subscription")
@@ -237,27 +305,30 @@ func (r *BMCEventSubscriptionReconciler) getProvisioner(ctx context.Context, req
 	return prov, ready, nil
 }
 
-func (r *BMCEventSubscriptionReconciler) getHTTPHeaders(ctx context.Context, subscription metal3api.BMCEventSubscription) ([]map[string]string, error) {
+// getHTTPHeaders returns the HTTP headers of the subscription, and a digest
+// of their content, so that the changes of the metadata of their secret do
+// not recreate the subscription.
+func (r *BMCEventSubscriptionReconciler) getHTTPHeaders(ctx context.Context, subscription metal3api.BMCEventSubscription) ([]map[string]string, string, error) {
 	headers := []map[string]string{}
 
 	if subscription.Spec.HTTPHeadersRef == nil {
-		return headers, nil
+		return headers, "", nil
 	}
 
 	if subscription.Spec.HTTPHeadersRef.Namespace != subscription.Namespace {
-		return headers, errors.New("httpHeadersRef secret must be in the same namespace as the BMCEventSubscription")
+		return headers, "", errors.New("httpHeadersRef secret must be in the same namespace as the BMCEventSubscription")
 	}
 
-	secret := &corev1.Secret{}
 	secretKey := types.NamespacedName{
 		Name:      subscription.Spec.HTTPHeadersRef.Name,
 		Namespace: subscription.Spec.HTTPHeadersRef.Namespace,
 	}
 
-	err := r.Get(ctx, secretKey, secret)
-
+	// The secret manager labels the secret so that its changes are watched
+	secretManager := secretutils.NewSecretManager(r.Log, r.Client, r.APIReader)
+	secret, err := secretManager.ObtainSecret(ctx, secretKey)
 	if err != nil {
-		return headers, err
+		return headers, "", err
 	}
 
 	for headerName, headerValueBytes := range secret.Data {
@@ -266,7 +337,40 @@ func (r *BMCEventSubscriptionReconciler) getHTTPHeaders(ctx context.Context, sub
 		headers = append(headers, header)
 	}
 
-	return headers, err
+	return headers, httpHeadersDigest(secret.Data), nil
+}
+
+// httpHeadersDigest returns a short hash identifying the content of the
+// HTTP headers secret.
+func httpHeadersDigest(data map[string][]byte) string {
+	names := make([]string, 0, len(data))
+	for name := range data {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	hash := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(hash, "%q=%q\n", name, data[name])
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
+// secretToBMCEventSubscriptions returns the subscriptions using a secret
+// for their HTTP headers.
+func (r *BMCEventSubscriptionReconciler) secretToBMCEventSubscriptions(ctx context.Context, obj client.Object) []reconcile.Request {
+	subscriptions := metal3api.BMCEventSubscriptionList{}
+	if err := r.List(ctx, &subscriptions, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "failed to list the bmceventsubscriptions", "namespace", obj.GetNamespace())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, subscription := range subscriptions.Items {
+		if subscription.Spec.HTTPHeadersRef != nil && subscription.Spec.HTTPHeadersRef.Name == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&subscription)})
+		}
+	}
+	return requests
 }
 
 func (r *BMCEventSubscriptionReconciler) updateEventHandler(e event.UpdateEvent) bool {
@@ -298,6 +402,7 @@ func (r *BMCEventSubscriptionReconciler) SetupWithManager(mgr ctrl.Manager, maxC
 			UpdateFunc: r.updateEventHandler,
 		}).
 		Watches(&metal3api.BareMetalHost{}, &handler.EnqueueRequestForObject{}, builder.Predicates{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.secretToBMCEventSubscriptions)).
 		Complete(r)
 }
 
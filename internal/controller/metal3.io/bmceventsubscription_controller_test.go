@@ -1,13 +1,17 @@
 package controllers
 
 import (
+	"errors"
+	"fmt"
 	"slices"
 	"testing"
+	"time"
 
 	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
 	"github.com/metal3-io/baremetal-operator/pkg/provisioner/fixture"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -209,7 +213,7 @@ func TestGetHTTPHeaders(t *testing.T) {
 				}
 			}
 
-			headers, err := r.getHTTPHeaders(t.Context(), *tc.Subscription)
+			headers, _, err := r.getHTTPHeaders(t.Context(), *tc.Subscription)
 			if tc.ExpectedError && err == nil {
 				t.Error("Expected error but got none")
 			}
@@ -224,3 +228,131 @@ func TestGetHTTPHeaders(t *testing.T) {
 		})
 	}
 }
+
+func getSubscription(t *testing.T, r *BMCEventSubscriptionReconciler, subscription *metal3api.BMCEventSubscription) *metal3api.BMCEventSubscription {
+	t.Helper()
+	current := &metal3api.BMCEventSubscription{}
+	require.NoError(t, r.Get(t.Context(), client.ObjectKeyFromObject(subscription), current))
+	return current
+}
+
+func requireSubscriptionReady(t *testing.T, subscription *metal3api.BMCEventSubscription, expectedID string) {
+	t.Helper()
+	require.Equal(t, expectedID, subscription.Status.SubscriptionID)
+	require.Empty(t, subscription.Status.Error)
+	require.NotNil(t, subscription.Status.LastCheckTime)
+	require.Equal(t, subscription.Generation, subscription.Status.ObservedGeneration)
+	require.True(t, meta.IsStatusConditionTrue(subscription.Status.Conditions, string(metal3api.BMCEventSubscriptionReady)))
+	require.True(t, meta.IsStatusConditionFalse(subscription.Status.Conditions, string(metal3api.BMCEventSubscriptionError)))
+}
+
+func TestBMCEventSubscriptionResubscribe(t *testing.T) {
+	host := newDefaultHost(t)
+	subscription := newDefaultSubscription(t)
+	// The fake client does not manage the generation
+	subscription.Generation = 1
+	fix := fixture.Fixture{}
+	r := newBMCTestReconcilerWithFixture(t, &fix, subscription, host)
+	request := newBMCRequest(subscription)
+
+	// The subscription is created
+	result, err := r.Reconcile(t.Context(), request)
+	require.NoError(t, err)
+	require.Equal(t, subscriptionCheckInterval, result.RequeueAfter)
+	current := getSubscription(t, r, subscription)
+	requireSubscriptionReady(t, current, "subscription-1")
+	require.Equal(t, []string{"subscription-1"}, fix.BMCEventSubscriptions)
+
+	// Nothing is done until the next check
+	result, err = r.Reconcile(t.Context(), request)
+	require.NoError(t, err)
+	require.Positive(t, result.RequeueAfter)
+	require.LessOrEqual(t, result.RequeueAfter, subscriptionCheckInterval)
+	require.Equal(t, "subscription-1", getSubscription(t, r, subscription).Status.SubscriptionID)
+
+	// The BMC drops the subscription
+	fix.BMCEventSubscriptions = nil
+	current = getSubscription(t, r, subscription)
+	current.Status.LastCheckTime = &metav1.Time{Time: time.Now().Add(-subscriptionCheckInterval)}
+	require.NoError(t, r.Status().Update(t.Context(), current))
+	_, err = r.Reconcile(t.Context(), request)
+	require.NoError(t, err)
+	requireSubscriptionReady(t, getSubscription(t, r, subscription), "subscription-2")
+	require.Equal(t, []string{"subscription-2"}, fix.BMCEventSubscriptions)
+
+	// The destination changes
+	current = getSubscription(t, r, subscription)
+	current.Spec.Destination = "https://example.com/events"
+	current.Generation++
+	require.NoError(t, r.Update(t.Context(), current))
+	_, err = r.Reconcile(t.Context(), request)
+	require.NoError(t, err)
+	requireSubscriptionReady(t, getSubscription(t, r, subscription), "subscription-3")
+	require.Equal(t, []string{"subscription-3"}, fix.BMCEventSubscriptions)
+
+	// The HTTP headers change
+	secret := &corev1.Secret{}
+	require.NoError(t, r.Get(t.Context(), client.ObjectKey{Namespace: namespace, Name: defaultSecretName}, secret))
+	secret.Data["Authorization"] = []byte("Bearer token")
+	require.NoError(t, r.Update(t.Context(), secret))
+	require.Len(t, r.secretToBMCEventSubscriptions(t.Context(), secret), 1)
+	_, err = r.Reconcile(t.Context(), request)
+	require.NoError(t, err)
+	requireSubscriptionReady(t, getSubscription(t, r, subscription), "subscription-4")
+	require.Equal(t, []string{"subscription-4"}, fix.BMCEventSubscriptions)
+}
+
+func TestBMCEventSubscriptionChangedAfterDropped(t *testing.T) {
+	host := newDefaultHost(t)
+	subscription := newDefaultSubscription(t)
+	subscription.Generation = 1
+	fix := fixture.Fixture{}
+	r := newBMCTestReconcilerWithFixture(t, &fix, subscription, host)
+	request := newBMCRequest(subscription)
+
+	_, err := r.Reconcile(t.Context(), request)
+	require.NoError(t, err)
+	requireSubscriptionReady(t, getSubscription(t, r, subscription), "subscription-1")
+
+	// The BMC drops the subscription and fails to remove it
+	fix.BMCEventSubscriptions = nil
+	fix.BMCEventSubscriptionRemoveError = errors.New("subscription not found")
+
+	// The destination changes
+	current := getSubscription(t, r, subscription)
+	current.Spec.Destination = "https://example.com/events"
+	current.Generation++
+	require.NoError(t, r.Update(t.Context(), current))
+	_, err = r.Reconcile(t.Context(), request)
+	require.NoError(t, err)
+	requireSubscriptionReady(t, getSubscription(t, r, subscription), "subscription-2")
+	require.Equal(t, []string{"subscription-2"}, fix.BMCEventSubscriptions)
+}
+
+func TestBMCEventSubscriptionHostNotFound(t *testing.T) {
+	subscription := newDefaultSubscription(t)
+	r := newBMCTestReconciler(t, subscription)
+
+	result, err := r.Reconcile(t.Context(), newBMCRequest(subscription))
+	require.NoError(t, err)
+	require.Equal(t, subscriptionRetryDelay, result.RequeueAfter)
+
+	current := getSubscription(t, r, subscription)
+	require.Equal(t, fmt.Sprintf("baremetal host %q not found", t.Name()), current.Status.Error)
+	ready := meta.FindStatusCondition(current.Status.Conditions, string(metal3api.BMCEventSubscriptionReady))
+	require.NotNil(t, ready)
+	require.Equal(t, metav1.ConditionFalse, ready.Status)
+	require.Equal(t, "HostNotFound", ready.Reason)
+	require.True(t, meta.IsStatusConditionTrue(current.Status.Conditions, string(metal3api.BMCEventSubscriptionError)))
+}
+
+func TestHTTPHeadersDigest(t *testing.T) {
+	data := map[string][]byte{"Authorization": []byte("Bearer abc"), "X-Custom": []byte("value")}
+	digest := httpHeadersDigest(data)
+
+	sameData := map[string][]byte{"X-Custom": []byte("value"), "Authorization": []byte("Bearer abc")}
+	require.Equal(t, digest, httpHeadersDigest(sameData))
+
+	changedData := map[string][]byte{"Authorization": []byte("Bearer xyz"), "X-Custom": []byte("value")}
+	require.NotEqual(t, digest, httpHeadersDigest(changedData))
+}
@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=metal3.io,resources=hostdeploypolicies,verbs=get;list;watch
+
+// maxDiskErasures is the number of disk erasures kept in the status of a
+// host.
+const maxDiskErasures = 10
+
+// automatedCleaningMode returns the cleaning mode of the host. A
+// HostDeployPolicy of its namespace erasing the disks takes precedence over
+// the mode of a host that does not erase them itself.
+func (r *BareMetalHostReconciler) automatedCleaningMode(ctx context.Context, host *metal3api.BareMetalHost) (metal3api.AutomatedCleaningMode, error) {
+	mode := host.Spec.AutomatedCleaningMode
+	if mode.ErasesDisks() {
+		return mode, nil
+	}
+
+	policies := metal3api.HostDeployPolicyList{}
+	if err := r.List(ctx, &policies, client.InNamespace(host.Namespace)); err != nil {
+		return mode, fmt.Errorf("failed to list host deploy policies: %w", err)
+	}
+	slices.SortFunc(policies.Items, func(a, b metal3api.HostDeployPolicy) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	for _, policy := range policies.Items {
+		if policy.Spec.AutomatedCleaningMode.ErasesDisks() {
+			return policy.Spec.AutomatedCleaningMode, nil
+		}
+	}
+	return mode, nil
+}
+
+// recordDiskErasure adds an erasure reported by the provisioner to the
+// status of the host, dropping the oldest ones beyond maxDiskErasures. An
+// erasure already recorded is not added again.
+func recordDiskErasure(host *metal3api.BareMetalHost, erasure *metal3api.DiskErasure) {
+	if erasure == nil {
+		return
+	}
+	if n := len(host.Status.DiskErasures); n > 0 {
+		last := host.Status.DiskErasures[n-1]
+		if last.Method == erasure.Method && last.CompletedAt.Equal(&erasure.CompletedAt) {
+			return
+		}
+	}
+
+	host.Status.DiskErasures = append(host.Status.DiskErasures, *erasure)
+	if excess := len(host.Status.DiskErasures) - maxDiskErasures; excess > 0 {
+		host.Status.DiskErasures = host.Status.DiskErasures[excess:]
+	}
+}
@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func newDiskErasurePolicy(name, ns string, mode metal3api.AutomatedCleaningMode) *metal3api.HostDeployPolicy {
+	return &metal3api.HostDeployPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ns,
+		},
+		Spec: metal3api.HostDeployPolicySpec{
+			AutomatedCleaningMode: mode,
+		},
+	}
+}
+
+func TestAutomatedCleaningMode(t *testing.T) {
+	testCases := []struct {
+		Scenario string
+		HostMode metal3api.AutomatedCleaningMode
+		Policies []runtime.Object
+		Expected metal3api.AutomatedCleaningMode
+	}{
+		{
+			Scenario: "no policy",
+			HostMode: metal3api.CleaningModeMetadata,
+			Expected: metal3api.CleaningModeMetadata,
+		},
+		{
+			Scenario: "policy erasing disks",
+			HostMode: metal3api.CleaningModeMetadata,
+			Policies: []runtime.Object{
+				newDiskErasurePolicy("erase", namespace, metal3api.CleaningModeSecure),
+			},
+			Expected: metal3api.CleaningModeSecure,
+		},
+		{
+			Scenario: "policy overriding disabled cleaning",
+			HostMode: metal3api.CleaningModeDisabled,
+			Policies: []runtime.Object{
+				newDiskErasurePolicy("erase", namespace, metal3api.CleaningModeFull),
+			},
+			Expected: metal3api.CleaningModeFull,
+		},
+		{
+			Scenario: "host erasing disks",
+			HostMode: metal3api.CleaningModeCrypto,
+			Policies: []runtime.Object{
+				newDiskErasurePolicy("erase", namespace, metal3api.CleaningModeFull),
+			},
+			Expected: metal3api.CleaningModeCrypto,
+		},
+		{
+			Scenario: "first policy by name",
+			HostMode: metal3api.CleaningModeMetadata,
+			Policies: []runtime.Object{
+				newDiskErasurePolicy("b-erase", namespace, metal3api.CleaningModeFull),
+				newDiskErasurePolicy("a-erase", namespace, metal3api.CleaningModeCrypto),
+				newDiskErasurePolicy("0-deploy", namespace, ""),
+			},
+			Expected: metal3api.CleaningModeCrypto,
+		},
+		{
+			Scenario: "policy of another namespace",
+			HostMode: metal3api.CleaningModeMetadata,
+			Policies: []runtime.Object{
+				newDiskErasurePolicy("erase", "other", metal3api.CleaningModeFull),
+			},
+			Expected: metal3api.CleaningModeMetadata,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			host := newDefaultHost(t)
+			host.Spec.AutomatedCleaningMode = tc.HostMode
+			r := newTestReconciler(t, tc.Policies...)
+
+			mode, err := r.automatedCleaningMode(t.Context(), host)
+			require.NoError(t, err)
+			assert.Equal(t, tc.Expected, mode)
+		})
+	}
+}
+
+func TestRecordDiskErasure(t *testing.T) {
+	host := newDefaultHost(t)
+	start := time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC)
+	erasure := func(method metal3api.AutomatedCleaningMode, hours int) *metal3api.DiskErasure {
+		return &metal3api.DiskErasure{
+			Method:      method,
+			Steps:       []string{"erase_devices"},
+			CompletedAt: metav1.NewTime(start.Add(time.Duration(hours) * time.Hour)),
+		}
+	}
+
+	recordDiskErasure(host, nil)
+	assert.Empty(t, host.Status.DiskErasures)
+
+	for i := range maxDiskErasures {
+		recordDiskErasure(host, erasure(metal3api.CleaningModeFull, i))
+	}
+	recordDiskErasure(host, erasure(metal3api.CleaningModeSecure, maxDiskErasures))
+	// The same erasure reported again is not recorded twice.
+	recordDiskErasure(host, erasure(metal3api.CleaningModeSecure, maxDiskErasures))
+
+	require.Len(t, host.Status.DiskErasures, maxDiskErasures)
+	assert.Equal(t, *erasure(metal3api.CleaningModeSecure, maxDiskErasures), host.Status.DiskErasures[maxDiskErasures-1])
+	assert.Equal(t, *erasure(metal3api.CleaningModeFull, 1), host.Status.DiskErasures[0])
+}
@@ -0,0 +1,287 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/go-logr/logr"
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/hardwarematch"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+// FirmwareBaselineReconciler reports the compliance of the BareMetalHosts
+// with a FirmwareBaseline and optionally requests the updates needed.
+type FirmwareBaselineReconciler struct {
+	client.Client
+	Log logr.Logger
+}
+
+//+kubebuilder:rbac:groups=metal3.io,resources=firmwarebaselines,verbs=get;list;watch
+//+kubebuilder:rbac:groups=metal3.io,resources=firmwarebaselines/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=metal3.io,resources=hardwaredata,verbs=get;list;watch
+//+kubebuilder:rbac:groups=metal3.io,resources=hostfirmwarecomponents,verbs=get;list;watch;update
+//+kubebuilder:rbac:groups=metal3.io,resources=firmwareupdatecampaigns,verbs=get;list;watch
+
+// Reconcile compares the firmware components of the inspected hosts of the
+// namespace with the baseline.
+func (r *FirmwareBaselineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("firmwarebaseline", req.NamespacedName)
+
+	baseline := &metal3api.FirmwareBaseline{}
+	if err := r.Get(ctx, req.NamespacedName, baseline); err != nil {
+		if k8serrors.IsNotFound(err) {
+			firmwareNonCompliantHosts.DeletePartialMatch(prometheus.Labels{
+				labelHostNamespace: req.Namespace,
+				labelBaseline:      req.Name,
+			})
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("could not load firmwarebaseline: %w", err)
+	}
+
+	hardwareDataList := metal3api.HardwareDataList{}
+	if err := r.List(ctx, &hardwareDataList, client.InNamespace(baseline.Namespace)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list hardwaredata: %w", err)
+	}
+	hfcList := metal3api.HostFirmwareComponentsList{}
+	if err := r.List(ctx, &hfcList, client.InNamespace(baseline.Namespace)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list hostfirmwarecomponents: %w", err)
+	}
+	hfcs := make(map[string]*metal3api.HostFirmwareComponents, len(hfcList.Items))
+	for i := range hfcList.Items {
+		hfcs[hfcList.Items[i].Name] = &hfcList.Items[i]
+	}
+
+	status := metal3api.FirmwareBaselineStatus{LastUpdated: baseline.Status.LastUpdated}
+	nonCompliant := map[string]float64{}
+	for _, model := range baseline.Spec.Models {
+		for _, component := range model.Components {
+			nonCompliant[component.Component] = 0
+		}
+	}
+
+	for i := range hardwareDataList.Items {
+		hardwareData := &hardwareDataList.Items[i]
+		hfc := hfcs[hardwareData.Name]
+		model := matchFirmwareBaselineModel(baseline.Spec.Models, hardwareData.Spec.HardwareDetails)
+		if model == nil || hfc == nil {
+			continue
+		}
+
+		hostStatus := checkFirmwareCompliance(hardwareData.Name, model, hfc)
+		status.Hosts = append(status.Hosts, hostStatus)
+		if hostStatus.Compliant {
+			status.CompliantHosts++
+			continue
+		}
+		status.NonCompliantHosts++
+		for _, component := range hostStatus.Components {
+			if !component.Compliant {
+				nonCompliant[component.Component]++
+			}
+		}
+
+		if baseline.Spec.ApplyUpdates {
+			if err := r.applyFirmwareUpdates(ctx, log, model, hostStatus, hfc); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	}
+	slices.SortFunc(status.Hosts, func(a, b metal3api.FirmwareBaselineHostStatus) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	firmwareNonCompliantHosts.DeletePartialMatch(prometheus.Labels{
+		labelHostNamespace: baseline.Namespace,
+		labelBaseline:      baseline.Name,
+	})
+	for component, count := range nonCompliant {
+		firmwareNonCompliantHosts.With(prometheus.Labels{
+			labelHostNamespace: baseline.Namespace,
+			labelBaseline:      baseline.Name,
+			labelComponent:     component,
+		}).Set(count)
+	}
+
+	if equality.Semantic.DeepEqual(status, baseline.Status) {
+		return ctrl.Result{}, nil
+	}
+	now := metav1.Now()
+	status.LastUpdated = &now
+	baseline.Status = status
+	log.Info("updating status", "compliant", status.CompliantHosts, "nonCompliant", status.NonCompliantHosts)
+	if err := r.Status().Update(ctx, baseline); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update the status of the firmwarebaseline: %w", err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// matchFirmwareBaselineModel returns the first model matching the system
+// vendor of the host, or nil if there is none.
+func matchFirmwareBaselineModel(models []metal3api.FirmwareBaselineModel, details *metal3api.HardwareDetails) *metal3api.FirmwareBaselineModel {
+	if details == nil {
+		return nil
+	}
+	for i := range models {
+		requirements := metal3api.HardwareRequirements{Vendor: models[i].Vendor, Product: models[i].Product}
+		if matches, _ := hardwarematch.Match(&requirements, details); matches {
+			return &models[i]
+		}
+	}
+	return nil
+}
+
+// checkFirmwareCompliance compares the versions reported by the
+// HostFirmwareComponents of a host with the ones of a model. Components
+// that are not reported are not compliant.
+func checkFirmwareCompliance(name string, model *metal3api.FirmwareBaselineModel, hfc *metal3api.HostFirmwareComponents) metal3api.FirmwareBaselineHostStatus {
+	hostStatus := metal3api.FirmwareBaselineHostStatus{Name: name, Compliant: true}
+	for _, expected := range model.Components {
+		compliance := metal3api.FirmwareComponentCompliance{
+			Component:       expected.Component,
+			ExpectedVersion: expected.Version,
+		}
+		for _, component := range hfc.Status.Components {
+			if component.Component == expected.Component {
+				compliance.CurrentVersion = component.CurrentVersion
+				break
+			}
+		}
+
+		if compliance.CurrentVersion != "" {
+			cmp := compareFirmwareVersions(compliance.CurrentVersion, expected.Version)
+			compliance.Compliant = cmp == 0 || (cmp > 0 && expected.Match != metal3api.FirmwareVersionExact)
+		}
+		hostStatus.Compliant = hostStatus.Compliant && compliance.Compliant
+		hostStatus.Components = append(hostStatus.Components, compliance)
+	}
+	return hostStatus
+}
+
+// applyFirmwareUpdates adds the updates of the components that are not
+// compliant to the HostFirmwareComponents of the host, replacing the
+// previous updates of the same components. The hosts claimed by a
+// FirmwareUpdateCampaign are left to the campaign.
+func (r *FirmwareBaselineReconciler) applyFirmwareUpdates(ctx context.Context, log logr.Logger, model *metal3api.FirmwareBaselineModel, hostStatus metal3api.FirmwareBaselineHostStatus, hfc *metal3api.HostFirmwareComponents) error {
+	claim, err := firmwareCampaignClaim(ctx, r.Client, hfc)
+	if err != nil {
+		return err
+	}
+	if claim != "" {
+		log.Info("not requesting firmware updates for a host updated by a campaign", "host", hfc.Name, "campaign", claim)
+		return nil
+	}
+
+	var wanted []metal3api.FirmwareUpdate
+	for i, compliance := range hostStatus.Components {
+		url := model.Components[i].URL
+		if compliance.Compliant || url == "" {
+			continue
+		}
+		wanted = append(wanted, metal3api.FirmwareUpdate{Component: compliance.Component, URL: url})
+	}
+	updates := mergeFirmwareUpdates(hfc.Spec.Updates, wanted)
+	if slices.Equal(updates, hfc.Spec.Updates) {
+		return nil
+	}
+
+	log.Info("requesting firmware updates", "host", hfc.Name, "updates", updates)
+	hfc.Spec.Updates = updates
+	if err := r.Update(ctx, hfc); err != nil {
+		return fmt.Errorf("failed to update the hostfirmwarecomponents of host %s: %w", hfc.Name, err)
+	}
+	return nil
+}
+
+// compareFirmwareVersions compares two versions such as "2.19.1" or
+// "U46 v2.10", returning -1, 0 or 1. Numbers are compared by value, and
+// the other parts of the versions alphabetically.
+func compareFirmwareVersions(a, b string) int {
+	partsA, partsB := splitFirmwareVersion(a), splitFirmwareVersion(b)
+	for i := range min(len(partsA), len(partsB)) {
+		numA, errA := strconv.ParseUint(partsA[i], 10, 64)
+		numB, errB := strconv.ParseUint(partsB[i], 10, 64)
+		var cmp int
+		if errA == nil && errB == nil {
+			cmp = compareUint(numA, numB)
+		} else {
+			cmp = strings.Compare(strings.ToLower(partsA[i]), strings.ToLower(partsB[i]))
+		}
+		if cmp != 0 {
+			return cmp
+		}
+	}
+	return compareUint(uint64(len(partsA)), uint64(len(partsB)))
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// splitFirmwareVersion splits a version into runs of digits and of
+// other characters, dropping the separators.
+func splitFirmwareVersion(version string) []string {
+	var parts []string
+	fields := strings.FieldsFunc(version, func(char rune) bool {
+		return !unicode.IsDigit(char) && !unicode.IsLetter(char)
+	})
+	for _, field := range fields {
+		start, prevDigit := 0, false
+		for i, char := range field {
+			digit := unicode.IsDigit(char)
+			if i > 0 && digit != prevDigit {
+				parts = append(parts, field[start:i])
+				start = i
+			}
+			prevDigit = digit
+		}
+		parts = append(parts, field[start:])
+	}
+	return parts
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *FirmwareBaselineReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	toNamespace := handler.EnqueueRequestsFromMapFunc(namespaceMapper[metal3api.FirmwareBaselineList](r.Client, r.Log))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&metal3api.FirmwareBaseline{}).
+		Watches(&metal3api.HardwareData{}, toNamespace).
+		Watches(&metal3api.HostFirmwareComponents{}, toNamespace).
+		Watches(&metal3api.FirmwareUpdateCampaign{}, toNamespace).
+		Complete(r)
+}
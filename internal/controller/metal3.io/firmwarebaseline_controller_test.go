@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"slices"
+	"testing"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestCompareFirmwareVersions(t *testing.T) {
+	testCases := []struct {
+		A, B     string
+		Expected int
+	}{
+		{A: "2.19.1", B: "2.19.1", Expected: 0},
+		{A: "2.10.0", B: "2.9.3", Expected: 1},
+		{A: "2.9", B: "2.9.1", Expected: -1},
+		{A: "U46 v2.10", B: "U46 v2.8", Expected: 1},
+		{A: "1.2a", B: "1.2b", Expected: -1},
+		{A: "iDRAC 7.00.00.00", B: "idrac 7.00.00.00", Expected: 0},
+		{A: "20.5.13", B: "20.05.13", Expected: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.A+"/"+tc.B, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, compareFirmwareVersions(tc.A, tc.B))
+			assert.Equal(t, -tc.Expected, compareFirmwareVersions(tc.B, tc.A))
+		})
+	}
+}
+
+func newBaseline(applyUpdates bool) *metal3api.FirmwareBaseline {
+	return &metal3api.FirmwareBaseline{
+		ObjectMeta: metav1.ObjectMeta{Name: "baseline", Namespace: namespace},
+		Spec: metal3api.FirmwareBaselineSpec{
+			ApplyUpdates: applyUpdates,
+			Models: []metal3api.FirmwareBaselineModel{
+				{
+					Vendor:  "dell",
+					Product: "r640",
+					Components: []metal3api.FirmwareBaselineComponent{
+						{Component: "bios", Version: "2.19.1", URL: "http://example.com/bios-2.19.1.exe"},
+						{Component: "bmc", Version: "7.00.00.00", Match: metal3api.FirmwareVersionExact},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestFirmwareBaselineCompliance(t *testing.T) {
+	var objs []runtime.Object
+	for _, host := range []struct {
+		Name         string
+		Manufacturer string
+		Versions     map[string]string
+	}{
+		{Name: "compliant", Manufacturer: "Dell Inc.", Versions: map[string]string{"bios": "2.20.0", "bmc": "7.00.00.00"}},
+		{Name: "old-bios", Manufacturer: "Dell Inc.", Versions: map[string]string{"bios": "2.9.0", "bmc": "7.00.00.00"}},
+		{Name: "newer-bmc", Manufacturer: "Dell Inc.", Versions: map[string]string{"bios": "2.19.1", "bmc": "7.10.00.00"}},
+		{Name: "no-bmc", Manufacturer: "Dell Inc.", Versions: map[string]string{"bios": "2.19.1"}},
+		{Name: "other-vendor", Manufacturer: "HPE", Versions: map[string]string{"bios": "1.0"}},
+	} {
+		objs = append(objs, newPolicyHost(host.Name).withHardware(host.Manufacturer, "PowerEdge R640").withComponents(host.Versions).objects()...)
+	}
+
+	baseline, _, c := reconcileObject(t, newBaseline(false), objs...)
+
+	compliance := map[string]bool{}
+	for _, host := range baseline.Status.Hosts {
+		compliance[host.Name] = host.Compliant
+	}
+	assert.Equal(t, map[string]bool{
+		"compliant": true,
+		"newer-bmc": false,
+		"no-bmc":    false,
+		"old-bios":  false,
+	}, compliance)
+	assert.Equal(t, 1, baseline.Status.CompliantHosts)
+	assert.Equal(t, 3, baseline.Status.NonCompliantHosts)
+	assert.Equal(t, metal3api.FirmwareComponentCompliance{
+		Component: "bios", CurrentVersion: "2.9.0", ExpectedVersion: "2.19.1",
+	}, baseline.Status.Hosts[3].Components[0])
+
+	assert.InDelta(t, 1, testutil.ToFloat64(firmwareNonCompliantHosts.WithLabelValues(namespace, "baseline", "bios")), 0)
+	assert.InDelta(t, 2, testutil.ToFloat64(firmwareNonCompliantHosts.WithLabelValues(namespace, "baseline", "bmc")), 0)
+
+	hfc := &metal3api.HostFirmwareComponents{}
+	require.NoError(t, c.Get(t.Context(), client.ObjectKey{Name: "old-bios", Namespace: namespace}, hfc))
+	assert.Empty(t, hfc.Spec.Updates)
+}
+
+func TestFirmwareBaselineApplyUpdates(t *testing.T) {
+	campaign := newCampaign(1, 0)
+	updates := []metal3api.FirmwareUpdate{
+		{Component: "bios", URL: "http://example.com/bios-2.10.exe"},
+		{Component: "nic:1", URL: "http://example.com/nic.bin"},
+	}
+
+	testCases := []struct {
+		Scenario string
+		Claim    string
+		Objects  []runtime.Object
+		Expected []metal3api.FirmwareUpdate
+	}{
+		{
+			Scenario: "not claimed",
+			// The BMC has no URL in the baseline, so it is not updated.
+			Expected: []metal3api.FirmwareUpdate{
+				{Component: "bios", URL: "http://example.com/bios-2.19.1.exe"},
+				{Component: "nic:1", URL: "http://example.com/nic.bin"},
+			},
+		},
+		{
+			Scenario: "claimed by a campaign",
+			Claim:    campaign.Name,
+			Objects:  []runtime.Object{campaign},
+			Expected: updates,
+		},
+		{
+			Scenario: "claimed by a deleted campaign",
+			Claim:    campaign.Name,
+			Expected: []metal3api.FirmwareUpdate{
+				{Component: "bios", URL: "http://example.com/bios-2.19.1.exe"},
+				{Component: "nic:1", URL: "http://example.com/nic.bin"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			h := newPolicyHost("old-bios").withHardware("Dell Inc.", "PowerEdge R640").withComponents(map[string]string{"bios": "2.9.0", "bmc": "6.0"})
+			hfc := h.components
+			hfc.Spec.Updates = slices.Clone(updates)
+			if tc.Claim != "" {
+				hfc.Annotations = map[string]string{metal3api.FirmwareUpdateCampaignAnnotation: tc.Claim}
+			}
+
+			_, _, c := reconcileObject(t, newBaseline(true), append(h.objects(), tc.Objects...)...)
+
+			require.NoError(t, c.Get(t.Context(), client.ObjectKeyFromObject(hfc), hfc))
+			assert.Equal(t, tc.Expected, hfc.Spec.Updates)
+		})
+	}
+}
@@ -0,0 +1,385 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+// maxTemplateProblems is the number of problems listed in the message of
+// a condition of a FirmwareSettingsTemplate.
+const maxTemplateProblems = 5
+
+// FirmwareSettingsTemplateReconciler renders the settings of the
+// FirmwareSettingsTemplates into the HostFirmwareSettings of the hosts
+// they match.
+type FirmwareSettingsTemplateReconciler struct {
+	client.Client
+	Log logr.Logger
+}
+
+//+kubebuilder:rbac:groups=metal3.io,resources=firmwaresettingstemplates,verbs=get;list;watch;update
+//+kubebuilder:rbac:groups=metal3.io,resources=firmwaresettingstemplates/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=metal3.io,resources=hostfirmwaresettings,verbs=get;list;watch;update
+//+kubebuilder:rbac:groups=metal3.io,resources=firmwareschemas,verbs=get;list;watch
+//+kubebuilder:rbac:groups=metal3.io,resources=baremetalhosts,verbs=get;list;watch
+
+// Reconcile writes the valid settings of the template that do not conflict
+// with another template to the HostFirmwareSettings of the matching hosts,
+// and removes the settings it no longer renders.
+func (r *FirmwareSettingsTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("firmwaresettingstemplate", req.NamespacedName)
+
+	template := &metal3api.FirmwareSettingsTemplate{}
+	if err := r.Get(ctx, req.NamespacedName, template); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("could not load firmwaresettingstemplate: %w", err)
+	}
+
+	deleting := !template.DeletionTimestamp.IsZero()
+	if deleting && !slices.Contains(template.Finalizers, metal3api.FirmwareSettingsTemplateFinalizer) {
+		return ctrl.Result{}, nil
+	}
+	if !deleting && !slices.Contains(template.Finalizers, metal3api.FirmwareSettingsTemplateFinalizer) {
+		log.Info("adding finalizer")
+		template.Finalizers = append(template.Finalizers, metal3api.FirmwareSettingsTemplateFinalizer)
+		if err := r.Update(ctx, template); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to add finalizer: %w", err)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	templates := metal3api.FirmwareSettingsTemplateList{}
+	if err := r.List(ctx, &templates, client.InNamespace(template.Namespace)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list firmwaresettingstemplates: %w", err)
+	}
+	hosts := metal3api.BareMetalHostList{}
+	if err := r.List(ctx, &hosts, client.InNamespace(template.Namespace)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list hosts: %w", err)
+	}
+	hfsList := metal3api.HostFirmwareSettingsList{}
+	if err := r.List(ctx, &hfsList, client.InNamespace(template.Namespace)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list hostfirmwaresettings: %w", err)
+	}
+	hfsByName := make(map[string]*metal3api.HostFirmwareSettings, len(hfsList.Items))
+	for i := range hfsList.Items {
+		hfsByName[hfsList.Items[i].Name] = &hfsList.Items[i]
+	}
+
+	status := metal3api.FirmwareSettingsTemplateStatus{
+		Conditions: slices.Clone(template.Status.Conditions),
+	}
+	var invalid, conflicts, overrides []string
+	schemas := map[metal3api.SchemaReference]*metal3api.FirmwareSchema{}
+
+	for i := range hosts.Items {
+		host := &hosts.Items[i]
+		hfs := hfsByName[host.Name]
+		if hfs == nil {
+			continue
+		}
+		schema, err := r.getSchema(ctx, hfs, schemas)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		var others []*metal3api.FirmwareSettingsTemplate
+		matched := false
+		for j := range templates.Items {
+			other := &templates.Items[j]
+			if !templateMatches(other, host, schema) {
+				continue
+			}
+			if other.Name == template.Name {
+				matched = !deleting
+			} else if other.DeletionTimestamp.IsZero() {
+				others = append(others, other)
+			}
+		}
+		if matched {
+			status.Hosts = append(status.Hosts, host.Name)
+			// The settings cannot be validated before they are read from the host.
+			if len(hfs.Status.Settings) == 0 {
+				continue
+			}
+		}
+
+		rendered := renderedSettings(log, hfs)
+		previous := rendered[template.Name]
+		current := map[string]string{}
+		settings := maps.Clone(hfs.Spec.Settings)
+		if settings == nil {
+			settings = metal3api.DesiredSettingsMap{}
+		}
+		// A setting that cannot be rendered any more is still tracked, so
+		// that it is removed once it is no longer in the template.
+		keepRendered := func(name string) {
+			if value, found := previous[name]; found {
+				current[name] = value
+			}
+		}
+
+		if matched {
+			for _, name := range slices.Sorted(maps.Keys(template.Spec.Settings)) {
+				value := template.Spec.Settings[name]
+				if other := conflictingTemplate(others, name, value.String()); other != "" {
+					conflicts = append(conflicts, fmt.Sprintf("%s: setting %s has a different value in template %s", host.Name, name, other))
+					keepRendered(name)
+					continue
+				}
+
+				previousValue, tracked := previous[name]
+				if set, found := settings[name]; found && set.String() != value.String() {
+					if !tracked || set.String() != previousValue {
+						overrides = append(overrides, fmt.Sprintf("%s: setting %s is overridden by the HostFirmwareSettings", host.Name, name))
+						continue
+					}
+				} else if found && !tracked {
+					// The value was not set by the template.
+					continue
+				}
+
+				candidate := &metal3api.HostFirmwareSettings{
+					Spec: metal3api.HostFirmwareSettingsSpec{Settings: metal3api.DesiredSettingsMap{name: value}},
+				}
+				if errs := validateHostFirmwareSettings(candidate, &hfs.Status, schema); len(errs) > 0 {
+					for _, err := range errs {
+						invalid = append(invalid, fmt.Sprintf("%s: %s", host.Name, err))
+					}
+					keepRendered(name)
+					continue
+				}
+				settings[name] = value
+				current[name] = value.String()
+			}
+		}
+
+		// The settings no longer rendered are removed unless they were
+		// changed since, or another template renders them too.
+		for name, value := range previous {
+			if _, found := current[name]; found {
+				continue
+			}
+			if set, found := settings[name]; found && set.String() == value && !renderedByOtherTemplate(rendered, template.Name, name) {
+				delete(settings, name)
+			}
+		}
+
+		if len(current) > 0 {
+			rendered[template.Name] = current
+		} else {
+			delete(rendered, template.Name)
+		}
+		annotationChanged, err := setRenderedSettings(hfs, rendered)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		if !annotationChanged && equality.Semantic.DeepEqual(settings, hfs.Spec.Settings) {
+			continue
+		}
+		log.Info("rendering firmware settings", "host", host.Name)
+		hfs.Spec.Settings = settings
+		if err := r.Update(ctx, hfs); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to update the hostfirmwaresettings of host %s: %w", host.Name, err)
+		}
+	}
+
+	if deleting {
+		log.Info("removing finalizer")
+		controllerutil.RemoveFinalizer(template, metal3api.FirmwareSettingsTemplateFinalizer)
+		if err := r.Update(ctx, template); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to remove finalizer: %w", err)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	setTemplateCondition(&status, template, metal3api.SettingsValidCondition,
+		metal3api.InvalidSettingsReason, invalid)
+	conflictsReason := metal3api.ConflictingTemplatesReason
+	if len(conflicts) == 0 {
+		conflictsReason = metal3api.OverriddenSettingsReason
+	}
+	setTemplateCondition(&status, template, metal3api.NoConflictsCondition,
+		conflictsReason, append(conflicts, overrides...))
+
+	if equality.Semantic.DeepEqual(status, template.Status) {
+		return ctrl.Result{}, nil
+	}
+	template.Status = status
+	log.Info("updating status", "hosts", len(status.Hosts), "invalid", len(invalid),
+		"conflicts", len(conflicts), "overrides", len(overrides))
+	if err := r.Status().Update(ctx, template); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update the status of the firmwaresettingstemplate: %w", err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// renderedSettings returns the settings rendered by each template into
+// the HostFirmwareSettings. An annotation that cannot be parsed is
+// ignored.
+func renderedSettings(log logr.Logger, hfs *metal3api.HostFirmwareSettings) map[string]map[string]string {
+	rendered := map[string]map[string]string{}
+	value, found := hfs.Annotations[metal3api.RenderedSettingsAnnotation]
+	if !found {
+		return rendered
+	}
+	if err := json.Unmarshal([]byte(value), &rendered); err != nil {
+		log.Info("ignoring invalid rendered settings annotation", "host", hfs.Name, "error", err.Error())
+		return map[string]map[string]string{}
+	}
+	return rendered
+}
+
+// setRenderedSettings records the rendered settings in the annotation of
+// the HostFirmwareSettings and tells whether it changed.
+func setRenderedSettings(hfs *metal3api.HostFirmwareSettings, rendered map[string]map[string]string) (bool, error) {
+	old, found := hfs.Annotations[metal3api.RenderedSettingsAnnotation]
+	if len(rendered) == 0 {
+		delete(hfs.Annotations, metal3api.RenderedSettingsAnnotation)
+		return found, nil
+	}
+	value, err := json.Marshal(rendered)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal the rendered settings of host %s: %w", hfs.Name, err)
+	}
+	if found && old == string(value) {
+		return false, nil
+	}
+	if hfs.Annotations == nil {
+		hfs.Annotations = map[string]string{}
+	}
+	hfs.Annotations[metal3api.RenderedSettingsAnnotation] = string(value)
+	return true, nil
+}
+
+// renderedByOtherTemplate tells whether another template rendered the
+// setting.
+func renderedByOtherTemplate(rendered map[string]map[string]string, template, name string) bool {
+	for other, settings := range rendered {
+		if _, found := settings[name]; found && other != template {
+			return true
+		}
+	}
+	return false
+}
+
+// getSchema returns the FirmwareSchema of the HostFirmwareSettings, or nil
+// if it does not have one yet.
+func (r *FirmwareSettingsTemplateReconciler) getSchema(ctx context.Context, hfs *metal3api.HostFirmwareSettings, schemas map[metal3api.SchemaReference]*metal3api.FirmwareSchema) (*metal3api.FirmwareSchema, error) {
+	ref := hfs.Status.FirmwareSchema
+	if ref == nil {
+		return nil, nil
+	}
+	if schema, found := schemas[*ref]; found {
+		return schema, nil
+	}
+
+	schema := &metal3api.FirmwareSchema{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, schema); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return nil, fmt.Errorf("could not load firmwareschema %s: %w", ref.Name, err)
+		}
+		schema = nil
+	}
+	schemas[*ref] = schema
+	return schema, nil
+}
+
+// templateMatches tells whether a template applies to a host. The vendor
+// and the model can only match once the FirmwareSchema of the host is
+// known.
+func templateMatches(template *metal3api.FirmwareSettingsTemplate, host *metal3api.BareMetalHost, schema *metal3api.FirmwareSchema) bool {
+	if template.Spec.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(template.Spec.Selector)
+		if err != nil || !selector.Matches(labels.Set(host.Labels)) {
+			return false
+		}
+	}
+	if template.Spec.Vendor == "" && template.Spec.Model == "" {
+		return true
+	}
+	if schema == nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(schema.Spec.HardwareVendor), strings.ToLower(template.Spec.Vendor)) &&
+		strings.Contains(strings.ToLower(schema.Spec.HardwareModel), strings.ToLower(template.Spec.Model))
+}
+
+// conflictingTemplate returns the name of the first template setting a
+// different value for the setting, or an empty string.
+func conflictingTemplate(templates []*metal3api.FirmwareSettingsTemplate, name, value string) string {
+	for _, template := range templates {
+		if other, found := template.Spec.Settings[name]; found && other.String() != value {
+			return template.Name
+		}
+	}
+	return ""
+}
+
+// setTemplateCondition sets a condition that is true when there are no
+// problems, and lists the first problems otherwise.
+func setTemplateCondition(status *metal3api.FirmwareSettingsTemplateStatus, template *metal3api.FirmwareSettingsTemplate, conditionType, reason string, problems []string) {
+	condition := metav1.Condition{
+		Type:               conditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             metal3api.TemplateRenderedReason,
+		ObservedGeneration: template.Generation,
+	}
+	if len(problems) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reason
+		condition.Message = strings.Join(problems[:min(len(problems), maxTemplateProblems)], "; ")
+		if len(problems) > maxTemplateProblems {
+			condition.Message += fmt.Sprintf("; and %d more", len(problems)-maxTemplateProblems)
+		}
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
+
+// SetupWithManager sets up the controller with the Manager. A change to a
+// template may also change the conflicts of the other templates of its
+// namespace.
+func (r *FirmwareSettingsTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	toNamespace := handler.EnqueueRequestsFromMapFunc(namespaceMapper[metal3api.FirmwareSettingsTemplateList](r.Client, r.Log))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&metal3api.FirmwareSettingsTemplate{}).
+		Watches(&metal3api.FirmwareSettingsTemplate{}, toNamespace).
+		Watches(&metal3api.BareMetalHost{}, toNamespace).
+		Watches(&metal3api.HostFirmwareSettings{}, toNamespace).
+		Complete(r)
+}
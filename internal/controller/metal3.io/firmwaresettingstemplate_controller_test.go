@@ -0,0 +1,332 @@
+package controllers
+
+import (
+	"testing"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newTemplateSchema(name, vendor, model string) *metal3api.FirmwareSchema {
+	return &metal3api.FirmwareSchema{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: metal3api.FirmwareSchemaSpec{
+			HardwareVendor: vendor,
+			HardwareModel:  model,
+			Schema: map[string]metal3api.SettingSchema{
+				"ProcVirtualization": {AttributeType: "Enumeration", AllowableValues: []string{"Enabled", "Disabled"}},
+				"BootMode":           {AttributeType: "Enumeration", AllowableValues: []string{"Uefi", "Bios"}},
+			},
+		},
+	}
+}
+
+func newTemplateHost(name, schema string, hostLabels map[string]string) []runtime.Object {
+	h := newPolicyHost(name).withSettings(schema, metal3api.SettingsMap{
+		"ProcVirtualization": "Disabled",
+		"BootMode":           "Bios",
+		"AssetTag":           "",
+	})
+	h.host.Labels = hostLabels
+	h.settings.Spec.Settings = metal3api.DesiredSettingsMap{"AssetTag": intstr.FromString(name)}
+	return h.objects()
+}
+
+func newSettingsTemplate(name string, settings map[string]string) *metal3api.FirmwareSettingsTemplate {
+	template := &metal3api.FirmwareSettingsTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       name,
+			Namespace:  namespace,
+			Finalizers: []string{metal3api.FirmwareSettingsTemplateFinalizer},
+		},
+		Spec: metal3api.FirmwareSettingsTemplateSpec{Settings: metal3api.DesiredSettingsMap{}},
+	}
+	for setting, value := range settings {
+		template.Spec.Settings[setting] = intstr.FromString(value)
+	}
+	return template
+}
+
+func getRenderedSettings(t *testing.T, c client.Client, name string) metal3api.DesiredSettingsMap {
+	t.Helper()
+	hfs := &metal3api.HostFirmwareSettings{}
+	require.NoError(t, c.Get(t.Context(), client.ObjectKey{Name: name, Namespace: namespace}, hfs))
+	return hfs.Spec.Settings
+}
+
+func TestFirmwareSettingsTemplateMatching(t *testing.T) {
+	testCases := []struct {
+		Scenario string
+		Selector *metav1.LabelSelector
+		Vendor   string
+		Model    string
+		Expected []string
+	}{
+		{
+			Scenario: "all hosts",
+			Expected: []string{"dell-compute", "dell-storage", "hpe-compute"},
+		},
+		{
+			Scenario: "label",
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"role": "compute"}},
+			Expected: []string{"dell-compute", "hpe-compute"},
+		},
+		{
+			Scenario: "vendor and model",
+			Vendor:   "dell",
+			Model:    "r640",
+			Expected: []string{"dell-compute", "dell-storage"},
+		},
+		{
+			Scenario: "label and vendor",
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"role": "compute"}},
+			Vendor:   "HPE",
+			Expected: []string{"hpe-compute"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			objs := []runtime.Object{
+				newTemplateSchema("schema-dell", "Dell Inc.", "PowerEdge R640"),
+				newTemplateSchema("schema-hpe", "HPE", "ProLiant DL360"),
+			}
+			objs = append(objs, newTemplateHost("dell-compute", "schema-dell", map[string]string{"role": "compute"})...)
+			objs = append(objs, newTemplateHost("dell-storage", "schema-dell", map[string]string{"role": "storage"})...)
+			objs = append(objs, newTemplateHost("hpe-compute", "schema-hpe", map[string]string{"role": "compute"})...)
+
+			template := newSettingsTemplate("template", map[string]string{"ProcVirtualization": "Enabled"})
+			template.Spec.Selector = tc.Selector
+			template.Spec.Vendor = tc.Vendor
+			template.Spec.Model = tc.Model
+
+			template, _, c := reconcileObject(t, template, objs...)
+
+			assert.Equal(t, tc.Expected, template.Status.Hosts)
+			for _, name := range []string{"dell-compute", "dell-storage", "hpe-compute"} {
+				settings := getRenderedSettings(t, c, name)
+				assert.Equal(t, intstr.FromString(name), settings["AssetTag"])
+				if _, rendered := settings["ProcVirtualization"]; rendered {
+					assert.Contains(t, tc.Expected, name)
+				} else {
+					assert.NotContains(t, tc.Expected, name)
+				}
+			}
+			assert.True(t, meta.IsStatusConditionTrue(template.Status.Conditions, metal3api.SettingsValidCondition))
+			assert.True(t, meta.IsStatusConditionTrue(template.Status.Conditions, metal3api.NoConflictsCondition))
+		})
+	}
+}
+
+func TestFirmwareSettingsTemplateInvalidSettings(t *testing.T) {
+	objs := []runtime.Object{newTemplateSchema("schema", "Dell Inc.", "PowerEdge R640")}
+	objs = append(objs, newTemplateHost("host", "schema", nil)...)
+	template := newSettingsTemplate("template", map[string]string{
+		"ProcVirtualization": "Enabled",
+		"BootMode":           "Legacy",
+		"NoSuchSetting":      "1",
+	})
+
+	template, _, c := reconcileObject(t, template, objs...)
+
+	settings := getRenderedSettings(t, c, "host")
+	assert.Equal(t, intstr.FromString("Enabled"), settings["ProcVirtualization"])
+	assert.NotContains(t, settings, "BootMode")
+	assert.NotContains(t, settings, "NoSuchSetting")
+
+	cond := meta.FindStatusCondition(template.Status.Conditions, metal3api.SettingsValidCondition)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, metal3api.InvalidSettingsReason, cond.Reason)
+	assert.Equal(t, "host: Setting BootMode is invalid, unknown enumeration value - Legacy; "+
+		"host: setting NoSuchSetting is not in the Status field", cond.Message)
+}
+
+func TestFirmwareSettingsTemplateConflicts(t *testing.T) {
+	objs := []runtime.Object{newTemplateSchema("schema", "Dell Inc.", "PowerEdge R640")}
+	objs = append(objs, newTemplateHost("host", "schema", nil)...)
+	objs = append(objs, newSettingsTemplate("other", map[string]string{
+		"ProcVirtualization": "Disabled",
+		"BootMode":           "Uefi",
+	}))
+	template := newSettingsTemplate("template", map[string]string{
+		"ProcVirtualization": "Enabled",
+		"BootMode":           "Uefi",
+	})
+
+	template, _, c := reconcileObject(t, template, objs...)
+
+	settings := getRenderedSettings(t, c, "host")
+	assert.Equal(t, intstr.FromString("Uefi"), settings["BootMode"])
+	assert.NotContains(t, settings, "ProcVirtualization")
+
+	cond := meta.FindStatusCondition(template.Status.Conditions, metal3api.NoConflictsCondition)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, metal3api.ConflictingTemplatesReason, cond.Reason)
+	assert.Equal(t, "host: setting ProcVirtualization has a different value in template other", cond.Message)
+	assert.True(t, meta.IsStatusConditionTrue(template.Status.Conditions, metal3api.SettingsValidCondition))
+}
+
+func TestFirmwareSettingsTemplateAddsFinalizer(t *testing.T) {
+	objs := []runtime.Object{newTemplateSchema("schema", "Dell Inc.", "PowerEdge R640")}
+	objs = append(objs, newTemplateHost("host", "schema", nil)...)
+	template := newSettingsTemplate("template", map[string]string{"ProcVirtualization": "Enabled"})
+	template.Finalizers = nil
+
+	template, _, c := reconcileObject(t, template, objs...)
+
+	assert.Equal(t, []string{metal3api.FirmwareSettingsTemplateFinalizer}, template.Finalizers)
+	assert.NotContains(t, getRenderedSettings(t, c, "host"), "ProcVirtualization")
+
+	reconcileAgain(t, c, template)
+	assert.Equal(t, intstr.FromString("Enabled"), getRenderedSettings(t, c, "host")["ProcVirtualization"])
+}
+
+func TestFirmwareSettingsTemplateRenderedSettings(t *testing.T) {
+	testCases := []struct {
+		Scenario         string
+		Template         map[string]string
+		Selector         *metav1.LabelSelector
+		Settings         map[string]string
+		Rendered         string
+		ExpectedSettings map[string]string
+		ExpectedRendered string
+		ExpectedReason   string
+	}{
+		{
+			Scenario:         "setting rendered",
+			Template:         map[string]string{"ProcVirtualization": "Enabled"},
+			ExpectedSettings: map[string]string{"ProcVirtualization": "Enabled"},
+			ExpectedRendered: `{"template":{"ProcVirtualization":"Enabled"}}`,
+			ExpectedReason:   metal3api.TemplateRenderedReason,
+		},
+		{
+			Scenario:         "setting removed from the template",
+			Template:         map[string]string{"ProcVirtualization": "Enabled"},
+			Settings:         map[string]string{"ProcVirtualization": "Enabled", "BootMode": "Uefi"},
+			Rendered:         `{"template":{"BootMode":"Uefi","ProcVirtualization":"Enabled"}}`,
+			ExpectedSettings: map[string]string{"ProcVirtualization": "Enabled"},
+			ExpectedRendered: `{"template":{"ProcVirtualization":"Enabled"}}`,
+			ExpectedReason:   metal3api.TemplateRenderedReason,
+		},
+		{
+			Scenario:         "removed setting changed on the host",
+			Template:         map[string]string{"ProcVirtualization": "Enabled"},
+			Settings:         map[string]string{"ProcVirtualization": "Enabled", "BootMode": "Bios"},
+			Rendered:         `{"template":{"BootMode":"Uefi","ProcVirtualization":"Enabled"}}`,
+			ExpectedSettings: map[string]string{"ProcVirtualization": "Enabled", "BootMode": "Bios"},
+			ExpectedRendered: `{"template":{"ProcVirtualization":"Enabled"}}`,
+			ExpectedReason:   metal3api.TemplateRenderedReason,
+		},
+		{
+			Scenario:         "removed setting rendered by another template",
+			Template:         map[string]string{"ProcVirtualization": "Enabled"},
+			Settings:         map[string]string{"ProcVirtualization": "Enabled", "BootMode": "Uefi"},
+			Rendered:         `{"other":{"BootMode":"Uefi"},"template":{"BootMode":"Uefi","ProcVirtualization":"Enabled"}}`,
+			ExpectedSettings: map[string]string{"ProcVirtualization": "Enabled", "BootMode": "Uefi"},
+			ExpectedRendered: `{"other":{"BootMode":"Uefi"},"template":{"ProcVirtualization":"Enabled"}}`,
+			ExpectedReason:   metal3api.TemplateRenderedReason,
+		},
+		{
+			Scenario:         "host no longer matching",
+			Template:         map[string]string{"ProcVirtualization": "Enabled"},
+			Selector:         &metav1.LabelSelector{MatchLabels: map[string]string{"role": "storage"}},
+			Settings:         map[string]string{"ProcVirtualization": "Enabled"},
+			Rendered:         `{"template":{"ProcVirtualization":"Enabled"}}`,
+			ExpectedSettings: map[string]string{},
+			ExpectedReason:   metal3api.TemplateRenderedReason,
+		},
+		{
+			Scenario:         "template value changed",
+			Template:         map[string]string{"ProcVirtualization": "Enabled"},
+			Settings:         map[string]string{"ProcVirtualization": "Disabled"},
+			Rendered:         `{"template":{"ProcVirtualization":"Disabled"}}`,
+			ExpectedSettings: map[string]string{"ProcVirtualization": "Enabled"},
+			ExpectedRendered: `{"template":{"ProcVirtualization":"Enabled"}}`,
+			ExpectedReason:   metal3api.TemplateRenderedReason,
+		},
+		{
+			Scenario:         "overridden on the host",
+			Template:         map[string]string{"ProcVirtualization": "Enabled"},
+			Settings:         map[string]string{"ProcVirtualization": "Disabled"},
+			ExpectedSettings: map[string]string{"ProcVirtualization": "Disabled"},
+			ExpectedReason:   metal3api.OverriddenSettingsReason,
+		},
+		{
+			Scenario:         "changed on the host after rendering",
+			Template:         map[string]string{"ProcVirtualization": "Enabled"},
+			Settings:         map[string]string{"ProcVirtualization": "Disabled"},
+			Rendered:         `{"template":{"ProcVirtualization":"Enabled"}}`,
+			ExpectedSettings: map[string]string{"ProcVirtualization": "Disabled"},
+			ExpectedReason:   metal3api.OverriddenSettingsReason,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			objs := []runtime.Object{newTemplateSchema("schema", "Dell Inc.", "PowerEdge R640")}
+			objs = append(objs, newTemplateHost("host", "schema", nil)...)
+			hfs := objs[2].(*metal3api.HostFirmwareSettings)
+			hfs.Spec.Settings = metal3api.DesiredSettingsMap{}
+			for name, value := range tc.Settings {
+				hfs.Spec.Settings[name] = intstr.FromString(value)
+			}
+			if tc.Rendered != "" {
+				hfs.Annotations = map[string]string{metal3api.RenderedSettingsAnnotation: tc.Rendered}
+			}
+			template := newSettingsTemplate("template", tc.Template)
+			template.Spec.Selector = tc.Selector
+
+			template, _, c := reconcileObject(t, template, objs...)
+
+			require.NoError(t, c.Get(t.Context(), client.ObjectKeyFromObject(hfs), hfs))
+			settings := map[string]string{}
+			for name, value := range hfs.Spec.Settings {
+				settings[name] = value.String()
+			}
+			assert.Equal(t, tc.ExpectedSettings, settings)
+			assert.Equal(t, tc.ExpectedRendered, hfs.Annotations[metal3api.RenderedSettingsAnnotation])
+			cond := meta.FindStatusCondition(template.Status.Conditions, metal3api.NoConflictsCondition)
+			require.NotNil(t, cond)
+			assert.Equal(t, tc.ExpectedReason, cond.Reason)
+		})
+	}
+}
+
+func TestFirmwareSettingsTemplateDeleted(t *testing.T) {
+	objs := []runtime.Object{newTemplateSchema("schema", "Dell Inc.", "PowerEdge R640")}
+	objs = append(objs, newTemplateHost("host", "schema", nil)...)
+	hfs := objs[2].(*metal3api.HostFirmwareSettings)
+	hfs.Spec.Settings["ProcVirtualization"] = intstr.FromString("Enabled")
+	hfs.Spec.Settings["BootMode"] = intstr.FromString("Bios")
+	hfs.Annotations = map[string]string{
+		metal3api.RenderedSettingsAnnotation: `{"template":{"BootMode":"Uefi","ProcVirtualization":"Enabled"}}`,
+	}
+	template := newSettingsTemplate("template", map[string]string{"ProcVirtualization": "Enabled", "BootMode": "Uefi"})
+	now := metav1.Now()
+	template.DeletionTimestamp = &now
+
+	c := newPolicyClient(append(objs, template)...)
+	r := newPolicyReconciler(t, c, template)
+	_, err := r.Reconcile(t.Context(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(template)})
+	require.NoError(t, err)
+
+	// The setting changed on the host is kept.
+	assert.Equal(t, metal3api.DesiredSettingsMap{
+		"AssetTag": intstr.FromString("host"),
+		"BootMode": intstr.FromString("Bios"),
+	}, getRenderedSettings(t, c, "host"))
+	require.NoError(t, c.Get(t.Context(), client.ObjectKeyFromObject(hfs), hfs))
+	assert.NotContains(t, hfs.Annotations, metal3api.RenderedSettingsAnnotation)
+	err = c.Get(t.Context(), client.ObjectKeyFromObject(template), template)
+	assert.True(t, k8serrors.IsNotFound(err))
+}
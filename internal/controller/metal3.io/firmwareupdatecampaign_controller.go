@@ -0,0 +1,477 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/maintenancewindow"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+// FirmwareUpdateCampaignReconciler rolls the firmware updates of a
+// FirmwareUpdateCampaign out to the selected BareMetalHosts in waves.
+type FirmwareUpdateCampaignReconciler struct {
+	client.Client
+	Log logr.Logger
+}
+
+// campaignHost is a host selected by a campaign with its progress.
+type campaignHost struct {
+	host   *metal3api.BareMetalHost
+	hfc    *metal3api.HostFirmwareComponents
+	status metal3api.FirmwareUpdateCampaignHostStatus
+	// ready tells whether a pending host can start updating.
+	ready bool
+	// needsReboot tells whether an updating host has not been rebooted
+	// into servicing yet.
+	needsReboot bool
+	// retryAfter is when to check again whether a host waiting for its
+	// HostUpdatePolicy may be rebooted, never when zero.
+	retryAfter time.Duration
+}
+
+// campaignRetryDelay is how often a host waiting for a servicing slot of
+// its group is checked.
+const campaignRetryDelay = time.Minute
+
+//+kubebuilder:rbac:groups=metal3.io,resources=firmwareupdatecampaigns,verbs=get;list;watch
+//+kubebuilder:rbac:groups=metal3.io,resources=firmwareupdatecampaigns/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=metal3.io,resources=baremetalhosts,verbs=get;list;watch;update
+//+kubebuilder:rbac:groups=metal3.io,resources=hostfirmwarecomponents,verbs=get;list;watch;update
+//+kubebuilder:rbac:groups=metal3.io,resources=hostupdatepolicies,verbs=get;list;watch
+
+// Reconcile reports the progress of the hosts of the campaign and starts
+// the next wave once the previous one is over.
+func (r *FirmwareUpdateCampaignReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("firmwareupdatecampaign", req.NamespacedName)
+
+	campaign := &metal3api.FirmwareUpdateCampaign{}
+	if err := r.Get(ctx, req.NamespacedName, campaign); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("could not load firmwareupdatecampaign: %w", err)
+	}
+
+	status := metal3api.FirmwareUpdateCampaignStatus{
+		Conditions: slices.Clone(campaign.Status.Conditions),
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&campaign.Spec.Selector)
+	if err == nil {
+		err = (&metal3api.HostFirmwareComponents{
+			Spec: metal3api.HostFirmwareComponentsSpec{Updates: campaign.Spec.Updates},
+		}).ValidateHostFirmwareComponents()
+	}
+	if err != nil {
+		setCampaignCondition(&status, campaign, metal3api.FirmwareUpdateCampaignPausedCondition,
+			metav1.ConditionTrue, metal3api.InvalidCampaignReason, err.Error())
+		setCampaignCondition(&status, campaign, metal3api.FirmwareUpdateCampaignCompletedCondition,
+			metav1.ConditionFalse, metal3api.InvalidCampaignReason, err.Error())
+		return ctrl.Result{}, r.updateCampaignStatus(ctx, log, campaign, status)
+	}
+
+	hosts, err := r.campaignHosts(ctx, campaign, selector)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	for _, host := range hosts {
+		switch host.status.Phase {
+		case metal3api.FirmwareUpdateCampaignHostPending:
+			status.Pending++
+		case metal3api.FirmwareUpdateCampaignHostUpdating:
+			status.Updating++
+		case metal3api.FirmwareUpdateCampaignHostUpdated:
+			status.Updated++
+		case metal3api.FirmwareUpdateCampaignHostFailed:
+			status.Failed++
+		}
+	}
+
+	var pausedReason, pausedMessage string
+	switch {
+	case campaign.Spec.Paused:
+		pausedReason, pausedMessage = metal3api.PausedByUserReason, "the campaign is paused in its spec"
+	case status.Failed > campaign.Spec.MaxFailures:
+		pausedReason = metal3api.FailureBudgetExceededReason
+		pausedMessage = fmt.Sprintf("%d hosts are in a servicing error, the failure budget is %d",
+			status.Failed, campaign.Spec.MaxFailures)
+	}
+
+	// The next wave starts when no host of the previous one is updating.
+	if pausedReason == "" && status.Updating == 0 {
+		batchSize := max(campaign.Spec.BatchSize, 1)
+		for _, host := range hosts {
+			if status.Updating >= batchSize {
+				break
+			}
+			if host.status.Phase != metal3api.FirmwareUpdateCampaignHostPending || !host.ready {
+				continue
+			}
+			log.Info("starting firmware updates", "host", host.host.Name)
+			if err := r.startHostUpdate(ctx, campaign, host); err != nil {
+				return ctrl.Result{}, err
+			}
+			host.status.Phase = metal3api.FirmwareUpdateCampaignHostUpdating
+			status.Pending--
+			status.Updating++
+		}
+	}
+
+	result := ctrl.Result{}
+	for _, host := range hosts {
+		if host.status.Phase == metal3api.FirmwareUpdateCampaignHostUpdated &&
+			host.hfc.Annotations[metal3api.FirmwareUpdateCampaignAnnotation] == campaign.Name {
+			log.Info("releasing updated host", "host", host.host.Name)
+			if err := r.releaseHost(ctx, host); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		// The host was not annotated after its updates were written.
+		if host.needsReboot {
+			log.Info("rebooting host into servicing", "host", host.host.Name)
+			if err := r.rebootHost(ctx, host.host); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		status.Hosts = append(status.Hosts, host.status)
+		if host.retryAfter > 0 && (result.RequeueAfter == 0 || host.retryAfter < result.RequeueAfter) {
+			result.RequeueAfter = host.retryAfter
+		}
+	}
+
+	if pausedReason != "" {
+		setCampaignCondition(&status, campaign, metal3api.FirmwareUpdateCampaignPausedCondition,
+			metav1.ConditionTrue, pausedReason, pausedMessage)
+	} else {
+		setCampaignCondition(&status, campaign, metal3api.FirmwareUpdateCampaignPausedCondition,
+			metav1.ConditionFalse, metal3api.RollingOutReason, "")
+	}
+	if len(hosts) > 0 && status.Updated == len(hosts) {
+		setCampaignCondition(&status, campaign, metal3api.FirmwareUpdateCampaignCompletedCondition,
+			metav1.ConditionTrue, metal3api.AllHostsUpdatedReason, "")
+	} else {
+		setCampaignCondition(&status, campaign, metal3api.FirmwareUpdateCampaignCompletedCondition,
+			metav1.ConditionFalse, metal3api.RollingOutReason,
+			fmt.Sprintf("%d of %d hosts updated", status.Updated, len(hosts)))
+	}
+
+	return result, r.updateCampaignStatus(ctx, log, campaign, status)
+}
+
+// campaignHosts returns the hosts selected by the campaign, sorted by name,
+// with their progress.
+func (r *FirmwareUpdateCampaignReconciler) campaignHosts(ctx context.Context, campaign *metal3api.FirmwareUpdateCampaign, selector labels.Selector) ([]*campaignHost, error) {
+	hostList := metal3api.BareMetalHostList{}
+	if err := r.List(ctx, &hostList, client.InNamespace(campaign.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("failed to list hosts: %w", err)
+	}
+	slices.SortFunc(hostList.Items, func(a, b metal3api.BareMetalHost) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	hosts := make([]*campaignHost, 0, len(hostList.Items))
+	for i := range hostList.Items {
+		host := &campaignHost{host: &hostList.Items[i]}
+		if err := r.hostProgress(ctx, campaign, host); err != nil {
+			return nil, err
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts, nil
+}
+
+// hostProgress finds the phase of a host in the campaign from its
+// HostFirmwareComponents and its operational status.
+func (r *FirmwareUpdateCampaignReconciler) hostProgress(ctx context.Context, campaign *metal3api.FirmwareUpdateCampaign, host *campaignHost) error {
+	host.status = metal3api.FirmwareUpdateCampaignHostStatus{
+		Name:  host.host.Name,
+		Phase: metal3api.FirmwareUpdateCampaignHostPending,
+	}
+
+	hfc := &metal3api.HostFirmwareComponents{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(host.host), hfc); err != nil {
+		if k8serrors.IsNotFound(err) {
+			host.status.Message = "waiting for the HostFirmwareComponents of the host"
+			return nil
+		}
+		return fmt.Errorf("could not load the hostfirmwarecomponents of host %s: %w", host.host.Name, err)
+	}
+	host.hfc = hfc
+
+	for _, component := range hfc.Status.Components {
+		if slices.ContainsFunc(campaign.Spec.Updates, func(update metal3api.FirmwareUpdate) bool {
+			return update.Component == component.Component
+		}) {
+			host.status.Components = append(host.status.Components, component)
+		}
+	}
+
+	if !containsFirmwareUpdates(hfc.Status.Updates, campaign.Spec.Updates) {
+		claim, err := firmwareCampaignClaim(ctx, r.Client, hfc)
+		if err != nil {
+			return err
+		}
+		if claim != "" && claim != campaign.Name {
+			host.status.Message = fmt.Sprintf("the host is being updated by FirmwareUpdateCampaign %s", claim)
+			return nil
+		}
+	}
+
+	if !containsFirmwareUpdates(hfc.Spec.Updates, campaign.Spec.Updates) {
+		message, err := r.hostNotReadyMessage(ctx, host.host)
+		if err != nil {
+			return err
+		}
+		host.status.Message = message
+		host.ready = message == ""
+		return nil
+	}
+
+	switch {
+	case host.host.Status.ErrorType == metal3api.ServicingError:
+		host.status.Phase = metal3api.FirmwareUpdateCampaignHostFailed
+		host.status.Message = host.host.Status.ErrorMessage
+	case containsFirmwareUpdates(hfc.Status.Updates, campaign.Spec.Updates) &&
+		host.host.Status.OperationalStatus != metal3api.OperationalStatusServicing:
+		host.status.Phase = metal3api.FirmwareUpdateCampaignHostUpdated
+	default:
+		host.status.Phase = metal3api.FirmwareUpdateCampaignHostUpdating
+		if cond := meta.FindStatusCondition(host.host.Status.Conditions, metal3api.ServicingScheduledCondition); cond != nil {
+			host.status.Message = cond.Message
+			break
+		}
+		_, rebooting := host.host.Annotations[metal3api.RebootAnnotationPrefix]
+		if rebooting || !host.host.Status.PoweredOn ||
+			host.host.Status.OperationalStatus != metal3api.OperationalStatusOK ||
+			containsFirmwareUpdates(hfc.Status.Updates, campaign.Spec.Updates) {
+			break
+		}
+		message, retryAfter, err := r.rebootHeldMessage(ctx, host.host)
+		if err != nil {
+			return err
+		}
+		host.status.Message = message
+		host.retryAfter = retryAfter
+		host.needsReboot = message == ""
+	}
+	return nil
+}
+
+// rebootHeldMessage explains why the HostUpdatePolicy of an updating host
+// would hold its servicing, so that the host is not rebooted before it may
+// start. The host is then checked again after the returned delay.
+func (r *FirmwareUpdateCampaignReconciler) rebootHeldMessage(ctx context.Context, host *metal3api.BareMetalHost) (string, time.Duration, error) {
+	hup := &metal3api.HostUpdatePolicy{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(host), hup); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return "no HostUpdatePolicy allows firmware updates on reboot", 0, nil
+		}
+		return "", 0, fmt.Errorf("could not load the hostupdatepolicy of host %s: %w", host.Name, err)
+	}
+
+	now := time.Now()
+	open, nextOpen, err := maintenancewindow.Status(hup.Spec.MaintenanceWindows, now)
+	if err != nil {
+		return err.Error(), 0, nil
+	}
+	if !open {
+		if nextOpen.IsZero() {
+			return "no maintenance window will open", 0, nil
+		}
+		return "waiting for the maintenance window opening at " + nextOpen.UTC().Format(time.RFC3339),
+			nextOpen.Sub(now), nil
+	}
+
+	if hup.Spec.MaxConcurrentServicing == nil {
+		return "", 0, nil
+	}
+	slots, err := servicingSlots(host, hup)
+	if err != nil {
+		return "", 0, err
+	}
+	servicing, err := slots.taken(ctx, r.Client, host, now, now)
+	if err != nil {
+		return "", 0, err
+	}
+	if servicing >= int(*hup.Spec.MaxConcurrentServicing) {
+		return fmt.Sprintf("waiting for one of the %d servicing slots of the group", servicing), campaignRetryDelay, nil
+	}
+	return "", 0, nil
+}
+
+// hostNotReadyMessage explains why the servicing of a host would not
+// apply the updates, or returns an empty string if it would.
+func (r *FirmwareUpdateCampaignReconciler) hostNotReadyMessage(ctx context.Context, host *metal3api.BareMetalHost) (string, error) {
+	state := host.Status.Provisioning.State
+	if state != metal3api.StateProvisioned && state != metal3api.StateExternallyProvisioned {
+		return "waiting for the host to be provisioned", nil
+	}
+	if !host.Spec.Online {
+		return "waiting for the host to be online", nil
+	}
+
+	hup := &metal3api.HostUpdatePolicy{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(host), hup); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return "no HostUpdatePolicy allows firmware updates on reboot", nil
+		}
+		return "", fmt.Errorf("could not load the hostupdatepolicy of host %s: %w", host.Name, err)
+	}
+	if hup.Spec.FirmwareUpdates != metal3api.HostUpdatePolicyOnReboot {
+		return "the HostUpdatePolicy does not allow firmware updates on reboot", nil
+	}
+	return "", nil
+}
+
+// startHostUpdate claims the host and merges the updates of the campaign
+// into its HostFirmwareComponents. The host is rebooted into servicing once
+// its HostUpdatePolicy allows it.
+func (r *FirmwareUpdateCampaignReconciler) startHostUpdate(ctx context.Context, campaign *metal3api.FirmwareUpdateCampaign, host *campaignHost) error {
+	if host.hfc.Annotations == nil {
+		host.hfc.Annotations = map[string]string{}
+	}
+	host.hfc.Annotations[metal3api.FirmwareUpdateCampaignAnnotation] = campaign.Name
+	host.hfc.Spec.Updates = mergeFirmwareUpdates(host.hfc.Spec.Updates, campaign.Spec.Updates)
+	// The update fails with a conflict if another campaign claimed the
+	// host since it was read.
+	if err := r.Update(ctx, host.hfc); err != nil {
+		return fmt.Errorf("failed to update the hostfirmwarecomponents of host %s: %w", host.host.Name, err)
+	}
+	return nil
+}
+
+// releaseHost removes the claim of the campaign on an updated host.
+func (r *FirmwareUpdateCampaignReconciler) releaseHost(ctx context.Context, host *campaignHost) error {
+	delete(host.hfc.Annotations, metal3api.FirmwareUpdateCampaignAnnotation)
+	if err := r.Update(ctx, host.hfc); err != nil {
+		return fmt.Errorf("failed to release the hostfirmwarecomponents of host %s: %w", host.host.Name, err)
+	}
+	return nil
+}
+
+// firmwareCampaignClaim returns the name of the campaign updating the
+// host of the HostFirmwareComponents, or an empty string if the host is
+// not claimed or its campaign was deleted.
+func firmwareCampaignClaim(ctx context.Context, c client.Reader, hfc *metal3api.HostFirmwareComponents) (string, error) {
+	name := hfc.Annotations[metal3api.FirmwareUpdateCampaignAnnotation]
+	if name == "" {
+		return "", nil
+	}
+	campaign := &metal3api.FirmwareUpdateCampaign{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: hfc.Namespace, Name: name}, campaign); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("could not load firmwareupdatecampaign %s: %w", name, err)
+	}
+	return name, nil
+}
+
+// mergeFirmwareUpdates replaces the updates of the same components and
+// appends the other wanted updates, keeping the updates of the other
+// components.
+func mergeFirmwareUpdates(updates, wanted []metal3api.FirmwareUpdate) []metal3api.FirmwareUpdate {
+	merged := slices.Clone(updates)
+	for _, update := range wanted {
+		i := slices.IndexFunc(merged, func(u metal3api.FirmwareUpdate) bool {
+			return u.Component == update.Component
+		})
+		if i < 0 {
+			merged = append(merged, update)
+		} else {
+			merged[i] = update
+		}
+	}
+	return merged
+}
+
+// rebootHost adds the reboot annotation removed by the host controller
+// once the host is powered off, servicing starts when it powers on again.
+func (r *FirmwareUpdateCampaignReconciler) rebootHost(ctx context.Context, host *metal3api.BareMetalHost) error {
+	if host.Annotations == nil {
+		host.Annotations = map[string]string{}
+	}
+	host.Annotations[metal3api.RebootAnnotationPrefix] = ""
+	if err := r.Update(ctx, host); err != nil {
+		return fmt.Errorf("failed to add the reboot annotation to host %s: %w", host.Name, err)
+	}
+	return nil
+}
+
+// containsFirmwareUpdates tells whether all the wanted updates are in the
+// list of updates.
+func containsFirmwareUpdates(updates, wanted []metal3api.FirmwareUpdate) bool {
+	for _, update := range wanted {
+		if !slices.Contains(updates, update) {
+			return false
+		}
+	}
+	return true
+}
+
+func setCampaignCondition(status *metal3api.FirmwareUpdateCampaignStatus, campaign *metal3api.FirmwareUpdateCampaign, conditionType string, conditionStatus metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             conditionStatus,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: campaign.Generation,
+	})
+}
+
+func (r *FirmwareUpdateCampaignReconciler) updateCampaignStatus(ctx context.Context, log logr.Logger, campaign *metal3api.FirmwareUpdateCampaign, status metal3api.FirmwareUpdateCampaignStatus) error {
+	if equality.Semantic.DeepEqual(status, campaign.Status) {
+		return nil
+	}
+	campaign.Status = status
+	log.Info("updating status", "pending", status.Pending, "updating", status.Updating,
+		"updated", status.Updated, "failed", status.Failed)
+	if err := r.Status().Update(ctx, campaign); err != nil {
+		return fmt.Errorf("failed to update the status of the firmwareupdatecampaign: %w", err)
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *FirmwareUpdateCampaignReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	toNamespace := handler.EnqueueRequestsFromMapFunc(namespaceMapper[metal3api.FirmwareUpdateCampaignList](r.Client, r.Log))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&metal3api.FirmwareUpdateCampaign{}).
+		// The hosts claimed by a deleted campaign are released.
+		Watches(&metal3api.FirmwareUpdateCampaign{}, toNamespace).
+		Watches(&metal3api.BareMetalHost{}, toNamespace).
+		Watches(&metal3api.HostFirmwareComponents{}, toNamespace).
+		Watches(&metal3api.HostUpdatePolicy{}, toNamespace).
+		Complete(r)
+}
@@ -0,0 +1,415 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var campaignUpdates = []metal3api.FirmwareUpdate{
+	{Component: "bios", URL: "http://example.com/bios-2.exe"},
+}
+
+type campaignHostState int
+
+const (
+	campaignHostReady campaignHostState = iota
+	campaignHostNoPolicy
+	campaignHostUpdating
+	campaignHostUpdated
+	campaignHostFailed
+)
+
+func newCampaignHost(name string, state campaignHostState) []runtime.Object {
+	h := newPolicyHost(name).withComponents(map[string]string{"bios": "1.0", "bmc": "5.0"})
+	if state != campaignHostNoPolicy {
+		h.withUpdatePolicy(metal3api.HostUpdatePolicyOnReboot)
+	}
+
+	if state != campaignHostReady && state != campaignHostNoPolicy {
+		h.components.Annotations = map[string]string{metal3api.FirmwareUpdateCampaignAnnotation: "campaign"}
+	}
+
+	switch state {
+	case campaignHostUpdating:
+		h.components.Spec.Updates = campaignUpdates
+		h.host.Status.OperationalStatus = metal3api.OperationalStatusServicing
+	case campaignHostUpdated:
+		h.components.Spec.Updates = campaignUpdates
+		h.components.Status.Updates = campaignUpdates
+		h.components.Status.Components[0].CurrentVersion = "2.0"
+		h.components.Status.Components[0].LastVersionFlashed = "2.0"
+	case campaignHostFailed:
+		h.components.Spec.Updates = campaignUpdates
+		h.host.Status.OperationalStatus = metal3api.OperationalStatusError
+		h.host.Status.ErrorType = metal3api.ServicingError
+		h.host.Status.ErrorMessage = "flashing failed"
+	case campaignHostReady, campaignHostNoPolicy:
+	}
+	return h.objects()
+}
+
+func newCampaign(batchSize, maxFailures int) *metal3api.FirmwareUpdateCampaign {
+	return &metal3api.FirmwareUpdateCampaign{
+		ObjectMeta: metav1.ObjectMeta{Name: "campaign", Namespace: namespace},
+		Spec: metal3api.FirmwareUpdateCampaignSpec{
+			Selector:    policySelector(),
+			Updates:     campaignUpdates,
+			BatchSize:   batchSize,
+			MaxFailures: maxFailures,
+		},
+	}
+}
+
+func campaignHostPhases(campaign *metal3api.FirmwareUpdateCampaign) map[string]metal3api.FirmwareUpdateCampaignHostPhase {
+	phases := map[string]metal3api.FirmwareUpdateCampaignHostPhase{}
+	for _, host := range campaign.Status.Hosts {
+		phases[host.Name] = host.Phase
+	}
+	return phases
+}
+
+func TestFirmwareUpdateCampaignStartsWave(t *testing.T) {
+	var objs []runtime.Object
+	for _, name := range []string{"host-c", "host-a", "host-b"} {
+		objs = append(objs, newCampaignHost(name, campaignHostReady)...)
+	}
+	other := newHost("other", &metal3api.BareMetalHostSpec{Online: true})
+	objs = append(objs, other)
+
+	campaign, _, c := reconcileObject(t, newCampaign(2, 0), objs...)
+
+	assert.Equal(t, map[string]metal3api.FirmwareUpdateCampaignHostPhase{
+		"host-a": metal3api.FirmwareUpdateCampaignHostUpdating,
+		"host-b": metal3api.FirmwareUpdateCampaignHostUpdating,
+		"host-c": metal3api.FirmwareUpdateCampaignHostPending,
+	}, campaignHostPhases(campaign))
+	assert.Equal(t, 2, campaign.Status.Updating)
+	assert.Equal(t, 1, campaign.Status.Pending)
+	assert.True(t, meta.IsStatusConditionFalse(campaign.Status.Conditions, metal3api.FirmwareUpdateCampaignPausedCondition))
+	assert.True(t, meta.IsStatusConditionFalse(campaign.Status.Conditions, metal3api.FirmwareUpdateCampaignCompletedCondition))
+
+	for name, started := range map[string]bool{"host-a": true, "host-b": true, "host-c": false} {
+		key := types.NamespacedName{Name: name, Namespace: namespace}
+		hfc := &metal3api.HostFirmwareComponents{}
+		require.NoError(t, c.Get(t.Context(), key, hfc))
+		host := &metal3api.BareMetalHost{}
+		require.NoError(t, c.Get(t.Context(), key, host))
+		// The hosts are rebooted once their updates are written
+		assert.NotContains(t, host.Annotations, metal3api.RebootAnnotationPrefix, name)
+		if started {
+			assert.Equal(t, campaignUpdates, hfc.Spec.Updates, name)
+			assert.Equal(t, "campaign", hfc.Annotations[metal3api.FirmwareUpdateCampaignAnnotation], name)
+		} else {
+			assert.Empty(t, hfc.Spec.Updates, name)
+			assert.NotContains(t, hfc.Annotations, metal3api.FirmwareUpdateCampaignAnnotation, name)
+		}
+	}
+}
+
+func TestMergeFirmwareUpdates(t *testing.T) {
+	bios1 := metal3api.FirmwareUpdate{Component: "bios", URL: "http://example.com/bios-1.exe"}
+	bios2 := metal3api.FirmwareUpdate{Component: "bios", URL: "http://example.com/bios-2.exe"}
+	bmc := metal3api.FirmwareUpdate{Component: "bmc", URL: "http://example.com/bmc.exe"}
+
+	testCases := []struct {
+		Scenario string
+		Updates  []metal3api.FirmwareUpdate
+		Wanted   []metal3api.FirmwareUpdate
+		Expected []metal3api.FirmwareUpdate
+	}{
+		{
+			Scenario: "no updates",
+			Wanted:   []metal3api.FirmwareUpdate{bios2},
+			Expected: []metal3api.FirmwareUpdate{bios2},
+		},
+		{
+			Scenario: "other component kept",
+			Updates:  []metal3api.FirmwareUpdate{bmc},
+			Wanted:   []metal3api.FirmwareUpdate{bios2},
+			Expected: []metal3api.FirmwareUpdate{bmc, bios2},
+		},
+		{
+			Scenario: "same component replaced",
+			Updates:  []metal3api.FirmwareUpdate{bios1, bmc},
+			Wanted:   []metal3api.FirmwareUpdate{bios2},
+			Expected: []metal3api.FirmwareUpdate{bios2, bmc},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			merged := mergeFirmwareUpdates(tc.Updates, tc.Wanted)
+			assert.Equal(t, tc.Expected, merged)
+		})
+	}
+}
+
+func TestFirmwareUpdateCampaignClaims(t *testing.T) {
+	bmc := metal3api.FirmwareUpdate{Component: "bmc", URL: "http://example.com/bmc.exe"}
+	other := newCampaign(1, 0)
+	other.Name = "other"
+
+	testCases := []struct {
+		Scenario        string
+		State           campaignHostState
+		Claim           string
+		Objects         []runtime.Object
+		ExpectedPhase   metal3api.FirmwareUpdateCampaignHostPhase
+		ExpectedMessage string
+		ExpectedClaim   string
+		ExpectedUpdates []metal3api.FirmwareUpdate
+	}{
+		{
+			Scenario:        "claimed by another campaign",
+			State:           campaignHostReady,
+			Claim:           "other",
+			Objects:         []runtime.Object{other},
+			ExpectedPhase:   metal3api.FirmwareUpdateCampaignHostPending,
+			ExpectedMessage: "the host is being updated by FirmwareUpdateCampaign other",
+			ExpectedClaim:   "other",
+			ExpectedUpdates: []metal3api.FirmwareUpdate{bmc},
+		},
+		{
+			Scenario:        "claimed by a deleted campaign",
+			State:           campaignHostReady,
+			Claim:           "other",
+			ExpectedPhase:   metal3api.FirmwareUpdateCampaignHostUpdating,
+			ExpectedClaim:   "campaign",
+			ExpectedUpdates: append([]metal3api.FirmwareUpdate{bmc}, campaignUpdates...),
+		},
+		{
+			Scenario:        "released once updated",
+			State:           campaignHostUpdated,
+			Claim:           "campaign",
+			ExpectedPhase:   metal3api.FirmwareUpdateCampaignHostUpdated,
+			ExpectedUpdates: campaignUpdates,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			objs := newCampaignHost("host-a", tc.State)
+			hfc := objs[1].(*metal3api.HostFirmwareComponents)
+			hfc.Annotations = map[string]string{metal3api.FirmwareUpdateCampaignAnnotation: tc.Claim}
+			if tc.State == campaignHostReady {
+				hfc.Spec.Updates = []metal3api.FirmwareUpdate{bmc}
+			}
+
+			campaign, _, c := reconcileObject(t, newCampaign(1, 0), append(objs, tc.Objects...)...)
+
+			require.Len(t, campaign.Status.Hosts, 1)
+			assert.Equal(t, tc.ExpectedPhase, campaign.Status.Hosts[0].Phase)
+			assert.Equal(t, tc.ExpectedMessage, campaign.Status.Hosts[0].Message)
+			require.NoError(t, c.Get(t.Context(), client.ObjectKeyFromObject(hfc), hfc))
+			assert.Equal(t, tc.ExpectedClaim, hfc.Annotations[metal3api.FirmwareUpdateCampaignAnnotation])
+			assert.Equal(t, tc.ExpectedUpdates, hfc.Spec.Updates)
+		})
+	}
+}
+
+func TestFirmwareUpdateCampaignFailureBudget(t *testing.T) {
+	testCases := []struct {
+		Scenario    string
+		MaxFailures int
+		Paused      bool
+	}{
+		{
+			Scenario:    "budget exceeded",
+			MaxFailures: 0,
+			Paused:      true,
+		},
+		{
+			Scenario:    "within budget",
+			MaxFailures: 1,
+			Paused:      false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			var objs []runtime.Object
+			objs = append(objs, newCampaignHost("host-a", campaignHostFailed)...)
+			objs = append(objs, newCampaignHost("host-b", campaignHostReady)...)
+
+			campaign, _, _ := reconcileObject(t, newCampaign(1, tc.MaxFailures), objs...)
+
+			assert.Equal(t, 1, campaign.Status.Failed)
+			assert.Equal(t, "flashing failed", campaign.Status.Hosts[0].Message)
+			cond := meta.FindStatusCondition(campaign.Status.Conditions, metal3api.FirmwareUpdateCampaignPausedCondition)
+			require.NotNil(t, cond)
+			if tc.Paused {
+				assert.Equal(t, metav1.ConditionTrue, cond.Status)
+				assert.Equal(t, metal3api.FailureBudgetExceededReason, cond.Reason)
+				assert.Equal(t, metal3api.FirmwareUpdateCampaignHostPending, campaign.Status.Hosts[1].Phase)
+			} else {
+				assert.Equal(t, metav1.ConditionFalse, cond.Status)
+				assert.Equal(t, metal3api.FirmwareUpdateCampaignHostUpdating, campaign.Status.Hosts[1].Phase)
+			}
+		})
+	}
+}
+
+func TestFirmwareUpdateCampaignHostNotReady(t *testing.T) {
+	objs := newCampaignHost("host-a", campaignHostNoPolicy)
+
+	campaign, _, c := reconcileObject(t, newCampaign(1, 0), objs...)
+
+	require.Len(t, campaign.Status.Hosts, 1)
+	assert.Equal(t, metal3api.FirmwareUpdateCampaignHostPending, campaign.Status.Hosts[0].Phase)
+	assert.Equal(t, "no HostUpdatePolicy allows firmware updates on reboot", campaign.Status.Hosts[0].Message)
+
+	host := &metal3api.BareMetalHost{}
+	require.NoError(t, c.Get(t.Context(), types.NamespacedName{Name: "host-a", Namespace: namespace}, host))
+	assert.NotContains(t, host.Annotations, metal3api.RebootAnnotationPrefix)
+}
+
+func TestFirmwareUpdateCampaignHostPhases(t *testing.T) {
+	testCases := []struct {
+		Scenario          string
+		BatchSize         int
+		Hosts             map[string]campaignHostState
+		ExpectedUpdated   int
+		ExpectedPhases    map[string]metal3api.FirmwareUpdateCampaignHostPhase
+		ExpectedCompleted bool
+	}{
+		{
+			Scenario:  "waits for the wave",
+			BatchSize: 2,
+			Hosts: map[string]campaignHostState{
+				"host-a": campaignHostUpdated,
+				"host-b": campaignHostUpdating,
+				"host-c": campaignHostReady,
+			},
+			ExpectedPhases: map[string]metal3api.FirmwareUpdateCampaignHostPhase{
+				"host-a": metal3api.FirmwareUpdateCampaignHostUpdated,
+				"host-b": metal3api.FirmwareUpdateCampaignHostUpdating,
+				"host-c": metal3api.FirmwareUpdateCampaignHostPending,
+			},
+			ExpectedUpdated: 1,
+		},
+		{
+			Scenario:  "completed",
+			BatchSize: 1,
+			Hosts: map[string]campaignHostState{
+				"host-a": campaignHostUpdated,
+				"host-b": campaignHostUpdated,
+			},
+			ExpectedPhases: map[string]metal3api.FirmwareUpdateCampaignHostPhase{
+				"host-a": metal3api.FirmwareUpdateCampaignHostUpdated,
+				"host-b": metal3api.FirmwareUpdateCampaignHostUpdated,
+			},
+			ExpectedUpdated:   2,
+			ExpectedCompleted: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			var objs []runtime.Object
+			for name, state := range tc.Hosts {
+				objs = append(objs, newCampaignHost(name, state)...)
+			}
+
+			campaign, _, _ := reconcileObject(t, newCampaign(tc.BatchSize, 0), objs...)
+
+			assert.Equal(t, tc.ExpectedUpdated, campaign.Status.Updated)
+			assert.Equal(t, tc.ExpectedPhases, campaignHostPhases(campaign))
+			assert.Equal(t, tc.ExpectedCompleted,
+				meta.IsStatusConditionTrue(campaign.Status.Conditions, metal3api.FirmwareUpdateCampaignCompletedCondition))
+			assert.Equal(t, []metal3api.FirmwareComponentStatus{
+				{Component: "bios", InitialVersion: "1.0", CurrentVersion: "2.0", LastVersionFlashed: "2.0"},
+			}, campaign.Status.Hosts[0].Components)
+		})
+	}
+}
+
+func TestFirmwareUpdateCampaignInvalidUpdates(t *testing.T) {
+	campaign := newCampaign(1, 0)
+	campaign.Spec.Updates = []metal3api.FirmwareUpdate{{Component: "disk", URL: "http://example.com/disk.bin"}}
+	objs := newCampaignHost("host-a", campaignHostReady)
+
+	campaign, _, _ = reconcileObject(t, campaign, objs...)
+
+	cond := meta.FindStatusCondition(campaign.Status.Conditions, metal3api.FirmwareUpdateCampaignPausedCondition)
+	require.NotNil(t, cond)
+	assert.Equal(t, metal3api.InvalidCampaignReason, cond.Reason)
+	assert.Empty(t, campaign.Status.Hosts)
+}
+
+func TestFirmwareUpdateCampaignRebootsUpdatingHost(t *testing.T) {
+	testCases := []struct {
+		Scenario        string
+		Policy          func(*metal3api.HostUpdatePolicy)
+		ExpectedReboot  bool
+		ExpectedMessage string
+		ExpectedRequeue time.Duration
+	}{
+		{
+			Scenario:       "servicing may start",
+			ExpectedReboot: true,
+		},
+		{
+			Scenario: "outside of the maintenance windows",
+			Policy: func(hup *metal3api.HostUpdatePolicy) {
+				start := time.Now().UTC().Add(2 * time.Hour)
+				hup.Spec.MaintenanceWindows = []metal3api.MaintenanceWindow{{
+					Schedule: fmt.Sprintf("%d %d * * *", start.Minute(), start.Hour()),
+					Duration: metav1.Duration{Duration: time.Minute},
+				}}
+			},
+			ExpectedMessage: "waiting for the maintenance window opening at",
+		},
+		{
+			Scenario: "all the servicing slots of the group are taken",
+			Policy: func(hup *metal3api.HostUpdatePolicy) {
+				hup.Spec.MaxConcurrentServicing = ptr.To[int32](1)
+				hup.Spec.ServicingGroupLabel = "rack"
+			},
+			ExpectedMessage: "waiting for one of the 1 servicing slots of the group",
+			ExpectedRequeue: campaignRetryDelay,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			objs := newCampaignHost("host-a", campaignHostUpdating)
+			host := objs[0].(*metal3api.BareMetalHost)
+			host.Status.OperationalStatus = metal3api.OperationalStatusOK
+			host.Status.PoweredOn = true
+			for _, obj := range objs {
+				if hup, ok := obj.(*metal3api.HostUpdatePolicy); ok && tc.Policy != nil {
+					tc.Policy(hup)
+				}
+			}
+			// Another host of the rack is servicing
+			servicing := newPolicyHost("servicing").host
+			servicing.Status.OperationalStatus = metal3api.OperationalStatusServicing
+			objs = append(objs, servicing)
+
+			campaign, result, c := reconcileObject(t, newCampaign(1, 0), objs...)
+
+			assert.Equal(t, metal3api.FirmwareUpdateCampaignHostUpdating, campaign.Status.Hosts[0].Phase)
+			assert.True(t, strings.HasPrefix(campaign.Status.Hosts[0].Message, tc.ExpectedMessage), campaign.Status.Hosts[0].Message)
+			require.NoError(t, c.Get(t.Context(), client.ObjectKeyFromObject(host), host))
+			_, rebooting := host.Annotations[metal3api.RebootAnnotationPrefix]
+			assert.Equal(t, tc.ExpectedReboot, rebooting)
+			if tc.ExpectedRequeue != 0 {
+				assert.Equal(t, tc.ExpectedRequeue, result.RequeueAfter)
+			} else if tc.ExpectedMessage != "" {
+				assert.Positive(t, result.RequeueAfter)
+			}
+		})
+	}
+}
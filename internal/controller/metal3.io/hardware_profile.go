@@ -0,0 +1,177 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1/profile"
+	"github.com/metal3-io/baremetal-operator/pkg/hardwarematch"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// +kubebuilder:rbac:groups=metal3.io,resources=hardwareprofiles,verbs=get;list;watch
+
+var errHardwareProfileNotFound = errors.New("no hardware profile")
+
+// builtinProfile returns the built-in profile with the given name.
+func builtinProfile(name string) (profile.Profile, error) {
+	hwProf, err := profile.GetProfile(name)
+	if err != nil {
+		return profile.Profile{}, fmt.Errorf("%w named %q", errHardwareProfileNotFound, name)
+	}
+	return hwProf, nil
+}
+
+// getHardwareProfile returns the named profile. A HardwareProfile resource
+// takes precedence over the built-in profile with the same name.
+func (r *BareMetalHostReconciler) getHardwareProfile(ctx context.Context, name string) (profile.Profile, error) {
+	hwProfile := &metal3api.HardwareProfile{}
+	err := r.Get(ctx, client.ObjectKey{Name: name}, hwProfile)
+	switch {
+	case err == nil:
+		return profile.FromHardwareProfile(hwProfile), nil
+	case k8serrors.IsNotFound(err):
+		return builtinProfile(name)
+	default:
+		return profile.Profile{}, fmt.Errorf("failed to get hardware profile %s: %w", name, err)
+	}
+}
+
+// defaultHardwareProfileName returns the profile of a host that neither
+// names one nor matches any HardwareProfile.
+func defaultHardwareProfileName(host *metal3api.BareMetalHost) string {
+	// FIXME(dhellmann): Insert more robust logic to match
+	// hardware profiles here.
+	if strings.HasPrefix(host.Spec.BMC.Address, "libvirt") {
+		return "libvirt"
+	}
+	return profile.DefaultProfileName
+}
+
+// matchInspectedProfile chooses the HardwareProfile matching the inspected
+// hardware of a host that does not name its profile, and records it in the
+// status of the host. The current profile is kept when none matches, unless
+// it was matched from a HardwareProfile that was since deleted or no longer
+// matches, in which case the host goes back to the default profile.
+func (r *BareMetalHostReconciler) matchInspectedProfile(ctx context.Context, info *reconcileInfo, details *metal3api.HardwareDetails) (dirty bool, err error) {
+	if info.host.Spec.HardwareProfile != "" || details == nil {
+		return false, nil
+	}
+
+	hwProfiles := metal3api.HardwareProfileList{}
+	if err := r.List(ctx, &hwProfiles); err != nil {
+		return false, fmt.Errorf("failed to list hardware profiles: %w", err)
+	}
+
+	var name string
+	if matched := selectHardwareProfile(hwProfiles.Items, details); matched != nil {
+		name = matched.Name
+	} else if staleHardwareProfile(hwProfiles.Items, info.host.Status.HardwareProfile) {
+		name = defaultHardwareProfileName(info.host)
+	} else {
+		info.log.V(1).Info("no hardware profile matches the host")
+		return false, nil
+	}
+
+	if info.host.SetHardwareProfile(name) {
+		dirty = true
+		info.log.Info("updating hardware profile", "profile", name)
+		info.publishEvent("ProfileSet", "Hardware profile matched: "+name)
+	}
+
+	hintsDirty, err := updateRootDeviceHints(info.host, info)
+	return dirty || hintsDirty, err
+}
+
+// staleHardwareProfile returns whether the named profile was matched from a
+// HardwareProfile that no longer exists or no longer matches. It is only
+// called once the matching failed, so any profile with requirements is stale.
+func staleHardwareProfile(hwProfiles []metal3api.HardwareProfile, name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := range hwProfiles {
+		if hwProfiles[i].Name == name {
+			return hwProfiles[i].Spec.Match != nil
+		}
+	}
+	_, err := profile.GetProfile(name)
+	return err != nil
+}
+
+// selectHardwareProfile returns the profile with the highest priority among
+// the ones matching the hardware, or nil.
+func selectHardwareProfile(hwProfiles []metal3api.HardwareProfile, details *metal3api.HardwareDetails) *metal3api.HardwareProfile {
+	var matched *metal3api.HardwareProfile
+	for i := range hwProfiles {
+		candidate := &hwProfiles[i]
+		if candidate.Spec.Match == nil {
+			continue
+		}
+		if ok, _ := hardwarematch.Match(candidate.Spec.Match, details); !ok {
+			continue
+		}
+		if matched == nil || candidate.Spec.Priority > matched.Spec.Priority ||
+			(candidate.Spec.Priority == matched.Spec.Priority && candidate.Name < matched.Name) {
+			matched = candidate
+		}
+	}
+	return matched
+}
+
+// hostProfile returns the hardware profile of the host, or an empty
+// profile when none has been chosen yet. When the matched HardwareProfile
+// has been deleted, the default profile is used until the host is matched
+// again; a profile named in the spec of the host must exist.
+func hostProfile(host *metal3api.BareMetalHost, info *reconcileInfo) (profile.Profile, error) {
+	name := host.HardwareProfile()
+	if name == "" {
+		return profile.Profile{}, nil
+	}
+	hwProf, err := info.hardwareProfile(name)
+	if errors.Is(err, errHardwareProfileNotFound) && host.Spec.HardwareProfile == "" {
+		info.log.Info("hardware profile no longer exists, using the default one", "profile", name)
+		return info.hardwareProfile(defaultHardwareProfileName(host))
+	}
+	return hwProf, err
+}
+
+// hardwareProfileToHosts returns the hosts to match again when a
+// HardwareProfile changes, that is all the hosts not naming their profile
+// and the ones naming the changed profile.
+func (r *BareMetalHostReconciler) hardwareProfileToHosts(ctx context.Context, obj client.Object) []reconcile.Request {
+	hosts := metal3api.BareMetalHostList{}
+	if err := r.List(ctx, &hosts); err != nil {
+		r.Log.Error(err, "failed to list hosts for hardware profile")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range hosts.Items {
+		if name := hosts.Items[i].Spec.HardwareProfile; name != "" && name != obj.GetName() {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(&hosts.Items[i]),
+		})
+	}
+	return requests
+}
+
+// targetFirmware returns the BIOS settings of the host, or the ones of its
+// hardware profile when the host does not set any.
+func targetFirmware(host *metal3api.BareMetalHost, info *reconcileInfo) (*metal3api.FirmwareConfig, error) {
+	if host.Spec.Firmware != nil {
+		return host.Spec.Firmware, nil
+	}
+	hwProf, err := hostProfile(host, info)
+	if err != nil {
+		return nil, err
+	}
+	return hwProf.Firmware, nil
+}
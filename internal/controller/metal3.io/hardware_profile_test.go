@@ -0,0 +1,258 @@
+package controllers
+
+import (
+	"testing"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1/profile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+)
+
+func newHardwareProfile(name string, priority int32, match *metal3api.HardwareRequirements) *metal3api.HardwareProfile {
+	return &metal3api.HardwareProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: metal3api.HardwareProfileSpec{
+			Match:    match,
+			Priority: priority,
+		},
+	}
+}
+
+func TestSelectHardwareProfile(t *testing.T) {
+	details := &metal3api.HardwareDetails{
+		SystemVendor: metal3api.HardwareSystemVendor{
+			Manufacturer: "Dell Inc.",
+			ProductName:  "PowerEdge R640",
+		},
+		Storage: []metal3api.Storage{
+			{Name: "/dev/sda", Type: metal3api.SSD, SizeBytes: 480 * metal3api.GigaByte},
+			{Name: "/dev/sdb", Type: metal3api.HDD, SizeBytes: 4000 * metal3api.GigaByte},
+		},
+	}
+	dell := &metal3api.HardwareRequirements{Vendor: "dell"}
+	r640 := &metal3api.HardwareRequirements{Vendor: "dell", Product: "R640"}
+	twoSSDs := &metal3api.HardwareRequirements{
+		Vendor: "dell",
+		Disks:  []metal3api.DiskRequirement{{Type: metal3api.SSD, MinCount: 2}},
+	}
+
+	testCases := []struct {
+		Scenario string
+		Profiles []metal3api.HardwareProfile
+		Expected string
+	}{
+		{
+			Scenario: "no profiles",
+		},
+		{
+			Scenario: "profile without requirements is not matched",
+			Profiles: []metal3api.HardwareProfile{*newHardwareProfile("manual", 10, nil)},
+		},
+		{
+			Scenario: "disk layout does not match",
+			Profiles: []metal3api.HardwareProfile{*newHardwareProfile("dell-ssd", 0, twoSSDs)},
+		},
+		{
+			Scenario: "highest priority wins",
+			Profiles: []metal3api.HardwareProfile{
+				*newHardwareProfile("dell", 0, dell),
+				*newHardwareProfile("dell-r640", 10, r640),
+				*newHardwareProfile("dell-ssd", 20, twoSSDs),
+			},
+			Expected: "dell-r640",
+		},
+		{
+			Scenario: "name breaks ties",
+			Profiles: []metal3api.HardwareProfile{
+				*newHardwareProfile("dell-r640", 0, r640),
+				*newHardwareProfile("dell", 0, dell),
+			},
+			Expected: "dell",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			matched := selectHardwareProfile(tc.Profiles, details)
+			if tc.Expected == "" {
+				assert.Nil(t, matched)
+				return
+			}
+			require.NotNil(t, matched)
+			assert.Equal(t, tc.Expected, matched.Name)
+		})
+	}
+}
+
+func TestGetHardwareProfile(t *testing.T) {
+	hwProfile := newHardwareProfile("libvirt", 0, nil)
+	hwProfile.Spec.RootDeviceHints = &metal3api.RootDeviceHints{DeviceName: "/dev/vdc"}
+	hwProfile.Spec.BootMode = metal3api.Legacy
+	r := newTestReconciler(t, hwProfile)
+
+	// The resource takes precedence over the built-in profile
+	hwProf, err := r.getHardwareProfile(t.Context(), "libvirt")
+	require.NoError(t, err)
+	assert.Equal(t, "/dev/vdc", hwProf.RootDeviceHints.DeviceName)
+	assert.Equal(t, metal3api.Legacy, hwProf.BootMode)
+
+	hwProf, err = r.getHardwareProfile(t.Context(), "openstack")
+	require.NoError(t, err)
+	assert.Equal(t, "/dev/vdb", hwProf.RootDeviceHints.DeviceName)
+
+	_, err = r.getHardwareProfile(t.Context(), "missing")
+	assert.Error(t, err)
+}
+
+func TestMatchInspectedProfile(t *testing.T) {
+	hwProfile := newHardwareProfile("dell", 0, &metal3api.HardwareRequirements{Vendor: "dell"})
+	hwProfile.Spec.RootDeviceHints = &metal3api.RootDeviceHints{HCTL: "0:2:0:0"}
+	hwProfile.Spec.RAID = &metal3api.RAIDConfig{
+		HardwareRAIDVolumes: []metal3api.HardwareRAIDVolume{{Level: "1"}},
+	}
+	hwProfile.Spec.Firmware = &metal3api.FirmwareConfig{SriovEnabled: ptr.To(true)}
+	details := &metal3api.HardwareDetails{
+		SystemVendor: metal3api.HardwareSystemVendor{Manufacturer: "Dell Inc."},
+	}
+
+	t.Run("profile named by the host is kept", func(t *testing.T) {
+		host := newDefaultHost(t)
+		host.Status.HardwareProfile = "libvirt"
+		r := newTestReconciler(t, hwProfile)
+		info := makeReconcileInfo(host)
+
+		dirty, err := r.matchInspectedProfile(t.Context(), info, details)
+		require.NoError(t, err)
+		assert.False(t, dirty)
+		assert.Equal(t, "libvirt", host.Status.HardwareProfile)
+	})
+
+	t.Run("matched profile gives the defaults", func(t *testing.T) {
+		host := newDefaultHost(t)
+		host.Spec.HardwareProfile = ""
+		host.Spec.RootDeviceHints = nil
+		host.Status.HardwareProfile = "unknown"
+		r := newTestReconciler(t, hwProfile)
+		info := makeReconcileInfo(host)
+		info.profileLookup = func(name string) (profile.Profile, error) {
+			return r.getHardwareProfile(t.Context(), name)
+		}
+
+		dirty, err := r.matchInspectedProfile(t.Context(), info, details)
+		require.NoError(t, err)
+		assert.True(t, dirty)
+		assert.Equal(t, "dell", host.Status.HardwareProfile)
+		assert.Equal(t, "0:2:0:0", host.Status.Provisioning.RootDeviceHints.HCTL)
+
+		dirty, err = saveHostProvisioningSettings(host, info)
+		require.NoError(t, err)
+		assert.True(t, dirty)
+		assert.Equal(t, hwProfile.Spec.RAID, host.Status.Provisioning.RAID)
+		assert.Equal(t, hwProfile.Spec.Firmware, host.Status.Provisioning.Firmware)
+
+		assert.True(t, updateBootModeStatus(host, metal3api.Legacy))
+		assert.Equal(t, metal3api.Legacy, host.Status.Provisioning.BootMode)
+	})
+}
+
+func TestRematchHardwareProfile(t *testing.T) {
+	details := &metal3api.HardwareDetails{
+		SystemVendor: metal3api.HardwareSystemVendor{Manufacturer: "Dell Inc."},
+	}
+
+	testCases := []struct {
+		Scenario string
+		Profiles []runtime.Object
+		Expected string
+	}{
+		{
+			Scenario: "matched profile was deleted",
+			Expected: profile.DefaultProfileName,
+		},
+		{
+			Scenario: "matched profile no longer matches",
+			Profiles: []runtime.Object{
+				newHardwareProfile("dell-r650", 0, &metal3api.HardwareRequirements{Vendor: "hpe"}),
+			},
+			Expected: profile.DefaultProfileName,
+		},
+		{
+			Scenario: "another profile matches",
+			Profiles: []runtime.Object{
+				newHardwareProfile("dell-r650", 0, &metal3api.HardwareRequirements{Vendor: "hpe"}),
+				newHardwareProfile("any", 0, &metal3api.HardwareRequirements{}),
+			},
+			Expected: "any",
+		},
+		{
+			Scenario: "matched profile still matches",
+			Profiles: []runtime.Object{
+				newHardwareProfile("dell-r650", 0, &metal3api.HardwareRequirements{Vendor: "dell"}),
+			},
+			Expected: "dell-r650",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			host := newDefaultHost(t)
+			host.Spec.HardwareProfile = ""
+			host.Spec.RootDeviceHints = nil
+			host.Status.HardwareProfile = "dell-r650"
+			r := newTestReconciler(t, tc.Profiles...)
+			info := makeReconcileInfo(host)
+			info.profileLookup = func(name string) (profile.Profile, error) {
+				return r.getHardwareProfile(t.Context(), name)
+			}
+
+			_, err := r.matchInspectedProfile(t.Context(), info, details)
+			require.NoError(t, err)
+			assert.Equal(t, tc.Expected, host.Status.HardwareProfile)
+		})
+	}
+}
+
+func TestHostProfileDeleted(t *testing.T) {
+	host := newDefaultHost(t)
+	host.Status.HardwareProfile = "dell-r650"
+	r := newTestReconciler(t)
+	info := makeReconcileInfo(host)
+	info.profileLookup = func(name string) (profile.Profile, error) {
+		return r.getHardwareProfile(t.Context(), name)
+	}
+
+	// A profile named by the host must exist
+	host.Spec.HardwareProfile = "dell-r650"
+	_, err := hostProfile(host, info)
+	require.Error(t, err)
+
+	// A matched profile falls back to the default one
+	host.Spec.HardwareProfile = ""
+	hwProf, err := hostProfile(host, info)
+	require.NoError(t, err)
+	assert.Equal(t, profile.DefaultProfileName, hwProf.Name)
+
+	_, err = updateRootDeviceHints(host, info)
+	require.NoError(t, err)
+}
+
+func TestHardwareProfileToHosts(t *testing.T) {
+	matched := newHost("matched", &metal3api.BareMetalHostSpec{})
+	matched.Spec.HardwareProfile = ""
+	named := newHost("named", &metal3api.BareMetalHostSpec{})
+	named.Spec.HardwareProfile = "libvirt"
+	namedChanged := newHost("named-changed", &metal3api.BareMetalHostSpec{})
+	namedChanged.Spec.HardwareProfile = "dell-r650"
+	r := newTestReconciler(t, matched, named, namedChanged)
+
+	requests := r.hardwareProfileToHosts(t.Context(), newHardwareProfile("dell-r650", 0, nil))
+	names := []string{}
+	for _, request := range requests {
+		names = append(names, request.Name)
+	}
+	assert.ElementsMatch(t, []string{"matched", "named-changed"}, names)
+}
@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner"
+)
+
+// resolveHostNetwork returns the network of the host with the switch port
+// of each NIC resolved. The switch and port not given in the spec are
+// found from the LLDP data of the inspected NIC, the switch being the
+// BareMetalSwitch with the MAC address reported as chassis ID.
+func resolveHostNetwork(network *metal3api.HostNetwork, details *metal3api.HardwareDetails, switches []metal3api.BareMetalSwitch) (*provisioner.NetworkData, error) {
+	if network == nil {
+		return nil, nil
+	}
+
+	data := &provisioner.NetworkData{
+		ProvisioningVLAN: network.ProvisioningVLAN,
+		Bonds:            network.Bonds,
+	}
+
+	bonds := make(map[string]string)
+	for _, bond := range network.Bonds {
+		for _, mac := range bond.MACAddresses {
+			bonds[strings.ToLower(mac)] = bond.Name
+		}
+	}
+
+	// The NICs of a bond do not have to be listed in the interfaces
+	interfaces := slices.Clone(network.Interfaces)
+	for _, bond := range network.Bonds {
+		for _, mac := range bond.MACAddresses {
+			listed := slices.ContainsFunc(network.Interfaces, func(iface metal3api.NetworkInterface) bool {
+				return strings.EqualFold(iface.MACAddress, mac)
+			})
+			if !listed {
+				interfaces = append(interfaces, metal3api.NetworkInterface{MACAddress: mac})
+			}
+		}
+	}
+
+	for _, iface := range interfaces {
+		attachment, err := resolveNetworkInterface(iface, details, switches)
+		if err != nil {
+			return nil, err
+		}
+		if bond, found := bonds[strings.ToLower(iface.MACAddress)]; found {
+			attachment.Bond = bond
+			attachment.SwitchPortConfig = metal3api.SwitchPortConfig{}
+		}
+		data.Interfaces = append(data.Interfaces, attachment)
+	}
+
+	return data, nil
+}
+
+func resolveNetworkInterface(iface metal3api.NetworkInterface, details *metal3api.HardwareDetails, switches []metal3api.BareMetalSwitch) (attachment provisioner.NetworkAttachment, err error) {
+	var lldp *metal3api.LLDP
+	if details != nil {
+		for _, nic := range details.NIC {
+			if strings.EqualFold(nic.MAC, iface.MACAddress) && nic.LLDP != nil {
+				lldp = nic.LLDP
+				break
+			}
+		}
+	}
+
+	var bms *metal3api.BareMetalSwitch
+	switch {
+	case iface.Switch != "":
+		for i := range switches {
+			if switches[i].Name == iface.Switch {
+				bms = &switches[i]
+				break
+			}
+		}
+		if bms == nil {
+			return attachment, fmt.Errorf("BareMetalSwitch %s of NIC %s not found", iface.Switch, iface.MACAddress)
+		}
+	case lldp != nil && lldp.SwitchID != "":
+		for i := range switches {
+			if strings.EqualFold(switches[i].Spec.MACAddress, lldp.SwitchID) {
+				bms = &switches[i]
+				break
+			}
+		}
+		if bms == nil {
+			return attachment, fmt.Errorf("no BareMetalSwitch with MAC address %s, reported by LLDP for NIC %s", lldp.SwitchID, iface.MACAddress)
+		}
+	default:
+		return attachment, fmt.Errorf("the switch of NIC %s is not set and no LLDP data was inspected for it", iface.MACAddress)
+	}
+
+	port := iface.Port
+	if port == "" && lldp != nil {
+		port = lldp.PortID
+	}
+	if port == "" {
+		return attachment, fmt.Errorf("the switch port of NIC %s is not set and no LLDP data was inspected for it", iface.MACAddress)
+	}
+
+	return provisioner.NetworkAttachment{
+		MACAddress:       strings.ToLower(iface.MACAddress),
+		SwitchID:         strings.ToLower(bms.Spec.MACAddress),
+		SwitchInfo:       bms.Name,
+		PortID:           port,
+		SwitchPortConfig: iface.SwitchPortConfig,
+	}, nil
+}
@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"testing"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func hostNetworkSwitches() []metal3api.BareMetalSwitch {
+	return []metal3api.BareMetalSwitch{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "tor-1", Namespace: namespace},
+			Spec:       metal3api.BareMetalSwitchSpec{MACAddress: "AA:BB:CC:DD:EE:01"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "tor-2", Namespace: namespace},
+			Spec:       metal3api.BareMetalSwitchSpec{MACAddress: "aa:bb:cc:dd:ee:02"},
+		},
+	}
+}
+
+func hostNetworkDetails() *metal3api.HardwareDetails {
+	return &metal3api.HardwareDetails{
+		NIC: []metal3api.NIC{
+			{MAC: "00:00:00:00:00:01", LLDP: &metal3api.LLDP{SwitchID: "aa:bb:cc:dd:ee:01", PortID: "Ethernet1/1"}},
+			{MAC: "00:00:00:00:00:02", LLDP: &metal3api.LLDP{SwitchID: "aa:bb:cc:dd:ee:02", PortID: "Ethernet1/1"}},
+			{MAC: "00:00:00:00:00:03", LLDP: &metal3api.LLDP{SwitchID: "aa:bb:cc:dd:ee:99", PortID: "Ethernet1/3"}},
+			{MAC: "00:00:00:00:00:04"},
+		},
+	}
+}
+
+func TestResolveHostNetwork(t *testing.T) {
+	access := metal3api.SwitchPortConfig{VLAN: ptr.To(metal3api.VLANID(100))}
+	trunk := metal3api.SwitchPortConfig{
+		Mode:         metal3api.SwitchPortModeTrunk,
+		AllowedVLANs: []metal3api.VLANID{20, 30},
+	}
+
+	testCases := []struct {
+		Scenario      string
+		Network       *metal3api.HostNetwork
+		Expected      *provisioner.NetworkData
+		ExpectedError string
+	}{
+		{
+			Scenario: "no network",
+		},
+		{
+			Scenario: "switch and port from LLDP",
+			Network: &metal3api.HostNetwork{
+				ProvisioningVLAN: ptr.To(metal3api.VLANID(5)),
+				Interfaces: []metal3api.NetworkInterface{
+					{MACAddress: "00:00:00:00:00:01", SwitchPortConfig: access},
+				},
+			},
+			Expected: &provisioner.NetworkData{
+				ProvisioningVLAN: ptr.To(metal3api.VLANID(5)),
+				Interfaces: []provisioner.NetworkAttachment{
+					{MACAddress: "00:00:00:00:00:01", SwitchID: "aa:bb:cc:dd:ee:01", SwitchInfo: "tor-1", PortID: "Ethernet1/1", SwitchPortConfig: access},
+				},
+			},
+		},
+		{
+			Scenario: "switch and port from the spec",
+			Network: &metal3api.HostNetwork{
+				Interfaces: []metal3api.NetworkInterface{
+					{MACAddress: "00:00:00:00:00:04", Switch: "tor-2", Port: "Ethernet1/4", SwitchPortConfig: trunk},
+				},
+			},
+			Expected: &provisioner.NetworkData{
+				Interfaces: []provisioner.NetworkAttachment{
+					{MACAddress: "00:00:00:00:00:04", SwitchID: "aa:bb:cc:dd:ee:02", SwitchInfo: "tor-2", PortID: "Ethernet1/4", SwitchPortConfig: trunk},
+				},
+			},
+		},
+		{
+			Scenario: "bond of two switches",
+			Network: &metal3api.HostNetwork{
+				Interfaces: []metal3api.NetworkInterface{
+					{MACAddress: "00:00:00:00:00:02", Port: "Ethernet1/2"},
+				},
+				Bonds: []metal3api.NetworkBond{
+					{Name: "bond0", MACAddresses: []string{"00:00:00:00:00:01", "00:00:00:00:00:02"}, SwitchPortConfig: trunk},
+				},
+			},
+			Expected: &provisioner.NetworkData{
+				Interfaces: []provisioner.NetworkAttachment{
+					{MACAddress: "00:00:00:00:00:02", SwitchID: "aa:bb:cc:dd:ee:02", SwitchInfo: "tor-2", PortID: "Ethernet1/2", Bond: "bond0"},
+					{MACAddress: "00:00:00:00:00:01", SwitchID: "aa:bb:cc:dd:ee:01", SwitchInfo: "tor-1", PortID: "Ethernet1/1", Bond: "bond0"},
+				},
+				Bonds: []metal3api.NetworkBond{
+					{Name: "bond0", MACAddresses: []string{"00:00:00:00:00:01", "00:00:00:00:00:02"}, SwitchPortConfig: trunk},
+				},
+			},
+		},
+		{
+			Scenario: "unknown switch",
+			Network: &metal3api.HostNetwork{
+				Interfaces: []metal3api.NetworkInterface{
+					{MACAddress: "00:00:00:00:00:01", Switch: "tor-3"},
+				},
+			},
+			ExpectedError: "BareMetalSwitch tor-3 of NIC 00:00:00:00:00:01 not found",
+		},
+		{
+			Scenario: "LLDP of an unknown switch",
+			Network: &metal3api.HostNetwork{
+				Interfaces: []metal3api.NetworkInterface{
+					{MACAddress: "00:00:00:00:00:03"},
+				},
+			},
+			ExpectedError: "no BareMetalSwitch with MAC address aa:bb:cc:dd:ee:99, reported by LLDP for NIC 00:00:00:00:00:03",
+		},
+		{
+			Scenario: "no LLDP",
+			Network: &metal3api.HostNetwork{
+				Interfaces: []metal3api.NetworkInterface{
+					{MACAddress: "00:00:00:00:00:04"},
+				},
+			},
+			ExpectedError: "the switch of NIC 00:00:00:00:00:04 is not set and no LLDP data was inspected for it",
+		},
+		{
+			Scenario: "no port without LLDP",
+			Network: &metal3api.HostNetwork{
+				Interfaces: []metal3api.NetworkInterface{
+					{MACAddress: "00:00:00:00:00:04", Switch: "tor-1"},
+				},
+			},
+			ExpectedError: "the switch port of NIC 00:00:00:00:00:04 is not set and no LLDP data was inspected for it",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			network, err := resolveHostNetwork(tc.Network, hostNetworkDetails(), hostNetworkSwitches())
+			if tc.ExpectedError != "" {
+				require.EqualError(t, err, tc.ExpectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.Expected, network)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// hostSlots limits how many hosts of a scope do something at the same time.
+// A host reserves one of the slots by recording the time of its reservation
+// on itself before reading the other hosts from the API, so that of two
+// hosts reserving at the same time at least one sees the other, and both
+// agree on which one was first.
+type hostSlots struct {
+	// scope selects the hosts sharing the slots.
+	scope []client.ListOption
+	// timeout is how long a reservation holds a slot, forever when zero.
+	timeout time.Duration
+	// reservation returns when the host reserved a slot.
+	reservation func(*metal3api.BareMetalHost) (time.Time, bool)
+	// active tells whether the host holds a slot regardless of when it
+	// reserved it, for example because it already started.
+	active func(*metal3api.BareMetalHost) bool
+}
+
+// taken returns the number of the other hosts of the scope that are active
+// or reserved a slot before the host, which reserved its own at the given
+// time.
+func (s hostSlots) taken(ctx context.Context, reader client.Reader, host *metal3api.BareMetalHost, reserved, now time.Time) (int, error) {
+	hosts := metal3api.BareMetalHostList{}
+	if err := reader.List(ctx, &hosts, s.scope...); err != nil {
+		return 0, fmt.Errorf("failed to list hosts: %w", err)
+	}
+	key := client.ObjectKeyFromObject(host).String()
+	taken := 0
+	for i := range hosts.Items {
+		other := &hosts.Items[i]
+		otherKey := client.ObjectKeyFromObject(other).String()
+		if otherKey == key {
+			continue
+		}
+		if s.active(other) {
+			taken++
+			continue
+		}
+		otherReserved, found := s.reservation(other)
+		if !found || (s.timeout > 0 && now.Sub(otherReserved) > s.timeout) {
+			continue
+		}
+		if otherReserved.Before(reserved) || (otherReserved.Equal(reserved) && otherKey < key) {
+			taken++
+		}
+	}
+	return taken, nil
+}
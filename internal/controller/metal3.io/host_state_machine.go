@@ -85,13 +85,32 @@ func recordStateEnd(info *reconcileInfo, host *metal3api.BareMetalHost, state me
 }
 
 func (hsm *hostStateMachine) ensureCapacity(ctx context.Context, info *reconcileInfo, state metal3api.ProvisioningState) actionResult {
+	if actionRes := hsm.ensureProvisionerCapacity(ctx, info, state); actionRes != nil {
+		return actionRes
+	}
+
+	group, bucket, err := hsm.Reconciler.fullProvisioningGroup(ctx, info.host)
+	if err != nil {
+		return actionError{fmt.Errorf("failed to determine provisioning group capacity: %w", err)}
+	}
+	if group != nil {
+		info.log.Info("provisioning group limit reached", "group", group.Name(), "bucket", bucket, "limit", group.Limit)
+		return recordActionDelayed(info, state, group.Name(), bucket)
+	}
+
+	return nil
+}
+
+// ensureProvisionerCapacity only checks the limit of the provisioner,
+// which knows whether the host already uses one of its slots.
+func (hsm *hostStateMachine) ensureProvisionerCapacity(ctx context.Context, info *reconcileInfo, state metal3api.ProvisioningState) actionResult {
 	hasCapacity, err := hsm.Provisioner.HasCapacity(ctx)
 	if err != nil {
 		return actionError{fmt.Errorf("failed to determine current provisioner capacity: %w", err)}
 	}
 
 	if !hasCapacity {
-		return recordActionDelayed(info, state)
+		return recordActionDelayed(info, state, provisionerBucket, "")
 	}
 
 	return nil
@@ -137,7 +156,14 @@ func (hsm *hostStateMachine) updateHostStateFrom(ctx context.Context, initialSta
 			// controller to this point. We can't move it yet because
 			// it needs error handling logic that we can't support in
 			// this function.
-			if updateBootModeStatus(hsm.Host) {
+			// The hardware profile gives the default boot mode
+			var profileBootMode metal3api.BootMode
+			if hwProf, err := hostProfile(hsm.Host, info); err == nil {
+				profileBootMode = hwProf.BootMode
+			} else {
+				info.log.Info("cannot read the hardware profile, using the default boot mode", "error", err.Error())
+			}
+			if updateBootModeStatus(hsm.Host, profileBootMode) {
 				info.log.Info("saving boot mode",
 					"new mode", hsm.Host.Status.Provisioning.BootMode)
 			}
@@ -161,11 +187,12 @@ func (hsm *hostStateMachine) checkDelayedHost(ctx context.Context, info *reconci
 	}
 
 	// Make sure the check is re-applied when provisioning an
-	// host not yet tracked by the provisioner
+	// host not yet tracked by the provisioner. The provisioning groups
+	// are not checked again, the host already counts in its buckets.
 	switch info.host.Status.Provisioning.State {
 	case metal3api.StateInspecting, metal3api.StateProvisioning,
 		metal3api.StateDeprovisioning:
-		if actionRes := hsm.ensureCapacity(ctx, info, info.host.Status.Provisioning.State); actionRes != nil {
+		if actionRes := hsm.ensureProvisionerCapacity(ctx, info, info.host.Status.Provisioning.State); actionRes != nil {
 			return actionRes
 		}
 	default:
@@ -209,9 +236,12 @@ func (hsm *hostStateMachine) ReconcileState(ctx context.Context, info *reconcile
 	return actionError{fmt.Errorf("no handler found for state \"%s\"", initialState)}
 }
 
-func updateBootModeStatus(host *metal3api.BareMetalHost) bool {
+func updateBootModeStatus(host *metal3api.BareMetalHost, profileBootMode metal3api.BootMode) bool {
 	// Make sure we have saved the current boot mode value.
 	bootMode := host.BootMode()
+	if host.Spec.BootMode == "" && profileBootMode != "" {
+		bootMode = profileBootMode
+	}
 	if bootMode == host.Status.Provisioning.BootMode {
 		return false
 	}
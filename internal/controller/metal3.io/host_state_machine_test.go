@@ -156,7 +156,7 @@ func TestProvisioningCapacity(t *testing.T) {
 			assert.Equal(t, tc.ExpectedDelayed, assert.ObjectsAreEqual(actionDelayed{}, result), "Expected actionDelayed")
 
 			if tc.ExpectedDelayed {
-				counter, _ := delayedProvisioningHostCounters.GetMetricWith(hostMetricLabels(info.request))
+				counter, _ := delayedProvisioningHostCounters.GetMetricWith(delayedMetricLabels(info.request, provisionerBucket, ""))
 				initialCounterValue := promutil.ToFloat64(counter)
 				for _, sb := range info.postSaveCallbacks {
 					sb()
@@ -204,7 +204,7 @@ func TestDeprovisioningCapacity(t *testing.T) {
 			assert.Equal(t, tc.ExpectedDelayed, assert.ObjectsAreEqual(actionDelayed{}, result), "Expected actionDelayed")
 
 			if tc.ExpectedDelayed {
-				counter, _ := delayedDeprovisioningHostCounters.GetMetricWith(hostMetricLabels(info.request))
+				counter, _ := delayedDeprovisioningHostCounters.GetMetricWith(delayedMetricLabels(info.request, provisionerBucket, ""))
 				initialCounterValue := promutil.ToFloat64(counter)
 				for _, sb := range info.postSaveCallbacks {
 					sb()
@@ -1385,6 +1385,10 @@ func (m *mockProvisioner) RemoveBMCEventSubscriptionForNode(_ context.Context, _
 	return result, nil
 }
 
+func (m *mockProvisioner) CheckBMCEventSubscriptionForNode(_ context.Context, _ metal3api.BMCEventSubscription) (exists bool, err error) {
+	return true, nil
+}
+
 func (p *mockProvisioner) GetFirmwareComponents(context.Context) (components []metal3api.FirmwareComponentStatus, err error) {
 	return components, nil
 }
@@ -1409,6 +1413,10 @@ func (p *mockProvisioner) GetHealth(_ context.Context) string {
 	return ""
 }
 
+func (p *mockProvisioner) GetDiskErasure(_ context.Context) (*metal3api.DiskErasure, error) {
+	return nil, nil
+}
+
 func TestUpdateBootModeStatus(t *testing.T) {
 	testCases := []struct {
 		Scenario       string
@@ -1480,7 +1488,7 @@ func TestUpdateBootModeStatus(t *testing.T) {
 					},
 				},
 			}
-			changed := updateBootModeStatus(&host)
+			changed := updateBootModeStatus(&host, "")
 			assert.Equal(t, tc.ExpectedChange, changed, "unexpected change response")
 			assert.Equal(t, tc.ExpectedValue, host.Status.Provisioning.BootMode)
 		})
@@ -18,32 +18,223 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
 
+	"github.com/go-logr/logr"
 	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/hostclaim"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/cluster-api/util/patch"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 // HostClaimReconciler reconciles a HostClaim object.
 type HostClaimReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme    *runtime.Scheme
+	Log       logr.Logger
+	APIReader client.Reader
 }
 
 //+kubebuilder:rbac:groups=metal3.io,resources=hostclaims,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=metal3.io,resources=hostclaims/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=metal3.io,resources=hostclaims/finalizers,verbs=update
 //+kubebuilder:rbac:groups=metal3.io,resources=hostdeploypolicies,verbs=get;list;watch
+//+kubebuilder:rbac:groups=metal3.io,resources=hostclaimsets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=metal3.io,resources=hostclaimsets/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=metal3.io,resources=baremetalhosts,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=metal3.io,resources=hardwaredata,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=namespaces,verbs=get
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
 
-func (r *HostClaimReconciler) Reconcile(_ context.Context, _ ctrl.Request) (ctrl.Result, error) {
+// Reconcile binds the HostClaim to a BareMetalHost, keeps the host in sync
+// with the claim and releases the host when the claim is deleted.
+func (r *HostClaimReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, rerr error) {
+	log := r.Log.WithValues("hostclaim", req.NamespacedName)
+	log.Info("start")
+	defer log.Info("done")
+
+	hostClaim := &metal3api.HostClaim{}
+	if err := r.Get(ctx, req.NamespacedName, hostClaim); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("could not load hostclaim: %w", err)
+	}
+
+	helper, err := patch.NewHelper(hostClaim, r.Client)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to init patch helper: %w", err)
+	}
+	oldStatus := hostClaim.Status.DeepCopy()
+
+	defer func() {
+		if !equality.Semantic.DeepEqual(oldStatus, &hostClaim.Status) {
+			now := metav1.Now()
+			hostClaim.Status.LastUpdated = &now
+		}
+		if err := helper.Patch(ctx, hostClaim); err != nil {
+			if !hostClaim.DeletionTimestamp.IsZero() && len(hostClaim.Finalizers) == 0 && isNotFound(err) {
+				// The status cannot be patched once the finalizer is gone.
+				return
+			}
+			log.Error(err, "failed to patch hostclaim")
+			rerr = kerrors.NewAggregate([]error{rerr, err})
+		}
+	}()
+
+	mgr, err := hostclaim.NewHostManager(r.Client, log, hostClaim, r.APIReader)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to create the hostclaim manager: %w", err)
+	}
+
+	if !hostClaim.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(ctx, mgr)
+	}
+	return r.reconcileNormal(ctx, mgr)
+}
+
+func (r *HostClaimReconciler) reconcileNormal(ctx context.Context, mgr *hostclaim.HostManager) (ctrl.Result, error) {
+	// The finalizer must be registered before the host is associated,
+	// otherwise the host could be orphaned.
+	if mgr.SetFinalizer() {
+		return ctrl.Result{}, nil
+	}
+
+	if !mgr.IsAssociated() {
+		associate := mgr.Associate
+		if mgr.InGroup() {
+			associate = mgr.AssociateGroup
+		}
+		if err := associate(ctx); err != nil {
+			return checkHostClaimError(err, "failed to associate the hostclaim with a baremetalhost")
+		}
+	}
+
+	if err := mgr.Update(ctx); err != nil {
+		return checkHostClaimError(err, "failed to synchronize the baremetalhost")
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *HostClaimReconciler) reconcileDelete(ctx context.Context, mgr *hostclaim.HostManager) (ctrl.Result, error) {
+	if err := mgr.Delete(ctx); err != nil {
+		return checkHostClaimError(err, "failed to release the baremetalhost")
+	}
+	mgr.UnsetFinalizer()
 	return ctrl.Result{}, nil
 }
 
+// checkHostClaimError converts the requeueAfterError of the hostclaim manager
+// into a result with a delay.
+func checkHostClaimError(err error, message string) (ctrl.Result, error) {
+	if ok, delay := hostclaim.IsRequeueAfterError(err); ok {
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
+	return ctrl.Result{}, fmt.Errorf("%s: %w", message, err)
+}
+
+// isNotFound checks if an error, or one of the errors of an aggregate, is a
+// NotFound error.
+func isNotFound(err error) bool {
+	var aggr kerrors.Aggregate
+	if errors.As(err, &aggr) {
+		return slices.ContainsFunc(aggr.Errors(), k8serrors.IsNotFound)
+	}
+	return k8serrors.IsNotFound(err)
+}
+
+// hostToHostClaim maps a BareMetalHost to the HostClaim consuming it.
+func (r *HostClaimReconciler) hostToHostClaim(_ context.Context, obj client.Object) []reconcile.Request {
+	host, ok := obj.(*metal3api.BareMetalHost)
+	if !ok || host.Spec.ConsumerRef == nil {
+		return nil
+	}
+	ref := host.Spec.ConsumerRef
+	if ref.Kind != hostclaim.HostClaimKind || ref.GroupVersionKind().Group != metal3api.GroupVersion.Group {
+		return nil
+	}
+	return []reconcile.Request{{
+		NamespacedName: client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name},
+	}}
+}
+
+// secretToHostClaims maps a Secret to the HostClaims using it as userData,
+// metaData or networkData, so that its changes reach the copy made for the
+// BareMetalHost.
+func (r *HostClaimReconciler) secretToHostClaims(ctx context.Context, obj client.Object) []reconcile.Request {
+	claims := metal3api.HostClaimList{}
+	if err := r.List(ctx, &claims, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "failed to list the hostclaims using the secret", "secret", client.ObjectKeyFromObject(obj))
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, claim := range claims.Items {
+		refs := []*corev1.SecretReference{claim.Spec.UserData, claim.Spec.MetaData, claim.Spec.NetworkData}
+		if slices.ContainsFunc(refs, func(ref *corev1.SecretReference) bool {
+			return ref != nil && ref.Name == obj.GetName()
+		}) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&claim)})
+		}
+	}
+	return requests
+}
+
+// hostClaimSetToHostClaims maps a HostClaimSet to its members.
+func (r *HostClaimReconciler) hostClaimSetToHostClaims(ctx context.Context, obj client.Object) []reconcile.Request {
+	claims := metal3api.HostClaimList{}
+	err := r.List(ctx, &claims, client.InNamespace(obj.GetNamespace()),
+		client.MatchingLabels{metal3api.HostClaimSetLabel: obj.GetName()})
+	if err != nil {
+		r.Log.Error(err, "failed to list the members of the hostclaimset", "hostclaimset", client.ObjectKeyFromObject(obj))
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(claims.Items))
+	for _, claim := range claims.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&claim)})
+	}
+	return requests
+}
+
+func (r *HostClaimReconciler) updateEventHandler(e event.UpdateEvent) bool {
+	_, oldOK := e.ObjectOld.(*metal3api.HostClaim)
+	_, newOK := e.ObjectNew.(*metal3api.HostClaim)
+	if !(oldOK && newOK) {
+		return true
+	}
+
+	if e.ObjectNew.GetGeneration() != e.ObjectOld.GetGeneration() {
+		return true
+	}
+
+	// Discard updates of the status only, they are produced by this controller.
+	return !reflect.DeepEqual(e.ObjectNew.GetFinalizers(), e.ObjectOld.GetFinalizers()) ||
+		!reflect.DeepEqual(e.ObjectNew.GetAnnotations(), e.ObjectOld.GetAnnotations()) ||
+		!e.ObjectNew.GetDeletionTimestamp().Equal(e.ObjectOld.GetDeletionTimestamp())
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *HostClaimReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&metal3api.HostClaim{}).
+		For(&metal3api.HostClaim{}, builder.WithPredicates(predicate.Funcs{
+			UpdateFunc: r.updateEventHandler,
+		})).
+		Watches(&metal3api.BareMetalHost{}, handler.EnqueueRequestsFromMapFunc(r.hostToHostClaim)).
+		Watches(&metal3api.HostClaimSet{}, handler.EnqueueRequestsFromMapFunc(r.hostClaimSetToHostClaims)).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.secretToHostClaims)).
 		Complete(r)
 }
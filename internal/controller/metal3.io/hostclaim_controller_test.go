@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSecretToHostClaims(t *testing.T) {
+	newClaim := func(name string, spec metal3api.HostClaimSpec) *metal3api.HostClaim {
+		return &metal3api.HostClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       spec,
+		}
+	}
+	c := fakeclient.NewClientBuilder().WithObjects(
+		newClaim("user-data", metal3api.HostClaimSpec{UserData: &corev1.SecretReference{Name: "cloud-init"}}),
+		newClaim("meta-data", metal3api.HostClaimSpec{MetaData: &corev1.SecretReference{Name: "cloud-init"}}),
+		newClaim("network-data", metal3api.HostClaimSpec{NetworkData: &corev1.SecretReference{Name: "cloud-init"}}),
+		newClaim("other-secret", metal3api.HostClaimSpec{UserData: &corev1.SecretReference{Name: "other"}}),
+		newClaim("no-secret", metal3api.HostClaimSpec{}),
+	).Build()
+	r := &HostClaimReconciler{Client: c, Log: logr.Discard()}
+
+	testCases := []struct {
+		Scenario string
+		Secret   client.ObjectKey
+		Expected []string
+	}{
+		{
+			Scenario: "secret used by claims",
+			Secret:   client.ObjectKey{Namespace: namespace, Name: "cloud-init"},
+			Expected: []string{"meta-data", "network-data", "user-data"},
+		},
+		{
+			Scenario: "unused secret",
+			Secret:   client.ObjectKey{Namespace: namespace, Name: "unused"},
+		},
+		{
+			Scenario: "secret in another namespace",
+			Secret:   client.ObjectKey{Namespace: "other", Name: "cloud-init"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: tc.Secret.Namespace, Name: tc.Secret.Name}}
+			var names []string
+			for _, request := range r.secretToHostClaims(t.Context(), secret) {
+				assert.Equal(t, namespace, request.Namespace)
+				names = append(names, request.Name)
+			}
+			assert.ElementsMatch(t, tc.Expected, names)
+		})
+	}
+}
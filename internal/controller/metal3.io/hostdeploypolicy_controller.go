@@ -0,0 +1,100 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/hostclaim"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+// HostDeployPolicyReconciler reports the capacity and the usage of the
+// BareMetalHosts governed by a HostDeployPolicy.
+type HostDeployPolicyReconciler struct {
+	client.Client
+	Log logr.Logger
+}
+
+//+kubebuilder:rbac:groups=metal3.io,resources=hostdeploypolicies,verbs=get;list;watch
+//+kubebuilder:rbac:groups=metal3.io,resources=hostdeploypolicies/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=metal3.io,resources=baremetalhosts,verbs=get;list;watch
+//+kubebuilder:rbac:groups=metal3.io,resources=hardwaredata,verbs=get;list;watch
+
+// Reconcile updates the status of the HostDeployPolicy with the resources of
+// the BareMetalHosts of its namespace.
+func (r *HostDeployPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("hostdeploypolicy", req.NamespacedName)
+
+	policy := &metal3api.HostDeployPolicy{}
+	if err := r.Get(ctx, req.NamespacedName, policy); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("could not load hostdeploypolicy: %w", err)
+	}
+
+	resources, err := hostclaim.ComputeNamespaceResources(ctx, r.Client, policy.Namespace)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not compute the resources of the namespace: %w", err)
+	}
+
+	status := metal3api.HostDeployPolicyStatus{
+		Capacity:    resources.Capacity,
+		Free:        resources.Free,
+		LastUpdated: policy.Status.LastUpdated,
+	}
+	for namespace, usage := range resources.Usage {
+		status.Usage = append(status.Usage, metal3api.NamespaceUsage{Namespace: namespace, HostResources: usage})
+	}
+	slices.SortFunc(status.Usage, func(a, b metal3api.NamespaceUsage) int {
+		return strings.Compare(a.Namespace, b.Namespace)
+	})
+
+	if equality.Semantic.DeepEqual(status, policy.Status) {
+		return ctrl.Result{}, nil
+	}
+	now := metav1.Now()
+	status.LastUpdated = &now
+	policy.Status = status
+	log.Info("updating status", "capacity", status.Capacity, "free", status.Free)
+	if err := r.Status().Update(ctx, policy); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update the status of the hostdeploypolicy: %w", err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *HostDeployPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	toNamespace := handler.EnqueueRequestsFromMapFunc(namespaceMapper[metal3api.HostDeployPolicyList](r.Client, r.Log))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&metal3api.HostDeployPolicy{}).
+		Watches(&metal3api.BareMetalHost{}, toNamespace).
+		Watches(&metal3api.HardwareData{}, toNamespace).
+		Complete(r)
+}
@@ -22,7 +22,9 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -71,6 +73,7 @@ type conditionReason string
 const (
 	reasonSuccess            conditionReason = "Success"
 	reasonConfigurationError conditionReason = "ConfigurationError"
+	reasonAwaitingApproval   conditionReason = "AwaitingApproval"
 )
 
 func (info *rInfo) publishEvent(reason, message string) {
@@ -253,7 +256,7 @@ func (r *HostFirmwareSettingsReconciler) updateStatus(ctx context.Context, info
 
 		// Run validation on the Spec to detect invalid values entered by user, including Spec settings not in Status
 		// Eventually this will be handled by a webhook
-		errors := r.validateHostFirmwareSettings(info, &newStatus, schema)
+		errors := validateHostFirmwareSettings(info.hfs, &newStatus, schema)
 		if len(errors) == 0 {
 			if setCondition(generation, &newStatus, info, metal3api.FirmwareSettingsValid, metav1.ConditionTrue, reason, "") {
 				dirty = true
@@ -279,6 +282,28 @@ func (r *HostFirmwareSettingsReconciler) updateStatus(ctx context.Context, info
 		}
 	}
 
+	newStatus.PendingChanges = firmwareSettingsChanges(info.hfs.Spec.Settings, newStatus.Settings, schema)
+	newStatus.PendingChangesDigest = firmwareSettingsChangesDigest(newStatus.PendingChanges)
+	if !reflect.DeepEqual(info.hfs.Status.PendingChanges, newStatus.PendingChanges) {
+		dirty = true
+	}
+
+	newStatus.Conditions = slices.DeleteFunc(newStatus.Conditions, func(cond metav1.Condition) bool {
+		return cond.Type == string(metal3api.FirmwareSettingsHeld)
+	})
+	if _, hold := info.hfs.Annotations[metal3api.FirmwareSettingsHoldAnnotation]; hold {
+		if firmwareSettingsHeld(info.hfs, &newStatus) {
+			setCondition(generation, &newStatus, info, metal3api.FirmwareSettingsHeld, metav1.ConditionTrue, reasonAwaitingApproval,
+				fmt.Sprintf("set the %s annotation to %s to apply the changes", metal3api.FirmwareSettingsApprovedAnnotation, newStatus.PendingChangesDigest))
+		} else {
+			setCondition(generation, &newStatus, info, metal3api.FirmwareSettingsHeld, metav1.ConditionFalse, reasonSuccess, "")
+		}
+	}
+	if !reflect.DeepEqual(meta.FindStatusCondition(info.hfs.Status.Conditions, string(metal3api.FirmwareSettingsHeld)),
+		meta.FindStatusCondition(newStatus.Conditions, string(metal3api.FirmwareSettingsHeld))) {
+		dirty = true
+	}
+
 	// Update Status if it has changed
 	if dirty {
 		info.log.Info("Status has changed")
@@ -378,6 +403,15 @@ func (r *HostFirmwareSettingsReconciler) updateEventHandler(e event.UpdateEvent)
 		return true
 	}
 
+	// Holding or approving the changes updates the Held condition.
+	for _, annotation := range []string{metal3api.FirmwareSettingsHoldAnnotation, metal3api.FirmwareSettingsApprovedAnnotation} {
+		oldValue, oldFound := e.ObjectOld.GetAnnotations()[annotation]
+		newValue, newFound := e.ObjectNew.GetAnnotations()[annotation]
+		if oldValue != newValue || oldFound != newFound {
+			return true
+		}
+	}
+
 	// NOTE(dtantsur): the only realistic case of a changed owner reference is when pre-created HFS is adopted by a BMH that was created later.
 	// In this case, it's reasonable to reconcile the resource using the information from the new BMH.
 	if !reflect.DeepEqual(e.ObjectNew.GetOwnerReferences(), e.ObjectOld.GetOwnerReferences()) {
@@ -390,10 +424,10 @@ func (r *HostFirmwareSettingsReconciler) updateEventHandler(e event.UpdateEvent)
 }
 
 // Validate the HostFirmwareSetting Spec against the schema.
-func (r *HostFirmwareSettingsReconciler) validateHostFirmwareSettings(info *rInfo, status *metal3api.HostFirmwareSettingsStatus, schema *metal3api.FirmwareSchema) []error {
+func validateHostFirmwareSettings(hfs *metal3api.HostFirmwareSettings, status *metal3api.HostFirmwareSettingsStatus, schema *metal3api.FirmwareSchema) []error {
 	var errs []error
 
-	for name, val := range info.hfs.Spec.Settings {
+	for name, val := range hfs.Spec.Settings {
 		// Prohibit any Spec settings with "Password"
 		if strings.Contains(name, "Password") {
 			errs = append(errs, errors.New("cannot set Password field"))
@@ -421,6 +455,64 @@ func (r *HostFirmwareSettingsReconciler) validateHostFirmwareSettings(info *rInf
 	return nil
 }
 
+// firmwareSettingsChanges lists the settings of the spec that differ from
+// the current ones, sorted by name, with the problems found by the
+// validation of each of them.
+func firmwareSettingsChanges(spec metal3api.DesiredSettingsMap, current metal3api.SettingsMap, schema *metal3api.FirmwareSchema) []metal3api.FirmwareSettingChange {
+	var changes []metal3api.FirmwareSettingChange
+	status := &metal3api.HostFirmwareSettingsStatus{Settings: current}
+	for _, name := range slices.Sorted(maps.Keys(spec)) {
+		value := spec[name]
+		currentValue, found := current[name]
+		if found && currentValue == value.String() {
+			continue
+		}
+
+		change := metal3api.FirmwareSettingChange{
+			Name:         name,
+			CurrentValue: currentValue,
+			NewValue:     value.String(),
+		}
+		if schema != nil {
+			if settingSchema, found := schema.Spec.Schema[name]; found && settingSchema.ResetRequired != nil {
+				change.RequiresReboot = *settingSchema.ResetRequired
+			}
+		}
+		single := &metal3api.HostFirmwareSettings{
+			Spec: metal3api.HostFirmwareSettingsSpec{Settings: metal3api.DesiredSettingsMap{name: value}},
+		}
+		if errs := validateHostFirmwareSettings(single, status, schema); len(errs) > 0 {
+			change.Problem = errors.Join(errs...).Error()
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// firmwareSettingsChangesDigest returns a short hash identifying the
+// changes, or an empty string if there are none.
+func firmwareSettingsChangesDigest(changes []metal3api.FirmwareSettingChange) string {
+	if len(changes) == 0 {
+		return ""
+	}
+	hash := sha256.New()
+	for _, change := range changes {
+		fmt.Fprintf(hash, "%s=%s->%s\n", change.Name, change.CurrentValue, change.NewValue)
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
+// firmwareSettingsHeld tells whether the pending changes of the settings
+// are held by the hold annotation and have not been approved.
+func firmwareSettingsHeld(hfs *metal3api.HostFirmwareSettings, status *metal3api.HostFirmwareSettingsStatus) bool {
+	if _, hold := hfs.Annotations[metal3api.FirmwareSettingsHoldAnnotation]; !hold || len(status.PendingChanges) == 0 {
+		return false
+	}
+	// An empty digest with pending changes comes from an outdated status.
+	return status.PendingChangesDigest == "" ||
+		hfs.Annotations[metal3api.FirmwareSettingsApprovedAnnotation] != status.PendingChangesDigest
+}
+
 func (r *HostFirmwareSettingsReconciler) publishEvent(ctx context.Context, request ctrl.Request, event corev1.Event) {
 	reqLogger := r.Log.WithValues("hostfirmwaresettings", request.NamespacedName)
 	reqLogger.Info("publishing event", "reason", event.Reason, "message", event.Message)
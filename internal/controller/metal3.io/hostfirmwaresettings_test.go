@@ -10,6 +10,7 @@ import (
 	"github.com/metal3-io/baremetal-operator/pkg/provisioner/fixture"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -119,6 +120,7 @@ func getCurrentSchemaSettings() map[string]metal3api.SettingSchema {
 			AttributeType:   "Enumeration",
 			AllowableValues: []string{"Enabled", "Disabled"},
 			ReadOnly:        &iFalse,
+			ResetRequired:   &iTrue,
 		},
 		"SecureBoot": {
 			AttributeType:   "Enumeration",
@@ -354,6 +356,11 @@ func TestStoreHostFirmwareSettings(t *testing.T) {
 						{Type: "ChangeDetected", Status: "True", Reason: "Success"},
 						{Type: "Valid", Status: "True", Reason: "Success"},
 					},
+					PendingChanges: []metal3api.FirmwareSettingChange{
+						{Name: "AssetTag", CurrentValue: "X45672917", NewValue: "Z98765432"},
+						{Name: "NetworkBootRetryCount", CurrentValue: "20", NewValue: "10"},
+						{Name: "ProcVirtualization", CurrentValue: "Disabled", NewValue: "Enabled", RequiresReboot: true},
+					},
 				},
 			},
 			SpecIsValid: true,
@@ -410,6 +417,10 @@ func TestStoreHostFirmwareSettings(t *testing.T) {
 						{Type: "ChangeDetected", Status: "True", Reason: "Success"},
 						{Type: "Valid", Status: "False", Reason: "ConfigurationError", Message: "Invalid BIOS setting"},
 					},
+					PendingChanges: []metal3api.FirmwareSettingChange{
+						{Name: "NetworkBootRetryCount", CurrentValue: "20", NewValue: "1000", Problem: "Setting NetworkBootRetryCount is invalid, integer 1000 is above maximum value 20"},
+						{Name: "ProcVirtualization", CurrentValue: "Disabled", NewValue: "Enabled", RequiresReboot: true},
+					},
 				},
 			},
 			SpecIsValid: false,
@@ -514,6 +525,8 @@ func TestStoreHostFirmwareSettings(t *testing.T) {
 			err = r.Client.Get(ctx, key, actualSettings)
 			require.NoError(t, err)
 
+			tc.ExpectedSettings.Status.PendingChangesDigest = firmwareSettingsChangesDigest(tc.ExpectedSettings.Status.PendingChanges)
+
 			// Use the same time for expected and actual
 			currentTime := metav1.Now()
 			tc.ExpectedSettings.Status.LastUpdated = &currentTime
@@ -628,13 +641,8 @@ func TestValidateHostFirmwareSettings(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.Scenario, func(t *testing.T) {
 			hfs := getHFS(tc.SpecSettings)
-			r := getTestHFSReconciler(hfs)
-			info := &rInfo{
-				log: logf.Log.WithName("controllers").WithName("HostFirmwareSettings"),
-				hfs: hfs,
-			}
 
-			errors := r.validateHostFirmwareSettings(info, &info.hfs.Status, getExpectedSchema())
+			errors := validateHostFirmwareSettings(hfs, &hfs.Status, getExpectedSchema())
 			if len(errors) == 0 {
 				assert.Empty(t, tc.ExpectedError)
 			} else {
@@ -645,3 +653,109 @@ func TestValidateHostFirmwareSettings(t *testing.T) {
 		})
 	}
 }
+
+// Test holding the changes of the settings until they are approved.
+func TestHoldHostFirmwareSettings(t *testing.T) {
+	testCases := []struct {
+		Scenario string
+		// whether the hold annotation is set
+		Hold bool
+		// the value of the approved annotation, approving the current digest when "digest"
+		Approved string
+		// the desired value of the setting, "Disabled" being the current one
+		Value string
+		// the expected status of the Held condition, empty when absent
+		ExpectedHeld metav1.ConditionStatus
+	}{
+		{
+			Scenario:     "no hold",
+			ExpectedHeld: "",
+		},
+		{
+			Scenario:     "held",
+			Hold:         true,
+			ExpectedHeld: metav1.ConditionTrue,
+		},
+		{
+			Scenario:     "approved another digest",
+			Hold:         true,
+			Approved:     "0123456789abcdef",
+			ExpectedHeld: metav1.ConditionTrue,
+		},
+		{
+			Scenario:     "approved",
+			Hold:         true,
+			Approved:     "digest",
+			ExpectedHeld: metav1.ConditionFalse,
+		},
+		{
+			Scenario:     "held without pending changes",
+			Hold:         true,
+			Value:        "Disabled",
+			ExpectedHeld: metav1.ConditionFalse,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			ctx := t.Context()
+
+			value := "Enabled"
+			if tc.Value != "" {
+				value = tc.Value
+			}
+			hfs := getHFS(metal3api.HostFirmwareSettingsSpec{
+				Settings: metal3api.DesiredSettingsMap{
+					"ProcVirtualization": intstr.FromString(value),
+				},
+			})
+			hfs.Annotations = map[string]string{}
+			if tc.Hold {
+				hfs.Annotations[metal3api.FirmwareSettingsHoldAnnotation] = ""
+			}
+			if tc.Approved == "digest" {
+				hfs.Annotations[metal3api.FirmwareSettingsApprovedAnnotation] = firmwareSettingsChangesDigest(
+					firmwareSettingsChanges(hfs.Spec.Settings, getCurrentSettings(), getExpectedSchema()))
+			} else if tc.Approved != "" {
+				hfs.Annotations[metal3api.FirmwareSettingsApprovedAnnotation] = tc.Approved
+			}
+
+			r := getTestHFSReconciler(hfs)
+			bmh := createBaremetalHost()
+			prov := getMockProvisioner(bmh, getCurrentSettings(), getCurrentSchemaSettings())
+			info := &rInfo{
+				log: logf.Log.WithName("controllers").WithName("HostFirmwareSettings"),
+				hfs: hfs,
+				bmh: bmh,
+			}
+
+			currentSettings, schema, err := prov.GetFirmwareSettings(ctx, true)
+			require.NoError(t, err)
+			err = r.updateHostFirmwareSettings(ctx, currentSettings, schema, info)
+			require.NoError(t, err)
+
+			actual := &metal3api.HostFirmwareSettings{}
+			err = r.Client.Get(ctx, client.ObjectKeyFromObject(hfs), actual)
+			require.NoError(t, err)
+
+			if value == "Disabled" {
+				assert.Empty(t, actual.Status.PendingChanges)
+				assert.Empty(t, actual.Status.PendingChangesDigest)
+			} else {
+				assert.Equal(t, []metal3api.FirmwareSettingChange{
+					{Name: "ProcVirtualization", CurrentValue: "Disabled", NewValue: "Enabled", RequiresReboot: true},
+				}, actual.Status.PendingChanges)
+				assert.NotEmpty(t, actual.Status.PendingChangesDigest)
+			}
+
+			cond := meta.FindStatusCondition(actual.Status.Conditions, string(metal3api.FirmwareSettingsHeld))
+			if tc.ExpectedHeld == "" {
+				assert.Nil(t, cond)
+			} else {
+				require.NotNil(t, cond)
+				assert.Equal(t, tc.ExpectedHeld, cond.Status)
+			}
+			assert.Equal(t, tc.ExpectedHeld == metav1.ConditionTrue, firmwareSettingsHeld(actual, &actual.Status))
+		})
+	}
+}
@@ -0,0 +1,531 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+const (
+	defaultCriticalHealthTimeout = 10 * time.Minute
+	defaultPowerFailureThreshold = 3
+	defaultPowerFailureWindow    = time.Hour
+	defaultRemediationBackoff    = 5 * time.Minute
+	defaultMaxRemediationBackoff = time.Hour
+
+	remediationSucceeded = "succeeded"
+	remediationExhausted = "exhausted"
+
+	// remediationReservationTimeout is how long the remediation slot
+	// reserved by a host counts against the limit of the namespace before
+	// the first action is taken.
+	remediationReservationTimeout = 10 * time.Minute
+)
+
+// HostRemediationPolicyReconciler takes the actions of a
+// HostRemediationPolicy on the unhealthy BareMetalHosts it selects.
+type HostRemediationPolicyReconciler struct {
+	client.Client
+	Log       logr.Logger
+	APIReader client.Reader
+}
+
+//+kubebuilder:rbac:groups=metal3.io,resources=hostremediationpolicies,verbs=get;list;watch
+//+kubebuilder:rbac:groups=metal3.io,resources=hostremediationpolicies/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=metal3.io,resources=baremetalhosts,verbs=get;list;watch;update
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;update;patch
+
+// Reconcile follows the health of the selected hosts and takes the next
+// action on those that stay unhealthy, within the maximum of concurrent
+// remediations of the namespace.
+func (r *HostRemediationPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("hostremediationpolicy", req.NamespacedName)
+
+	policy := &metal3api.HostRemediationPolicy{}
+	if err := r.Get(ctx, req.NamespacedName, policy); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("could not load hostremediationpolicy: %w", err)
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&policy.Spec.Selector)
+	if err != nil {
+		log.Error(err, "invalid selector")
+		return ctrl.Result{}, nil
+	}
+
+	hostList := metal3api.BareMetalHostList{}
+	if err := r.List(ctx, &hostList, client.InNamespace(policy.Namespace)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list hosts: %w", err)
+	}
+	slices.SortFunc(hostList.Items, func(a, b metal3api.BareMetalHost) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	// The hosts remediated by any policy count against the limit. The
+	// cache may be stale, the limit is checked again without it before a
+	// remediation starts.
+	remediations := 0
+	for _, host := range hostList.Items {
+		if _, remediating := host.Annotations[metal3api.RemediationAnnotation]; remediating {
+			remediations++
+		}
+	}
+
+	previous := map[string]metal3api.HostRemediationStatus{}
+	for _, entry := range policy.Status.Hosts {
+		previous[entry.Name] = entry
+	}
+
+	now := time.Now()
+	status := metal3api.HostRemediationPolicyStatus{}
+	var requeueAfter time.Duration
+	for i := range hostList.Items {
+		host := &hostList.Items[i]
+		if !selector.Matches(labels.Set(host.Labels)) || host.DeletionTimestamp != nil {
+			continue
+		}
+		if owner := remediationOwner(host); owner != "" && owner != policy.Name {
+			continue
+		}
+
+		entry, wait, err := r.remediateHost(ctx, log, policy, host, previous[host.Name], &remediations, now)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if entry != nil {
+			status.Hosts = append(status.Hosts, *entry)
+		}
+		if host.Annotations[metal3api.RemediationAnnotation] == policy.Name {
+			status.Remediating++
+		}
+		if wait > 0 && (requeueAfter == 0 || wait < requeueAfter) {
+			requeueAfter = wait
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, r.updateRemediationStatus(ctx, log, policy, status)
+}
+
+// remediateHost updates the remediation of a host and takes the next action
+// when it is due. It returns the status of the host, or nil when the host
+// is healthy, and how long to wait before looking at it again.
+func (r *HostRemediationPolicyReconciler) remediateHost(ctx context.Context, log logr.Logger, policy *metal3api.HostRemediationPolicy, host *metal3api.BareMetalHost, entry metal3api.HostRemediationStatus, remediations *int, now time.Time) (*metal3api.HostRemediationStatus, time.Duration, error) {
+	entry.Name = host.Name
+	entry.Message = ""
+
+	// The actions taken are recorded on the host, the status only
+	// reports them.
+	attempt, _ := remediationAttempt(host)
+	entry.Attempts = attempt.Attempts
+	entry.LastAction = attempt.LastAction
+	entry.LastActionTime = attempt.LastActionTime
+	if attempt.Reason != "" {
+		entry.Reason = attempt.Reason
+	}
+
+	critical, criticalSince := hostCriticalHealth(host)
+	powerFailing := hostPowerFailing(host)
+	window := durationOrDefault(policy.Spec.PowerFailureWindow, defaultPowerFailureWindow)
+	entry.PowerFailureTimes = slices.DeleteFunc(slices.Clone(entry.PowerFailureTimes), func(failure metav1.Time) bool {
+		return now.Sub(failure.Time) >= window
+	})
+	if powerFailing && !entry.PowerFailing {
+		entry.PowerFailureTimes = append(entry.PowerFailureTimes, metav1.Time{Time: now})
+	}
+	entry.PowerFailures = len(entry.PowerFailureTimes)
+	entry.PowerFailing = powerFailing
+	healthy := !critical && !powerFailing && entry.PowerFailures == 0
+
+	_, remediating := host.Annotations[metal3api.RemediationAnnotation]
+	switch {
+	case remediating:
+		if entry.Attempts == 0 {
+			// The slot was reserved but the first action was not taken.
+			if healthy {
+				return nil, 0, r.releaseRemediationSlot(ctx, host)
+			}
+			return r.startRemediation(ctx, log, policy, host, &entry, now)
+		}
+		if entry.LastActionTime != nil {
+			elapsed := now.Sub(entry.LastActionTime.Time)
+			if wait := remediationBackoff(policy, entry.Attempts) - elapsed; wait > 0 {
+				return &entry, wait, nil
+			}
+		}
+		if healthy {
+			return nil, 0, r.endRemediation(ctx, log, policy, host, remediationSucceeded)
+		}
+		if entry.Attempts >= len(policy.Spec.Actions) {
+			entry.Message = "all the remediation actions have been taken"
+			return &entry, 0, r.endRemediation(ctx, log, policy, host, remediationExhausted)
+		}
+		return &entry, remediationBackoff(policy, entry.Attempts+1), r.takeRemediationAction(ctx, log, policy, host, &entry, now)
+
+	case entry.Attempts > 0:
+		// All the actions have been taken, the host is left to an
+		// administrator until it is healthy again.
+		if healthy {
+			return nil, 0, r.releaseHost(ctx, host)
+		}
+		entry.Message = "all the remediation actions have been taken"
+		return &entry, 0, nil
+	}
+
+	var wait time.Duration
+	entry.Reason = ""
+	if critical {
+		timeout := durationOrDefault(policy.Spec.CriticalHealthTimeout, defaultCriticalHealthTimeout)
+		if elapsed := now.Sub(criticalSince); elapsed >= timeout {
+			entry.Reason = metal3api.CriticalHealthRemediationReason
+		} else {
+			wait = timeout - elapsed
+		}
+	}
+	threshold := policy.Spec.PowerFailureThreshold
+	if threshold <= 0 {
+		threshold = defaultPowerFailureThreshold
+	}
+	if entry.Reason == "" && entry.PowerFailures >= threshold {
+		entry.Reason = metal3api.PowerFailureRemediationReason
+	}
+
+	if entry.Reason == "" {
+		if !critical && !powerFailing && entry.PowerFailures == 0 {
+			return nil, 0, nil
+		}
+		if entry.PowerFailures > 0 {
+			// Look again when the oldest power failure leaves the window.
+			expires := window - now.Sub(entry.PowerFailureTimes[0].Time)
+			if wait == 0 || expires < wait {
+				wait = expires
+			}
+		}
+		return &entry, wait, nil
+	}
+
+	if *remediations >= max(policy.Spec.MaxConcurrentRemediations, 1) {
+		return delayRemediation(policy, &entry, *remediations), 0, nil
+	}
+	*remediations++
+	return r.startRemediation(ctx, log, policy, host, &entry, now)
+}
+
+// startRemediation reserves a remediation slot for the host, then takes the
+// first action unless the other hosts of the namespace already hold all
+// the slots.
+func (r *HostRemediationPolicyReconciler) startRemediation(ctx context.Context, log logr.Logger, policy *metal3api.HostRemediationPolicy, host *metal3api.BareMetalHost, entry *metal3api.HostRemediationStatus, now time.Time) (*metal3api.HostRemediationStatus, time.Duration, error) {
+	remediations, err := r.reserveRemediationSlot(ctx, policy, host, entry.Reason, now)
+	if err != nil {
+		return nil, 0, err
+	}
+	if remediations >= max(policy.Spec.MaxConcurrentRemediations, 1) {
+		if err := r.releaseRemediationSlot(ctx, host); err != nil {
+			return nil, 0, err
+		}
+		return delayRemediation(policy, entry, remediations), 0, nil
+	}
+	return entry, remediationBackoff(policy, 1), r.takeRemediationAction(ctx, log, policy, host, entry, now)
+}
+
+// delayRemediation reports that the host waits for a remediation slot.
+func delayRemediation(policy *metal3api.HostRemediationPolicy, entry *metal3api.HostRemediationStatus, remediations int) *metal3api.HostRemediationStatus {
+	entry.Message = fmt.Sprintf("waiting for one of the %d remediations of the namespace to end", remediations)
+	hostRemediationsDelayed.With(remediationMetricLabels(policy)).Inc()
+	return entry
+}
+
+// reserveRemediationSlot records the start of the remediation on the host,
+// unless it already did, then returns the number of hosts of the namespace
+// that are remediated or reserved a slot before it.
+func (r *HostRemediationPolicyReconciler) reserveRemediationSlot(ctx context.Context, policy *metal3api.HostRemediationPolicy, host *metal3api.BareMetalHost, reason metal3api.HostRemediationReason, now time.Time) (int, error) {
+	attempt, found := remediationAttempt(host)
+	if !found || host.Annotations[metal3api.RemediationAnnotation] != policy.Name {
+		// The JSON encoding of the start time keeps microseconds only.
+		attempt = metal3api.HostRemediationAttempt{
+			Policy:    policy.Name,
+			Reason:    reason,
+			StartTime: metav1.NewMicroTime(now.Truncate(time.Microsecond)),
+		}
+		if host.Annotations == nil {
+			host.Annotations = map[string]string{}
+		}
+		host.Annotations[metal3api.RemediationAnnotation] = policy.Name
+		if err := setRemediationAttempt(host, attempt); err != nil {
+			return 0, err
+		}
+		if err := r.Update(ctx, host); err != nil {
+			return 0, fmt.Errorf("failed to reserve a remediation slot for host %s: %w", host.Name, err)
+		}
+	}
+
+	slots := hostSlots{
+		scope:   []client.ListOption{client.InNamespace(host.Namespace)},
+		timeout: remediationReservationTimeout,
+		reservation: func(host *metal3api.BareMetalHost) (time.Time, bool) {
+			attempt, found := remediationAttempt(host)
+			if _, remediating := host.Annotations[metal3api.RemediationAnnotation]; !remediating || !found {
+				return time.Time{}, false
+			}
+			return attempt.StartTime.Time, true
+		},
+		// A host holds its slot once an action was taken on it.
+		active: func(host *metal3api.BareMetalHost) bool {
+			if _, remediating := host.Annotations[metal3api.RemediationAnnotation]; !remediating {
+				return false
+			}
+			attempt, found := remediationAttempt(host)
+			return !found || attempt.Attempts > 0
+		},
+	}
+	return slots.taken(ctx, r.APIReader, host, attempt.StartTime.Time, now)
+}
+
+// releaseRemediationSlot removes the remediation slot reserved by the host
+// before any action was taken.
+func (r *HostRemediationPolicyReconciler) releaseRemediationSlot(ctx context.Context, host *metal3api.BareMetalHost) error {
+	delete(host.Annotations, metal3api.RemediationAnnotation)
+	delete(host.Annotations, metal3api.RemediationAttemptAnnotation)
+	if err := r.Update(ctx, host); err != nil {
+		return fmt.Errorf("failed to release the remediation slot of host %s: %w", host.Name, err)
+	}
+	return nil
+}
+
+// takeRemediationAction takes the next action of the policy on the host,
+// and records it on the host in the same update.
+func (r *HostRemediationPolicyReconciler) takeRemediationAction(ctx context.Context, log logr.Logger, policy *metal3api.HostRemediationPolicy, host *metal3api.BareMetalHost, entry *metal3api.HostRemediationStatus, now time.Time) error {
+	action := policy.Spec.Actions[entry.Attempts]
+	if host.Annotations == nil {
+		host.Annotations = map[string]string{}
+	}
+	host.Annotations[metal3api.RemediationAnnotation] = policy.Name
+	attempt, _ := remediationAttempt(host)
+	attempt.Policy = policy.Name
+	attempt.Reason = entry.Reason
+	attempt.Attempts = entry.Attempts + 1
+	attempt.LastAction = action
+	attempt.LastActionTime = &metav1.Time{Time: now}
+	if err := setRemediationAttempt(host, attempt); err != nil {
+		return err
+	}
+
+	switch action {
+	case metal3api.HostRemediationCordon:
+		if !slices.ContainsFunc(host.Spec.Taints, isCordonTaint) {
+			host.Spec.Taints = append(host.Spec.Taints, metal3api.CordonTaint())
+		}
+	case metal3api.HostRemediationReboot:
+		host.Annotations[metal3api.RebootAnnotationPrefix] = ""
+	case metal3api.HostRemediationInspect:
+		// The annotation is rejected for the hosts with inspection
+		// disabled, the action is skipped for them.
+		if !host.InspectionDisabled() {
+			host.Annotations[metal3api.InspectAnnotationPrefix] = ""
+		}
+	case metal3api.HostRemediationReplace:
+		host.Annotations[metal3api.ReplaceAnnotation] = fmt.Sprintf("%s not fixed by the HostRemediationPolicy %s",
+			entry.Reason, policy.Name)
+	}
+
+	log.Info("taking remediation action", "host", host.Name, "action", action, "reason", entry.Reason)
+	if err := r.Update(ctx, host); err != nil {
+		return fmt.Errorf("failed to take remediation action %s on host %s: %w", action, host.Name, err)
+	}
+
+	entry.Attempts = attempt.Attempts
+	entry.LastAction = attempt.LastAction
+	entry.LastActionTime = attempt.LastActionTime
+	entry.PowerFailures = 0
+	entry.PowerFailureTimes = nil
+	entry.Message = ""
+
+	r.publishEvent(ctx, log, host, "HostRemediation",
+		fmt.Sprintf("Remediation action %s taken for %s (%d of %d)",
+			action, entry.Reason, entry.Attempts, len(policy.Spec.Actions)))
+	hostRemediationActions.With(remediationActionMetricLabels(policy, string(action))).Inc()
+	return nil
+}
+
+// endRemediation releases the remediation slot of the host. The host is
+// uncordoned when the remediation succeeded, otherwise the actions taken
+// stay recorded on it.
+func (r *HostRemediationPolicyReconciler) endRemediation(ctx context.Context, log logr.Logger, policy *metal3api.HostRemediationPolicy, host *metal3api.BareMetalHost, result string) error {
+	delete(host.Annotations, metal3api.RemediationAnnotation)
+	reason, message := "HostRemediationSucceeded", "Host is healthy again after its remediation"
+	if result == remediationSucceeded {
+		delete(host.Annotations, metal3api.RemediationAttemptAnnotation)
+		host.Spec.Taints = slices.DeleteFunc(host.Spec.Taints, isCordonTaint)
+	} else {
+		reason, message = "HostRemediationExhausted", "Host is still unhealthy after all the remediation actions"
+	}
+
+	log.Info("remediation ended", "host", host.Name, "result", result)
+	if err := r.Update(ctx, host); err != nil {
+		return fmt.Errorf("failed to end the remediation of host %s: %w", host.Name, err)
+	}
+	r.publishEvent(ctx, log, host, reason, message)
+	hostRemediationActions.With(remediationActionMetricLabels(policy, result)).Inc()
+	return nil
+}
+
+// releaseHost uncordons a host that is healthy again after all the
+// remediation actions were taken, and forgets these actions.
+func (r *HostRemediationPolicyReconciler) releaseHost(ctx context.Context, host *metal3api.BareMetalHost) error {
+	delete(host.Annotations, metal3api.RemediationAttemptAnnotation)
+	host.Spec.Taints = slices.DeleteFunc(host.Spec.Taints, isCordonTaint)
+	if err := r.Update(ctx, host); err != nil {
+		return fmt.Errorf("failed to uncordon host %s: %w", host.Name, err)
+	}
+	return nil
+}
+
+func (r *HostRemediationPolicyReconciler) publishEvent(ctx context.Context, log logr.Logger, host *metal3api.BareMetalHost, reason, message string) {
+	event := host.NewEvent(reason, message)
+	if reason != "HostRemediationSucceeded" {
+		event.Type = corev1.EventTypeWarning
+	}
+	if err := r.Create(ctx, &event); err != nil {
+		log.Info("failed to record event, ignoring",
+			"reason", event.Reason, "message", event.Message, "error", err)
+	}
+}
+
+func (r *HostRemediationPolicyReconciler) updateRemediationStatus(ctx context.Context, log logr.Logger, policy *metal3api.HostRemediationPolicy, status metal3api.HostRemediationPolicyStatus) error {
+	if equality.Semantic.DeepEqual(status, policy.Status) {
+		return nil
+	}
+	policy.Status = status
+	log.Info("updating status", "hosts", len(status.Hosts), "remediating", status.Remediating)
+	if err := r.Status().Update(ctx, policy); err != nil {
+		return fmt.Errorf("failed to update the status of the hostremediationpolicy: %w", err)
+	}
+	return nil
+}
+
+// remediationAttempt returns the remediation recorded on the host.
+func remediationAttempt(host *metal3api.BareMetalHost) (metal3api.HostRemediationAttempt, bool) {
+	attempt := metal3api.HostRemediationAttempt{}
+	value, found := host.Annotations[metal3api.RemediationAttemptAnnotation]
+	if !found {
+		return attempt, false
+	}
+	if err := json.Unmarshal([]byte(value), &attempt); err != nil {
+		// An invalid annotation does not record any action.
+		return metal3api.HostRemediationAttempt{}, false
+	}
+	return attempt, true
+}
+
+func setRemediationAttempt(host *metal3api.BareMetalHost, attempt metal3api.HostRemediationAttempt) error {
+	value, err := json.Marshal(attempt)
+	if err != nil {
+		return fmt.Errorf("failed to encode the remediation of host %s: %w", host.Name, err)
+	}
+	host.Annotations[metal3api.RemediationAttemptAnnotation] = string(value)
+	return nil
+}
+
+// remediationOwner returns the name of the policy remediating the host, or
+// of the policy that took all its actions on it.
+func remediationOwner(host *metal3api.BareMetalHost) string {
+	if owner, remediating := host.Annotations[metal3api.RemediationAnnotation]; remediating {
+		return owner
+	}
+	attempt, _ := remediationAttempt(host)
+	return attempt.Policy
+}
+
+// hostCriticalHealth tells whether the BMC of the host reports a critical
+// health, and since when.
+func hostCriticalHealth(host *metal3api.BareMetalHost) (bool, time.Time) {
+	cond := meta.FindStatusCondition(host.Status.Conditions, metal3api.HealthyCondition)
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != metal3api.CriticalHealthReason {
+		return false, time.Time{}
+	}
+	return true, cond.LastTransitionTime.Time
+}
+
+// hostPowerFailing tells whether the host has a power failure.
+func hostPowerFailing(host *metal3api.BareMetalHost) bool {
+	cond := meta.FindStatusCondition(host.Status.Conditions, metal3api.ManageableCondition)
+	return cond != nil && cond.Reason == metal3api.PowerFailureReason
+}
+
+func isCordonTaint(taint corev1.Taint) bool {
+	return taint.Key == metal3api.CordonTaintKey
+}
+
+// remediationBackoff is how long the given action is given to fix the
+// host, doubling after each action up to the maximum backoff.
+func remediationBackoff(policy *metal3api.HostRemediationPolicy, attempt int) time.Duration {
+	backoff := durationOrDefault(policy.Spec.Backoff, defaultRemediationBackoff)
+	maxBackoff := durationOrDefault(policy.Spec.MaxBackoff, defaultMaxRemediationBackoff)
+	for i := 1; i < attempt && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxBackoff)
+}
+
+func durationOrDefault(duration metav1.Duration, defaultDuration time.Duration) time.Duration {
+	if duration.Duration <= 0 {
+		return defaultDuration
+	}
+	return duration.Duration
+}
+
+func remediationMetricLabels(policy *metal3api.HostRemediationPolicy) prometheus.Labels {
+	return prometheus.Labels{
+		labelHostNamespace: policy.Namespace,
+		labelPolicy:        policy.Name,
+	}
+}
+
+func remediationActionMetricLabels(policy *metal3api.HostRemediationPolicy, action string) prometheus.Labels {
+	return prometheus.Labels{
+		labelHostNamespace: policy.Namespace,
+		labelPolicy:        policy.Name,
+		labelAction:        action,
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *HostRemediationPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&metal3api.HostRemediationPolicy{}).
+		Watches(&metal3api.BareMetalHost{}, handler.EnqueueRequestsFromMapFunc(namespaceMapper[metal3api.HostRemediationPolicyList](r.Client, r.Log))).
+		Complete(r)
+}
@@ -0,0 +1,520 @@
+package controllers
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type remediationHostHealth int
+
+const (
+	remediationHostHealthy remediationHostHealth = iota
+	remediationHostCritical
+	remediationHostPowerFailure
+)
+
+func newRemediationHost(name string, health remediationHostHealth, since time.Duration) *metal3api.BareMetalHost {
+	host := newPolicyHost(name).host
+	transition := metav1.NewTime(time.Now().Add(-since))
+
+	healthy := metav1.Condition{Type: metal3api.HealthyCondition, Status: metav1.ConditionTrue,
+		Reason: metal3api.HealthyReason, LastTransitionTime: transition}
+	manageable := metav1.Condition{Type: metal3api.ManageableCondition, Status: metav1.ConditionTrue,
+		Reason: metal3api.ManageableReason, LastTransitionTime: transition}
+	switch health {
+	case remediationHostCritical:
+		healthy.Status, healthy.Reason = metav1.ConditionFalse, metal3api.CriticalHealthReason
+	case remediationHostPowerFailure:
+		manageable.Status, manageable.Reason = metav1.ConditionFalse, metal3api.PowerFailureReason
+	case remediationHostHealthy:
+	}
+	host.Status.Conditions = []metav1.Condition{healthy, manageable}
+	return host
+}
+
+// remediatedHost marks a host as remediated by the policy. The host has
+// reserved a remediation slot without taking any action when attempts is
+// 0.
+func remediatedHost(host *metal3api.BareMetalHost, policy *metal3api.HostRemediationPolicy, attempts int, since time.Duration) *metal3api.BareMetalHost {
+	attempt := metal3api.HostRemediationAttempt{
+		Policy:    policy.Name,
+		Reason:    metal3api.CriticalHealthRemediationReason,
+		StartTime: metav1.NewMicroTime(time.Now().Add(-since)),
+		Attempts:  attempts,
+	}
+	if attempts > 0 {
+		attempt.LastAction = policy.Spec.Actions[attempts-1]
+		attempt.LastActionTime = &metav1.Time{Time: time.Now().Add(-since)}
+		host.Spec.Taints = append(host.Spec.Taints, metal3api.CordonTaint())
+	}
+	value, _ := json.Marshal(attempt)
+	host.Annotations = map[string]string{
+		metal3api.RemediationAnnotation:        policy.Name,
+		metal3api.RemediationAttemptAnnotation: string(value),
+	}
+	return host
+}
+
+func getRemediationAttempt(t *testing.T, host *metal3api.BareMetalHost) (metal3api.HostRemediationAttempt, bool) {
+	t.Helper()
+	attempt := metal3api.HostRemediationAttempt{}
+	value, found := host.Annotations[metal3api.RemediationAttemptAnnotation]
+	if found {
+		require.NoError(t, json.Unmarshal([]byte(value), &attempt))
+	}
+	return attempt, found
+}
+
+func newRemediationPolicy(actions ...metal3api.HostRemediationAction) *metal3api.HostRemediationPolicy {
+	return &metal3api.HostRemediationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "remediation", Namespace: namespace},
+		Spec: metal3api.HostRemediationPolicySpec{
+			Selector: policySelector(),
+			Actions:  actions,
+		},
+	}
+}
+
+func getRemediationHost(t *testing.T, c client.Client, name string) *metal3api.BareMetalHost {
+	t.Helper()
+	host := &metal3api.BareMetalHost{}
+	require.NoError(t, c.Get(t.Context(), types.NamespacedName{Name: name, Namespace: namespace}, host))
+	return host
+}
+
+func remediationEntries(policy *metal3api.HostRemediationPolicy) map[string]metal3api.HostRemediationStatus {
+	entries := map[string]metal3api.HostRemediationStatus{}
+	for _, entry := range policy.Status.Hosts {
+		entry.LastActionTime = nil
+		entries[entry.Name] = entry
+	}
+	return entries
+}
+
+func TestHostRemediationPolicyStartsRemediation(t *testing.T) {
+	other := newRemediationHost("other", remediationHostCritical, time.Hour)
+	other.Labels = nil
+
+	policy, result, c := reconcileObject(t,
+		newRemediationPolicy(metal3api.HostRemediationCordon, metal3api.HostRemediationReboot),
+		newRemediationHost("host-b", remediationHostCritical, 20*time.Minute),
+		newRemediationHost("host-a", remediationHostCritical, 20*time.Minute),
+		newRemediationHost("host-c", remediationHostCritical, time.Minute),
+		newRemediationHost("host-d", remediationHostHealthy, time.Hour),
+		other,
+	)
+
+	assert.Equal(t, map[string]metal3api.HostRemediationStatus{
+		"host-a": {
+			Name:       "host-a",
+			Reason:     metal3api.CriticalHealthRemediationReason,
+			Attempts:   1,
+			LastAction: metal3api.HostRemediationCordon,
+		},
+		"host-b": {
+			Name:    "host-b",
+			Reason:  metal3api.CriticalHealthRemediationReason,
+			Message: "waiting for one of the 1 remediations of the namespace to end",
+		},
+		"host-c": {Name: "host-c"},
+	}, remediationEntries(policy))
+	assert.Equal(t, 1, policy.Status.Remediating)
+	assert.NotZero(t, result.RequeueAfter)
+	assert.LessOrEqual(t, result.RequeueAfter, defaultRemediationBackoff)
+
+	hostA := getRemediationHost(t, c, "host-a")
+	assert.Equal(t, policy.Name, hostA.Annotations[metal3api.RemediationAnnotation])
+	assert.True(t, hostA.IsCordoned())
+	attempt, _ := getRemediationAttempt(t, hostA)
+	assert.Equal(t, policy.Name, attempt.Policy)
+	assert.Equal(t, metal3api.CriticalHealthRemediationReason, attempt.Reason)
+	assert.Equal(t, 1, attempt.Attempts)
+	assert.Equal(t, metal3api.HostRemediationCordon, attempt.LastAction)
+	assert.NotNil(t, attempt.LastActionTime)
+	for _, name := range []string{"host-b", "host-c", "host-d", "other"} {
+		host := getRemediationHost(t, c, name)
+		assert.NotContains(t, host.Annotations, metal3api.RemediationAnnotation, name)
+		assert.NotContains(t, host.Annotations, metal3api.RemediationAttemptAnnotation, name)
+		assert.False(t, host.IsCordoned(), name)
+	}
+}
+
+func TestHostRemediationPolicyAttemptsFromHost(t *testing.T) {
+	policy := newRemediationPolicy(metal3api.HostRemediationCordon, metal3api.HostRemediationReboot)
+	host := remediatedHost(newRemediationHost("host", remediationHostCritical, time.Hour), policy, 1, time.Hour)
+
+	// The status of the policy was lost, the host still records the
+	// action taken.
+	policy, _, c := reconcileObject(t, policy, host)
+
+	entry := remediationEntries(policy)["host"]
+	assert.Equal(t, 2, entry.Attempts)
+	assert.Equal(t, metal3api.HostRemediationReboot, entry.LastAction)
+	attempt, _ := getRemediationAttempt(t, getRemediationHost(t, c, "host"))
+	assert.Equal(t, 2, attempt.Attempts)
+	assert.Equal(t, metal3api.HostRemediationReboot, attempt.LastAction)
+}
+
+func TestHostRemediationPolicyConcurrentRemediations(t *testing.T) {
+	policy := newRemediationPolicy(metal3api.HostRemediationReboot)
+	policy.Spec.MaxConcurrentRemediations = 2
+	busy := newRemediationHost("busy", remediationHostCritical, time.Hour)
+	busy.Labels = nil
+	busy.Annotations = map[string]string{metal3api.RemediationAnnotation: "other-policy"}
+
+	policy, _, c := reconcileObject(t, policy,
+		busy,
+		newRemediationHost("host-a", remediationHostCritical, time.Hour),
+		newRemediationHost("host-b", remediationHostCritical, time.Hour),
+	)
+
+	entries := remediationEntries(policy)
+	assert.Equal(t, 1, entries["host-a"].Attempts)
+	assert.Equal(t, "waiting for one of the 2 remediations of the namespace to end", entries["host-b"].Message)
+	_, rebooting := getRemediationHost(t, c, "host-a").Annotations[metal3api.RebootAnnotationPrefix]
+	assert.True(t, rebooting)
+}
+
+func TestHostRemediationPolicyReservations(t *testing.T) {
+	testCases := []struct {
+		Scenario         string
+		OtherAttempts    int
+		OtherSince       time.Duration
+		ExpectedAttempts int
+	}{
+		{
+			Scenario:      "other host remediated",
+			OtherAttempts: 1,
+			OtherSince:    -time.Minute,
+		},
+		{
+			Scenario:   "other host reserved first",
+			OtherSince: time.Minute,
+		},
+		{
+			Scenario:         "other host reserved later",
+			OtherSince:       -time.Minute,
+			ExpectedAttempts: 1,
+		},
+		{
+			Scenario:         "other reservation expired",
+			OtherSince:       time.Hour,
+			ExpectedAttempts: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			policy := newRemediationPolicy(metal3api.HostRemediationReboot)
+			other := newRemediationHost("other", remediationHostCritical, time.Hour)
+			other.Labels = nil
+			host := newRemediationHost("host", remediationHostCritical, time.Hour)
+			// The cache does not show the remediation of the other host.
+			cached := newPolicyClient(policy, other.DeepCopy(), host)
+			apiReader := newPolicyClient(policy.DeepCopy(),
+				remediatedHost(other, newRemediationPolicy(metal3api.HostRemediationReboot), tc.OtherAttempts, tc.OtherSince),
+				host.DeepCopy())
+
+			r := &HostRemediationPolicyReconciler{Client: cached, APIReader: apiReader,
+				Log: ctrl.Log.WithName("controllers").WithName("HostRemediationPolicy")}
+			_, err := r.Reconcile(t.Context(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(policy)})
+			require.NoError(t, err)
+
+			host = getRemediationHost(t, cached, "host")
+			attempt, found := getRemediationAttempt(t, host)
+			assert.Equal(t, tc.ExpectedAttempts > 0, found)
+			assert.Equal(t, tc.ExpectedAttempts, attempt.Attempts)
+			_, remediating := host.Annotations[metal3api.RemediationAnnotation]
+			assert.Equal(t, tc.ExpectedAttempts > 0, remediating)
+			require.NoError(t, cached.Get(t.Context(), client.ObjectKeyFromObject(policy), policy))
+			if tc.ExpectedAttempts == 0 {
+				assert.Equal(t, "waiting for one of the 1 remediations of the namespace to end",
+					remediationEntries(policy)["host"].Message)
+			}
+		})
+	}
+}
+
+func TestHostRemediationPolicyReservedSlot(t *testing.T) {
+	testCases := []struct {
+		Scenario         string
+		Health           remediationHostHealth
+		ExpectedAttempts int
+	}{
+		{
+			Scenario:         "still unhealthy",
+			Health:           remediationHostCritical,
+			ExpectedAttempts: 1,
+		},
+		{
+			Scenario: "healthy again",
+			Health:   remediationHostHealthy,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			policy := newRemediationPolicy(metal3api.HostRemediationReboot)
+			host := remediatedHost(newRemediationHost("host", tc.Health, time.Hour), policy, 0, time.Minute)
+
+			_, _, c := reconcileObject(t, policy, host)
+
+			host = getRemediationHost(t, c, "host")
+			attempt, found := getRemediationAttempt(t, host)
+			assert.Equal(t, tc.ExpectedAttempts > 0, found)
+			assert.Equal(t, tc.ExpectedAttempts, attempt.Attempts)
+			_, remediating := host.Annotations[metal3api.RemediationAnnotation]
+			assert.Equal(t, tc.ExpectedAttempts > 0, remediating)
+		})
+	}
+}
+
+func TestHostRemediationPolicyPowerFailures(t *testing.T) {
+	failures := func(ages ...time.Duration) []metav1.Time {
+		times := []metav1.Time{}
+		for _, age := range ages {
+			times = append(times, metav1.NewTime(time.Now().Add(-age)))
+		}
+		return times
+	}
+
+	testCases := []struct {
+		Scenario         string
+		Health           remediationHostHealth
+		Previous         metal3api.HostRemediationStatus
+		ExpectedFailures int
+		ExpectedAttempts int
+		ExpectedEntry    bool
+	}{
+		{
+			Scenario:         "first failure",
+			Health:           remediationHostPowerFailure,
+			ExpectedFailures: 1,
+			ExpectedEntry:    true,
+		},
+		{
+			Scenario:         "same failure",
+			Health:           remediationHostPowerFailure,
+			Previous:         metal3api.HostRemediationStatus{PowerFailureTimes: failures(time.Minute), PowerFailing: true},
+			ExpectedFailures: 1,
+			ExpectedEntry:    true,
+		},
+		{
+			Scenario:         "recovered",
+			Health:           remediationHostHealthy,
+			Previous:         metal3api.HostRemediationStatus{PowerFailureTimes: failures(2*time.Minute, time.Minute), PowerFailing: true},
+			ExpectedFailures: 2,
+			ExpectedEntry:    true,
+		},
+		{
+			Scenario:         "threshold reached",
+			Health:           remediationHostPowerFailure,
+			Previous:         metal3api.HostRemediationStatus{PowerFailureTimes: failures(2*time.Minute, time.Minute)},
+			ExpectedAttempts: 1,
+			ExpectedEntry:    true,
+		},
+		{
+			Scenario:         "old failures out of the window",
+			Health:           remediationHostPowerFailure,
+			Previous:         metal3api.HostRemediationStatus{PowerFailureTimes: failures(3*time.Hour, 2*time.Hour)},
+			ExpectedFailures: 1,
+			ExpectedEntry:    true,
+		},
+		{
+			Scenario: "all failures out of the window",
+			Health:   remediationHostHealthy,
+			Previous: metal3api.HostRemediationStatus{PowerFailureTimes: failures(3*time.Hour, 2*time.Hour)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			policy := newRemediationPolicy(metal3api.HostRemediationReboot)
+			policy.Spec.PowerFailureThreshold = 3
+			tc.Previous.Name = "host"
+			policy.Status.Hosts = []metal3api.HostRemediationStatus{tc.Previous}
+
+			policy, _, c := reconcileObject(t, policy,
+				newRemediationHost("host", tc.Health, time.Minute))
+
+			entry, found := remediationEntries(policy)["host"]
+			assert.Equal(t, tc.ExpectedEntry, found)
+			assert.Equal(t, tc.ExpectedFailures, entry.PowerFailures)
+			assert.Len(t, entry.PowerFailureTimes, tc.ExpectedFailures)
+			assert.Equal(t, tc.ExpectedAttempts, entry.Attempts)
+			_, remediating := getRemediationHost(t, c, "host").Annotations[metal3api.RemediationAnnotation]
+			assert.Equal(t, tc.ExpectedAttempts > 0, remediating)
+			if remediating {
+				assert.Equal(t, metal3api.PowerFailureRemediationReason, entry.Reason)
+			}
+		})
+	}
+}
+
+func TestHostRemediationPolicyEscalates(t *testing.T) {
+	testCases := []struct {
+		Scenario         string
+		Health           remediationHostHealth
+		Attempts         int
+		Since            time.Duration
+		ExpectedAttempts int
+		ExpectedEnded    bool
+		ExpectedCordoned bool
+		ExpectedMessage  string
+	}{
+		{
+			Scenario:         "within backoff",
+			Health:           remediationHostCritical,
+			Attempts:         1,
+			Since:            time.Minute,
+			ExpectedAttempts: 1,
+			ExpectedCordoned: true,
+		},
+		{
+			Scenario:         "next action",
+			Health:           remediationHostCritical,
+			Attempts:         1,
+			Since:            6 * time.Minute,
+			ExpectedAttempts: 2,
+			ExpectedCordoned: true,
+		},
+		{
+			Scenario:         "backoff doubled",
+			Health:           remediationHostCritical,
+			Attempts:         2,
+			Since:            6 * time.Minute,
+			ExpectedAttempts: 2,
+			ExpectedCordoned: true,
+		},
+		{
+			Scenario:         "healthy again",
+			Health:           remediationHostHealthy,
+			Attempts:         2,
+			Since:            11 * time.Minute,
+			ExpectedEnded:    true,
+			ExpectedCordoned: false,
+		},
+		{
+			Scenario:         "all actions taken",
+			Health:           remediationHostCritical,
+			Attempts:         3,
+			Since:            time.Hour,
+			ExpectedAttempts: 3,
+			ExpectedEnded:    true,
+			ExpectedCordoned: true,
+			ExpectedMessage:  "all the remediation actions have been taken",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			policy := newRemediationPolicy(metal3api.HostRemediationCordon,
+				metal3api.HostRemediationReboot, metal3api.HostRemediationReplace)
+			host := remediatedHost(newRemediationHost("host", tc.Health, time.Hour), policy, tc.Attempts, tc.Since)
+
+			policy, _, c := reconcileObject(t, policy, host)
+
+			host = getRemediationHost(t, c, "host")
+			_, remediating := host.Annotations[metal3api.RemediationAnnotation]
+			assert.Equal(t, !tc.ExpectedEnded, remediating)
+			assert.Equal(t, tc.ExpectedCordoned, host.IsCordoned())
+			attempt, _ := getRemediationAttempt(t, host)
+			assert.Equal(t, tc.ExpectedAttempts, attempt.Attempts)
+
+			entry, found := remediationEntries(policy)["host"]
+			assert.Equal(t, tc.ExpectedAttempts > 0, found)
+			assert.Equal(t, tc.ExpectedAttempts, entry.Attempts)
+			assert.Equal(t, tc.ExpectedMessage, entry.Message)
+			if tc.ExpectedAttempts > tc.Attempts {
+				assert.Equal(t, policy.Spec.Actions[tc.ExpectedAttempts-1], entry.LastAction)
+				_, rebooting := host.Annotations[metal3api.RebootAnnotationPrefix]
+				assert.True(t, rebooting)
+			}
+		})
+	}
+}
+
+func TestHostRemediationPolicyReplace(t *testing.T) {
+	policy := newRemediationPolicy(metal3api.HostRemediationInspect, metal3api.HostRemediationReplace)
+	host := remediatedHost(newRemediationHost("host", remediationHostCritical, time.Hour), policy, 1, time.Hour)
+	host.Spec.Taints = nil
+
+	policy, _, c := reconcileObject(t, policy, host)
+
+	host = getRemediationHost(t, c, "host")
+	assert.Equal(t, "CriticalHealth not fixed by the HostRemediationPolicy remediation",
+		host.Annotations[metal3api.ReplaceAnnotation])
+	assert.True(t, host.IsCordoned())
+	assert.Equal(t, metal3api.HostRemediationReplace, policy.Status.Hosts[0].LastAction)
+}
+
+func TestHostRemediationPolicyExhausted(t *testing.T) {
+	testCases := []struct {
+		Scenario         string
+		Health           remediationHostHealth
+		ExpectedReleased bool
+	}{
+		{
+			Scenario: "still unhealthy",
+			Health:   remediationHostCritical,
+		},
+		{
+			Scenario:         "healthy again",
+			Health:           remediationHostHealthy,
+			ExpectedReleased: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			policy := newRemediationPolicy(metal3api.HostRemediationCordon)
+			host := remediatedHost(newRemediationHost("host", tc.Health, time.Hour), policy, 1, time.Hour)
+			delete(host.Annotations, metal3api.RemediationAnnotation)
+
+			policy, _, c := reconcileObject(t, policy, host)
+
+			host = getRemediationHost(t, c, "host")
+			_, found := getRemediationAttempt(t, host)
+			assert.Equal(t, !tc.ExpectedReleased, found)
+			assert.Equal(t, !tc.ExpectedReleased, host.IsCordoned())
+			entry, found := remediationEntries(policy)["host"]
+			assert.Equal(t, !tc.ExpectedReleased, found)
+			if found {
+				assert.Equal(t, "all the remediation actions have been taken", entry.Message)
+			}
+		})
+	}
+}
+
+func TestHostRemediationPolicyExhaustedByOtherPolicy(t *testing.T) {
+	other := newRemediationPolicy(metal3api.HostRemediationCordon)
+	other.Name = "other"
+	host := remediatedHost(newRemediationHost("host", remediationHostCritical, time.Hour), other, 1, time.Hour)
+	delete(host.Annotations, metal3api.RemediationAnnotation)
+
+	policy, _, _ := reconcileObject(t, newRemediationPolicy(metal3api.HostRemediationCordon), host)
+
+	assert.Empty(t, policy.Status.Hosts)
+}
+
+func TestRemediationBackoff(t *testing.T) {
+	policy := newRemediationPolicy(metal3api.HostRemediationReboot)
+	policy.Spec.Backoff = metav1.Duration{Duration: 10 * time.Minute}
+	policy.Spec.MaxBackoff = metav1.Duration{Duration: 30 * time.Minute}
+
+	assert.Equal(t, 10*time.Minute, remediationBackoff(policy, 1))
+	assert.Equal(t, 20*time.Minute, remediationBackoff(policy, 2))
+	assert.Equal(t, 30*time.Minute, remediationBackoff(policy, 3))
+	assert.Equal(t, 30*time.Minute, remediationBackoff(policy, 10))
+
+	assert.Equal(t, defaultRemediationBackoff, remediationBackoff(newRemediationPolicy(), 1))
+}
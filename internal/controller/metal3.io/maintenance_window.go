@@ -0,0 +1,157 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/maintenancewindow"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// servicingHold returns the reason and message of the ServicingScheduled
+// condition when the HostUpdatePolicy does not allow servicing to start
+// now, or empty strings when it does. With a concurrency limit, the host
+// reserves one of the slots of its group before servicing may start, and
+// keeps it while waiting for its turn.
+func (r *BareMetalHostReconciler) servicingHold(ctx context.Context, info *reconcileInfo, hup *metal3api.HostUpdatePolicy, now time.Time) (reason, message string, err error) {
+	if hup == nil {
+		return "", "", nil
+	}
+
+	open, nextOpen, err := maintenancewindow.Status(hup.Spec.MaintenanceWindows, now)
+	if err != nil {
+		return metal3api.InvalidMaintenanceWindowReason, err.Error(), nil
+	}
+	if !open {
+		if nextOpen.IsZero() {
+			return metal3api.OutsideMaintenanceWindowReason, "no maintenance window will open", nil
+		}
+		return metal3api.OutsideMaintenanceWindowReason,
+			"servicing will start with a reboot after " + nextOpen.UTC().Format(time.RFC3339), nil
+	}
+
+	if hup.Spec.MaxConcurrentServicing == nil {
+		return "", "", nil
+	}
+
+	servicing, err := r.reserveServicingSlot(ctx, info, hup, now)
+	if err != nil {
+		return "", "", err
+	}
+	if servicing >= int(*hup.Spec.MaxConcurrentServicing) {
+		return metal3api.MaxConcurrentServicingReason,
+			fmt.Sprintf("%d hosts of the group are servicing or waiting before this one", servicing), nil
+	}
+	return "", "", nil
+}
+
+// reserveServicingSlot reserves a servicing slot for the host, unless it
+// already did, and returns the number of hosts of its group that hold a
+// slot before it. The hosts are only written and read from the API once a
+// slot looks free in the cache.
+func (r *BareMetalHostReconciler) reserveServicingSlot(ctx context.Context, info *reconcileInfo, hup *metal3api.HostUpdatePolicy, now time.Time) (int, error) {
+	slots, err := servicingSlots(info.host, hup)
+	if err != nil {
+		return 0, err
+	}
+
+	reserved, found := servicingReservation(info.host)
+	if !found {
+		reserved = now
+	}
+	servicing, err := slots.taken(ctx, r.Client, info.host, reserved, now)
+	if err != nil || servicing >= int(*hup.Spec.MaxConcurrentServicing) {
+		return servicing, err
+	}
+	if !found {
+		if info.host.Annotations == nil {
+			info.host.Annotations = map[string]string{}
+		}
+		info.host.Annotations[metal3api.ServicingReservedAnnotation] = reserved.UTC().Format(time.RFC3339Nano)
+		if err := r.Update(ctx, info.host); err != nil {
+			return 0, fmt.Errorf("failed to reserve a servicing slot: %w", err)
+		}
+	}
+	return slots.taken(ctx, r.APIReader, info.host, reserved, now)
+}
+
+// servicingSlots returns the servicing slots the host shares with the
+// hosts of its group. A host holds its slot while servicing, after it
+// failed servicing, and from its reservation until it is released.
+func servicingSlots(host *metal3api.BareMetalHost, hup *metal3api.HostUpdatePolicy) (hostSlots, error) {
+	scope, err := servicingGroupScope(host, hup.Spec.ServicingGroupLabel)
+	if err != nil {
+		return hostSlots{}, err
+	}
+	return hostSlots{
+		scope:       scope,
+		reservation: servicingReservation,
+		active: func(host *metal3api.BareMetalHost) bool {
+			return host.Status.OperationalStatus == metal3api.OperationalStatusServicing ||
+				host.Status.ErrorType == metal3api.ServicingError
+		},
+	}, nil
+}
+
+// servicingGroupScope selects the hosts sharing the servicing slots of the
+// host, all of them without a group label.
+func servicingGroupScope(host *metal3api.BareMetalHost, label string) ([]client.ListOption, error) {
+	if label == "" {
+		return nil, nil
+	}
+	if group, found := host.Labels[label]; found {
+		return []client.ListOption{client.MatchingLabels{label: group}}, nil
+	}
+	requirement, err := labels.NewRequirement(label, selection.DoesNotExist, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid servicing group label: %w", err)
+	}
+	return []client.ListOption{client.MatchingLabelsSelector{Selector: labels.NewSelector().Add(*requirement)}}, nil
+}
+
+// servicingReservation returns when the host reserved a servicing slot.
+func servicingReservation(host *metal3api.BareMetalHost) (time.Time, bool) {
+	value, found := host.Annotations[metal3api.ServicingReservedAnnotation]
+	if !found {
+		return time.Time{}, false
+	}
+	reserved, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		// An invalid reservation does not hold any slot.
+		return time.Time{}, false
+	}
+	return reserved, true
+}
+
+// releaseServicingSlot removes the servicing slot reserved by the host.
+func (r *BareMetalHostReconciler) releaseServicingSlot(ctx context.Context, info *reconcileInfo) error {
+	if _, found := info.host.Annotations[metal3api.ServicingReservedAnnotation]; !found {
+		return nil
+	}
+	delete(info.host.Annotations, metal3api.ServicingReservedAnnotation)
+	if err := r.Update(ctx, info.host); err != nil {
+		return fmt.Errorf("failed to release the servicing slot: %w", err)
+	}
+	return nil
+}
+
+// setServicingScheduled records why servicing is held, or removes the
+// condition when reason is empty, and reports whether the host changed.
+func setServicingScheduled(host *metal3api.BareMetalHost, reason, message string) bool {
+	if reason == "" {
+		return meta.RemoveStatusCondition(&host.Status.Conditions, metal3api.ServicingScheduledCondition)
+	}
+	return meta.SetStatusCondition(&host.Status.Conditions, metav1.Condition{
+		Type:               metal3api.ServicingScheduledCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: host.Generation,
+	})
+}
@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestServicingHoldConcurrency(t *testing.T) {
+	rackHost := func(name, rack string, status metal3api.OperationalStatus) *metal3api.BareMetalHost {
+		h := newDefaultHost(t)
+		h.Name = name
+		h.Labels = map[string]string{"rack": rack}
+		h.Status.OperationalStatus = status
+		return h
+	}
+	host := rackHost("myhost", "r1", metal3api.OperationalStatusOK)
+	r := newTestReconciler(t, host,
+		rackHost("servicing", "r1", metal3api.OperationalStatusServicing),
+		rackHost("other-rack", "r2", metal3api.OperationalStatusServicing),
+		rackHost("idle", "r1", metal3api.OperationalStatusOK))
+	info := makeReconcileInfo(host)
+	hup := &metal3api.HostUpdatePolicy{
+		Spec: metal3api.HostUpdatePolicySpec{
+			MaxConcurrentServicing: ptr.To[int32](1),
+			ServicingGroupLabel:    "rack",
+		},
+	}
+
+	// A held host does not reserve a slot while none looks free
+	reason, _, err := r.servicingHold(t.Context(), info, hup, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, metal3api.MaxConcurrentServicingReason, reason)
+	assert.NotContains(t, host.Annotations, metal3api.ServicingReservedAnnotation)
+
+	hup.Spec.MaxConcurrentServicing = ptr.To[int32](2)
+	reason, _, err = r.servicingHold(t.Context(), info, hup, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, reason)
+	assert.Contains(t, host.Annotations, metal3api.ServicingReservedAnnotation)
+
+	// Without a group label all the hosts are counted together, and a
+	// held host keeps its reservation
+	hup.Spec.ServicingGroupLabel = ""
+	reason, _, err = r.servicingHold(t.Context(), info, hup, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, metal3api.MaxConcurrentServicingReason, reason)
+	assert.Contains(t, host.Annotations, metal3api.ServicingReservedAnnotation)
+}
+
+func TestServicingHoldFailedServicing(t *testing.T) {
+	host := newDefaultHost(t)
+	failed := newDefaultHost(t)
+	failed.Name = "failed"
+	failed.Status.ErrorType = metal3api.ServicingError
+	r := newTestReconciler(t, host, failed)
+	info := makeReconcileInfo(host)
+	hup := &metal3api.HostUpdatePolicy{
+		Spec: metal3api.HostUpdatePolicySpec{MaxConcurrentServicing: ptr.To[int32](1)},
+	}
+
+	// A host that failed servicing keeps its slot until it is fixed
+	reason, _, err := r.servicingHold(t.Context(), info, hup, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, metal3api.MaxConcurrentServicingReason, reason)
+}
+
+func TestServicingReservation(t *testing.T) {
+	now := time.Now()
+	reservedHost := func(name string, reserved time.Time) *metal3api.BareMetalHost {
+		h := newDefaultHost(t)
+		h.Name = name
+		if !reserved.IsZero() {
+			h.Annotations = map[string]string{
+				metal3api.ServicingReservedAnnotation: reserved.Format(time.RFC3339Nano),
+			}
+		}
+		return h
+	}
+
+	testCases := []struct {
+		Scenario string
+		Other    time.Time
+		Expected string
+	}{
+		{
+			Scenario: "no other reservation",
+		},
+		{
+			Scenario: "earlier reservation holds the slot",
+			Other:    now.Add(-time.Second),
+			Expected: metal3api.MaxConcurrentServicingReason,
+		},
+		{
+			Scenario: "later reservation yields the slot",
+			Other:    now.Add(time.Second),
+		},
+		{
+			Scenario: "old reservation holds the slot until released",
+			Other:    now.Add(-time.Hour),
+			Expected: metal3api.MaxConcurrentServicingReason,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			host := reservedHost("myhost", time.Time{})
+			r := newTestReconciler(t, host, reservedHost("other", tc.Other))
+			info := makeReconcileInfo(host)
+			hup := &metal3api.HostUpdatePolicy{
+				Spec: metal3api.HostUpdatePolicySpec{MaxConcurrentServicing: ptr.To[int32](1)},
+			}
+
+			reason, _, err := r.servicingHold(t.Context(), info, hup, now)
+			require.NoError(t, err)
+			assert.Equal(t, tc.Expected, reason)
+
+			// The reservation is written to the API, not only to the cache
+			saved := &metal3api.BareMetalHost{}
+			require.NoError(t, r.Get(t.Context(), client.ObjectKeyFromObject(host), saved))
+			_, reserved := saved.Annotations[metal3api.ServicingReservedAnnotation]
+			assert.Equal(t, tc.Expected == "", reserved)
+		})
+	}
+}
+
+// TestRebootOutsideMaintenanceWindow ensures servicing is held until a
+// maintenance window while the host powers on.
+func TestRebootOutsideMaintenanceWindow(t *testing.T) {
+	host := newDefaultHost(t)
+	host.Annotations = map[string]string{metal3api.RebootAnnotationPrefix: ""}
+	host.Status.PoweredOn = true
+	host.Status.Provisioning.State = metal3api.StateProvisioned
+	host.Spec.Online = true
+	host.Spec.Image = &metal3api.Image{URL: "foo", Checksum: "123"}
+	host.Status.Provisioning.Image.URL = "foo"
+	host.Spec.Firmware = &metal3api.FirmwareConfig{
+		VirtualizationEnabled: ptr.To(true),
+	}
+
+	hup := &metal3api.HostUpdatePolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      host.Name,
+			Namespace: namespace,
+		},
+		Spec: metal3api.HostUpdatePolicySpec{
+			FirmwareSettings: metal3api.HostUpdatePolicyOnReboot,
+			// Only opens on the 29th of February
+			MaintenanceWindows: []metal3api.MaintenanceWindow{
+				{Schedule: "0 0 29 2 *", Duration: metav1.Duration{Duration: time.Minute}},
+			},
+		},
+	}
+
+	r := newTestReconciler(t, host, hup)
+
+	tryReconcile(t, r, host,
+		func(host *metal3api.BareMetalHost, result reconcile.Result) bool {
+			return meta.IsStatusConditionTrue(host.Status.Conditions, metal3api.ServicingScheduledCondition)
+		},
+	)
+	cond := meta.FindStatusCondition(host.Status.Conditions, metal3api.ServicingScheduledCondition)
+	assert.Equal(t, metal3api.OutsideMaintenanceWindowReason, cond.Reason)
+	assert.Contains(t, cond.Message, "-02-29T00:00:00Z")
+
+	tryReconcile(t, r, host,
+		func(host *metal3api.BareMetalHost, result reconcile.Result) bool {
+			return host.Status.PoweredOn
+		},
+	)
+	assert.NotEqual(t, metal3api.OperationalStatusServicing, host.Status.OperationalStatus)
+	assert.Nil(t, host.Status.Provisioning.Firmware)
+	assert.True(t, meta.IsStatusConditionTrue(host.Status.Conditions, metal3api.ServicingScheduledCondition))
+
+	// Once the window opens, the host reboots and servicing starts
+	require.NoError(t, r.Get(t.Context(), client.ObjectKeyFromObject(hup), hup))
+	hup.Spec.MaintenanceWindows = nil
+	require.NoError(t, r.Update(t.Context(), hup))
+
+	tryReconcile(t, r, host,
+		func(host *metal3api.BareMetalHost, result reconcile.Result) bool {
+			return host.Status.OperationalStatus == metal3api.OperationalStatusServicing
+		},
+	)
+	assert.False(t, meta.IsStatusConditionTrue(host.Status.Conditions, metal3api.ServicingScheduledCondition))
+}
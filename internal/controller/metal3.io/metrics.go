@@ -15,6 +15,12 @@ const (
 	labelPrevState     = "prev_state"
 	labelNewState      = "new_state"
 	labelHostDataType  = "host_data_type"
+	labelBucket        = "bucket"
+	labelBucketValue   = "bucket_value"
+	labelBaseline      = "baseline"
+	labelComponent     = "component"
+	labelPolicy        = "policy"
+	labelAction        = "action"
 )
 
 var reconcileCounters = prometheus.NewCounterVec(prometheus.CounterOpts{
@@ -65,12 +71,12 @@ var hostConfigDataError = prometheus.NewCounterVec(prometheus.CounterOpts{
 }, []string{labelHostDataType})
 var delayedProvisioningHostCounters = prometheus.NewCounterVec(prometheus.CounterOpts{
 	Name: "metal3_delayed__provisioning_total",
-	Help: "The number of times hosts have been delayed while provisioning due a busy provisioner",
-}, []string{labelHostNamespace, labelHostName})
+	Help: "The number of times hosts have been delayed while provisioning due a busy provisioner or provisioning group",
+}, []string{labelHostNamespace, labelHostName, labelBucket, labelBucketValue})
 var delayedDeprovisioningHostCounters = prometheus.NewCounterVec(prometheus.CounterOpts{
 	Name: "metal3_delayed__deprovisioning_total",
-	Help: "The number of times hosts have been delayed while deprovisioning due a busy provisioner",
-}, []string{labelHostNamespace, labelHostName})
+	Help: "The number of times hosts have been delayed while deprovisioning due a busy provisioner or provisioning group",
+}, []string{labelHostNamespace, labelHostName, labelBucket, labelBucketValue})
 
 var slowOperationBuckets = []float64{30, 90, 180, 360, 720, 1440}
 
@@ -131,6 +137,21 @@ var deleteDelayedForDetached = prometheus.NewCounter(prometheus.CounterOpts{
 	Help: "Number of times a host delete action was delayed due to the detached annotation",
 })
 
+var firmwareNonCompliantHosts = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "metal3_firmware_noncompliant_hosts",
+	Help: "Number of hosts with a firmware component not matching a FirmwareBaseline",
+}, []string{labelHostNamespace, labelBaseline, labelComponent})
+
+var hostRemediationActions = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "metal3_host_remediation_total",
+	Help: "Number of remediation actions taken and remediations ended, by action or result",
+}, []string{labelHostNamespace, labelPolicy, labelAction})
+
+var hostRemediationsDelayed = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "metal3_host_remediation_delayed_total",
+	Help: "Number of times a host remediation was delayed by the maximum of concurrent remediations",
+}, []string{labelHostNamespace, labelPolicy})
+
 func init() {
 	metrics.Registry.MustRegister(
 		reconcileCounters,
@@ -159,6 +180,13 @@ func init() {
 		deleteWithoutDeprov,
 		provisionerNotReady,
 		deleteDelayedForDetached)
+
+	metrics.Registry.MustRegister(
+		firmwareNonCompliantHosts)
+
+	metrics.Registry.MustRegister(
+		hostRemediationActions,
+		hostRemediationsDelayed)
 }
 
 func hostMetricLabels(request ctrl.Request) prometheus.Labels {
@@ -168,6 +196,15 @@ func hostMetricLabels(request ctrl.Request) prometheus.Labels {
 	}
 }
 
+func delayedMetricLabels(request ctrl.Request, bucket, bucketValue string) prometheus.Labels {
+	return prometheus.Labels{
+		labelHostNamespace: request.Namespace,
+		labelHostName:      request.Name,
+		labelBucket:        bucket,
+		labelBucketValue:   bucketValue,
+	}
+}
+
 func stateChangeMetricLabels(prevState, newState metal3api.ProvisioningState) prometheus.Labels {
 	return prometheus.Labels{
 		labelPrevState: string(prevState),
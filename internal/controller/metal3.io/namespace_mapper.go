@@ -0,0 +1,56 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// namespaceMapper returns a map function enqueuing all the objects of the
+// list type L in the namespace of the changed object. It is used by the
+// controllers whose objects select hosts or host resources of their
+// namespace.
+func namespaceMapper[L any, PL interface {
+	*L
+	client.ObjectList
+}](c client.Reader, log logr.Logger) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		list := PL(new(L))
+		if err := c.List(ctx, list, client.InNamespace(obj.GetNamespace())); err != nil {
+			log.Error(err, "failed to list the objects to reconcile", "namespace", obj.GetNamespace())
+			return nil
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			log.Error(err, "failed to read the objects to reconcile", "namespace", obj.GetNamespace())
+			return nil
+		}
+		requests := make([]reconcile.Request, 0, len(items))
+		for _, item := range items {
+			if object, ok := item.(client.Object); ok {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(object)})
+			}
+		}
+		return requests
+	}
+}
@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"testing"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNamespaceMapper(t *testing.T) {
+	policy := func(name, ns string) *metal3api.HostDeployPolicy {
+		return &metal3api.HostDeployPolicy{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns}}
+	}
+	c := fakeclient.NewClientBuilder().WithRuntimeObjects(
+		policy("policy-a", namespace), policy("policy-b", namespace), policy("other", "other-namespace"),
+	).Build()
+
+	requests := namespaceMapper[metal3api.HostDeployPolicyList](c, ctrl.Log)(t.Context(), newHost("host", &metal3api.BareMetalHostSpec{}))
+
+	names := []string{}
+	for _, request := range requests {
+		assert.Equal(t, namespace, request.Namespace)
+		names = append(names, request.Name)
+	}
+	assert.ElementsMatch(t, []string{"policy-a", "policy-b"}, names)
+}
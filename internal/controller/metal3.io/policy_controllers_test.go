@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"testing"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// The helpers below are shared by the tests of the controllers selecting
+// the hosts of their namespace.
+
+// newPolicyReconciler returns the reconciler of the kind of obj.
+func newPolicyReconciler(t *testing.T, c client.Client, obj client.Object) reconcile.Reconciler {
+	t.Helper()
+	log := ctrl.Log.WithName("controllers")
+	switch obj.(type) {
+	case *metal3api.FirmwareUpdateCampaign:
+		return &FirmwareUpdateCampaignReconciler{Client: c, Log: log.WithName("FirmwareUpdateCampaign")}
+	case *metal3api.FirmwareBaseline:
+		return &FirmwareBaselineReconciler{Client: c, Log: log.WithName("FirmwareBaseline")}
+	case *metal3api.FirmwareSettingsTemplate:
+		return &FirmwareSettingsTemplateReconciler{Client: c, Log: log.WithName("FirmwareSettingsTemplate")}
+	case *metal3api.HostRemediationPolicy:
+		return &HostRemediationPolicyReconciler{Client: c, Log: log.WithName("HostRemediationPolicy"), APIReader: c}
+	default:
+		require.FailNow(t, fmt.Sprintf("no reconciler for %T", obj))
+		return nil
+	}
+}
+
+// newPolicyClient returns a fake client holding objs.
+func newPolicyClient(objs ...runtime.Object) client.Client {
+	clientBuilder := fakeclient.NewClientBuilder().WithRuntimeObjects(objs...)
+	for _, obj := range objs {
+		clientBuilder = clientBuilder.WithStatusSubresource(obj.(client.Object))
+	}
+	return clientBuilder.Build()
+}
+
+// reconcileObject reconciles obj once on a fake client holding it and objs,
+// and returns the updated object, the result and the client.
+func reconcileObject[T client.Object](t *testing.T, obj T, objs ...runtime.Object) (T, ctrl.Result, client.Client) {
+	t.Helper()
+	c := newPolicyClient(append(objs, obj)...)
+	updated, result := reconcileAgain(t, c, obj)
+	return updated, result, c
+}
+
+// reconcileAgain reconciles obj once more on an existing client.
+func reconcileAgain[T client.Object](t *testing.T, c client.Client, obj T) (T, ctrl.Result) {
+	t.Helper()
+	r := newPolicyReconciler(t, c, obj)
+	result, err := r.Reconcile(t.Context(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(obj)})
+	require.NoError(t, err)
+
+	updated, ok := obj.DeepCopyObject().(T)
+	require.True(t, ok)
+	require.NoError(t, c.Get(t.Context(), client.ObjectKeyFromObject(obj), updated))
+	return updated, result
+}
+
+// policyHostLabels are the labels of the hosts selected by policySelector.
+var policyHostLabels = map[string]string{"rack": "r1"}
+
+// policySelector selects the hosts returned by newPolicyHost.
+func policySelector() metav1.LabelSelector {
+	return metav1.LabelSelector{MatchLabels: maps.Clone(policyHostLabels)}
+}
+
+// policyHost holds a host and the host resources read by the policy
+// controllers. Only the resources that are set are created.
+type policyHost struct {
+	host         *metal3api.BareMetalHost
+	hardware     *metal3api.HardwareData
+	components   *metal3api.HostFirmwareComponents
+	settings     *metal3api.HostFirmwareSettings
+	updatePolicy *metal3api.HostUpdatePolicy
+}
+
+// newPolicyHost returns an online provisioned host selected by
+// policySelector.
+func newPolicyHost(name string) *policyHost {
+	host := newHost(name, &metal3api.BareMetalHostSpec{Online: true})
+	host.Labels = maps.Clone(policyHostLabels)
+	host.Status.Provisioning.State = metal3api.StateProvisioned
+	host.Status.OperationalStatus = metal3api.OperationalStatusOK
+	return &policyHost{host: host}
+}
+
+// withHardware sets the vendor and the product of the inspected hardware
+// of the host.
+func (h *policyHost) withHardware(manufacturer, product string) *policyHost {
+	h.hardware = &metal3api.HardwareData{
+		ObjectMeta: metav1.ObjectMeta{Name: h.host.Name, Namespace: h.host.Namespace},
+		Spec: metal3api.HardwareDataSpec{
+			HardwareDetails: &metal3api.HardwareDetails{
+				SystemVendor: metal3api.HardwareSystemVendor{Manufacturer: manufacturer, ProductName: product},
+			},
+		},
+	}
+	return h
+}
+
+// withComponents sets the current version of the firmware components of
+// the host.
+func (h *policyHost) withComponents(versions map[string]string) *policyHost {
+	h.components = &metal3api.HostFirmwareComponents{
+		ObjectMeta: metav1.ObjectMeta{Name: h.host.Name, Namespace: h.host.Namespace},
+	}
+	for _, component := range slices.Sorted(maps.Keys(versions)) {
+		h.components.Status.Components = append(h.components.Status.Components, metal3api.FirmwareComponentStatus{
+			Component: component, InitialVersion: versions[component], CurrentVersion: versions[component],
+		})
+	}
+	return h
+}
+
+// withSettings sets the current firmware settings of the host and the
+// schema they were read with.
+func (h *policyHost) withSettings(schema string, settings metal3api.SettingsMap) *policyHost {
+	h.settings = &metal3api.HostFirmwareSettings{
+		ObjectMeta: metav1.ObjectMeta{Name: h.host.Name, Namespace: h.host.Namespace},
+		Status: metal3api.HostFirmwareSettingsStatus{
+			FirmwareSchema: &metal3api.SchemaReference{Name: schema, Namespace: h.host.Namespace},
+			Settings:       settings,
+		},
+	}
+	return h
+}
+
+// withUpdatePolicy sets the policy applying the firmware updates of the
+// host.
+func (h *policyHost) withUpdatePolicy(updates metal3api.UpdatePolicy) *policyHost {
+	h.updatePolicy = &metal3api.HostUpdatePolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: h.host.Name, Namespace: h.host.Namespace},
+		Spec:       metal3api.HostUpdatePolicySpec{FirmwareUpdates: updates},
+	}
+	return h
+}
+
+// objects returns the host and its resources.
+func (h *policyHost) objects() []runtime.Object {
+	objs := []runtime.Object{h.host}
+	if h.hardware != nil {
+		objs = append(objs, h.hardware)
+	}
+	if h.components != nil {
+		objs = append(objs, h.components)
+	}
+	if h.settings != nil {
+		objs = append(objs, h.settings)
+	}
+	if h.updatePolicy != nil {
+		objs = append(objs, h.updatePolicy)
+	}
+	return objs
+}
@@ -0,0 +1,161 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/hardwareutils/bmc"
+	"github.com/metal3-io/baremetal-operator/pkg/hostclaim"
+)
+
+// ProvisioningGroupKind tells how the hosts are put into the buckets of a
+// provisioning group.
+type ProvisioningGroupKind string
+
+const (
+	// ProvisioningGroupLabel puts the hosts with the same value of a label
+	// in the same bucket.
+	ProvisioningGroupLabel ProvisioningGroupKind = "label"
+	// ProvisioningGroupFailureDomain puts the hosts of the same failure
+	// domain in the same bucket.
+	ProvisioningGroupFailureDomain ProvisioningGroupKind = "failureDomain"
+	// ProvisioningGroupBMCType puts the hosts with the same type of BMC
+	// address, e.g. idrac-virtualmedia, in the same bucket.
+	ProvisioningGroupBMCType ProvisioningGroupKind = "bmcType"
+
+	// provisionerBucket labels the hosts delayed by the global limit of
+	// the provisioner.
+	provisionerBucket = "provisioner"
+)
+
+// ProvisioningGroupLimit limits the number of hosts of each bucket of a
+// group that are inspected, provisioned or deprovisioned at the same time.
+type ProvisioningGroupLimit struct {
+	Kind ProvisioningGroupKind
+	// Label is the name of the label for the label kind.
+	Label string
+	// Value restricts the limit to the bucket with this value when set.
+	Value string
+	Limit int
+}
+
+// Name identifies the group in the metrics, e.g. "label:rack".
+func (g ProvisioningGroupLimit) Name() string {
+	if g.Kind == ProvisioningGroupLabel {
+		return fmt.Sprintf("%s:%s", g.Kind, g.Label)
+	}
+	return string(g.Kind)
+}
+
+// bucket returns the bucket of the host in the group, or false when the
+// limit does not apply to the host.
+func (g ProvisioningGroupLimit) bucket(host *metal3api.BareMetalHost) (string, bool) {
+	var value string
+	switch g.Kind {
+	case ProvisioningGroupLabel:
+		value = host.Labels[g.Label]
+	case ProvisioningGroupFailureDomain:
+		value = host.Labels[hostclaim.FailureDomainLabelName]
+	case ProvisioningGroupBMCType:
+		accessDetails, err := bmc.NewAccessDetails(host.Spec.BMC.Address, host.Spec.BMC.DisableCertificateVerification)
+		if err == nil {
+			value = accessDetails.Type()
+		}
+	}
+	if value == "" || (g.Value != "" && g.Value != value) {
+		return "", false
+	}
+	return value, true
+}
+
+// ParseProvisioningGroupLimits reads a comma-separated list of limits such
+// as "label:rack=2,failureDomain=5,bmcType:idrac-virtualmedia=3". Each
+// entry is one of label:<name>, failureDomain or bmcType, optionally
+// followed by :<value> to only limit the bucket with this value.
+func ParseProvisioningGroupLimits(value string) ([]ProvisioningGroupLimit, error) {
+	var limits []ProvisioningGroupLimit
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		group, limitStr, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid provisioning group limit %q, expected <group>=<limit>", entry)
+		}
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			return nil, fmt.Errorf("invalid limit in provisioning group limit %q", entry)
+		}
+
+		parts := strings.Split(group, ":")
+		groupLimit := ProvisioningGroupLimit{Kind: ProvisioningGroupKind(parts[0]), Limit: limit}
+		switch {
+		case groupLimit.Kind == ProvisioningGroupLabel && (len(parts) == 2 || len(parts) == 3) && parts[1] != "":
+			groupLimit.Label = parts[1]
+			if len(parts) == 3 {
+				groupLimit.Value = parts[2]
+			}
+		case (groupLimit.Kind == ProvisioningGroupFailureDomain || groupLimit.Kind == ProvisioningGroupBMCType) && len(parts) <= 2:
+			if len(parts) == 2 {
+				groupLimit.Value = parts[1]
+			}
+		default:
+			return nil, fmt.Errorf("invalid provisioning group %q, use label:<name>, failureDomain or bmcType", group)
+		}
+		limits = append(limits, groupLimit)
+	}
+	return limits, nil
+}
+
+// isBusy tells whether a host is using a slot of its buckets.
+func isBusy(host *metal3api.BareMetalHost) bool {
+	if host.OperationalStatus() == metal3api.OperationalStatusDelayed {
+		return false
+	}
+	switch host.Status.Provisioning.State {
+	case metal3api.StateInspecting, metal3api.StateProvisioning,
+		metal3api.StateDeprovisioning:
+		return true
+	default:
+		return false
+	}
+}
+
+// fullProvisioningGroup returns the first group limit reached by the
+// host, with the bucket of the host, or nil if all of them have room left.
+func (r *BareMetalHostReconciler) fullProvisioningGroup(ctx context.Context, host *metal3api.BareMetalHost) (*ProvisioningGroupLimit, string, error) {
+	if len(r.ProvisioningGroupLimits) == 0 {
+		return nil, "", nil
+	}
+
+	hosts := metal3api.BareMetalHostList{}
+	if err := r.List(ctx, &hosts); err != nil {
+		return nil, "", fmt.Errorf("failed to list hosts: %w", err)
+	}
+
+	for i := range r.ProvisioningGroupLimits {
+		group := &r.ProvisioningGroupLimits[i]
+		bucket, applies := group.bucket(host)
+		if !applies {
+			continue
+		}
+		busy := 0
+		for j := range hosts.Items {
+			other := &hosts.Items[j]
+			if other.Namespace == host.Namespace && other.Name == host.Name {
+				continue
+			}
+			if otherBucket, found := group.bucket(other); found && otherBucket == bucket && isBusy(other) {
+				busy++
+			}
+		}
+		if busy >= group.Limit {
+			return group, bucket, nil
+		}
+	}
+	return nil, "", nil
+}
@@ -0,0 +1,175 @@
+package controllers
+
+import (
+	"testing"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/hostclaim"
+	promutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestParseProvisioningGroupLimits(t *testing.T) {
+	testCases := []struct {
+		Scenario      string
+		Value         string
+		Expected      []ProvisioningGroupLimit
+		ExpectedError string
+	}{
+		{
+			Scenario: "empty",
+		},
+		{
+			Scenario: "all kinds",
+			Value:    "label:example.com/rack=2, failureDomain=5,bmcType:idrac-virtualmedia=3,bmcType=10",
+			Expected: []ProvisioningGroupLimit{
+				{Kind: ProvisioningGroupLabel, Label: "example.com/rack", Limit: 2},
+				{Kind: ProvisioningGroupFailureDomain, Limit: 5},
+				{Kind: ProvisioningGroupBMCType, Value: "idrac-virtualmedia", Limit: 3},
+				{Kind: ProvisioningGroupBMCType, Limit: 10},
+			},
+		},
+		{
+			Scenario: "label value",
+			Value:    "label:rack:r1=1",
+			Expected: []ProvisioningGroupLimit{
+				{Kind: ProvisioningGroupLabel, Label: "rack", Value: "r1", Limit: 1},
+			},
+		},
+		{
+			Scenario:      "missing limit",
+			Value:         "failureDomain",
+			ExpectedError: "expected <group>=<limit>",
+		},
+		{
+			Scenario:      "invalid limit",
+			Value:         "failureDomain=0",
+			ExpectedError: "invalid limit",
+		},
+		{
+			Scenario:      "label without name",
+			Value:         "label=2",
+			ExpectedError: "invalid provisioning group",
+		},
+		{
+			Scenario:      "unknown kind",
+			Value:         "vendor=2",
+			ExpectedError: "invalid provisioning group",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			limits, err := ParseProvisioningGroupLimits(tc.Value)
+			if tc.ExpectedError != "" {
+				assert.ErrorContains(t, err, tc.ExpectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.Expected, limits)
+		})
+	}
+}
+
+func TestProvisioningGroupCapacity(t *testing.T) {
+	rackHost := func(name, rack string, state metal3api.ProvisioningState) *metal3api.BareMetalHost {
+		h := host(state).build()
+		h.Name = name
+		h.Labels = map[string]string{
+			"rack":                           rack,
+			hostclaim.FailureDomainLabelName: "fd-" + rack,
+		}
+		h.Spec.BMC.Address = "ipmi://192.168.122.1"
+		return h
+	}
+	delayed := rackHost("delayed", "r1", metal3api.StateProvisioning)
+	delayed.SetOperationalStatus(metal3api.OperationalStatusDelayed)
+	others := []*metal3api.BareMetalHost{
+		rackHost("busy", "r1", metal3api.StateProvisioning),
+		rackHost("idle", "r1", metal3api.StateProvisioned),
+		rackHost("other-rack", "r2", metal3api.StateInspecting),
+		delayed,
+	}
+
+	testCases := []struct {
+		Scenario        string
+		Limits          string
+		ExpectedBucket  string
+		ExpectedValue   string
+		ExpectedDelayed bool
+	}{
+		{
+			Scenario: "no limits",
+		},
+		{
+			Scenario:        "label limit reached",
+			Limits:          "label:rack=1",
+			ExpectedBucket:  "label:rack",
+			ExpectedValue:   "r1",
+			ExpectedDelayed: true,
+		},
+		{
+			Scenario: "label limit not reached",
+			Limits:   "label:rack=2",
+		},
+		{
+			Scenario: "limit of another bucket",
+			Limits:   "label:rack:r2=1",
+		},
+		{
+			Scenario:        "failure domain limit reached",
+			Limits:          "label:rack=2,failureDomain=1",
+			ExpectedBucket:  "failureDomain",
+			ExpectedValue:   "fd-r1",
+			ExpectedDelayed: true,
+		},
+		{
+			Scenario:        "BMC type limit reached",
+			Limits:          "bmcType:ipmi=2",
+			ExpectedBucket:  "bmcType",
+			ExpectedValue:   "ipmi",
+			ExpectedDelayed: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			h := rackHost("myhost", "r1", metal3api.StateAvailable)
+			builder := fakeclient.NewClientBuilder().WithObjects(h)
+			for _, other := range others {
+				builder = builder.WithObjects(other.DeepCopy())
+			}
+			limits, err := ParseProvisioningGroupLimits(tc.Limits)
+			require.NoError(t, err)
+			reconciler := &BareMetalHostReconciler{
+				Client:                  builder.Build(),
+				Log:                     ctrl.Log.WithName("host_state_machine").WithName("BareMetalHost"),
+				ProvisioningGroupLimits: limits,
+			}
+
+			prov := newMockProvisioner()
+			prov.setHasCapacity(true)
+			hsm := newHostStateMachine(h, reconciler, prov, true)
+			info := makeDefaultReconcileInfo(h)
+			delayedProvisioningHostCounters.Reset()
+
+			result := hsm.ensureCapacity(t.Context(), info, metal3api.StateProvisioning)
+
+			if !tc.ExpectedDelayed {
+				assert.Nil(t, result)
+				return
+			}
+			assert.Equal(t, actionDelayed{}, result)
+			assert.EqualValues(t, metal3api.OperationalStatusDelayed, h.Status.OperationalStatus)
+			for _, cb := range info.postSaveCallbacks {
+				cb()
+			}
+			counter, err := delayedProvisioningHostCounters.GetMetricWith(delayedMetricLabels(info.request, tc.ExpectedBucket, tc.ExpectedValue))
+			require.NoError(t, err)
+			assert.InDelta(t, 1.0, promutil.ToFloat64(counter), 0)
+		})
+	}
+}
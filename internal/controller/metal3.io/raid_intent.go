@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"k8s.io/utils/ptr"
+)
+
+// raidIntentMinDisks is the minimum number of disks of a software RAID
+// volume for each level.
+var raidIntentMinDisks = map[string]int{
+	"0":   2,
+	"1":   2,
+	"1+0": 4,
+}
+
+// planRAIDIntent returns the RAID configuration with the RAID volumes
+// planned from its intent. Software RAID volumes are planned on the
+// inspected disks of the host, and the current plan is kept as long as the
+// intent does not change, so that the same disks are used again. The
+// intent is validated here too, since it may come from a HardwareProfile.
+func planRAIDIntent(raid, current *metal3api.RAIDConfig, details *metal3api.HardwareDetails) (*metal3api.RAIDConfig, error) {
+	if raid == nil || (len(raid.Intent) == 0 && raid.IntentType == "") {
+		return raid, nil
+	}
+	if errs := raid.ValidateIntent(); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid RAID intent: %w", errors.Join(errs...))
+	}
+	if raid.IntentType == metal3api.RAIDIntentHardware {
+		return planHardwareRAIDIntent(raid), nil
+	}
+	if current != nil && reflect.DeepEqual(current.Intent, raid.Intent) &&
+		current.IntentType == raid.IntentType && len(current.SoftwareRAIDVolumes) == len(raid.Intent) {
+		return current, nil
+	}
+	if details == nil || len(details.Storage) == 0 {
+		return nil, errors.New("cannot plan the RAID intent without the inspected disks of the host")
+	}
+
+	// Disks are picked from the smallest by default, the name breaking
+	// ties so that the plan does not depend on the inspection order.
+	disks := slices.Clone(details.Storage)
+	slices.SortStableFunc(disks, func(a, b metal3api.Storage) int {
+		return cmp.Or(cmp.Compare(a.SizeBytes, b.SizeBytes), cmp.Compare(a.Name, b.Name))
+	})
+
+	planned := &metal3api.RAIDConfig{
+		SoftwareRAIDVolumes: make([]metal3api.SoftwareRAIDVolume, 0, len(raid.Intent)),
+		Intent:              slices.Clone(raid.Intent),
+		IntentType:          raid.IntentType,
+	}
+	used := map[string]bool{}
+	for i, volume := range raid.Intent {
+		name := volume.Name
+		if name == "" {
+			name = fmt.Sprintf("%d", i)
+		}
+
+		var candidates []metal3api.Storage
+		for _, disk := range disks {
+			if disk.Name == "" || used[disk.Name] {
+				continue
+			}
+			if volume.DiskType != "" && disk.Type != volume.DiskType {
+				continue
+			}
+			candidates = append(candidates, disk)
+		}
+		if volume.Select == metal3api.RAIDDiskSelectionLargest {
+			slices.Reverse(candidates)
+		}
+
+		count := len(candidates)
+		if volume.DiskCount != nil {
+			count = *volume.DiskCount
+		}
+		needed := max(count, raidIntentMinDisks[volume.Level])
+		if len(candidates) < needed {
+			return nil, fmt.Errorf("RAID intent volume %s needs %d disks, only %d are available", name, needed, len(candidates))
+		}
+
+		plannedVolume := metal3api.SoftwareRAIDVolume{Level: volume.Level}
+		for _, disk := range candidates[:count] {
+			used[disk.Name] = true
+			plannedVolume.PhysicalDisks = append(plannedVolume.PhysicalDisks, metal3api.RootDeviceHints{DeviceName: disk.Name})
+		}
+		planned.SoftwareRAIDVolumes = append(planned.SoftwareRAIDVolumes, plannedVolume)
+	}
+	return planned, nil
+}
+
+// planHardwareRAIDIntent returns the RAID configuration with the hardware
+// RAID volumes of the intent. The disks behind a RAID controller are not
+// described by the inspection data, so the volumes only ask the controller
+// for disks of the wanted type.
+func planHardwareRAIDIntent(raid *metal3api.RAIDConfig) *metal3api.RAIDConfig {
+	planned := &metal3api.RAIDConfig{
+		HardwareRAIDVolumes: make([]metal3api.HardwareRAIDVolume, 0, len(raid.Intent)),
+		Intent:              slices.Clone(raid.Intent),
+		IntentType:          raid.IntentType,
+	}
+	for _, volume := range raid.Intent {
+		plannedVolume := metal3api.HardwareRAIDVolume{
+			Level:                 volume.Level,
+			Name:                  volume.Name,
+			NumberOfPhysicalDisks: volume.DiskCount,
+		}
+		switch volume.DiskType {
+		case metal3api.HDD:
+			plannedVolume.Rotational = ptr.To(true)
+		case metal3api.SSD, metal3api.NVME:
+			plannedVolume.Rotational = ptr.To(false)
+		}
+		planned.HardwareRAIDVolumes = append(planned.HardwareRAIDVolumes, plannedVolume)
+	}
+	return planned
+}
@@ -0,0 +1,242 @@
+package controllers
+
+import (
+	"testing"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1/profile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+func raidIntentDisks() *metal3api.HardwareDetails {
+	return &metal3api.HardwareDetails{
+		Storage: []metal3api.Storage{
+			{Name: "/dev/sdd", Type: metal3api.HDD, SizeBytes: 4 * metal3api.TebiByte},
+			{Name: "/dev/sda", Type: metal3api.SSD, SizeBytes: 480 * metal3api.GibiByte},
+			{Name: "/dev/sde", Type: metal3api.HDD, SizeBytes: 4 * metal3api.TebiByte},
+			{Name: "/dev/sdc", Type: metal3api.SSD, SizeBytes: 960 * metal3api.GibiByte},
+			{Name: "/dev/sdb", Type: metal3api.SSD, SizeBytes: 480 * metal3api.GibiByte},
+			{Name: "/dev/sdf", Type: metal3api.HDD, SizeBytes: 4 * metal3api.TebiByte},
+			{Name: "/dev/sdg", Type: metal3api.HDD, SizeBytes: 8 * metal3api.TebiByte},
+		},
+	}
+}
+
+func raidIntentHints(names ...string) []metal3api.RootDeviceHints {
+	hints := make([]metal3api.RootDeviceHints, 0, len(names))
+	for _, name := range names {
+		hints = append(hints, metal3api.RootDeviceHints{DeviceName: name})
+	}
+	return hints
+}
+
+func TestPlanRAIDIntent(t *testing.T) {
+	testCases := []struct {
+		Scenario      string
+		Intent        []metal3api.RAIDVolumeIntent
+		Current       *metal3api.RAIDConfig
+		Details       *metal3api.HardwareDetails
+		Expected      []metal3api.SoftwareRAIDVolume
+		ExpectedError string
+	}{
+		{
+			Scenario: "smallest SSDs for root and remaining HDDs for data",
+			Intent: []metal3api.RAIDVolumeIntent{
+				{Name: "root", Level: "1", DiskType: metal3api.SSD, DiskCount: ptr.To(2)},
+				{Name: "data", Level: "1+0", DiskType: metal3api.HDD},
+			},
+			Details: raidIntentDisks(),
+			Expected: []metal3api.SoftwareRAIDVolume{
+				{Level: "1", PhysicalDisks: raidIntentHints("/dev/sda", "/dev/sdb")},
+				{Level: "1+0", PhysicalDisks: raidIntentHints("/dev/sdd", "/dev/sde", "/dev/sdf", "/dev/sdg")},
+			},
+		},
+		{
+			Scenario: "largest disks of any type",
+			Intent: []metal3api.RAIDVolumeIntent{
+				{Level: "1", DiskCount: ptr.To(2), Select: metal3api.RAIDDiskSelectionLargest},
+				{Level: "0", DiskType: metal3api.SSD},
+			},
+			Details: raidIntentDisks(),
+			Expected: []metal3api.SoftwareRAIDVolume{
+				{Level: "1", PhysicalDisks: raidIntentHints("/dev/sdg", "/dev/sdf")},
+				{Level: "0", PhysicalDisks: raidIntentHints("/dev/sda", "/dev/sdb", "/dev/sdc")},
+			},
+		},
+		{
+			Scenario: "current plan kept",
+			Intent: []metal3api.RAIDVolumeIntent{
+				{Name: "root", Level: "1", DiskType: metal3api.SSD, DiskCount: ptr.To(2)},
+			},
+			Current: &metal3api.RAIDConfig{
+				SoftwareRAIDVolumes: []metal3api.SoftwareRAIDVolume{
+					{Level: "1", PhysicalDisks: raidIntentHints("/dev/sdb", "/dev/sdc")},
+				},
+				Intent: []metal3api.RAIDVolumeIntent{
+					{Name: "root", Level: "1", DiskType: metal3api.SSD, DiskCount: ptr.To(2)},
+				},
+			},
+			Details: raidIntentDisks(),
+			Expected: []metal3api.SoftwareRAIDVolume{
+				{Level: "1", PhysicalDisks: raidIntentHints("/dev/sdb", "/dev/sdc")},
+			},
+		},
+		{
+			Scenario: "plan redone when the intent changes",
+			Intent: []metal3api.RAIDVolumeIntent{
+				{Name: "root", Level: "1", DiskType: metal3api.HDD, DiskCount: ptr.To(2)},
+			},
+			Current: &metal3api.RAIDConfig{
+				SoftwareRAIDVolumes: []metal3api.SoftwareRAIDVolume{
+					{Level: "1", PhysicalDisks: raidIntentHints("/dev/sda", "/dev/sdb")},
+				},
+				Intent: []metal3api.RAIDVolumeIntent{
+					{Name: "root", Level: "1", DiskType: metal3api.SSD, DiskCount: ptr.To(2)},
+				},
+			},
+			Details: raidIntentDisks(),
+			Expected: []metal3api.SoftwareRAIDVolume{
+				{Level: "1", PhysicalDisks: raidIntentHints("/dev/sdd", "/dev/sde")},
+			},
+		},
+		{
+			Scenario: "not enough disks",
+			Intent: []metal3api.RAIDVolumeIntent{
+				{Name: "root", Level: "1", DiskType: metal3api.SSD},
+				{Name: "data", Level: "1+0", DiskType: metal3api.SSD},
+			},
+			Details:       raidIntentDisks(),
+			ExpectedError: "RAID intent volume data needs 4 disks, only 0 are available",
+		},
+		{
+			Scenario: "invalid intent",
+			Intent: []metal3api.RAIDVolumeIntent{
+				{Level: "0", DiskType: metal3api.SSD},
+			},
+			Details:       raidIntentDisks(),
+			ExpectedError: "invalid RAID intent: the level of the first volume of the RAID intent must be 1",
+		},
+		{
+			Scenario: "no inspected disks",
+			Intent: []metal3api.RAIDVolumeIntent{
+				{Level: "1"},
+			},
+			ExpectedError: "cannot plan the RAID intent without the inspected disks of the host",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			raid := &metal3api.RAIDConfig{Intent: tc.Intent}
+
+			planned, err := planRAIDIntent(raid, tc.Current, tc.Details)
+			if tc.ExpectedError != "" {
+				require.EqualError(t, err, tc.ExpectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.Expected, planned.SoftwareRAIDVolumes)
+			assert.Equal(t, tc.Intent, planned.Intent)
+			assert.Empty(t, planned.HardwareRAIDVolumes)
+		})
+	}
+}
+
+func TestPlanHardwareRAIDIntent(t *testing.T) {
+	raid := &metal3api.RAIDConfig{
+		Intent: []metal3api.RAIDVolumeIntent{
+			{Name: "root", Level: "1", DiskType: metal3api.SSD, DiskCount: ptr.To(2)},
+			{Name: "data", Level: "1+0", DiskType: metal3api.HDD},
+			{Level: "0"},
+		},
+		IntentType: metal3api.RAIDIntentHardware,
+	}
+
+	// The inspected disks are not needed for hardware RAID.
+	planned, err := planRAIDIntent(raid, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []metal3api.HardwareRAIDVolume{
+		{Name: "root", Level: "1", Rotational: ptr.To(false), NumberOfPhysicalDisks: ptr.To(2)},
+		{Name: "data", Level: "1+0", Rotational: ptr.To(true)},
+		{Level: "0"},
+	}, planned.HardwareRAIDVolumes)
+	assert.Empty(t, planned.SoftwareRAIDVolumes)
+	assert.Equal(t, raid.Intent, planned.Intent)
+	assert.Equal(t, metal3api.RAIDIntentHardware, planned.IntentType)
+
+	raid.Intent[0].Select = metal3api.RAIDDiskSelectionLargest
+	_, err = planRAIDIntent(raid, nil, nil)
+	require.EqualError(t, err, "invalid RAID intent: RAID intent volume 0 cannot pick the largest disks with hardware RAID")
+}
+
+func TestPlanRAIDIntentWithoutIntent(t *testing.T) {
+	raid := &metal3api.RAIDConfig{
+		SoftwareRAIDVolumes: []metal3api.SoftwareRAIDVolume{{Level: "1"}},
+	}
+
+	planned, err := planRAIDIntent(raid, nil, nil)
+	require.NoError(t, err)
+	assert.Same(t, raid, planned)
+
+	planned, err = planRAIDIntent(nil, nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, planned)
+}
+
+func TestSaveHostProvisioningSettingsRAIDIntent(t *testing.T) {
+	host := metal3api.BareMetalHost{
+		Spec: metal3api.BareMetalHostSpec{
+			HardwareProfile: "libvirt",
+			RAID: &metal3api.RAIDConfig{
+				Intent: []metal3api.RAIDVolumeIntent{
+					{Name: "root", Level: "1", DiskType: metal3api.SSD, DiskCount: ptr.To(2)},
+				},
+			},
+		},
+		Status: metal3api.BareMetalHostStatus{
+			HardwareProfile: "libvirt",
+			HardwareDetails: raidIntentDisks(),
+		},
+	}
+	info := makeReconcileInfo(&host)
+
+	dirty, err := saveHostProvisioningSettings(&host, info)
+	require.NoError(t, err)
+	assert.True(t, dirty)
+	require.NotNil(t, host.Status.Provisioning.RAID)
+	assert.Equal(t, []metal3api.SoftwareRAIDVolume{
+		{Level: "1", PhysicalDisks: raidIntentHints("/dev/sda", "/dev/sdb")},
+	}, host.Status.Provisioning.RAID.SoftwareRAIDVolumes)
+
+	// The plan is stable once recorded
+	dirty, err = saveHostProvisioningSettings(&host, info)
+	require.NoError(t, err)
+	assert.False(t, dirty)
+}
+
+func TestSaveHostProvisioningSettingsProfileRAIDIntent(t *testing.T) {
+	host := metal3api.BareMetalHost{
+		Spec: metal3api.BareMetalHostSpec{HardwareProfile: "custom"},
+		Status: metal3api.BareMetalHostStatus{
+			HardwareProfile: "custom",
+			HardwareDetails: raidIntentDisks(),
+		},
+	}
+	info := makeReconcileInfo(&host)
+	info.profileLookup = func(string) (profile.Profile, error) {
+		return profile.Profile{
+			Name: "custom",
+			RAID: &metal3api.RAIDConfig{
+				SoftwareRAIDVolumes: []metal3api.SoftwareRAIDVolume{{Level: "1"}},
+				Intent:              []metal3api.RAIDVolumeIntent{{Level: "1"}},
+			},
+		}, nil
+	}
+
+	// The webhook does not check the RAID of the hardware profiles.
+	_, err := saveHostProvisioningSettings(&host, info)
+	require.EqualError(t, err, "invalid RAID intent: intent can not be set together with hardwareRAIDVolumes or softwareRAIDVolumes")
+	assert.Nil(t, host.Status.Provisioning.RAID)
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"net"
 	"net/url"
+	"reflect"
 	"regexp"
 	"slices"
 	"strings"
@@ -58,6 +59,8 @@ func (webhook *BareMetalHost) validateHost(host *metal3api.BareMetalHost) []erro
 		errs = append(errs, raidErrors...)
 	}
 
+	errs = append(errs, validateNetwork(host.Spec.Network)...)
+
 	errs = append(errs, validateBMCAccess(host, bmcAccess)...)
 
 	if err := validateBMHName(host.Name); err != nil {
@@ -93,6 +96,13 @@ func (webhook *BareMetalHost) validateHost(host *metal3api.BareMetalHost) []erro
 	return errs
 }
 
+// ValidateBareMetalHost applies the creation rules of the webhook to a
+// BareMetalHost. It lets tools generating hosts report errors before the
+// hosts are submitted to the cluster.
+func ValidateBareMetalHost(host *metal3api.BareMetalHost) []error {
+	return (&BareMetalHost{}).validateHost(host)
+}
+
 // validateChanges validates BareMetalHost resource on changes
 // but also covers the validations of creation.
 func (webhook *BareMetalHost) validateChanges(oldObj *metal3api.BareMetalHost, newObj *metal3api.BareMetalHost) []error {
@@ -124,6 +134,75 @@ func (webhook *BareMetalHost) validateChanges(oldObj *metal3api.BareMetalHost, n
 	return errs
 }
 
+// validateSwitchPortConfig checks the VLAN configuration of a switch port.
+func validateSwitchPortConfig(name string, config metal3api.SwitchPortConfig) []error {
+	var errs []error
+
+	if config.VLAN != nil && (*config.VLAN < 1 || *config.VLAN > 4094) {
+		errs = append(errs, fmt.Errorf("the VLAN of %s must be between 1 and 4094", name))
+	}
+	if len(config.AllowedVLANs) > 0 && config.Mode != metal3api.SwitchPortModeTrunk {
+		errs = append(errs, fmt.Errorf("allowedVLANs of %s can only be set in trunk mode", name))
+	}
+	for _, vlan := range config.AllowedVLANs {
+		if vlan < 1 || vlan > 4094 {
+			errs = append(errs, fmt.Errorf("the allowed VLANs of %s must be between 1 and 4094", name))
+			break
+		}
+	}
+
+	return errs
+}
+
+func validateNetwork(network *metal3api.HostNetwork) []error {
+	var errs []error
+
+	if network == nil {
+		return errs
+	}
+
+	if network.ProvisioningVLAN != nil && (*network.ProvisioningVLAN < 1 || *network.ProvisioningVLAN > 4094) {
+		errs = append(errs, errors.New("provisioningVLAN must be between 1 and 4094"))
+	}
+
+	interfaces := make(map[string]bool, len(network.Interfaces))
+	for _, iface := range network.Interfaces {
+		mac := strings.ToLower(iface.MACAddress)
+		if interfaces[mac] {
+			errs = append(errs, fmt.Errorf("interface %s is listed more than once", iface.MACAddress))
+		}
+		interfaces[mac] = true
+		errs = append(errs, validateSwitchPortConfig("interface "+iface.MACAddress, iface.SwitchPortConfig)...)
+	}
+
+	bonds := make(map[string]bool, len(network.Bonds))
+	bonded := make(map[string]string)
+	for _, bond := range network.Bonds {
+		if bonds[bond.Name] {
+			errs = append(errs, fmt.Errorf("bond %s is listed more than once", bond.Name))
+		}
+		bonds[bond.Name] = true
+		for _, address := range bond.MACAddresses {
+			mac := strings.ToLower(address)
+			if other, found := bonded[mac]; found {
+				errs = append(errs, fmt.Errorf("interface %s can not be part of bonds %s and %s", address, other, bond.Name))
+			}
+			bonded[mac] = bond.Name
+		}
+		errs = append(errs, validateSwitchPortConfig("bond "+bond.Name, bond.SwitchPortConfig)...)
+	}
+
+	// The switch ports of a bond share its configuration
+	for _, iface := range network.Interfaces {
+		bond, found := bonded[strings.ToLower(iface.MACAddress)]
+		if found && !reflect.DeepEqual(iface.SwitchPortConfig, metal3api.SwitchPortConfig{}) {
+			errs = append(errs, fmt.Errorf("interface %s is part of bond %s and can not have its own VLAN configuration", iface.MACAddress, bond))
+		}
+	}
+
+	return errs
+}
+
 func validateBMCAccess(host *metal3api.BareMetalHost, bmcAccess bmc.AccessDetails) []error {
 	var errs []error
 	s := host.Spec
@@ -132,7 +211,7 @@ func validateBMCAccess(host *metal3api.BareMetalHost, bmcAccess bmc.AccessDetail
 		return errs
 	}
 
-	if s.RAID != nil && len(s.RAID.HardwareRAIDVolumes) > 0 {
+	if s.RAID != nil && (len(s.RAID.HardwareRAIDVolumes) > 0 || s.RAID.IntentType == metal3api.RAIDIntentHardware) {
 		if bmcAccess.RAIDInterface() == "no-raid" {
 			errs = append(errs, fmt.Errorf("BMC driver %s does not support configuring RAID", bmcAccess.Type()))
 		}
@@ -193,6 +272,8 @@ func validateRAID(r *metal3api.RAIDConfig) []error {
 		}
 	}
 
+	errs = append(errs, r.ValidateIntent()...)
+
 	return errs
 }
 
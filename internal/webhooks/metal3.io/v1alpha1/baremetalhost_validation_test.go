@@ -66,6 +66,12 @@ func TestValidateCreate(t *testing.T) {
 
 	// for RAID validation test cases
 	numberOfPhysicalDisks := 3
+	two := 2
+
+	// for network validation test cases
+	provisioningVLAN := metal3api.VLANID(5)
+	tenantVLAN := metal3api.VLANID(100)
+	noVLAN := metal3api.VLANID(0)
 
 	tests := []struct {
 		name      string
@@ -128,6 +134,195 @@ func TestValidateCreate(t *testing.T) {
 			oldBMH:    nil,
 			wantedErr: "hardwareRAIDVolumes and softwareRAIDVolumes can not be set at the same time",
 		},
+		{
+			name: "validRAIDIntent",
+			newBMH: &metal3api.BareMetalHost{
+				TypeMeta:   tm,
+				ObjectMeta: om,
+				Spec: metal3api.BareMetalHostSpec{
+					RAID: &metal3api.RAIDConfig{
+						Intent: []metal3api.RAIDVolumeIntent{
+							{Name: "root", Level: "1", DiskType: metal3api.SSD, DiskCount: &two},
+							{Name: "data", Level: "1+0", DiskType: metal3api.HDD},
+						},
+					}}},
+			oldBMH:    nil,
+			wantedErr: "",
+		},
+		{
+			name: "invalidRAIDIntentWithVolumes",
+			newBMH: &metal3api.BareMetalHost{
+				TypeMeta:   tm,
+				ObjectMeta: om,
+				Spec: metal3api.BareMetalHostSpec{
+					RAID: &metal3api.RAIDConfig{
+						SoftwareRAIDVolumes: []metal3api.SoftwareRAIDVolume{
+							{Level: "1"},
+						},
+						Intent: []metal3api.RAIDVolumeIntent{
+							{Level: "1"},
+						},
+					}}},
+			oldBMH:    nil,
+			wantedErr: "intent can not be set together with hardwareRAIDVolumes or softwareRAIDVolumes",
+		},
+		{
+			name: "invalidRAIDIntentFirstLevel",
+			newBMH: &metal3api.BareMetalHost{
+				TypeMeta:   tm,
+				ObjectMeta: om,
+				Spec: metal3api.BareMetalHostSpec{
+					RAID: &metal3api.RAIDConfig{
+						Intent: []metal3api.RAIDVolumeIntent{
+							{Level: "0"},
+						},
+					}}},
+			oldBMH:    nil,
+			wantedErr: "the level of the first volume of the RAID intent must be 1",
+		},
+		{
+			name: "invalidRAIDIntentDiskCount",
+			newBMH: &metal3api.BareMetalHost{
+				TypeMeta:   tm,
+				ObjectMeta: om,
+				Spec: metal3api.BareMetalHostSpec{
+					RAID: &metal3api.RAIDConfig{
+						Intent: []metal3api.RAIDVolumeIntent{
+							{Level: "1"},
+							{Level: "1+0", DiskCount: &two},
+						},
+					}}},
+			oldBMH:    nil,
+			wantedErr: "RAID intent volume 1 needs at least 4 disks for level 1+0",
+		},
+		{
+			name: "validHardwareRAIDIntent",
+			newBMH: &metal3api.BareMetalHost{
+				TypeMeta:   tm,
+				ObjectMeta: om,
+				Spec: metal3api.BareMetalHostSpec{
+					RAID: &metal3api.RAIDConfig{
+						Intent: []metal3api.RAIDVolumeIntent{
+							{Name: "data", Level: "1+0", DiskType: metal3api.HDD},
+						},
+						IntentType: metal3api.RAIDIntentHardware,
+					}}},
+			oldBMH:    nil,
+			wantedErr: "",
+		},
+		{
+			name: "invalidHardwareRAIDIntentSelect",
+			newBMH: &metal3api.BareMetalHost{
+				TypeMeta:   tm,
+				ObjectMeta: om,
+				Spec: metal3api.BareMetalHostSpec{
+					RAID: &metal3api.RAIDConfig{
+						Intent: []metal3api.RAIDVolumeIntent{
+							{Level: "1", Select: metal3api.RAIDDiskSelectionLargest},
+						},
+						IntentType: metal3api.RAIDIntentHardware,
+					}}},
+			oldBMH:    nil,
+			wantedErr: "RAID intent volume 0 cannot pick the largest disks with hardware RAID",
+		},
+		{
+			name: "invalidRAIDIntentTypeWithoutIntent",
+			newBMH: &metal3api.BareMetalHost{
+				TypeMeta:   tm,
+				ObjectMeta: om,
+				Spec: metal3api.BareMetalHostSpec{
+					RAID: &metal3api.RAIDConfig{
+						IntentType: metal3api.RAIDIntentHardware,
+					}}},
+			oldBMH:    nil,
+			wantedErr: "intentType can only be set together with intent",
+		},
+		{
+			name: "validNetwork",
+			newBMH: &metal3api.BareMetalHost{
+				TypeMeta:   tm,
+				ObjectMeta: om,
+				Spec: metal3api.BareMetalHostSpec{
+					Network: &metal3api.HostNetwork{
+						ProvisioningVLAN: &provisioningVLAN,
+						Interfaces: []metal3api.NetworkInterface{
+							{MACAddress: "00:00:00:00:00:01", Switch: "tor-1", Port: "Ethernet1/1"},
+							{MACAddress: "00:00:00:00:00:03", SwitchPortConfig: metal3api.SwitchPortConfig{VLAN: &tenantVLAN}},
+						},
+						Bonds: []metal3api.NetworkBond{
+							{
+								Name:         "bond0",
+								MACAddresses: []string{"00:00:00:00:00:01", "00:00:00:00:00:02"},
+								SwitchPortConfig: metal3api.SwitchPortConfig{
+									Mode:         metal3api.SwitchPortModeTrunk,
+									AllowedVLANs: []metal3api.VLANID{20, 30},
+								},
+							},
+						},
+					}}},
+			oldBMH:    nil,
+			wantedErr: "",
+		},
+		{
+			name: "invalidNetworkVLAN",
+			newBMH: &metal3api.BareMetalHost{
+				TypeMeta:   tm,
+				ObjectMeta: om,
+				Spec: metal3api.BareMetalHostSpec{
+					Network: &metal3api.HostNetwork{
+						Interfaces: []metal3api.NetworkInterface{
+							{MACAddress: "00:00:00:00:00:01", SwitchPortConfig: metal3api.SwitchPortConfig{VLAN: &noVLAN}},
+						},
+					}}},
+			oldBMH:    nil,
+			wantedErr: "the VLAN of interface 00:00:00:00:00:01 must be between 1 and 4094",
+		},
+		{
+			name: "invalidNetworkAllowedVLANsInAccessMode",
+			newBMH: &metal3api.BareMetalHost{
+				TypeMeta:   tm,
+				ObjectMeta: om,
+				Spec: metal3api.BareMetalHostSpec{
+					Network: &metal3api.HostNetwork{
+						Interfaces: []metal3api.NetworkInterface{
+							{MACAddress: "00:00:00:00:00:01", SwitchPortConfig: metal3api.SwitchPortConfig{AllowedVLANs: []metal3api.VLANID{20}}},
+						},
+					}}},
+			oldBMH:    nil,
+			wantedErr: "allowedVLANs of interface 00:00:00:00:00:01 can only be set in trunk mode",
+		},
+		{
+			name: "invalidNetworkInterfaceInTwoBonds",
+			newBMH: &metal3api.BareMetalHost{
+				TypeMeta:   tm,
+				ObjectMeta: om,
+				Spec: metal3api.BareMetalHostSpec{
+					Network: &metal3api.HostNetwork{
+						Bonds: []metal3api.NetworkBond{
+							{Name: "bond0", MACAddresses: []string{"00:00:00:00:00:01", "00:00:00:00:00:02"}},
+							{Name: "bond1", MACAddresses: []string{"00:00:00:00:00:02", "00:00:00:00:00:03"}},
+						},
+					}}},
+			oldBMH:    nil,
+			wantedErr: "interface 00:00:00:00:00:02 can not be part of bonds bond0 and bond1",
+		},
+		{
+			name: "invalidNetworkBondedInterfaceVLAN",
+			newBMH: &metal3api.BareMetalHost{
+				TypeMeta:   tm,
+				ObjectMeta: om,
+				Spec: metal3api.BareMetalHostSpec{
+					Network: &metal3api.HostNetwork{
+						Interfaces: []metal3api.NetworkInterface{
+							{MACAddress: "00:00:00:00:00:01", SwitchPortConfig: metal3api.SwitchPortConfig{VLAN: &tenantVLAN}},
+						},
+						Bonds: []metal3api.NetworkBond{
+							{Name: "bond0", MACAddresses: []string{"00:00:00:00:00:01", "00:00:00:00:00:02"}},
+						},
+					}}},
+			oldBMH:    nil,
+			wantedErr: "interface 00:00:00:00:00:01 is part of bond bond0 and can not have its own VLAN configuration",
+		},
 		{
 			name: "supportBMCType",
 			newBMH: &metal3api.BareMetalHost{
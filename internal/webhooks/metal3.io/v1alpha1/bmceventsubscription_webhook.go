@@ -69,8 +69,13 @@ func (webhook *BMCEventSubscription) ValidateUpdate(_ context.Context, oldBMCES,
 	}
 	bmcsubscriptionlog.Info("validate update", "namespace", newBMCES.Namespace, "name", newBMCES.Name)
 
+	if newBMCES.Spec.HostName != oldBMCES.Spec.HostName {
+		return nil, errors.New("hostName of subscriptions cannot be updated, please recreate it")
+	}
+
+	// The controller recreates the subscription when the spec changes
 	if newBMCES.Spec != oldBMCES.Spec {
-		return nil, errors.New("subscriptions cannot be updated, please recreate it")
+		return nil, kerrors.NewAggregate(webhook.validateSubscription(newBMCES))
 	}
 
 	return nil, nil
@@ -80,7 +80,74 @@ func TestBMCEventSubscriptionUpdate(t *testing.T) {
 				},
 				Spec: metal3api.BMCEventSubscriptionSpec{Context: "abc"},
 			},
-			wantedErr: "subscriptions cannot be updated, please recreate it",
+			wantedErr: "destination cannot be empty",
+		},
+		{
+			// The destination, context and headers can be updated
+			name: "spec updated",
+			bes: &metal3api.BMCEventSubscription{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "metal3api.BMCEventSubscription",
+					APIVersion: "metal3.io/v1alpha1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "test-namespace",
+				},
+				Spec: metal3api.BMCEventSubscriptionSpec{
+					HostName:    "worker-0",
+					Destination: "https://example.com/new",
+					Context:     "def",
+				},
+			},
+			old: &metal3api.BMCEventSubscription{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "metal3api.BMCEventSubscription",
+					APIVersion: "metal3.io/v1alpha1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "test-namespace",
+				},
+				Spec: metal3api.BMCEventSubscriptionSpec{
+					HostName:    "worker-0",
+					Destination: "https://example.com/old",
+					Context:     "abc",
+				},
+			},
+			wantedErr: "",
+		},
+		{
+			name: "hostName updated",
+			bes: &metal3api.BMCEventSubscription{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "metal3api.BMCEventSubscription",
+					APIVersion: "metal3.io/v1alpha1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "test-namespace",
+				},
+				Spec: metal3api.BMCEventSubscriptionSpec{
+					HostName:    "worker-1",
+					Destination: "https://example.com/",
+				},
+			},
+			old: &metal3api.BMCEventSubscription{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "metal3api.BMCEventSubscription",
+					APIVersion: "metal3.io/v1alpha1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "test-namespace",
+				},
+				Spec: metal3api.BMCEventSubscriptionSpec{
+					HostName:    "worker-0",
+					Destination: "https://example.com/",
+				},
+			},
+			wantedErr: "hostName of subscriptions cannot be updated, please recreate it",
 		},
 		{
 			// Status updates are valid
@@ -15,10 +15,12 @@ limitations under the License.
 package webhooks
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
 	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	hostclaimmanager "github.com/metal3-io/baremetal-operator/pkg/hostclaim"
 	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/util/validation"
 )
@@ -72,6 +74,10 @@ func (webhook *HostClaimWebhook) validateHostClaim(hostclaim *metal3api.HostClai
 		}
 	}
 
+	if hostclaim.Spec.HostSelector.Hardware != nil {
+		errs = append(errs, validateHardwareRequirements(hostclaim.Spec.HostSelector.Hardware)...)
+	}
+
 	annotationErrs := validateHostclaimAnnotations(hostclaim)
 	errs = append(errs, annotationErrs...)
 
@@ -80,6 +86,49 @@ func (webhook *HostClaimWebhook) validateHostClaim(hostclaim *metal3api.HostClai
 		errs = append(errs, imageErrs...)
 	}
 
+	if hostclaim.Spec.Scoring != nil && hostclaim.Spec.Scoring.Profile != "" {
+		if _, err := hostclaimmanager.GetScoringProfile(hostclaim.Spec.Scoring.Profile); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+func validateHardwareRequirements(req *metal3api.HardwareRequirements) []error {
+	var errs []error
+	if req.MinRAMMebibytes < 0 {
+		errs = append(errs, errors.New("hardware: minRAMMebibytes must not be negative"))
+	}
+	if req.MinCPUCount < 0 {
+		errs = append(errs, errors.New("hardware: minCPUCount must not be negative"))
+	}
+	for i, flag := range req.CPUFlags {
+		if strings.TrimSpace(flag) == "" {
+			errs = append(errs, fmt.Errorf("hardware: cpuFlags %d must not be empty", i+1))
+		}
+	}
+	for i, disk := range req.Disks {
+		switch disk.Type {
+		case "", metal3api.HDD, metal3api.SSD, metal3api.NVME:
+		default:
+			errs = append(errs, fmt.Errorf("hardware: disk %d: invalid type %s", i+1, disk.Type))
+		}
+		if disk.MinCount < 0 {
+			errs = append(errs, fmt.Errorf("hardware: disk %d: minCount must not be negative", i+1))
+		}
+		if disk.MinSizeBytes < 0 {
+			errs = append(errs, fmt.Errorf("hardware: disk %d: minSizeBytes must not be negative", i+1))
+		}
+	}
+	if req.NICs != nil {
+		if req.NICs.MinCount < 0 {
+			errs = append(errs, errors.New("hardware: nics: minCount must not be negative"))
+		}
+		if req.NICs.MinSpeedGbps < 0 {
+			errs = append(errs, errors.New("hardware: nics: minSpeedGbps must not be negative"))
+		}
+	}
 	return errs
 }
 
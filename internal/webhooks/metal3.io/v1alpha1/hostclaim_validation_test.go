@@ -203,3 +203,100 @@ func TestValidateHostClaimImageCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateHostClaimScoringCreate(t *testing.T) {
+	tests := []struct {
+		name      string
+		scoring   *metal3api.HostScoring
+		wantedErr string
+	}{
+		{
+			name:    "default profile",
+			scoring: &metal3api.HostScoring{PreferredVendors: []string{"Dell"}},
+		},
+		{
+			name:    "known profile",
+			scoring: &metal3api.HostScoring{Profile: "binpack"},
+		},
+		{
+			name:      "unknown profile",
+			scoring:   &metal3api.HostScoring{Profile: "best"},
+			wantedErr: "unknown scoring profile \"best\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhook := &HostClaimWebhook{}
+			hostclaim := &metal3api.HostClaim{
+				TypeMeta:   metav1.TypeMeta{},
+				ObjectMeta: metav1.ObjectMeta{},
+				Spec: metal3api.HostClaimSpec{
+					Scoring: tt.scoring,
+				},
+			}
+			if err := webhook.validateHostClaim(hostclaim); !errorArrContainsPrefix(err, tt.wantedErr) {
+				t.Errorf("metal3api.HostClaimWebhook Scoring error = %v, wantErr %v", err, tt.wantedErr)
+			}
+		})
+	}
+}
+
+func TestValidateHostClaimHardwareCreate(t *testing.T) {
+	tests := []struct {
+		name      string
+		hardware  *metal3api.HardwareRequirements
+		wantedErr string
+	}{
+		{
+			name: "valid requirements",
+			hardware: &metal3api.HardwareRequirements{
+				MinRAMMebibytes: 64 * 1024,
+				MinCPUCount:     16,
+				CPUArchitecture: "x86_64",
+				CPUFlags:        []string{"vmx"},
+				Disks:           []metal3api.DiskRequirement{{Type: metal3api.NVME, MinCount: 2, MinSizeBytes: metal3api.TeraByte}},
+				NICs:            &metal3api.NICRequirement{MinCount: 2, MinSpeedGbps: 25},
+			},
+		},
+		{
+			name:      "negative RAM",
+			hardware:  &metal3api.HardwareRequirements{MinRAMMebibytes: -1},
+			wantedErr: "hardware: minRAMMebibytes must not be negative",
+		},
+		{
+			name:      "empty CPU flag",
+			hardware:  &metal3api.HardwareRequirements{CPUFlags: []string{"vmx", ""}},
+			wantedErr: "hardware: cpuFlags 2 must not be empty",
+		},
+		{
+			name:      "invalid disk type",
+			hardware:  &metal3api.HardwareRequirements{Disks: []metal3api.DiskRequirement{{Type: "tape"}}},
+			wantedErr: "hardware: disk 1: invalid type tape",
+		},
+		{
+			name:      "negative disk size",
+			hardware:  &metal3api.HardwareRequirements{Disks: []metal3api.DiskRequirement{{MinSizeBytes: -1}}},
+			wantedErr: "hardware: disk 1: minSizeBytes must not be negative",
+		},
+		{
+			name:      "negative NIC speed",
+			hardware:  &metal3api.HardwareRequirements{NICs: &metal3api.NICRequirement{MinSpeedGbps: -10}},
+			wantedErr: "hardware: nics: minSpeedGbps must not be negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhook := &HostClaimWebhook{}
+			hostclaim := &metal3api.HostClaim{
+				TypeMeta:   metav1.TypeMeta{},
+				ObjectMeta: metav1.ObjectMeta{},
+				Spec: metal3api.HostClaimSpec{
+					HostSelector: metal3api.HostSelector{Hardware: tt.hardware},
+				},
+			}
+			if err := webhook.validateHostClaim(hostclaim); !errorArrContainsPrefix(err, tt.wantedErr) {
+				t.Errorf("metal3api.HostClaimWebhook Hardware error = %v, wantErr %v", err, tt.wantedErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2026 The Metal3 Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"fmt"
+	"strings"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/maintenancewindow"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// validatePolicy validates the HostUpdatePolicy resource for creation and update.
+func (webhook *HostUpdatePolicy) validatePolicy(hup *metal3api.HostUpdatePolicy) []error {
+	var errs []error
+
+	for i, window := range hup.Spec.MaintenanceWindows {
+		if err := maintenancewindow.Validate(window); err != nil {
+			errs = append(errs, fmt.Errorf("maintenanceWindows[%d] is invalid: %w", i, err))
+		}
+		if window.Duration.Duration <= 0 {
+			errs = append(errs, fmt.Errorf("maintenanceWindows[%d] must have a positive duration", i))
+		}
+	}
+
+	if label := hup.Spec.ServicingGroupLabel; label != "" {
+		if msgs := validation.IsQualifiedName(label); len(msgs) > 0 {
+			errs = append(errs, fmt.Errorf("servicingGroupLabel %q is invalid: %s", label, strings.Join(msgs, ", ")))
+		}
+	}
+
+	return errs
+}
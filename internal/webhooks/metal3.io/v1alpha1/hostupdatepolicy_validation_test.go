@@ -0,0 +1,95 @@
+/*
+Copyright 2026 The Metal3 Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"testing"
+	"time"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateHostUpdatePolicy(t *testing.T) {
+	window := func(schedule, timeZone string, duration time.Duration) metal3api.MaintenanceWindow {
+		return metal3api.MaintenanceWindow{
+			Schedule: schedule,
+			TimeZone: timeZone,
+			Duration: metav1.Duration{Duration: duration},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		spec      metal3api.HostUpdatePolicySpec
+		wantedErr string
+	}{
+		{
+			name: "valid",
+			spec: metal3api.HostUpdatePolicySpec{
+				MaintenanceWindows: []metal3api.MaintenanceWindow{
+					window("0 2 * * 6", "", 4*time.Hour),
+					window("*/30 22-23 1,15 * *", "Europe/Paris", time.Hour),
+				},
+				ServicingGroupLabel: "example.com/rack",
+			},
+		},
+		{
+			name: "invalid schedule",
+			spec: metal3api.HostUpdatePolicySpec{
+				MaintenanceWindows: []metal3api.MaintenanceWindow{window("0 25 * * *", "", time.Hour)},
+			},
+			wantedErr: "maintenanceWindows[0] is invalid: invalid hour",
+		},
+		{
+			name: "invalid time zone",
+			spec: metal3api.HostUpdatePolicySpec{
+				MaintenanceWindows: []metal3api.MaintenanceWindow{
+					window("0 2 * * *", "", time.Hour),
+					window("0 2 * * *", "Mars/Olympus_Mons", time.Hour),
+				},
+			},
+			wantedErr: "maintenanceWindows[1] is invalid: invalid time zone",
+		},
+		{
+			name: "no duration",
+			spec: metal3api.HostUpdatePolicySpec{
+				MaintenanceWindows: []metal3api.MaintenanceWindow{window("0 2 * * *", "", 0)},
+			},
+			wantedErr: "maintenanceWindows[0] must have a positive duration",
+		},
+		{
+			name: "invalid group label",
+			spec: metal3api.HostUpdatePolicySpec{
+				ServicingGroupLabel: "-rack-",
+			},
+			wantedErr: "servicingGroupLabel \"-rack-\" is invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hup := &metal3api.HostUpdatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-namespace"},
+				Spec:       tt.spec,
+			}
+			if errs := (&HostUpdatePolicy{}).validatePolicy(hup); !errorArrContainsPrefix(errs, tt.wantedErr) {
+				t.Errorf("validatePolicy() = %v, want prefix %q", errs, tt.wantedErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2026 The Metal3 Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"errors"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// hostupdatepolicylog is for logging in this webhook.
+var hostupdatepolicylog = logf.Log.WithName("webhooks").WithName("HostUpdatePolicy")
+
+// SetupWebhookWithManager registers the HostUpdatePolicy validation webhook with the manager.
+func (webhook *HostUpdatePolicy) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &metal3api.HostUpdatePolicy{}).
+		WithValidator(webhook).
+		Complete()
+}
+
+//+kubebuilder:webhook:verbs=create;update,path=/validate-metal3-io-v1alpha1-hostupdatepolicy,mutating=false,failurePolicy=fail,sideEffects=none,admissionReviewVersions=v1,groups=metal3.io,resources=hostupdatepolicies,versions=v1alpha1,name=hostupdatepolicy.metal3.io
+
+// HostUpdatePolicy implements a validation webhook for HostUpdatePolicy.
+type HostUpdatePolicy struct{}
+
+var _ admission.Validator[*metal3api.HostUpdatePolicy] = &HostUpdatePolicy{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (webhook *HostUpdatePolicy) ValidateCreate(_ context.Context, hup *metal3api.HostUpdatePolicy) (admission.Warnings, error) {
+	if hup == nil {
+		hostupdatepolicylog.Error(errors.New("object is nil"), "validate create error")
+		return nil, nil
+	}
+
+	hostupdatepolicylog.Info("validate create", "namespace", hup.Namespace, "name", hup.Name)
+	return nil, kerrors.NewAggregate(webhook.validatePolicy(hup))
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (webhook *HostUpdatePolicy) ValidateUpdate(_ context.Context, _, newHUP *metal3api.HostUpdatePolicy) (admission.Warnings, error) {
+	if newHUP == nil {
+		hostupdatepolicylog.Error(errors.New("new object is nil"), "validate update error")
+		return nil, nil
+	}
+
+	hostupdatepolicylog.Info("validate update", "namespace", newHUP.Namespace, "name", newHUP.Name)
+	return nil, kerrors.NewAggregate(webhook.validatePolicy(newHUP))
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (webhook *HostUpdatePolicy) ValidateDelete(_ context.Context, _ *metal3api.HostUpdatePolicy) (admission.Warnings, error) {
+	return nil, nil
+}
@@ -21,12 +21,14 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
 	"time"
 
 	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/internal/bmcevents"
 	metal3iocontroller "github.com/metal3-io/baremetal-operator/internal/controller/metal3.io"
 	webhooks "github.com/metal3-io/baremetal-operator/internal/webhooks/metal3.io/v1alpha1"
 	"github.com/metal3-io/baremetal-operator/pkg/imageprovider"
@@ -74,6 +76,10 @@ var (
 
 const leaderElectionID = "baremetal-operator"
 
+// defaultSwitchProbeInterval is how often the BareMetalSwitch management
+// addresses are probed, unless IRONIC_SWITCH_PROBE_INTERVAL is set.
+const defaultSwitchProbeInterval = 5 * time.Minute
+
 func init() {
 	_ = clientgoscheme.AddToScheme(scheme)
 
@@ -129,6 +135,11 @@ func setupWebhooks(mgr ctrl.Manager) {
 		setupLog.Error(err, "unable to create webhook", "webhook", "HostClaim")
 		os.Exit(1)
 	}
+
+	if err := (&webhooks.HostUpdatePolicy{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "HostUpdatePolicy")
+		os.Exit(1)
+	}
 }
 
 func main() {
@@ -147,6 +158,8 @@ func main() {
 	var leaseDurationSeconds string
 	var renewDeadlineSeconds string
 	var retryPeriodSeconds string
+	var bmcEventsAddr string
+	var bmcEventsCertDir string
 
 	// From CAPI point of view, BMO should be able to watch all namespaces
 	// in case of a deployment that is not multi-tenant. If the deployment
@@ -192,6 +205,11 @@ func main() {
 	flag.IntVar(&controllerConcurrency, "controller-concurrency", 0,
 		"Number of CRs of each type to process simultaneously")
 
+	flag.StringVar(&bmcEventsAddr, "bmc-events-addr", "",
+		"The address the BMC event receiver binds to (leave empty to disable).")
+	flag.StringVar(&bmcEventsCertDir, "bmc-events-cert-dir", filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs"),
+		"The directory holding the tls.crt and tls.key files of the BMC event receiver.")
+
 	flag.StringVar(&leaseDurationSeconds, "lease-duration-seconds", os.Getenv("LEASE_DURATION_SECONDS"), "Leader election duration in seconds.")
 	flag.StringVar(&renewDeadlineSeconds, "renew-deadline-seconds", os.Getenv("RENEW_DEADLINE_SECONDS"), "Leader election renew deadline duration in seconds.")
 	flag.StringVar(&retryPeriodSeconds, "retry-period-seconds", os.Getenv("RETRY_PERIOD_SECONDS"), "Leader election retry period in seconds.")
@@ -349,11 +367,33 @@ func main() {
 		os.Exit(1)
 	}
 
+	provisioningGroupLimits, err := metal3iocontroller.ParseProvisioningGroupLimits(os.Getenv("PROVISIONING_GROUP_LIMITS"))
+	if err != nil {
+		setupLog.Error(err, "invalid environment variable value", "name", "PROVISIONING_GROUP_LIMITS")
+		os.Exit(1)
+	}
+
+	if bmcEventsAddr != "" {
+		if err = mgr.Add(&bmcevents.Receiver{
+			Client:      mgr.GetClient(),
+			APIReader:   mgr.GetAPIReader(),
+			Log:         ctrl.Log.WithName("bmcevents"),
+			Recorder:    mgr.GetEventRecorderFor("bmc-event-receiver"),
+			BindAddress: bmcEventsAddr,
+			CertDir:     bmcEventsCertDir,
+			TLSOpts:     tlsOptionOverrides,
+		}); err != nil {
+			setupLog.Error(err, "unable to add the BMC event receiver")
+			os.Exit(1)
+		}
+	}
+
 	if err = (&metal3iocontroller.BareMetalHostReconciler{
-		Client:             mgr.GetClient(),
-		Log:                ctrl.Log.WithName("controllers").WithName("BareMetalHost"),
-		ProvisionerFactory: provisionerFactory,
-		APIReader:          mgr.GetAPIReader(),
+		Client:                  mgr.GetClient(),
+		Log:                     ctrl.Log.WithName("controllers").WithName("BareMetalHost"),
+		ProvisionerFactory:      provisionerFactory,
+		APIReader:               mgr.GetAPIReader(),
+		ProvisioningGroupLimits: provisioningGroupLimits,
 	}).SetupWithManager(mgr, preprovImgEnable, maxConcurrency); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "BareMetalHost")
 		os.Exit(1)
@@ -376,12 +416,21 @@ func main() {
 	}
 	if hostClaimEnable {
 		if err = (&metal3iocontroller.HostClaimReconciler{
-			Client: mgr.GetClient(),
-			Scheme: mgr.GetScheme(),
+			Client:    mgr.GetClient(),
+			Scheme:    mgr.GetScheme(),
+			Log:       ctrl.Log.WithName("controllers").WithName("HostClaim"),
+			APIReader: mgr.GetAPIReader(),
 		}).SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "HostClaim")
 			os.Exit(1)
 		}
+		if err = (&metal3iocontroller.HostDeployPolicyReconciler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("HostDeployPolicy"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "HostDeployPolicy")
+			os.Exit(1)
+		}
 	}
 	// +kubebuilder:scaffold:builder
 
@@ -412,6 +461,39 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = (&metal3iocontroller.FirmwareUpdateCampaignReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("FirmwareUpdateCampaign"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "FirmwareUpdateCampaign")
+		os.Exit(1)
+	}
+
+	if err = (&metal3iocontroller.FirmwareBaselineReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("FirmwareBaseline"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "FirmwareBaseline")
+		os.Exit(1)
+	}
+
+	if err = (&metal3iocontroller.FirmwareSettingsTemplateReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("FirmwareSettingsTemplate"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "FirmwareSettingsTemplate")
+		os.Exit(1)
+	}
+
+	if err = (&metal3iocontroller.HostRemediationPolicyReconciler{
+		Client:    mgr.GetClient(),
+		Log:       ctrl.Log.WithName("controllers").WithName("HostRemediationPolicy"),
+		APIReader: mgr.GetAPIReader(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "HostRemediationPolicy")
+		os.Exit(1)
+	}
+
 	if err = (&metal3iocontroller.DataImageReconciler{
 		Client:             mgr.GetClient(),
 		Log:                ctrl.Log.WithName("controllers").WithName("DataImage"),
@@ -446,6 +528,18 @@ func main() {
 			os.Exit(1)
 		}
 
+		switchProbeInterval := defaultSwitchProbeInterval
+		if value := os.Getenv("IRONIC_SWITCH_PROBE_INTERVAL"); value != "" {
+			switchProbeInterval, err = time.ParseDuration(value)
+			if err == nil && switchProbeInterval < 0 {
+				err = errors.New("the interval must not be negative")
+			}
+			if err != nil {
+				setupLog.Error(err, "invalid environment variable value", "name", "IRONIC_SWITCH_PROBE_INTERVAL", "value", value)
+				os.Exit(1)
+			}
+		}
+
 		if err = (&metal3iocontroller.BareMetalSwitchReconciler{
 			Client:                     mgr.GetClient(),
 			Log:                        ctrl.Log.WithName("controllers").WithName("BareMetalSwitch"),
@@ -453,6 +547,7 @@ func main() {
 			SwitchConfigsSecretName:    switchConfigsSecretName,
 			SwitchCredentialSecretName: switchCredentialSecretName,
 			SwitchCredentialPath:       switchCredentialPath,
+			ProbeInterval:              switchProbeInterval,
 		}).SetupWithManager(mgr, maxConcurrency); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "BareMetalSwitch")
 			os.Exit(1)
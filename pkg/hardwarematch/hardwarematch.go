@@ -0,0 +1,116 @@
+/*
+Copyright 2025 The Metal3 Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package hardwarematch checks the inspected hardware of a host against
+// HardwareRequirements. It is shared by the HostClaim scheduler and the
+// controllers selecting hosts by hardware.
+package hardwarematch
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+)
+
+// Match checks the hardware details of a host against the
+// requirements of a HostClaim or of a HardwareProfile. When the host does not
+// match, it returns a description of the first requirement that is not met.
+func Match(req *metal3api.HardwareRequirements, hw *metal3api.HardwareDetails) (bool, string) {
+	if hw == nil {
+		return false, "host has no hardware details"
+	}
+	if hw.RAMMebibytes < req.MinRAMMebibytes {
+		return false, fmt.Sprintf("RAM %d MiB is less than %d MiB", hw.RAMMebibytes, req.MinRAMMebibytes)
+	}
+	if hw.CPU.Count < req.MinCPUCount {
+		return false, fmt.Sprintf("CPU count %d is less than %d", hw.CPU.Count, req.MinCPUCount)
+	}
+	if req.CPUArchitecture != "" && hw.CPU.Arch != req.CPUArchitecture {
+		return false, fmt.Sprintf("CPU architecture %q is not %q", hw.CPU.Arch, req.CPUArchitecture)
+	}
+	for _, flag := range req.CPUFlags {
+		if !slices.Contains(hw.CPU.Flags, flag) {
+			return false, fmt.Sprintf("CPU flag %q is missing", flag)
+		}
+	}
+	for _, diskReq := range req.Disks {
+		if ok, reason := matchDiskRequirement(diskReq, hw.Storage); !ok {
+			return false, reason
+		}
+	}
+	if req.NICs != nil {
+		if ok, reason := matchNICRequirement(req.NICs, hw.NIC); !ok {
+			return false, reason
+		}
+	}
+	if !containsFold(hw.SystemVendor.Manufacturer, req.Vendor) {
+		return false, fmt.Sprintf("vendor %q does not match %q", hw.SystemVendor.Manufacturer, req.Vendor)
+	}
+	if !containsFold(hw.SystemVendor.ProductName, req.Product) {
+		return false, fmt.Sprintf("product %q does not match %q", hw.SystemVendor.ProductName, req.Product)
+	}
+	return true, ""
+}
+
+func matchDiskRequirement(req metal3api.DiskRequirement, storage []metal3api.Storage) (bool, string) {
+	count := 0
+	for _, disk := range storage {
+		if req.Type != "" && disk.Type != req.Type {
+			continue
+		}
+		if disk.SizeBytes < req.MinSizeBytes {
+			continue
+		}
+		count++
+	}
+	if count < max(req.MinCount, 1) {
+		diskType := "any type"
+		if req.Type != "" {
+			diskType = string(req.Type)
+		}
+		return false, fmt.Sprintf("found %d disks of %s with at least %d bytes, need %d",
+			count, diskType, req.MinSizeBytes, max(req.MinCount, 1))
+	}
+	return true, ""
+}
+
+func matchNICRequirement(req *metal3api.NICRequirement, nics []metal3api.NIC) (bool, string) {
+	// Dual-stack interfaces are reported once per IP address.
+	seen := map[string]bool{}
+	for _, nic := range nics {
+		key := nic.MAC
+		if key == "" {
+			key = nic.Name
+		}
+		if nic.SpeedGbps >= req.MinSpeedGbps {
+			seen[key] = true
+		}
+	}
+	count := len(seen)
+	if count < max(req.MinCount, 1) {
+		return false, fmt.Sprintf("found %d NICs with at least %d Gbps, need %d",
+			count, req.MinSpeedGbps, max(req.MinCount, 1))
+	}
+	return true, ""
+}
+
+// containsFold does a case insensitive substring match. An empty pattern
+// matches any value.
+func containsFold(value, pattern string) bool {
+	return strings.Contains(strings.ToLower(value), strings.ToLower(pattern))
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2025 The Metal3 Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hardwarematch
+
+import (
+	"testing"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func hardware() *metal3api.HardwareDetails {
+	return &metal3api.HardwareDetails{
+		SystemVendor: metal3api.HardwareSystemVendor{Manufacturer: "Dell Inc.", ProductName: "PowerEdge R650"},
+		RAMMebibytes: 256 * 1024,
+		CPU:          metal3api.CPU{Arch: "x86_64", Count: 32, Flags: []string{"sse4_2", "vmx"}},
+		Storage: []metal3api.Storage{
+			{Name: "/dev/sda", Type: metal3api.SSD, SizeBytes: 480 * metal3api.GigaByte},
+			{Name: "/dev/nvme0n1", Type: metal3api.NVME, SizeBytes: 2 * metal3api.TeraByte},
+			{Name: "/dev/nvme1n1", Type: metal3api.NVME, SizeBytes: 2 * metal3api.TeraByte},
+		},
+		NIC: []metal3api.NIC{
+			{Name: "eno1", MAC: "00:00:00:00:00:01", IP: "192.168.0.10", SpeedGbps: 1},
+			{Name: "ens1f0", MAC: "00:00:00:00:00:02", IP: "192.168.1.10", SpeedGbps: 25},
+			{Name: "ens1f0", MAC: "00:00:00:00:00:02", IP: "fd00::10", SpeedGbps: 25},
+			{Name: "ens1f1", MAC: "00:00:00:00:00:03", SpeedGbps: 25},
+		},
+	}
+}
+
+func TestMatch(t *testing.T) {
+	testCases := []struct {
+		Scenario      string
+		Requirements  metal3api.HardwareRequirements
+		NoHardware    bool
+		ExpectedMatch bool
+	}{
+		{
+			Scenario:      "no requirements",
+			ExpectedMatch: true,
+		},
+		{
+			Scenario:      "no hardware details",
+			NoHardware:    true,
+			ExpectedMatch: false,
+		},
+		{
+			Scenario: "all requirements met",
+			Requirements: metal3api.HardwareRequirements{
+				MinRAMMebibytes: 128 * 1024,
+				MinCPUCount:     32,
+				CPUArchitecture: "x86_64",
+				CPUFlags:        []string{"vmx"},
+				Disks: []metal3api.DiskRequirement{
+					{Type: metal3api.NVME, MinCount: 2, MinSizeBytes: metal3api.TeraByte},
+					{Type: metal3api.SSD},
+				},
+				NICs:    &metal3api.NICRequirement{MinCount: 2, MinSpeedGbps: 25},
+				Vendor:  "dell",
+				Product: "R650",
+			},
+			ExpectedMatch: true,
+		},
+		{
+			Scenario:      "not enough RAM",
+			Requirements:  metal3api.HardwareRequirements{MinRAMMebibytes: 512 * 1024},
+			ExpectedMatch: false,
+		},
+		{
+			Scenario:      "not enough CPUs",
+			Requirements:  metal3api.HardwareRequirements{MinCPUCount: 64},
+			ExpectedMatch: false,
+		},
+		{
+			Scenario:      "wrong architecture",
+			Requirements:  metal3api.HardwareRequirements{CPUArchitecture: "aarch64"},
+			ExpectedMatch: false,
+		},
+		{
+			Scenario:      "missing CPU flag",
+			Requirements:  metal3api.HardwareRequirements{CPUFlags: []string{"vmx", "avx512f"}},
+			ExpectedMatch: false,
+		},
+		{
+			Scenario:      "no HDD",
+			Requirements:  metal3api.HardwareRequirements{Disks: []metal3api.DiskRequirement{{Type: metal3api.HDD}}},
+			ExpectedMatch: false,
+		},
+		{
+			Scenario: "NVMe disks too small",
+			Requirements: metal3api.HardwareRequirements{
+				Disks: []metal3api.DiskRequirement{{Type: metal3api.NVME, MinSizeBytes: 4 * metal3api.TeraByte}},
+			},
+			ExpectedMatch: false,
+		},
+		{
+			Scenario: "disks of any type",
+			Requirements: metal3api.HardwareRequirements{
+				Disks: []metal3api.DiskRequirement{{MinCount: 3, MinSizeBytes: 400 * metal3api.GigaByte}},
+			},
+			ExpectedMatch: true,
+		},
+		{
+			Scenario:      "dual-stack NICs counted once",
+			Requirements:  metal3api.HardwareRequirements{NICs: &metal3api.NICRequirement{MinCount: 3, MinSpeedGbps: 25}},
+			ExpectedMatch: false,
+		},
+		{
+			Scenario:      "wrong vendor",
+			Requirements:  metal3api.HardwareRequirements{Vendor: "HPE"},
+			ExpectedMatch: false,
+		},
+		{
+			Scenario:      "wrong product",
+			Requirements:  metal3api.HardwareRequirements{Product: "R750"},
+			ExpectedMatch: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			hw := hardware()
+			if tc.NoHardware {
+				hw = nil
+			}
+			match, reason := Match(&tc.Requirements, hw)
+			assert.Equal(t, tc.ExpectedMatch, match, reason)
+			if !tc.ExpectedMatch {
+				assert.NotEmpty(t, reason)
+			}
+		})
+	}
+}
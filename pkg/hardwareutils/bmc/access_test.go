@@ -1,6 +1,7 @@
 package bmc
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -354,6 +355,58 @@ func TestStaticDriverInfo(t *testing.T) {
 			vendor:     "idrac-redfish",
 		},
 
+		{
+			Scenario:   "ilo4",
+			input:      "ilo4://192.168.122.1",
+			needsMac:   true,
+			driver:     "ilo",
+			bios:       "ilo",
+			boot:       "ilo-ipxe",
+			firmware:   "",
+			management: "ilo",
+			power:      "ilo",
+			vendor:     "ilo",
+		},
+
+		{
+			Scenario:   "ilo4 virtual media",
+			input:      "ilo4-virtualmedia://192.168.122.1",
+			needsMac:   false,
+			driver:     "ilo",
+			bios:       "ilo",
+			boot:       "ilo-virtual-media",
+			firmware:   "",
+			management: "ilo",
+			power:      "ilo",
+			vendor:     "ilo",
+		},
+
+		{
+			Scenario:   "ilo5",
+			input:      "ilo5://192.168.122.1",
+			needsMac:   true,
+			driver:     "ilo5",
+			bios:       "ilo",
+			boot:       "ilo-ipxe",
+			firmware:   "",
+			management: "ilo5",
+			power:      "ilo",
+			vendor:     "ilo",
+		},
+
+		{
+			Scenario:   "ilo5 HTTPS",
+			input:      "ilo5+https://192.168.122.1",
+			needsMac:   true,
+			driver:     "ilo5",
+			bios:       "ilo",
+			boot:       "ilo-ipxe",
+			firmware:   "",
+			management: "ilo5",
+			power:      "ilo",
+			vendor:     "ilo",
+		},
+
 		{
 			Scenario: "ilo5 virtual media",
 			input:    "ilo5-virtualmedia://192.168.122.1",
@@ -384,6 +437,52 @@ func TestStaticDriverInfo(t *testing.T) {
 			firmware: "redfish",
 		},
 
+		{
+			Scenario:   "irmc",
+			input:      "irmc://192.168.122.1",
+			needsMac:   true,
+			driver:     "irmc",
+			bios:       "irmc",
+			boot:       "ipxe",
+			firmware:   "",
+			management: "irmc",
+			power:      "irmc",
+			vendor:     "",
+		},
+
+		{
+			Scenario:   "irmc virtual media",
+			input:      "irmc-virtualmedia://192.168.122.1",
+			needsMac:   false,
+			driver:     "irmc",
+			bios:       "irmc",
+			boot:       "irmc-virtual-media",
+			firmware:   "",
+			management: "irmc",
+			power:      "irmc",
+			vendor:     "",
+		},
+
+		{
+			Scenario: "xcc redfish",
+			input:    "xcc-redfish://192.168.122.1",
+			needsMac: true,
+			driver:   "redfish",
+			bios:     "redfish",
+			boot:     "ipxe",
+			firmware: "redfish",
+		},
+
+		{
+			Scenario: "xcc virtual media HTTP",
+			input:    "xcc-virtualmedia+http://192.168.122.1",
+			needsMac: false,
+			driver:   "redfish",
+			bios:     "redfish",
+			boot:     "redfish-virtual-media",
+			firmware: "redfish",
+		},
+
 		{
 			Scenario:   "idrac virtual media",
 			input:      "idrac-virtualmedia://192.168.122.1",
@@ -603,6 +702,41 @@ func TestDriverInfo(t *testing.T) {
 			},
 		},
 
+		{
+			Scenario: "ilo4",
+			input:    "ilo4://192.168.122.1",
+			expects: map[string]interface{}{
+				"ilo_address":   "192.168.122.1",
+				"ilo_password":  "",
+				"ilo_username":  "",
+				"ilo_verify_ca": false,
+			},
+		},
+
+		{
+			Scenario: "ilo4 virtual media with port",
+			input:    "ilo4-virtualmedia://192.168.122.1:8443",
+			expects: map[string]interface{}{
+				"ilo_address":   "192.168.122.1",
+				"client_port":   "8443",
+				"ilo_password":  "",
+				"ilo_username":  "",
+				"ilo_verify_ca": false,
+			},
+		},
+
+		{
+			Scenario: "ilo5",
+			input:    "ilo5://[fe80::fc33:62ff:fe83:8a76]:443",
+			expects: map[string]interface{}{
+				"ilo_address":   "fe80::fc33:62ff:fe83:8a76",
+				"client_port":   "443",
+				"ilo_password":  "",
+				"ilo_username":  "",
+				"ilo_verify_ca": false,
+			},
+		},
+
 		{
 			Scenario: "ilo5 virtual media",
 			input:    "ilo5-virtualmedia://192.168.122.1/foo/bar",
@@ -615,6 +749,56 @@ func TestDriverInfo(t *testing.T) {
 			},
 		},
 
+		{
+			Scenario: "irmc",
+			input:    "irmc://192.168.122.1",
+			expects: map[string]interface{}{
+				"irmc_address":     "192.168.122.1",
+				"irmc_auth_method": "basic",
+				"irmc_password":    "",
+				"irmc_username":    "",
+				"irmc_verify_ca":   false,
+			},
+		},
+
+		{
+			Scenario: "irmc port and auth method",
+			input:    "irmc://192.168.122.1?port=80&authmethod=digest",
+			expects: map[string]interface{}{
+				"irmc_address":     "192.168.122.1",
+				"irmc_auth_method": "digest",
+				"irmc_port":        "80",
+				"irmc_password":    "",
+				"irmc_username":    "",
+				"irmc_verify_ca":   false,
+			},
+		},
+
+		{
+			Scenario: "irmc virtual media port in host",
+			input:    "irmc-virtualmedia://192.168.122.1:443",
+			expects: map[string]interface{}{
+				"irmc_address":     "192.168.122.1",
+				"irmc_auth_method": "basic",
+				"irmc_port":        "443",
+				"irmc_password":    "",
+				"irmc_username":    "",
+				"irmc_verify_ca":   false,
+			},
+		},
+
+		{
+			Scenario: "xcc redfish",
+			input:    "xcc-redfish://192.168.122.1/redfish/v1/Systems/1",
+			expects: map[string]interface{}{
+				"redfish_address":   "https://192.168.122.1",
+				"redfish_system_id": "/redfish/v1/Systems/1",
+				"redfish_password":  "",
+				"redfish_username":  "",
+				"redfish_verify_ca": false,
+			},
+		},
+
 		{
 			Scenario: "idrac redfish",
 			input:    "idrac-redfish://192.168.122.1/foo/bar",
@@ -672,6 +856,213 @@ func TestDriverInfo(t *testing.T) {
 	}
 }
 
+func TestStaticInterfaces(t *testing.T) {
+	for _, tc := range []struct {
+		Scenario                    string
+		input                       string
+		raid                        string
+		secureBoot                  bool
+		isoPreprovisioningImage     bool
+		requiresProvisioningNetwork bool
+	}{
+		{
+			Scenario:                    "ilo4",
+			input:                       "ilo4://192.168.122.1",
+			raid:                        "no-raid",
+			secureBoot:                  true,
+			requiresProvisioningNetwork: true,
+		},
+
+		{
+			Scenario:                "ilo4 virtual media",
+			input:                   "ilo4-virtualmedia://192.168.122.1",
+			raid:                    "no-raid",
+			secureBoot:              true,
+			isoPreprovisioningImage: true,
+		},
+
+		{
+			Scenario:                    "ilo5",
+			input:                       "ilo5://192.168.122.1",
+			raid:                        "ilo5",
+			secureBoot:                  true,
+			requiresProvisioningNetwork: true,
+		},
+
+		{
+			Scenario:                    "irmc",
+			input:                       "irmc://192.168.122.1",
+			raid:                        "irmc",
+			secureBoot:                  true,
+			requiresProvisioningNetwork: true,
+		},
+
+		{
+			Scenario:                "irmc virtual media",
+			input:                   "irmc-virtualmedia://192.168.122.1",
+			raid:                    "irmc",
+			secureBoot:              true,
+			isoPreprovisioningImage: true,
+		},
+
+		{
+			Scenario:                "xcc virtual media",
+			input:                   "xcc-virtualmedia://192.168.122.1",
+			raid:                    "redfish",
+			secureBoot:              true,
+			isoPreprovisioningImage: true,
+		},
+	} {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			acc, err := NewAccessDetails(tc.input, false)
+			if err != nil {
+				t.Fatalf("unexpected parse error: %v", err)
+			}
+			if acc.RAIDInterface() != tc.raid {
+				t.Fatalf("Unexpected raid interface %q, expected %q", acc.RAIDInterface(), tc.raid)
+			}
+			if acc.SupportsSecureBoot() != tc.secureBoot {
+				t.Fatalf("Secure boot supported: %v, expected %v", acc.SupportsSecureBoot(), tc.secureBoot)
+			}
+			if acc.SupportsISOPreprovisioningImage() != tc.isoPreprovisioningImage {
+				t.Fatalf("ISO preprovisioning image supported: %v, expected %v",
+					acc.SupportsISOPreprovisioningImage(), tc.isoPreprovisioningImage)
+			}
+			if acc.RequiresProvisioningNetwork() != tc.requiresProvisioningNetwork {
+				t.Fatalf("Provisioning network required: %v, expected %v",
+					acc.RequiresProvisioningNetwork(), tc.requiresProvisioningNetwork)
+			}
+		})
+	}
+}
+
+func TestBuildBIOSSettings(t *testing.T) {
+	enable := true
+	disable := false
+	for _, tc := range []struct {
+		Scenario       string
+		input          string
+		firmwareConfig *FirmwareConfig
+		expected       []map[string]string
+		expectError    bool
+	}{
+		{
+			Scenario: "ilo4 no firmware config",
+			input:    "ilo4://192.168.122.1",
+		},
+
+		{
+			Scenario: "ilo4 all settings",
+			input:    "ilo4://192.168.122.1",
+			firmwareConfig: &FirmwareConfig{
+				VirtualizationEnabled:             &enable,
+				SimultaneousMultithreadingEnabled: &disable,
+				SriovEnabled:                      &enable,
+			},
+			expected: []map[string]string{
+				{"name": "ProcVirtualization", "value": "Enabled"},
+				{"name": "ProcHyperthreading", "value": "Disabled"},
+				{"name": "Sriov", "value": "Enabled"},
+			},
+		},
+
+		{
+			Scenario:       "ilo4 virtual media partial settings",
+			input:          "ilo4-virtualmedia://192.168.122.1",
+			firmwareConfig: &FirmwareConfig{SimultaneousMultithreadingEnabled: &enable},
+			expected: []map[string]string{
+				{"name": "ProcHyperthreading", "value": "Enabled"},
+			},
+		},
+
+		{
+			Scenario:       "ilo5 settings",
+			input:          "ilo5://192.168.122.1",
+			firmwareConfig: &FirmwareConfig{VirtualizationEnabled: &disable},
+			expected: []map[string]string{
+				{"name": "ProcVirtualization", "value": "Disabled"},
+			},
+		},
+
+		{
+			Scenario: "irmc all settings",
+			input:    "irmc://192.168.122.1",
+			firmwareConfig: &FirmwareConfig{
+				VirtualizationEnabled:             &enable,
+				SimultaneousMultithreadingEnabled: &disable,
+				SriovEnabled:                      &enable,
+			},
+			expected: []map[string]string{
+				{"name": "cpu_vt_enabled", "value": "True"},
+				{"name": "hyper_threading_enabled", "value": "False"},
+				{"name": "single_root_io_virtualization_support_enabled", "value": "True"},
+			},
+		},
+
+		{
+			Scenario: "xcc all settings",
+			input:    "xcc-redfish://192.168.122.1",
+			firmwareConfig: &FirmwareConfig{
+				VirtualizationEnabled:             &disable,
+				SimultaneousMultithreadingEnabled: &enable,
+				SriovEnabled:                      &disable,
+			},
+			expected: []map[string]string{
+				{"name": "Processors_IntelVirtualizationTechnology", "value": "Disable"},
+				{"name": "Processors_HyperThreading", "value": "Enable"},
+				{"name": "DevicesandIOPorts_SRIOV", "value": "Disable"},
+			},
+		},
+
+		{
+			Scenario: "xcc virtual media no firmware config",
+			input:    "xcc-virtualmedia://192.168.122.1",
+		},
+
+		{
+			Scenario:       "redfish settings are not supported",
+			input:          "redfish://192.168.122.1",
+			firmwareConfig: &FirmwareConfig{VirtualizationEnabled: &enable},
+			expectError:    true,
+		},
+	} {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			acc, err := NewAccessDetails(tc.input, false)
+			if err != nil {
+				t.Fatalf("unexpected parse error: %v", err)
+			}
+			settings, err := acc.BuildBIOSSettings(tc.firmwareConfig)
+			if tc.expectError {
+				if err == nil {
+					t.Fatalf("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(settings, tc.expected) {
+				t.Fatalf("Unexpected settings %v, expected %v", settings, tc.expected)
+			}
+		})
+	}
+}
+
+func TestIRMCInvalidParameters(t *testing.T) {
+	for _, input := range []string{
+		"irmc://192.168.122.1?port=8443",
+		"irmc://192.168.122.1:623",
+		"irmc-virtualmedia://192.168.122.1?authmethod=ntlm",
+	} {
+		t.Run(input, func(t *testing.T) {
+			acc, err := NewAccessDetails(input, false)
+			if err == nil || acc != nil {
+				t.Fatalf("unexpected parse success")
+			}
+		})
+	}
+}
+
 func TestUnknownType(t *testing.T) {
 	acc, err := NewAccessDetails("foo://192.168.122.1", false)
 	if err == nil || acc != nil {
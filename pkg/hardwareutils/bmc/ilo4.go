@@ -0,0 +1,183 @@
+package bmc
+
+import (
+	"net/url"
+)
+
+const (
+	ilo               = "ilo"
+	iloIPXE           = "ilo-ipxe"
+	iloVirtualMedia   = "ilo-virtual-media"
+	iloVirtualization = "ProcVirtualization"
+	iloHyperthread    = "ProcHyperthreading"
+	iloSriov          = "Sriov"
+)
+
+func init() {
+	schemes := []string{"https"}
+	RegisterFactory("ilo4", newILOAccessDetails, schemes)
+	RegisterFactory("ilo4-virtualmedia", newILOVirtualMediaAccessDetails, schemes)
+}
+
+func iLODetails(parsedURL *url.URL, disableCertificateVerification bool) *iLOAccessDetails {
+	return &iLOAccessDetails{
+		bmcType:                        parsedURL.Scheme,
+		portNum:                        parsedURL.Port(),
+		hostname:                       parsedURL.Hostname(),
+		disableCertificateVerification: disableCertificateVerification,
+	}
+}
+
+func newILOAccessDetails(parsedURL *url.URL, disableCertificateVerification bool) (AccessDetails, error) {
+	return iLODetails(parsedURL, disableCertificateVerification), nil
+}
+
+func newILOVirtualMediaAccessDetails(parsedURL *url.URL, disableCertificateVerification bool) (AccessDetails, error) {
+	return &iLOVirtualMediaAccessDetails{
+		*iLODetails(parsedURL, disableCertificateVerification),
+	}, nil
+}
+
+type iLOAccessDetails struct {
+	bmcType                        string
+	portNum                        string
+	hostname                       string
+	disableCertificateVerification bool
+}
+
+type iLOVirtualMediaAccessDetails struct {
+	iLOAccessDetails
+}
+
+func (a *iLOAccessDetails) Type() string {
+	return a.bmcType
+}
+
+// NeedsMAC returns true when the host is going to need a separate
+// port created rather than having it discovered.
+func (a *iLOAccessDetails) NeedsMAC() bool {
+	return true
+}
+
+func (a *iLOAccessDetails) Driver() string {
+	return ilo
+}
+
+func (a *iLOAccessDetails) DisableCertificateVerification() bool {
+	return a.disableCertificateVerification
+}
+
+// DriverInfo returns a data structure to pass as the DriverInfo
+// parameter when creating a node in Ironic. The structure is
+// pre-populated with the access information, and the caller is
+// expected to add any other information that might be needed (such as
+// the kernel and ramdisk locations).
+func (a *iLOAccessDetails) DriverInfo(bmcCreds Credentials) map[string]interface{} {
+	result := map[string]interface{}{
+		"ilo_username": bmcCreds.Username,
+		"ilo_password": bmcCreds.Password,
+		"ilo_address":  a.hostname,
+	}
+
+	if a.disableCertificateVerification {
+		result["ilo_verify_ca"] = false
+	}
+
+	if a.portNum != "" {
+		result["client_port"] = a.portNum
+	}
+
+	return result
+}
+
+func (a *iLOAccessDetails) BIOSInterface() string {
+	return ilo
+}
+
+func (a *iLOAccessDetails) BootInterface() string {
+	return iloIPXE
+}
+
+func (a *iLOAccessDetails) FirmwareInterface() string {
+	return ""
+}
+
+func (a *iLOAccessDetails) ManagementInterface() string {
+	return ilo
+}
+
+func (a *iLOAccessDetails) PowerInterface() string {
+	return ilo
+}
+
+func (a *iLOAccessDetails) RAIDInterface() string {
+	return noRaid
+}
+
+func (a *iLOAccessDetails) VendorInterface() string {
+	return ilo
+}
+
+func (a *iLOAccessDetails) SupportsSecureBoot() bool {
+	return true
+}
+
+func (a *iLOAccessDetails) SupportsISOPreprovisioningImage() bool {
+	return false
+}
+
+func (a *iLOAccessDetails) RequiresProvisioningNetwork() bool {
+	return true
+}
+
+func (a *iLOAccessDetails) BuildBIOSSettings(firmwareConfig *FirmwareConfig) (settings []map[string]string, err error) {
+	return buildILOBIOSSettings(firmwareConfig), nil
+}
+
+// buildILOBIOSSettings translates the firmware configuration into the BIOS
+// attributes of the iLO 4 and iLO 5 BMCs.
+func buildILOBIOSSettings(firmwareConfig *FirmwareConfig) (settings []map[string]string) {
+	if firmwareConfig == nil {
+		return nil
+	}
+
+	for _, setting := range []struct {
+		name   string
+		enable *bool
+	}{
+		{iloVirtualization, firmwareConfig.VirtualizationEnabled},
+		{iloHyperthread, firmwareConfig.SimultaneousMultithreadingEnabled},
+		{iloSriov, firmwareConfig.SriovEnabled},
+	} {
+		if setting.enable == nil {
+			continue
+		}
+		value := disabled
+		if *setting.enable {
+			value = enabled
+		}
+		settings = append(settings, map[string]string{"name": setting.name, "value": value})
+	}
+
+	return settings
+}
+
+// iLO Virtual Media Overrides
+
+// NeedsMAC returns false for virtual media drivers since they can boot
+// from virtual media without requiring a pre-configured boot MAC address.
+func (a *iLOVirtualMediaAccessDetails) NeedsMAC() bool {
+	return false
+}
+
+func (a *iLOVirtualMediaAccessDetails) BootInterface() string {
+	return iloVirtualMedia
+}
+
+func (a *iLOVirtualMediaAccessDetails) SupportsISOPreprovisioningImage() bool {
+	return true
+}
+
+func (a *iLOVirtualMediaAccessDetails) RequiresProvisioningNetwork() bool {
+	return false
+}
@@ -0,0 +1,38 @@
+package bmc
+
+import (
+	"net/url"
+)
+
+const ilo5 = "ilo5"
+
+func init() {
+	schemes := []string{"https"}
+	RegisterFactory(ilo5, newILO5AccessDetails, schemes)
+}
+
+func newILO5AccessDetails(parsedURL *url.URL, disableCertificateVerification bool) (AccessDetails, error) {
+	return &iLO5AccessDetails{
+		*iLODetails(parsedURL, disableCertificateVerification),
+	}, nil
+}
+
+// iLO5AccessDetails uses the native iLO 5 driver. The ilo5-virtualmedia
+// scheme is kept on the Redfish driver, see redfish_virtualmedia.go.
+type iLO5AccessDetails struct {
+	iLOAccessDetails
+}
+
+// iLO 5 Overrides
+
+func (a *iLO5AccessDetails) Driver() string {
+	return ilo5
+}
+
+func (a *iLO5AccessDetails) ManagementInterface() string {
+	return ilo5
+}
+
+func (a *iLO5AccessDetails) RAIDInterface() string {
+	return ilo5
+}
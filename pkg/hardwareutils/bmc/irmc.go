@@ -0,0 +1,219 @@
+package bmc
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+)
+
+const (
+	irmc                = "irmc"
+	irmcVirtualMedia    = "irmc-virtual-media"
+	irmcDefaultAuth     = "basic"
+	irmcVirtualization  = "cpu_vt_enabled"
+	irmcHyperthreading  = "hyper_threading_enabled"
+	irmcSriov           = "single_root_io_virtualization_support_enabled"
+	irmcSettingEnabled  = "True"
+	irmcSettingDisabled = "False"
+)
+
+var (
+	irmcAuthMethods = []string{"basic", "digest"}
+	irmcPorts       = []string{"80", "443"}
+)
+
+func init() {
+	RegisterFactory(irmc, newIRMCAccessDetails, []string{})
+	RegisterFactory("irmc-virtualmedia", newIRMCVirtualMediaAccessDetails, []string{})
+}
+
+// irmcDetails reads the port and the authentication method of the iRMC
+// from the query parameters of the address, e.g.
+// irmc://192.168.122.1?port=80&authmethod=digest. The port may also be
+// given in the host part of the address.
+func irmcDetails(parsedURL *url.URL, disableCertificateVerification bool) (*iRMCAccessDetails, error) {
+	q, err := url.ParseQuery(parsedURL.RawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse iRMC query parameters: %w", err)
+	}
+
+	portNum := parsedURL.Port()
+	if q.Has("port") {
+		portNum = q.Get("port")
+	}
+	if portNum != "" && !slices.Contains(irmcPorts, portNum) {
+		return nil, fmt.Errorf("invalid iRMC port %q, must be one of %v", portNum, irmcPorts)
+	}
+
+	authMethod := irmcDefaultAuth
+	if q.Has("authmethod") {
+		authMethod = q.Get("authmethod")
+	}
+	if !slices.Contains(irmcAuthMethods, authMethod) {
+		return nil, fmt.Errorf("invalid iRMC authentication method %q, must be one of %v", authMethod, irmcAuthMethods)
+	}
+
+	return &iRMCAccessDetails{
+		bmcType:                        parsedURL.Scheme,
+		portNum:                        portNum,
+		hostname:                       parsedURL.Hostname(),
+		authMethod:                     authMethod,
+		disableCertificateVerification: disableCertificateVerification,
+	}, nil
+}
+
+func newIRMCAccessDetails(parsedURL *url.URL, disableCertificateVerification bool) (AccessDetails, error) {
+	details, err := irmcDetails(parsedURL, disableCertificateVerification)
+	if err != nil {
+		return nil, err
+	}
+	return details, nil
+}
+
+func newIRMCVirtualMediaAccessDetails(parsedURL *url.URL, disableCertificateVerification bool) (AccessDetails, error) {
+	details, err := irmcDetails(parsedURL, disableCertificateVerification)
+	if err != nil {
+		return nil, err
+	}
+	return &iRMCVirtualMediaAccessDetails{*details}, nil
+}
+
+type iRMCAccessDetails struct {
+	bmcType                        string
+	portNum                        string
+	hostname                       string
+	authMethod                     string
+	disableCertificateVerification bool
+}
+
+type iRMCVirtualMediaAccessDetails struct {
+	iRMCAccessDetails
+}
+
+func (a *iRMCAccessDetails) Type() string {
+	return a.bmcType
+}
+
+// NeedsMAC returns true when the host is going to need a separate
+// port created rather than having it discovered.
+func (a *iRMCAccessDetails) NeedsMAC() bool {
+	return true
+}
+
+func (a *iRMCAccessDetails) Driver() string {
+	return irmc
+}
+
+func (a *iRMCAccessDetails) DisableCertificateVerification() bool {
+	return a.disableCertificateVerification
+}
+
+// DriverInfo returns a data structure to pass as the DriverInfo
+// parameter when creating a node in Ironic. The structure is
+// pre-populated with the access information, and the caller is
+// expected to add any other information that might be needed (such as
+// the kernel and ramdisk locations).
+func (a *iRMCAccessDetails) DriverInfo(bmcCreds Credentials) map[string]interface{} {
+	result := map[string]interface{}{
+		"irmc_username":    bmcCreds.Username,
+		"irmc_password":    bmcCreds.Password,
+		"irmc_address":     a.hostname,
+		"irmc_auth_method": a.authMethod,
+	}
+
+	if a.disableCertificateVerification {
+		result["irmc_verify_ca"] = false
+	}
+
+	if a.portNum != "" {
+		result["irmc_port"] = a.portNum
+	}
+
+	return result
+}
+
+func (a *iRMCAccessDetails) BIOSInterface() string {
+	return irmc
+}
+
+func (a *iRMCAccessDetails) BootInterface() string {
+	return ipxe
+}
+
+func (a *iRMCAccessDetails) FirmwareInterface() string {
+	return ""
+}
+
+func (a *iRMCAccessDetails) ManagementInterface() string {
+	return irmc
+}
+
+func (a *iRMCAccessDetails) PowerInterface() string {
+	return irmc
+}
+
+func (a *iRMCAccessDetails) RAIDInterface() string {
+	return irmc
+}
+
+func (a *iRMCAccessDetails) VendorInterface() string {
+	return ""
+}
+
+func (a *iRMCAccessDetails) SupportsSecureBoot() bool {
+	return true
+}
+
+func (a *iRMCAccessDetails) SupportsISOPreprovisioningImage() bool {
+	return false
+}
+
+func (a *iRMCAccessDetails) RequiresProvisioningNetwork() bool {
+	return true
+}
+
+func (a *iRMCAccessDetails) BuildBIOSSettings(firmwareConfig *FirmwareConfig) (settings []map[string]string, err error) {
+	if firmwareConfig == nil {
+		return nil, nil
+	}
+
+	for _, setting := range []struct {
+		name   string
+		enable *bool
+	}{
+		{irmcVirtualization, firmwareConfig.VirtualizationEnabled},
+		{irmcHyperthreading, firmwareConfig.SimultaneousMultithreadingEnabled},
+		{irmcSriov, firmwareConfig.SriovEnabled},
+	} {
+		if setting.enable == nil {
+			continue
+		}
+		value := irmcSettingDisabled
+		if *setting.enable {
+			value = irmcSettingEnabled
+		}
+		settings = append(settings, map[string]string{"name": setting.name, "value": value})
+	}
+
+	return settings, nil
+}
+
+// iRMC Virtual Media Overrides
+
+// NeedsMAC returns false for virtual media drivers since they can boot
+// from virtual media without requiring a pre-configured boot MAC address.
+func (a *iRMCVirtualMediaAccessDetails) NeedsMAC() bool {
+	return false
+}
+
+func (a *iRMCVirtualMediaAccessDetails) BootInterface() string {
+	return irmcVirtualMedia
+}
+
+func (a *iRMCVirtualMediaAccessDetails) SupportsISOPreprovisioningImage() bool {
+	return true
+}
+
+func (a *iRMCVirtualMediaAccessDetails) RequiresProvisioningNetwork() bool {
+	return false
+}
@@ -0,0 +1,95 @@
+package bmc
+
+import (
+	"net/url"
+)
+
+const (
+	xccVirtualization = "Processors_IntelVirtualizationTechnology"
+	xccHyperthreading = "Processors_HyperThreading"
+	xccSriov          = "DevicesandIOPorts_SRIOV"
+	xccEnabled        = "Enable"
+	xccDisabled       = "Disable"
+)
+
+func init() {
+	schemes := []string{"http", "https"}
+	RegisterFactory("xcc-redfish", newXCCAccessDetails, schemes)
+	RegisterFactory("xcc-virtualmedia", newXCCVirtualMediaAccessDetails, schemes)
+}
+
+func newXCCAccessDetails(parsedURL *url.URL, disableCertificateVerification bool) (AccessDetails, error) {
+	return &redfishXCCAccessDetails{
+		*redfishDetails(parsedURL, disableCertificateVerification),
+	}, nil
+}
+
+func newXCCVirtualMediaAccessDetails(parsedURL *url.URL, disableCertificateVerification bool) (AccessDetails, error) {
+	return &redfishXCCVirtualMediaAccessDetails{
+		redfishXCCAccessDetails{
+			*redfishDetails(parsedURL, disableCertificateVerification),
+		},
+	}, nil
+}
+
+// redfishXCCAccessDetails uses the Redfish driver with the BIOS attributes
+// of the Lenovo XClarity Controller.
+type redfishXCCAccessDetails struct {
+	redfishAccessDetails
+}
+
+type redfishXCCVirtualMediaAccessDetails struct {
+	redfishXCCAccessDetails
+}
+
+// XCC Redfish Overrides
+
+func (a *redfishXCCAccessDetails) BIOSInterface() string {
+	return redfish
+}
+
+func (a *redfishXCCAccessDetails) BuildBIOSSettings(firmwareConfig *FirmwareConfig) (settings []map[string]string, err error) {
+	if firmwareConfig == nil {
+		return nil, nil
+	}
+
+	for _, setting := range []struct {
+		name   string
+		enable *bool
+	}{
+		{xccVirtualization, firmwareConfig.VirtualizationEnabled},
+		{xccHyperthreading, firmwareConfig.SimultaneousMultithreadingEnabled},
+		{xccSriov, firmwareConfig.SriovEnabled},
+	} {
+		if setting.enable == nil {
+			continue
+		}
+		value := xccDisabled
+		if *setting.enable {
+			value = xccEnabled
+		}
+		settings = append(settings, map[string]string{"name": setting.name, "value": value})
+	}
+
+	return settings, nil
+}
+
+// XCC Virtual Media Overrides
+
+// NeedsMAC returns false for virtual media drivers since they can boot
+// from virtual media without requiring a pre-configured boot MAC address.
+func (a *redfishXCCVirtualMediaAccessDetails) NeedsMAC() bool {
+	return false
+}
+
+func (a *redfishXCCVirtualMediaAccessDetails) BootInterface() string {
+	return "redfish-virtual-media"
+}
+
+func (a *redfishXCCVirtualMediaAccessDetails) SupportsISOPreprovisioningImage() bool {
+	return true
+}
+
+func (a *redfishXCCVirtualMediaAccessDetails) RequiresProvisioningNetwork() bool {
+	return false
+}
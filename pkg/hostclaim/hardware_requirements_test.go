@@ -0,0 +1,57 @@
+/*
+Copyright 2025 The Metal3 Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostclaim
+
+import (
+	"context"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	. "github.com/metal3-io/baremetal-operator/internal/testutil"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Hardware requirements", func() {
+	It("Only chooses hosts matching the hardware requirements", func() {
+		small := NewBaremetalhost("small", "ns", metal3api.StateAvailable).Build()
+		big := NewBaremetalhost("big", "ns", metal3api.StateAvailable).Build()
+		uninspected := NewBaremetalhost("uninspected", "ns", metal3api.StateAvailable).Build()
+		smallHW := NewHardwareData(small).Build()
+		smallHW.Spec.HardwareDetails.RAMMebibytes = 64 * 1024
+		bigHW := NewHardwareData(big).Build()
+		bigHW.Spec.HardwareDetails.RAMMebibytes = 512 * 1024
+		claim := NewHostclaim(HostclaimName).Build()
+		claim.Spec.HostSelector.Hardware = &metal3api.HardwareRequirements{MinRAMMebibytes: 256 * 1024}
+		objects := []client.Object{
+			claim, small, big, uninspected, smallHW, bigHW,
+			NewHostdeploypolicy("hdp", "ns").AcceptNames([]string{HostclaimNamespace}).Build(),
+			NewNamespace(HostclaimNamespace).Build(), NewNamespace("ns").Build(),
+		}
+		fakeClient := fake.NewClientBuilder().WithScheme(setupScheme()).WithObjects(objects...).Build()
+		hostMgr, err := NewHostManager(fakeClient, GinkgoLogr, claim, fakeClient)
+		Expect(err).NotTo(HaveOccurred())
+		bmh, err := hostMgr.chooseBMH(context.TODO())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bmh.Name).To(Equal("big"))
+
+		claim.Spec.HostSelector.Hardware.MinRAMMebibytes = 1024 * 1024
+		_, err = hostMgr.chooseBMH(context.TODO())
+		Expect(err).To(MatchError(ErrNoAvailableBMH))
+	})
+})
@@ -0,0 +1,309 @@
+/*
+Copyright 2025 The Metal3 Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostclaim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// errHostTaken is used when the chosen host got another consumer since it was
+// read from the cache.
+var errHostTaken = errors.New("host was reserved concurrently")
+
+// groupState is shared by the members of a HostClaimSet while hosts are
+// reserved for all of them.
+type groupState struct {
+	spread metal3api.FailureDomainSpread
+	// domains counts the members bound in each failure domain.
+	domains map[string]int
+	// reserved contains the hosts bound to the members, including the ones
+	// reserved during the current pass. The cache may not reflect their
+	// consumer yet.
+	reserved Set[client.ObjectKey]
+}
+
+// InGroup checks if the HostClaim belongs to a HostClaimSet.
+func (m *HostManager) InGroup() bool {
+	return m.HostClaim.Labels[metal3api.HostClaimSetLabel] != ""
+}
+
+// AssociateGroup reserves hosts for all the unbound members of the
+// HostClaimSet of the HostClaim at once. If a host cannot be found for one
+// of the members, the hosts already reserved are released and a
+// requeueAfterError is returned.
+func (m *HostManager) AssociateGroup(ctx context.Context) error {
+	setName := m.HostClaim.Labels[metal3api.HostClaimSetLabel]
+	log := m.Log.WithValues("hostclaimset", setName)
+
+	set := &metal3api.HostClaimSet{}
+	err := m.client.Get(ctx, client.ObjectKey{Namespace: m.HostClaim.Namespace, Name: setName}, set)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			m.SetConditionHostToFalse(metal3api.AssociatedCondition, metal3api.MissingHostClaimSetReason,
+				fmt.Sprintf("HostClaimSet %s not found: requeuing.", setName))
+			return RequeueAfterError{RequeueAfter: HostClaimRequeueDelay}
+		}
+		return err
+	}
+
+	members, err := m.groupMembers(ctx, setName)
+	if err != nil {
+		return err
+	}
+	if len(members) < set.Spec.MinMembers {
+		log.Info("Waiting for the members of the HostClaimSet", "members", len(members), "minMembers", set.Spec.MinMembers)
+		message := fmt.Sprintf("HostClaimSet %s has %d members out of %d: requeuing.", setName, len(members), set.Spec.MinMembers)
+		m.SetConditionHostToFalse(metal3api.AssociatedCondition, metal3api.WaitingForHostClaimSetReason, message)
+		m.updateSetStatus(ctx, set, len(members), 0, metav1.ConditionFalse, metal3api.WaitingForHostClaimSetReason, message)
+		return RequeueAfterError{RequeueAfter: HostClaimRequeueDelay}
+	}
+
+	bound, err := m.boundHosts(ctx, members)
+	if err != nil {
+		return err
+	}
+	group := &groupState{
+		spread:   set.Spec.FailureDomainSpread,
+		domains:  map[string]int{},
+		reserved: NewSet[client.ObjectKey](),
+	}
+	for _, bmh := range bound {
+		AddSet(group.reserved, client.ObjectKeyFromObject(bmh))
+		group.domains[bmh.Labels[FailureDomainLabelName]]++
+	}
+
+	reserved := []*metal3api.BareMetalHost{}
+	for _, member := range members {
+		if _, ok := bound[member.Name]; ok {
+			continue
+		}
+		memberMgr, _ := NewHostManager(m.client, m.Log.WithValues("member", member.Name), member, m.APIReader)
+		memberMgr.group = group
+		bmh, err := memberMgr.chooseBMH(ctx)
+		if err == nil {
+			bmh, err = m.reserveHost(ctx, bmh, member)
+		}
+		if err != nil {
+			log.Info("Cannot reserve a host for a member of the HostClaimSet, releasing the reserved hosts",
+				"member", member.Name, "error", err.Error())
+			if rollbackErr := m.releaseHosts(ctx, reserved); rollbackErr != nil {
+				return kerrors.NewAggregate([]error{err, rollbackErr})
+			}
+			message := fmt.Sprintf("Cannot reserve a host for member %s of HostClaimSet %s: %s", member.Name, setName, err)
+			m.SetConditionHostToFalse(metal3api.AssociatedCondition, metal3api.HostClaimSetNotSatisfiableReason, message)
+			m.updateSetStatus(ctx, set, len(members), len(bound)-len(reserved), metav1.ConditionFalse,
+				metal3api.HostClaimSetNotSatisfiableReason, message)
+			if errors.Is(err, ErrNoAvailableBMH) || errors.Is(err, ErrQuotaExceeded) {
+				return RequeueAfterError{RequeueAfter: HostClaimRequeueDelay}
+			}
+			if errors.Is(err, errHostTaken) || k8serrors.IsConflict(err) {
+				return RequeueAfterError{RequeueAfter: wait.Jitter(ConflictRequeueDelay, ConflictJitterFactor)}
+			}
+			return hideConflictError(err)
+		}
+		log.Info("Reserved host for a member of the HostClaimSet", "member", member.Name,
+			"bmh", bmh.Name, "bmhNamespace", bmh.Namespace)
+		reserved = append(reserved, bmh)
+		bound[member.Name] = bmh
+		AddSet(group.reserved, client.ObjectKeyFromObject(bmh))
+		group.domains[bmh.Labels[FailureDomainLabelName]]++
+	}
+
+	bmh := bound[m.HostClaim.Name]
+	m.HostClaim.Status.BareMetalHost = &metal3api.ObjectReference{Namespace: bmh.Namespace, Name: bmh.Name}
+	m.SetConditionHostToTrue(metal3api.AssociatedCondition, metal3api.BareMetalHostAssociatedReason)
+	m.updateSetStatus(ctx, set, len(members), len(bound), metav1.ConditionTrue, metal3api.HostClaimSetBoundReason,
+		fmt.Sprintf("The %d members are bound", len(members)))
+	return nil
+}
+
+// updateSetStatus records the members of the HostClaimSet and why they are
+// bound or not. The members update the status of their set concurrently, so
+// a failure is only logged: the next pass records it again.
+func (m *HostManager) updateSetStatus(
+	ctx context.Context, set *metal3api.HostClaimSet, members, boundMembers int,
+	status metav1.ConditionStatus, reason, message string,
+) {
+	patch := client.MergeFrom(set.DeepCopy())
+	set.Status.Members = members
+	set.Status.BoundMembers = boundMembers
+	meta.SetStatusCondition(&set.Status.Conditions, metav1.Condition{
+		Type:               metal3api.HostClaimSetBoundCondition,
+		Status:             status,
+		ObservedGeneration: set.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+	if err := m.client.Status().Patch(ctx, set, patch); err != nil {
+		m.Log.Info("Cannot update the status of the HostClaimSet", "hostclaimset", set.Name, "error", err.Error())
+	}
+}
+
+// groupMembers lists the HostClaims of the HostClaimSet that are not being
+// deleted, sorted by name. The current HostClaim is always part of the list.
+func (m *HostManager) groupMembers(ctx context.Context, setName string) ([]*metal3api.HostClaim, error) {
+	claims := metal3api.HostClaimList{}
+	err := m.client.List(ctx, &claims, client.InNamespace(m.HostClaim.Namespace),
+		client.MatchingLabels{metal3api.HostClaimSetLabel: setName})
+	if err != nil {
+		return nil, err
+	}
+	members := []*metal3api.HostClaim{m.HostClaim}
+	for i := range claims.Items {
+		claim := &claims.Items[i]
+		if claim.Name == m.HostClaim.Name || !claim.DeletionTimestamp.IsZero() {
+			continue
+		}
+		members = append(members, claim)
+	}
+	slices.SortFunc(members, func(a, b *metal3api.HostClaim) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return members, nil
+}
+
+// reserveHost sets the member as the consumer of the chosen host. The host
+// is read again without the cache, so that a host reserved by a concurrent
+// pass is not reserved twice.
+func (m *HostManager) reserveHost(
+	ctx context.Context, chosen *metal3api.BareMetalHost, member *metal3api.HostClaim,
+) (*metal3api.BareMetalHost, error) {
+	bmh := &metal3api.BareMetalHost{}
+	if err := m.APIReader.Get(ctx, client.ObjectKeyFromObject(chosen), bmh); err != nil {
+		return nil, err
+	}
+	if !bmh.DeletionTimestamp.IsZero() ||
+		(bmh.Spec.ConsumerRef != nil && !consumerRefMatches(bmh.Spec.ConsumerRef, member)) {
+		return nil, fmt.Errorf("%w: %s/%s", errHostTaken, bmh.Namespace, bmh.Name)
+	}
+	bmh.Spec.ConsumerRef = hostClaimConsumerRef(member)
+	if err := m.client.Update(ctx, bmh); err != nil {
+		return nil, err
+	}
+	return bmh, nil
+}
+
+// boundHosts returns the BareMetalHosts already consumed by the members,
+// indexed by the name of the member. The hosts are listed without the cache
+// as the hosts reserved by a previous pass may not be visible in it yet, but
+// only in the namespaces the members can be bound into.
+func (m *HostManager) boundHosts(ctx context.Context, members []*metal3api.HostClaim) (map[string]*metal3api.BareMetalHost, error) {
+	namespaces := NewSet[string]()
+	for _, inNamespace := range uniqueInNamespaces(members) {
+		accepted, err := m.acceptableNamespaces(ctx, inNamespace)
+		if err != nil {
+			return nil, err
+		}
+		for namespace := range accepted {
+			AddSet(namespaces, namespace)
+		}
+	}
+
+	bound := map[string]*metal3api.BareMetalHost{}
+	for namespace := range namespaces {
+		hosts := metal3api.BareMetalHostList{}
+		if err := m.APIReader.List(ctx, &hosts, client.InNamespace(namespace)); err != nil {
+			return nil, err
+		}
+		for i := range hosts.Items {
+			consumer := hosts.Items[i].Spec.ConsumerRef
+			if consumer == nil {
+				continue
+			}
+			for _, member := range members {
+				if consumerRefMatches(consumer, member) {
+					bound[member.Name] = &hosts.Items[i]
+				}
+			}
+		}
+	}
+	return bound, nil
+}
+
+// uniqueInNamespaces returns the distinct namespaces the members restrict
+// their hosts to. An empty namespace means any namespace accepting them.
+func uniqueInNamespaces(members []*metal3api.HostClaim) []string {
+	inNamespaces := []string{}
+	for _, member := range members {
+		if !slices.Contains(inNamespaces, member.Spec.HostSelector.InNamespace) {
+			inNamespaces = append(inNamespaces, member.Spec.HostSelector.InNamespace)
+		}
+	}
+	return inNamespaces
+}
+
+// releaseHosts removes the consumer of the hosts reserved during a pass that
+// could not be completed.
+func (m *HostManager) releaseHosts(ctx context.Context, hosts []*metal3api.BareMetalHost) error {
+	var errs []error
+	for _, bmh := range hosts {
+		bmh.Spec.ConsumerRef = nil
+		if err := m.client.Update(ctx, bmh); err != nil && !k8serrors.IsNotFound(err) {
+			errs = append(errs, err)
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// hostClaimConsumerRef is the consumer reference set on a BareMetalHost bound
+// to the HostClaim.
+func hostClaimConsumerRef(claim *metal3api.HostClaim) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		Kind:       HostClaimKind,
+		Name:       claim.Name,
+		Namespace:  claim.Namespace,
+		APIVersion: metal3api.GroupVersion.Identifier(),
+	}
+}
+
+// spreadHosts keeps the hosts allowed by the failure domain spread of the
+// group.
+func (g *groupState) spreadHosts(hosts []*metal3api.BareMetalHost) []*metal3api.BareMetalHost {
+	switch g.spread {
+	case metal3api.FailureDomainSpreadRequired:
+		return slices.DeleteFunc(slices.Clone(hosts), func(host *metal3api.BareMetalHost) bool {
+			domain := host.Labels[FailureDomainLabelName]
+			return domain == "" || g.domains[domain] > 0
+		})
+	case metal3api.FailureDomainSpreadPreferred:
+		if len(hosts) == 0 {
+			return hosts
+		}
+		lowest := g.domains[hosts[0].Labels[FailureDomainLabelName]]
+		for _, host := range hosts {
+			lowest = min(lowest, g.domains[host.Labels[FailureDomainLabelName]])
+		}
+		return slices.DeleteFunc(slices.Clone(hosts), func(host *metal3api.BareMetalHost) bool {
+			return g.domains[host.Labels[FailureDomainLabelName]] > lowest
+		})
+	default:
+		return hosts
+	}
+}
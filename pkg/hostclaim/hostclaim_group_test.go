@@ -0,0 +1,278 @@
+/*
+Copyright 2025 The Metal3 Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostclaim
+
+import (
+	"context"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	. "github.com/metal3-io/baremetal-operator/internal/testutil"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("HostClaimSet", func() {
+	const setName = "control-plane"
+
+	member := func(name string) *metal3api.HostClaim {
+		return NewHostclaim(name).SetLabels(map[string]string{metal3api.HostClaimSetLabel: setName}).Build()
+	}
+
+	host := func(name, zone string) *metal3api.BareMetalHost {
+		return NewBaremetalhost(name, "ns", metal3api.StateAvailable).
+			SetLabels(map[string]string{FailureDomainLabelName: zone}).Build()
+	}
+
+	type testCaseGroup struct {
+		MinMembers     int
+		Spread         metal3api.FailureDomainSpread
+		NoSet          bool
+		Members        []string
+		Hosts          []*metal3api.BareMetalHost
+		ExpectedReason string
+		// ExpectedZones is the list of failure domains of the hosts bound to
+		// the members, in the order of the members. Nil when nothing must be
+		// bound.
+		ExpectedZones []string
+	}
+
+	DescribeTable("Test AssociateGroup",
+		func(tc testCaseGroup) {
+			objects := []client.Object{
+				NewHostdeploypolicy("hdp", "ns").AcceptNames([]string{HostclaimNamespace}).Build(),
+				NewNamespace(HostclaimNamespace).Build(), NewNamespace("ns").Build(),
+			}
+			if !tc.NoSet {
+				objects = append(objects, &metal3api.HostClaimSet{
+					ObjectMeta: metav1.ObjectMeta{Name: setName, Namespace: HostclaimNamespace},
+					Spec:       metal3api.HostClaimSetSpec{MinMembers: tc.MinMembers, FailureDomainSpread: tc.Spread},
+				})
+			}
+			claims := []*metal3api.HostClaim{}
+			for _, name := range tc.Members {
+				claim := member(name)
+				claims = append(claims, claim)
+				objects = append(objects, claim)
+			}
+			for _, bmh := range tc.Hosts {
+				objects = append(objects, bmh)
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(setupScheme()).WithObjects(objects...).
+				WithStatusSubresource(&metal3api.HostClaimSet{}).Build()
+			hostMgr, err := NewHostManager(fakeClient, GinkgoLogr, claims[0], fakeClient)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hostMgr.InGroup()).To(BeTrue())
+
+			err = hostMgr.AssociateGroup(context.TODO())
+			condition := conditions.Get(claims[0], metal3api.AssociatedCondition)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal(tc.ExpectedReason))
+
+			if !tc.NoSet {
+				set := &metal3api.HostClaimSet{}
+				Expect(fakeClient.Get(context.TODO(), client.ObjectKey{Namespace: HostclaimNamespace, Name: setName}, set)).To(Succeed())
+				Expect(set.Status.Members).To(Equal(len(tc.Members)))
+				bound := meta.FindStatusCondition(set.Status.Conditions, metal3api.HostClaimSetBoundCondition)
+				Expect(bound).NotTo(BeNil())
+				if tc.ExpectedZones == nil {
+					Expect(bound.Status).To(Equal(metav1.ConditionFalse))
+					Expect(bound.Reason).To(Equal(tc.ExpectedReason))
+					Expect(set.Status.BoundMembers).To(BeZero())
+				} else {
+					Expect(bound.Status).To(Equal(metav1.ConditionTrue))
+					Expect(set.Status.BoundMembers).To(Equal(len(tc.Members)))
+				}
+			}
+
+			hosts := metal3api.BareMetalHostList{}
+			Expect(fakeClient.List(context.TODO(), &hosts)).To(Succeed())
+			if tc.ExpectedZones == nil {
+				ok, _ := IsRequeueAfterError(err)
+				Expect(ok).To(BeTrue())
+				for _, bmh := range hosts.Items {
+					Expect(bmh.Spec.ConsumerRef).To(BeNil(), "host %s should not be reserved", bmh.Name)
+				}
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(claims[0].Status.BareMetalHost).NotTo(BeNil())
+			zones := []string{}
+			for _, claim := range claims {
+				for _, bmh := range hosts.Items {
+					if bmh.Spec.ConsumerRef != nil && consumerRefMatches(bmh.Spec.ConsumerRef, claim) {
+						zones = append(zones, bmh.Labels[FailureDomainLabelName])
+					}
+				}
+			}
+			Expect(zones).To(ConsistOf(tc.ExpectedZones))
+		},
+		Entry("missing set", testCaseGroup{
+			NoSet:          true,
+			Members:        []string{"cp-0"},
+			Hosts:          []*metal3api.BareMetalHost{host("h1", "a")},
+			ExpectedReason: metal3api.MissingHostClaimSetReason,
+		}),
+		Entry("waiting for members", testCaseGroup{
+			MinMembers:     3,
+			Members:        []string{"cp-0", "cp-1"},
+			Hosts:          []*metal3api.BareMetalHost{host("h1", "a"), host("h2", "b"), host("h3", "c")},
+			ExpectedReason: metal3api.WaitingForHostClaimSetReason,
+		}),
+		Entry("all members bound in distinct failure domains", testCaseGroup{
+			MinMembers:     3,
+			Spread:         metal3api.FailureDomainSpreadRequired,
+			Members:        []string{"cp-0", "cp-1", "cp-2"},
+			Hosts:          []*metal3api.BareMetalHost{host("h1", "a"), host("h2", "a"), host("h3", "b"), host("h4", "c")},
+			ExpectedReason: metal3api.BareMetalHostAssociatedReason,
+			ExpectedZones:  []string{"a", "b", "c"},
+		}),
+		Entry("not enough failure domains rolls back", testCaseGroup{
+			MinMembers:     3,
+			Spread:         metal3api.FailureDomainSpreadRequired,
+			Members:        []string{"cp-0", "cp-1", "cp-2"},
+			Hosts:          []*metal3api.BareMetalHost{host("h1", "a"), host("h2", "a"), host("h3", "b")},
+			ExpectedReason: metal3api.HostClaimSetNotSatisfiableReason,
+		}),
+		Entry("not enough hosts rolls back", testCaseGroup{
+			MinMembers:     3,
+			Members:        []string{"cp-0", "cp-1", "cp-2"},
+			Hosts:          []*metal3api.BareMetalHost{host("h1", "a"), host("h2", "b")},
+			ExpectedReason: metal3api.HostClaimSetNotSatisfiableReason,
+		}),
+		Entry("preferred spread uses all the failure domains", testCaseGroup{
+			MinMembers:     3,
+			Spread:         metal3api.FailureDomainSpreadPreferred,
+			Members:        []string{"cp-0", "cp-1", "cp-2"},
+			Hosts:          []*metal3api.BareMetalHost{host("h1", "a"), host("h2", "a"), host("h3", "a"), host("h4", "b")},
+			ExpectedReason: metal3api.BareMetalHostAssociatedReason,
+			ExpectedZones:  []string{"a", "a", "b"},
+		}),
+	)
+
+	It("Keeps the hosts of the members already bound", func() {
+		bound := host("h1", "a")
+		bound.Spec.ConsumerRef = hostClaimConsumerRef(member("cp-1"))
+		claim := member("cp-0")
+		objects := []client.Object{
+			claim, member("cp-1"), bound, host("h2", "a"), host("h3", "b"),
+			&metal3api.HostClaimSet{
+				ObjectMeta: metav1.ObjectMeta{Name: setName, Namespace: HostclaimNamespace},
+				Spec: metal3api.HostClaimSetSpec{
+					MinMembers: 2, FailureDomainSpread: metal3api.FailureDomainSpreadRequired,
+				},
+			},
+			NewHostdeploypolicy("hdp", "ns").AcceptNames([]string{HostclaimNamespace}).Build(),
+			NewNamespace(HostclaimNamespace).Build(), NewNamespace("ns").Build(),
+		}
+		fakeClient := fake.NewClientBuilder().WithScheme(setupScheme()).WithObjects(objects...).Build()
+		hostMgr, err := NewHostManager(fakeClient, GinkgoLogr, claim, fakeClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hostMgr.AssociateGroup(context.TODO())).To(Succeed())
+		Expect(claim.Status.BareMetalHost).To(Equal(&metal3api.ObjectReference{Namespace: "ns", Name: "h3"}))
+	})
+
+	Context("With a stale cache", func() {
+		set := func() *metal3api.HostClaimSet {
+			return &metal3api.HostClaimSet{
+				ObjectMeta: metav1.ObjectMeta{Name: setName, Namespace: HostclaimNamespace},
+				Spec:       metal3api.HostClaimSetSpec{MinMembers: 2},
+			}
+		}
+		common := func() []client.Object {
+			return []client.Object{
+				set(),
+				NewHostdeploypolicy("hdp", "ns").AcceptNames([]string{HostclaimNamespace}).Build(),
+				NewNamespace(HostclaimNamespace).Build(), NewNamespace("ns").Build(),
+			}
+		}
+
+		It("Does not reserve a host taken by another claim", func() {
+			claim := member("cp-0")
+			// The cache does not show that h1 was taken by another claim.
+			cached := fake.NewClientBuilder().WithScheme(setupScheme()).
+				WithObjects(append(common(), claim, member("cp-1"), host("h1", "a"), host("h2", "b"))...).Build()
+			taken := host("h1", "a")
+			taken.Spec.ConsumerRef = hostClaimConsumerRef(NewHostclaim("other").Build())
+			taken2 := host("h2", "b")
+			taken2.Spec.ConsumerRef = hostClaimConsumerRef(NewHostclaim("other2").Build())
+			apiReader := fake.NewClientBuilder().WithScheme(setupScheme()).
+				WithObjects(append(common(), claim, member("cp-1"), taken, taken2)...).Build()
+
+			hostMgr, err := NewHostManager(cached, GinkgoLogr, claim, apiReader)
+			Expect(err).NotTo(HaveOccurred())
+			err = hostMgr.AssociateGroup(context.TODO())
+			ok, _ := IsRequeueAfterError(err)
+			Expect(ok).To(BeTrue())
+
+			hosts := metal3api.BareMetalHostList{}
+			Expect(cached.List(context.TODO(), &hosts)).To(Succeed())
+			for _, bmh := range hosts.Items {
+				Expect(bmh.Spec.ConsumerRef).To(BeNil(), "host %s should not be reserved", bmh.Name)
+			}
+		})
+
+		It("Does not reserve a second host for a member bound by a previous pass", func() {
+			claim := member("cp-0")
+			// The cache does not show the host reserved for cp-1.
+			cached := fake.NewClientBuilder().WithScheme(setupScheme()).
+				WithObjects(append(common(), claim, member("cp-1"), host("h1", "a"), host("h2", "b"), host("h3", "c"))...).Build()
+			bound := host("h1", "a")
+			bound.Spec.ConsumerRef = hostClaimConsumerRef(member("cp-1"))
+			apiReader := fake.NewClientBuilder().WithScheme(setupScheme()).
+				WithObjects(append(common(), claim, member("cp-1"), bound, host("h2", "b"), host("h3", "c"))...).Build()
+
+			hostMgr, err := NewHostManager(cached, GinkgoLogr, claim, apiReader)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hostMgr.AssociateGroup(context.TODO())).To(Succeed())
+
+			hosts := metal3api.BareMetalHostList{}
+			Expect(cached.List(context.TODO(), &hosts)).To(Succeed())
+			reserved := 0
+			for _, bmh := range hosts.Items {
+				if bmh.Spec.ConsumerRef != nil {
+					Expect(bmh.Spec.ConsumerRef.Name).To(Equal("cp-0"))
+					reserved++
+				}
+			}
+			Expect(reserved).To(Equal(1))
+		})
+
+		It("Does not choose the host of a member bound by a previous pass", func() {
+			claim := member("cp-0")
+			// h1 is bound to cp-1 but free in the cache, and scores better
+			// than h2 as its failure domain is not used yet.
+			used := host("h0", "a")
+			used.Spec.ConsumerRef = hostClaimConsumerRef(NewHostclaim("other").Build())
+			cached := fake.NewClientBuilder().WithScheme(setupScheme()).
+				WithObjects(append(common(), claim, member("cp-1"), used, host("h1", "b"), host("h2", "a"))...).Build()
+			bound := host("h1", "b")
+			bound.Spec.ConsumerRef = hostClaimConsumerRef(member("cp-1"))
+			apiReader := fake.NewClientBuilder().WithScheme(setupScheme()).
+				WithObjects(append(common(), claim, member("cp-1"), used.DeepCopy(), bound, host("h2", "a"))...).Build()
+
+			hostMgr, err := NewHostManager(cached, GinkgoLogr, claim, apiReader)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hostMgr.AssociateGroup(context.TODO())).To(Succeed())
+			Expect(claim.Status.BareMetalHost).To(Equal(&metal3api.ObjectReference{Namespace: "ns", Name: "h2"}))
+		})
+	})
+})
@@ -18,9 +18,9 @@ package hostclaim
 
 import (
 	"context"
-	"crypto/rand"
 	"errors"
-	"math/big"
+	"fmt"
+	"maps"
 	"regexp"
 	"slices"
 	"strings"
@@ -28,6 +28,7 @@ import (
 
 	"github.com/go-logr/logr"
 	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/hardwarematch"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -36,7 +37,9 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 type HostManager struct {
@@ -44,6 +47,9 @@ type HostManager struct {
 	HostClaim *metal3api.HostClaim
 	Log       logr.Logger
 	APIReader client.Reader
+	// group is set while hosts are reserved for all the members of a
+	// HostClaimSet.
+	group *groupState
 }
 
 const (
@@ -62,8 +68,23 @@ const (
 	FailureDomainLabelName = "infrastructure.cluster.x-k8s.io/failure-domain"
 	// HostClaimKind is the name of the kind.
 	HostClaimKind = "HostClaim"
+	// HostClaimNameLabel is set on the secrets copied in the namespace of the
+	// BareMetalHost and contains the name of the HostClaim.
+	HostClaimNameLabel = "hostclaim.metal3.io/name"
+	// HostClaimNamespaceLabel is set on the secrets copied in the namespace of the
+	// BareMetalHost and contains the namespace of the HostClaim.
+	HostClaimNamespaceLabel = "hostclaim.metal3.io/namespace"
 )
 
+// mirroredConditions are the conditions of the BareMetalHost that are copied
+// on the HostClaim so that the user can follow the progress of provisioning.
+var mirroredConditions = []string{
+	metal3api.ProvisionedCondition,
+	metal3api.ReadyCondition,
+	metal3api.ProgressingCondition,
+	metal3api.HealthyCondition,
+}
+
 // An error used when there is no BMH satisfying the constraints.
 var ErrNoAvailableBMH = errors.New("no available BareMetalHost")
 
@@ -85,12 +106,21 @@ func (m *HostManager) SetConditionHostToFalse(
 	conditions.Set(m.HostClaim, metav1.Condition{Type: t, Status: metav1.ConditionFalse, Reason: reason, Message: message})
 }
 
-// SetConditionHostToFalse sets Host condition status to False.
+// SetConditionHostToTrue sets Host condition status to True.
 func (m *HostManager) SetConditionHostToTrue(
 	t string,
 	reason string,
 ) {
-	conditions.Set(m.HostClaim, metav1.Condition{Type: t, Status: metav1.ConditionTrue, Reason: reason, Message: ""})
+	m.SetConditionHostToTrueWithMessage(t, reason, "")
+}
+
+// SetConditionHostToTrueWithMessage sets Host condition status to True with a message.
+func (m *HostManager) SetConditionHostToTrueWithMessage(
+	t string,
+	reason string,
+	message string,
+) {
+	conditions.Set(m.HostClaim, metav1.Condition{Type: t, Status: metav1.ConditionTrue, Reason: reason, Message: message})
 }
 
 // Associate associates a BareMetalHost to the HostClaim. It chooses a BareMetalHost in a namespace that accepts
@@ -124,18 +154,20 @@ func (m *HostManager) Associate(ctx context.Context) error {
 				"No available host found: requeuing.")
 			return RequeueAfterError{RequeueAfter: HostClaimRequeueDelay}
 		}
+		if errors.Is(err, ErrQuotaExceeded) {
+			m.Log.Info("Quota of the HostDeployPolicies exceeded. Requeuing.")
+			m.SetConditionHostToFalse(
+				metal3api.AssociatedCondition, metal3api.QuotaExceededReason,
+				"Quota of the HostDeployPolicies exceeded: requeuing.")
+			return RequeueAfterError{RequeueAfter: HostClaimRequeueDelay}
+		}
 		return err
 	}
 	m.Log.Info("Associating hostClaim with host", "bmh", bmh.Name, "bmhNamespace", bmh.Namespace)
 
 	// First we record the association in the BMH. If we fail, we must redo the
 	// whole selection process.
-	bmh.Spec.ConsumerRef = &corev1.ObjectReference{
-		Kind:       HostClaimKind,
-		Name:       m.HostClaim.Name,
-		Namespace:  m.HostClaim.Namespace,
-		APIVersion: metal3api.GroupVersion.Identifier(),
-	}
+	bmh.Spec.ConsumerRef = hostClaimConsumerRef(m.HostClaim)
 
 	if err = m.client.Update(ctx, bmh); err != nil {
 		m.Log.Error(err, "Error while updating the consumerRef on BMH")
@@ -167,6 +199,236 @@ func hideConflictError(err error) error {
 	return err
 }
 
+// SetFinalizer sets the finalizer on the HostClaim. It ensures that the
+// BareMetalHost is released before the HostClaim is removed.
+func (m *HostManager) SetFinalizer() bool {
+	return controllerutil.AddFinalizer(m.HostClaim, metal3api.HostClaimFinalizer)
+}
+
+// UnsetFinalizer removes the finalizer from the HostClaim.
+func (m *HostManager) UnsetFinalizer() {
+	controllerutil.RemoveFinalizer(m.HostClaim, metal3api.HostClaimFinalizer)
+}
+
+// IsAssociated returns true when the HostClaim has been bound to a BareMetalHost.
+func (m *HostManager) IsAssociated() bool {
+	return m.HostClaim.Status.BareMetalHost != nil
+}
+
+// getHost gets the BareMetalHost bound to the HostClaim. It returns nil
+// without error if the host does not exist anymore or if it is not consumed
+// by the HostClaim.
+func (m *HostManager) getHost(ctx context.Context) (*metal3api.BareMetalHost, error) {
+	ref := m.HostClaim.Status.BareMetalHost
+	if ref == nil {
+		return nil, nil //nolint:nilnil
+	}
+	bmh := &metal3api.BareMetalHost{}
+	err := m.client.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, bmh)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil //nolint:nilnil
+		}
+		return nil, err
+	}
+	if !consumerRefMatches(bmh.Spec.ConsumerRef, m.HostClaim) {
+		m.Log.Info("BareMetalHost is consumed by another object", "bmh", bmh.Name, "bmhNamespace", bmh.Namespace)
+		return nil, nil //nolint:nilnil
+	}
+	return bmh, nil
+}
+
+// Update synchronizes the BareMetalHost bound to the HostClaim with the
+// specification of the claim: image, custom deploy, power state and the
+// secrets for user data, meta data and network data. It then reports the
+// state of the BareMetalHost in the status of the HostClaim.
+func (m *HostManager) Update(ctx context.Context) error {
+	bmh, err := m.getHost(ctx)
+	if err != nil {
+		m.SetConditionHostToFalse(
+			metal3api.SynchronizedCondition, metal3api.BareMetalHostNotSynchronizedReason,
+			"Failed to get the associated BareMetalHost")
+		return err
+	}
+	if bmh == nil {
+		m.Log.Info("Associated BareMetalHost not found")
+		m.SetConditionHostToFalse(
+			metal3api.AssociatedCondition, metal3api.MissingBareMetalHostReason,
+			"The associated BareMetalHost does not exist or is consumed by another object")
+		return RequeueAfterError{RequeueAfter: HostClaimRequeueDelay}
+	}
+
+	helper, err := patch.NewHelper(bmh, m.client)
+	if err != nil {
+		return err
+	}
+
+	if isPaused(m.HostClaim) {
+		if !isPaused(bmh) {
+			m.Log.Info("Propagating pause annotation to BareMetalHost", "bmh", bmh.Name, "bmhNamespace", bmh.Namespace)
+			metav1.SetMetaDataAnnotation(&bmh.ObjectMeta, metal3api.PausedAnnotation, PausedAnnotationValue)
+			if err = helper.Patch(ctx, bmh); err != nil {
+				m.SetConditionHostToFalse(
+					metal3api.SynchronizedCondition, metal3api.PauseAnnotationSetFailedReason,
+					"Failed to set the pause annotation on the BareMetalHost")
+				return hideConflictError(err)
+			}
+		}
+		m.SetConditionHostToFalse(metal3api.SynchronizedCondition, metal3api.HostPausedReason, "HostClaim is paused")
+		return nil
+	}
+	unpaused := removePauseAnnotation(bmh)
+	if unpaused {
+		m.Log.Info("Removing pause annotation from BareMetalHost", "bmh", bmh.Name, "bmhNamespace", bmh.Namespace)
+	}
+
+	reason, err := m.synchronizeSecrets(ctx, bmh)
+	if err != nil {
+		m.Log.Error(err, "Failed to synchronize secrets", "reason", reason)
+		m.SetConditionHostToFalse(metal3api.SynchronizedCondition, reason, err.Error())
+		return err
+	}
+
+	bmh.Spec.Image = m.HostClaim.Spec.Image.DeepCopy()
+	bmh.Spec.CustomDeploy = m.HostClaim.Spec.CustomDeploy.DeepCopy()
+	bmh.Spec.Online = m.HostClaim.Spec.PoweredOn
+
+	if err = helper.Patch(ctx, bmh); err != nil {
+		reason := metal3api.BareMetalHostNotSynchronizedReason
+		if unpaused {
+			reason = metal3api.PauseAnnotationRemoveFailedReason
+		}
+		m.Log.Error(err, "Failed to update the BareMetalHost")
+		m.SetConditionHostToFalse(metal3api.SynchronizedCondition, reason, "Failed to update the BareMetalHost")
+		return hideConflictError(err)
+	}
+
+	if err = m.updateStatus(ctx, bmh); err != nil {
+		return err
+	}
+	m.SetConditionHostToTrue(metal3api.SynchronizedCondition, metal3api.ConfigurationSyncedReason)
+	return nil
+}
+
+// updateStatus reports the power state, the hardware data and the progress
+// of provisioning of the BareMetalHost in the status of the HostClaim.
+func (m *HostManager) updateStatus(ctx context.Context, bmh *metal3api.BareMetalHost) error {
+	m.HostClaim.Status.PoweredOn = bmh.Status.PoweredOn
+
+	for _, conditionType := range mirroredConditions {
+		if conditions.Has(bmh, conditionType) {
+			conditions.SetMirrorCondition(bmh, m.HostClaim, conditionType)
+		} else {
+			conditions.Delete(m.HostClaim, conditionType)
+		}
+	}
+
+	hardwareData := &metal3api.HardwareData{}
+	err := m.client.Get(ctx, client.ObjectKeyFromObject(bmh), hardwareData)
+	switch {
+	case err == nil:
+		m.HostClaim.Status.HardwareData = &metal3api.ObjectReference{
+			Namespace: hardwareData.Namespace,
+			Name:      hardwareData.Name,
+		}
+	case k8serrors.IsNotFound(err):
+		m.HostClaim.Status.HardwareData = nil
+	default:
+		m.Log.Error(err, "Failed to get the HardwareData of the BareMetalHost")
+		return err
+	}
+	return nil
+}
+
+// Delete releases the BareMetalHost bound to the HostClaim. The image is first
+// removed from the BareMetalHost so that it is deprovisioned. The consumer
+// reference is only removed once the BareMetalHost is back to an available
+// state. Until then, a requeueAfterError is returned.
+func (m *HostManager) Delete(ctx context.Context) error {
+	m.Log.Info("Releasing host")
+	bmh, err := m.getHost(ctx)
+	if err != nil {
+		m.SetConditionHostToFalse(
+			metal3api.AssociatedCondition, metal3api.HostClaimDeletionFailedReason,
+			"Failed to get the associated BareMetalHost")
+		return err
+	}
+	if bmh == nil {
+		m.Log.Info("No BareMetalHost to release")
+		m.HostClaim.Status.BareMetalHost = nil
+		m.HostClaim.Status.HardwareData = nil
+		return nil
+	}
+
+	helper, err := patch.NewHelper(bmh, m.client)
+	if err != nil {
+		return err
+	}
+
+	removePauseAnnotation(bmh)
+	bmh.Spec.Image = nil
+	bmh.Spec.CustomDeploy = nil
+	bmh.Spec.UserData = nil
+	bmh.Spec.MetaData = nil
+	bmh.Spec.NetworkData = nil
+	bmh.Spec.Online = false
+
+	stillProvisioned := bmh.WasProvisioned()
+	switch bmh.Status.Provisioning.State {
+	case metal3api.StateProvisioning, metal3api.StateProvisioned, metal3api.StateDeprovisioning:
+		stillProvisioned = true
+	default:
+	}
+	if !stillProvisioned {
+		m.Log.Info("Removing consumer reference from BareMetalHost", "bmh", bmh.Name, "bmhNamespace", bmh.Namespace)
+		bmh.Spec.ConsumerRef = nil
+	}
+
+	if err = helper.Patch(ctx, bmh); err != nil {
+		m.Log.Error(err, "Failed to release the BareMetalHost")
+		m.SetConditionHostToFalse(
+			metal3api.AssociatedCondition, metal3api.HostClaimDeletionFailedReason,
+			"Failed to release the BareMetalHost")
+		return hideConflictError(err)
+	}
+
+	if stillProvisioned {
+		m.Log.Info("Waiting for BareMetalHost deprovisioning", "bmh", bmh.Name, "bmhNamespace", bmh.Namespace,
+			"state", bmh.Status.Provisioning.State)
+		m.SetConditionHostToFalse(
+			metal3api.AssociatedCondition, metal3api.HostClaimDeletingReason,
+			"Waiting for the BareMetalHost to be deprovisioned")
+		return RequeueAfterError{RequeueAfter: HostClaimRequeueDelay}
+	}
+
+	if err = m.deleteSecrets(ctx, bmh); err != nil {
+		m.SetConditionHostToFalse(
+			metal3api.AssociatedCondition, metal3api.HostClaimDeletionFailedReason,
+			"Failed to delete the secrets copied for the BareMetalHost")
+		return err
+	}
+
+	m.HostClaim.Status.BareMetalHost = nil
+	m.HostClaim.Status.HardwareData = nil
+	return nil
+}
+
+// isPaused checks if the object has the pause annotation.
+func isPaused(obj metav1.Object) bool {
+	_, ok := obj.GetAnnotations()[metal3api.PausedAnnotation]
+	return ok
+}
+
+// removePauseAnnotation removes the pause annotation from the BareMetalHost
+// if it was set by a HostClaim. Returns true if the annotation was removed.
+func removePauseAnnotation(bmh *metal3api.BareMetalHost) bool {
+	if bmh.Annotations[metal3api.PausedAnnotation] != PausedAnnotationValue {
+		return false
+	}
+	delete(bmh.Annotations, metal3api.PausedAnnotation)
+	return true
+}
+
 // consumerRefMatches returns a boolean based on whether the consumer
 // reference and bareMetalHost metadata match.
 func consumerRefMatches(consumer *corev1.ObjectReference, claim *metal3api.HostClaim) bool {
@@ -189,14 +451,15 @@ func consumerRefMatches(consumer *corev1.ObjectReference, claim *metal3api.HostC
 }
 
 func (m *HostManager) selectBMH(
+	ctx context.Context,
 	availableHosts []*metal3api.BareMetalHost,
+	failureDomainUsage map[string]int,
 ) (*metal3api.BareMetalHost, error) {
 	// choose a host.
 	var err error
 	var chosenHost *metal3api.BareMetalHost
 
-	// If there are hosts with nodeReuseLabelName:
-	chosenHost, err = m.pickHost(availableHosts)
+	chosenHost, err = m.pickHost(ctx, availableHosts, failureDomainUsage)
 	if err != nil {
 		m.Log.Error(err, "Failed to choose host, not choosing host")
 		return nil, err
@@ -207,8 +470,12 @@ func (m *HostManager) selectBMH(
 
 // Picks host from list of available hosts, if failureDomain is set, tries to choose from hosts in failureDomain.
 // When none available in failureDomain it chooses from all available hosts.
-func (m *HostManager) pickHost(availableHosts []*metal3api.BareMetalHost) (*metal3api.BareMetalHost, error) {
-	var chosenHost *metal3api.BareMetalHost
+// The remaining hosts are ranked with the scoring profile of the HostClaim and the best one is chosen.
+func (m *HostManager) pickHost(
+	ctx context.Context,
+	availableHosts []*metal3api.BareMetalHost,
+	failureDomainUsage map[string]int,
+) (*metal3api.BareMetalHost, error) {
 	var availableHostsInFailureDomain []*metal3api.BareMetalHost
 
 	// When failureDomain is set, create a list from available hosts in failureDomain
@@ -236,26 +503,79 @@ func (m *HostManager) pickHost(availableHosts []*metal3api.BareMetalHost) (*meta
 	}
 
 	if len(availableHostsInFailureDomain) > 0 {
-		rHost, _ := rand.Int(rand.Reader, big.NewInt(int64(len(availableHostsInFailureDomain))))
-		randomHost := rHost.Int64()
-		chosenHost = availableHostsInFailureDomain[randomHost]
-	} else {
-		rHost, _ := rand.Int(rand.Reader, big.NewInt(int64(len(availableHosts))))
-		randomHost := rHost.Int64()
-		chosenHost = availableHosts[randomHost]
+		availableHosts = availableHostsInFailureDomain
 	}
 
+	if m.group != nil {
+		availableHosts = m.group.spreadHosts(availableHosts)
+		if len(availableHosts) == 0 {
+			m.Log.Info("No available hosts respecting the failure domain spread of the HostClaimSet",
+				"spread", m.group.spread)
+			return nil, ErrNoAvailableBMH
+		}
+	}
+
+	profileName := DefaultScoringProfile
+	if m.HostClaim.Spec.Scoring != nil && m.HostClaim.Spec.Scoring.Profile != "" {
+		profileName = m.HostClaim.Spec.Scoring.Profile
+	}
+	profile, err := GetScoringProfile(profileName)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]CandidateHost, 0, len(availableHosts))
+	for _, host := range availableHosts {
+		hardware, err := m.getHardwareDetails(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, CandidateHost{Host: host, Hardware: hardware})
+	}
+
+	state := &ScoringState{
+		HostClaim:          m.HostClaim,
+		FailureDomainUsage: failureDomainUsage,
+		Now:                time.Now(),
+	}
+	best, err := scoreHosts(profile, state, candidates)
+	if err != nil {
+		return nil, err
+	}
+	chosenHost := best.Candidate.Host
+	m.Log.Info("Host scored", "bmh", chosenHost.Name, "bmhNamespace", chosenHost.Namespace,
+		"profile", profileName, "score", best.String())
+	m.SetConditionHostToTrueWithMessage(metal3api.HostScoredCondition, metal3api.HostScoredReason,
+		fmt.Sprintf("Host %s/%s scored %s with profile %s", chosenHost.Namespace, chosenHost.Name, best, profileName))
+
 	return chosenHost, nil
 }
 
+// getHardwareDetails returns the hardware details of a host from its
+// HardwareData. It returns nil if the host has not been inspected.
+func (m *HostManager) getHardwareDetails(ctx context.Context, host *metal3api.BareMetalHost) (*metal3api.HardwareDetails, error) {
+	hardwareData := &metal3api.HardwareData{}
+	err := m.client.Get(ctx, client.ObjectKeyFromObject(host), hardwareData)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil //nolint:nilnil
+		}
+		return nil, err
+	}
+	return hardwareData.Spec.HardwareDetails, nil
+}
+
 // For a given HostClaim in a given namespace, computes all the namespaces that can contain BMH
 // that can be bound to the HostClaim.
 //
 // The function lists the HostDeployPolicy and keeps the namespaces of the ones that
 // match the namespace of the claim. If the namespace argument is not empty,
 // HostDeployPolicies are only listed in that namespace and the result is either
-// an empty set or a singleton containing that namespace.
-func (m *HostManager) acceptableNamespaces(ctx context.Context, namespace string) (Set[string], error) {
+// an empty map or a singleton containing that namespace. Each namespace is
+// associated with all the HostDeployPolicies accepting the claim.
+func (m *HostManager) acceptableNamespaces(
+	ctx context.Context, namespace string,
+) (map[string][]*metal3api.HostDeployPolicy, error) {
 	m.Log.V(1).Info("Searching for suitable namespaces")
 	hostdeploypolicies := metal3api.HostDeployPolicyList{}
 	options := []client.ListOption{}
@@ -278,14 +598,10 @@ func (m *HostManager) acceptableNamespaces(ctx context.Context, namespace string
 	if nsLabels == nil {
 		nsLabels = map[string]string{}
 	}
-	namespaces := NewSet[string]()
+	namespaces := map[string][]*metal3api.HostDeployPolicy{}
 LOOP_POLICY:
-	for _, hostDeployPolicy := range hostdeploypolicies.Items {
+	for i, hostDeployPolicy := range hostdeploypolicies.Items {
 		log := m.Log.WithValues("policyNamespace", hostDeployPolicy.Namespace, "policyName", hostDeployPolicy.Name)
-		if SetContains(namespaces, hostDeployPolicy.Namespace) {
-			// namespace already added, no reason to check this hdp.
-			continue
-		}
 		constraints := hostDeployPolicy.Spec.HostClaimNamespaces
 		if constraints == nil {
 			log.V(1).Info("Ignoring HostDeployPolicy without constraint")
@@ -325,9 +641,9 @@ LOOP_POLICY:
 			}
 		}
 		log.V(1).Info("Accepting namespace because of HostDeployPolicy", "namespace", hostDeployPolicy.Namespace)
-		AddSet(namespaces, hostDeployPolicy.Namespace)
+		namespaces[hostDeployPolicy.Namespace] = append(namespaces[hostDeployPolicy.Namespace], &hostdeploypolicies.Items[i])
 	}
-	m.Log.Info("Acceptable namespaces", "namespaces", namespaces)
+	m.Log.Info("Acceptable namespaces", "namespaces", slices.Collect(maps.Keys(namespaces)))
 	return namespaces, nil
 }
 
@@ -375,14 +691,25 @@ func (m *HostManager) chooseBMH(ctx context.Context) (*metal3api.BareMetalHost,
 	// Different from M3M: We do not restrict to a single namespace (namespace of Metal3Machine)
 
 	availableHosts := []*metal3api.BareMetalHost{}
+	failureDomainUsage := map[string]int{}
+
+	quotaExceeded := false
+	var reserved Set[client.ObjectKey]
+	if m.group != nil {
+		reserved = m.group.reserved
+	}
 
-	for namespace := range namespaces {
+	for namespace, policies := range namespaces {
 		bmhs := metal3api.BareMetalHostList{}
 		m.Log.V(1).Info("Looking for BMHs in namespace", "namespace", namespace)
 		err = m.client.List(ctx, &bmhs, client.MatchingLabelsSelector{Selector: labelSelector}, client.InNamespace(namespace))
 		if err != nil {
 			return nil, err
 		}
+		quota, err := newQuotaState(ctx, m.client, policies, m.HostClaim.Namespace, reserved)
+		if err != nil {
+			return nil, err
+		}
 		for i, bmh := range bmhs.Items {
 			if bmh.Spec.ConsumerRef != nil && consumerRefMatches(bmh.Spec.ConsumerRef, m.HostClaim) {
 				m.Log.Info("Found host with existing ConsumerRef", "bmh", bmh.Name, "bmhNamespace", bmh.Namespace)
@@ -390,11 +717,17 @@ func (m *HostManager) chooseBMH(ctx context.Context) (*metal3api.BareMetalHost,
 			}
 
 			if bmh.Spec.ConsumerRef != nil {
+				if bmh.Spec.ConsumerRef.Kind == HostClaimKind && bmh.Spec.ConsumerRef.Namespace == m.HostClaim.Namespace {
+					failureDomainUsage[bmh.Labels[FailureDomainLabelName]]++
+				}
 				continue
 			}
 			if bmh.GetDeletionTimestamp() != nil {
 				continue
 			}
+			if SetContains(reserved, client.ObjectKeyFromObject(&bmh)) {
+				continue
+			}
 			// continue if BaremetalHost is paused or marked with UnhealthyAnnotation.
 			annotations := bmh.GetAnnotations()
 			if annotations != nil {
@@ -402,6 +735,10 @@ func (m *HostManager) chooseBMH(ctx context.Context) (*metal3api.BareMetalHost,
 					continue
 				}
 			}
+			if bmh.IsCordoned() {
+				m.Log.V(1).Info("Host is cordoned by a remediation", "bmh", bmh.Name, "bmhNamespace", bmh.Namespace)
+				continue
+			}
 
 			switch bmh.Status.Provisioning.State {
 			case metal3api.StateReady, metal3api.StateAvailable:
@@ -415,6 +752,32 @@ func (m *HostManager) chooseBMH(ctx context.Context) (*metal3api.BareMetalHost,
 				continue
 			}
 
+			requirements := m.HostClaim.Spec.HostSelector.Hardware
+			var hardware *metal3api.HardwareDetails
+			if requirements != nil || quota != nil {
+				hardware, err = m.getHardwareDetails(ctx, &bmhs.Items[i])
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			if requirements != nil {
+				if ok, reason := hardwarematch.Match(requirements, hardware); !ok {
+					m.Log.V(1).Info("Host does not match the hardware requirements",
+						"bmh", bmh.Name, "bmhNamespace", bmh.Namespace, "reason", reason)
+					continue
+				}
+			}
+
+			if quota != nil {
+				if !quota.fits(hardware) {
+					m.Log.V(1).Info("Host does not fit in the quota of the HostDeployPolicies",
+						"bmh", bmh.Name, "bmhNamespace", bmh.Namespace)
+					quotaExceeded = true
+					continue
+				}
+			}
+
 			m.Log.Info("Host matched hostSelector for Host, adding it to availableHosts list",
 				"bmh", bmh.Name, "bmhNamespace", bmh.Namespace)
 			availableHosts = append(availableHosts, &bmhs.Items[i])
@@ -423,10 +786,13 @@ func (m *HostManager) chooseBMH(ctx context.Context) (*metal3api.BareMetalHost,
 
 	m.Log.Info("Host count available while choosing host for HostClaim", "hostcount", len(availableHosts))
 	if len(availableHosts) == 0 {
+		if quotaExceeded {
+			return nil, ErrQuotaExceeded
+		}
 		return nil, ErrNoAvailableBMH
 	}
 
-	chosenHost, err := m.selectBMH(availableHosts)
+	chosenHost, err := m.selectBMH(ctx, availableHosts, failureDomainUsage)
 	if err != nil {
 		m.Log.Error(err, "Failed to select a Host")
 		return nil, err
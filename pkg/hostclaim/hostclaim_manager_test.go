@@ -29,6 +29,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/selection"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -52,6 +53,8 @@ var _ = Describe("HostClaim manager", func() {
 
 	var (
 		defaultImage = metal3api.Image{URL: "url"}
+		hostClaimRef = corev1.ObjectReference{Kind: HostClaimKind, Namespace: HostclaimNamespace,
+			APIVersion: metal3api.GroupVersion.String(), Name: HostclaimName}
 	)
 
 	type testCaseChooseBMH struct {
@@ -78,6 +81,8 @@ var _ = Describe("HostClaim manager", func() {
 		bmhns1NotAvail          = NewBaremetalhost("notavail-bmh1", "ns1", metal3api.StateRegistering).SetLabels(defaultBmhLabels).Build()
 		bmhns1Paused            = NewBaremetalhost("paused-bmh1", "ns1", metal3api.StateRegistering).SetLabels(defaultBmhLabels).
 					SetAnnotations(map[string]string{metal3api.PausedAnnotation: PausedAnnotationValue}).Build()
+		bmhns1Replace = NewBaremetalhost("replace-bmh1", "ns1", metal3api.StateAvailable).SetLabels(defaultBmhLabels).
+				SetAnnotations(map[string]string{metal3api.ReplaceAnnotation: "CriticalHealth"}).Build()
 		bmhns1Consumed = NewBaremetalhost(
 			"bmh-consumed", "ns1", metal3api.StateAvailable).SetLabels(defaultBmhLabels).
 			SetConsumerRef(corev1.ObjectReference{Kind: HostClaimKind, Namespace: HostclaimNamespace,
@@ -199,6 +204,13 @@ var _ = Describe("HostClaim manager", func() {
 			BareMetalHosts: []*metal3api.BareMetalHost{bmhns1BadLabel, bmhns1ConsOther, bmhns1NotAvail, bmhns1Paused},
 			ExpectFail:     true,
 		}),
+		Entry("with remediated hosts", testCaseChooseBMH{
+			HostClaim:  NewHostclaim(HostclaimName).Build(),
+			Namespaces: []*corev1.Namespace{hcNs, ns1},
+			HostDeployPolicies: []*metal3api.HostDeployPolicy{
+				NewHostdeploypolicy("hdp", "ns1").AcceptNames([]string{HostclaimNamespace}).Build()},
+			BareMetalHosts: []*metal3api.BareMetalHost{bmhns1Replace, bmhns1Cordoned()},
+		}),
 		Entry("with consumerRef", testCaseChooseBMH{
 			HostClaim:  NewHostclaim(HostclaimName).Build(),
 			Namespaces: []*corev1.Namespace{hcNs, ns1},
@@ -358,8 +370,194 @@ var _ = Describe("HostClaim manager", func() {
 			ExpectRequeue: true,
 		}),
 	)
+
+	type testCaseUpdate struct {
+		HostClaim         *metal3api.HostClaim
+		BareMetalHost     *metal3api.BareMetalHost
+		ExpectRequeue     bool
+		ExpectFails       bool
+		ExpectedReason    string
+		ExpectPaused      bool
+		ExpectUserData    bool
+		ExpectHardwareRef bool
+	}
+
+	DescribeTable("test Update",
+		func(tc testCaseUpdate) {
+			sec := NewSecret("sec-user-data", HostclaimNamespace).SetData(map[string][]byte{"userData": []byte("v")}).Build()
+			objects := []client.Object{tc.HostClaim, sec}
+			if tc.BareMetalHost != nil {
+				objects = append(objects, tc.BareMetalHost)
+				if tc.ExpectHardwareRef {
+					objects = append(objects, NewHardwareData(tc.BareMetalHost).Build())
+				}
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(setupScheme()).WithObjects(objects...).Build()
+			hostMgr, err := NewHostManager(fakeClient, GinkgoLogr, tc.HostClaim, fakeClient)
+			Expect(err).NotTo(HaveOccurred())
+			err = hostMgr.Update(context.TODO())
+			if tc.ExpectRequeue || tc.ExpectFails {
+				Expect(err).To(HaveOccurred())
+				var requeueAfterError RequeueAfterError
+				Expect(errors.As(err, &requeueAfterError)).To(Equal(tc.ExpectRequeue))
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+			if tc.ExpectedReason != "" {
+				Expect(conditions.GetReason(tc.HostClaim, metal3api.SynchronizedCondition)).To(Equal(tc.ExpectedReason))
+			}
+			if tc.BareMetalHost == nil || tc.ExpectRequeue || tc.ExpectFails {
+				return
+			}
+			updatedBmh := &metal3api.BareMetalHost{}
+			err = fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(tc.BareMetalHost), updatedBmh)
+			Expect(err).NotTo(HaveOccurred())
+			if tc.ExpectPaused {
+				Expect(updatedBmh.Annotations).To(HaveKeyWithValue(metal3api.PausedAnnotation, PausedAnnotationValue))
+				Expect(updatedBmh.Spec.Image).To(BeNil())
+				return
+			}
+			Expect(updatedBmh.Annotations).NotTo(HaveKey(metal3api.PausedAnnotation))
+			Expect(updatedBmh.Spec.Image).To(Equal(tc.HostClaim.Spec.Image))
+			Expect(updatedBmh.Spec.Online).To(Equal(tc.HostClaim.Spec.PoweredOn))
+			if tc.ExpectUserData {
+				Expect(updatedBmh.Spec.UserData).NotTo(BeNil())
+				Expect(updatedBmh.Spec.UserData.Namespace).To(Equal(updatedBmh.Namespace))
+				copied := &corev1.Secret{}
+				err = fakeClient.Get(context.TODO(),
+					client.ObjectKey{Namespace: updatedBmh.Namespace, Name: updatedBmh.Spec.UserData.Name}, copied)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(copied.Data).To(Equal(sec.Data))
+				Expect(copied.Labels).To(HaveKeyWithValue(HostClaimNameLabel, tc.HostClaim.Name))
+			} else {
+				Expect(updatedBmh.Spec.UserData).To(BeNil())
+			}
+			if tc.ExpectHardwareRef {
+				Expect(tc.HostClaim.Status.HardwareData).To(Equal(
+					&metal3api.ObjectReference{Namespace: updatedBmh.Namespace, Name: updatedBmh.Name}))
+			} else {
+				Expect(tc.HostClaim.Status.HardwareData).To(BeNil())
+			}
+		},
+		Entry("Regular case", testCaseUpdate{
+			HostClaim: NewHostclaim(HostclaimName).SetImage(defaultImage).SetUserData("sec-user-data").SetPowerOn().
+				SetAssociatedBMH("ns", "bmh").Build(),
+			BareMetalHost: NewBaremetalhost("bmh", "ns", metal3api.StateAvailable).
+				SetConsumerRef(hostClaimRef).Build(),
+			ExpectedReason:    metal3api.ConfigurationSyncedReason,
+			ExpectUserData:    true,
+			ExpectHardwareRef: true,
+		}),
+		Entry("No secret", testCaseUpdate{
+			HostClaim: NewHostclaim(HostclaimName).SetImage(defaultImage).
+				SetAssociatedBMH("ns", "bmh").Build(),
+			BareMetalHost: NewBaremetalhost("bmh", "ns", metal3api.StateAvailable).
+				SetConsumerRef(hostClaimRef).Build(),
+			ExpectedReason: metal3api.ConfigurationSyncedReason,
+		}),
+		Entry("Missing secret", testCaseUpdate{
+			HostClaim: NewHostclaim(HostclaimName).SetImage(defaultImage).SetUserData("missing").
+				SetAssociatedBMH("ns", "bmh").Build(),
+			BareMetalHost: NewBaremetalhost("bmh", "ns", metal3api.StateAvailable).
+				SetConsumerRef(hostClaimRef).Build(),
+			ExpectFails:    true,
+			ExpectedReason: metal3api.BadUserDataSecretReason,
+		}),
+		Entry("Paused hostclaim", testCaseUpdate{
+			HostClaim: NewHostclaim(HostclaimName).SetImage(defaultImage).
+				SetAnnotations(map[string]string{metal3api.PausedAnnotation: ""}).
+				SetAssociatedBMH("ns", "bmh").Build(),
+			BareMetalHost: NewBaremetalhost("bmh", "ns", metal3api.StateAvailable).
+				SetConsumerRef(hostClaimRef).Build(),
+			ExpectedReason: metal3api.HostPausedReason,
+			ExpectPaused:   true,
+		}),
+		Entry("Unpaused hostclaim", testCaseUpdate{
+			HostClaim: NewHostclaim(HostclaimName).SetImage(defaultImage).
+				SetAssociatedBMH("ns", "bmh").Build(),
+			BareMetalHost: NewBaremetalhost("bmh", "ns", metal3api.StateAvailable).
+				SetAnnotations(map[string]string{metal3api.PausedAnnotation: PausedAnnotationValue}).
+				SetConsumerRef(hostClaimRef).Build(),
+			ExpectedReason: metal3api.ConfigurationSyncedReason,
+		}),
+		Entry("Missing host", testCaseUpdate{
+			HostClaim: NewHostclaim(HostclaimName).SetImage(defaultImage).
+				SetAssociatedBMH("ns", "bmh").Build(),
+			ExpectRequeue: true,
+		}),
+		Entry("Host consumed by another claim", testCaseUpdate{
+			HostClaim: NewHostclaim(HostclaimName).SetImage(defaultImage).
+				SetAssociatedBMH("ns", "bmh").Build(),
+			BareMetalHost: NewBaremetalhost("bmh", "ns", metal3api.StateAvailable).
+				SetConsumerRef(corev1.ObjectReference{Kind: HostClaimKind, Namespace: HostclaimNamespace,
+					APIVersion: metal3api.GroupVersion.String(), Name: "other"}).Build(),
+			ExpectRequeue: true,
+		}),
+	)
+
+	type testCaseDelete struct {
+		BareMetalHost  *metal3api.BareMetalHost
+		ExpectRequeue  bool
+		ExpectReleased bool
+	}
+
+	DescribeTable("test Delete",
+		func(tc testCaseDelete) {
+			claim := NewHostclaim(HostclaimName).SetAssociatedBMH("ns", "bmh").Build()
+			copied := NewSecret("bmh-"+userDataSuffix, "ns").Build()
+			copied.Labels = map[string]string{HostClaimNameLabel: HostclaimName, HostClaimNamespaceLabel: HostclaimNamespace}
+			fakeClient := fake.NewClientBuilder().WithScheme(setupScheme()).
+				WithObjects(claim, tc.BareMetalHost, copied).Build()
+			hostMgr, err := NewHostManager(fakeClient, GinkgoLogr, claim, fakeClient)
+			Expect(err).NotTo(HaveOccurred())
+			err = hostMgr.Delete(context.TODO())
+			if tc.ExpectRequeue {
+				var requeueAfterError RequeueAfterError
+				Expect(errors.As(err, &requeueAfterError)).To(BeTrue())
+				Expect(claim.Status.BareMetalHost).NotTo(BeNil())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(claim.Status.BareMetalHost).To(BeNil())
+			}
+			updatedBmh := &metal3api.BareMetalHost{}
+			err = fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(tc.BareMetalHost), updatedBmh)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updatedBmh.Spec.Image).To(BeNil())
+			Expect(updatedBmh.Spec.UserData).To(BeNil())
+			err = fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(copied), &corev1.Secret{})
+			if tc.ExpectReleased {
+				Expect(updatedBmh.Spec.ConsumerRef).To(BeNil())
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(updatedBmh.Spec.ConsumerRef).NotTo(BeNil())
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		Entry("Provisioned host", testCaseDelete{
+			BareMetalHost: NewBaremetalhost("bmh", "ns", metal3api.StateProvisioned).
+				SetImage(defaultImage).SetUserData("bmh-" + userDataSuffix).SetConsumerRef(hostClaimRef).Build(),
+			ExpectRequeue: true,
+		}),
+		Entry("Deprovisioned host", testCaseDelete{
+			BareMetalHost: NewBaremetalhost("bmh", "ns", metal3api.StateAvailable).
+				SetConsumerRef(hostClaimRef).Build(),
+			ExpectReleased: true,
+		}),
+		Entry("Host consumed by another claim", testCaseDelete{
+			BareMetalHost: NewBaremetalhost("bmh", "ns", metal3api.StateProvisioned).
+				SetConsumerRef(corev1.ObjectReference{Kind: HostClaimKind, Namespace: HostclaimNamespace,
+					APIVersion: metal3api.GroupVersion.String(), Name: "other"}).Build(),
+		}),
+	)
 })
 
+func bmhns1Cordoned() *metal3api.BareMetalHost {
+	bmh := NewBaremetalhost("cordoned-bmh1", "ns1", metal3api.StateAvailable).
+		SetLabels(map[string]string{"default-selector": "default-value"}).Build()
+	bmh.Spec.Taints = []corev1.Taint{metal3api.CordonTaint()}
+	return bmh
+}
+
 func TestManagers(t *testing.T) {
 	RegisterFailHandler(Fail)
 
@@ -0,0 +1,140 @@
+/*
+Copyright 2025 The Metal3 Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostclaim
+
+import (
+	"context"
+	"fmt"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/secretutils"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	userDataSuffix    = "hostclaim-user-data"
+	metaDataSuffix    = "hostclaim-meta-data"
+	networkDataSuffix = "hostclaim-network-data"
+)
+
+// copiedSecretName is the name of the copy of a HostClaim secret in the
+// namespace of the BareMetalHost. A BareMetalHost is bound to at most one
+// HostClaim, so the name of the host is enough to avoid collisions.
+func copiedSecretName(bmh *metal3api.BareMetalHost, suffix string) string {
+	return bmh.Name + "-" + suffix
+}
+
+// synchronizeSecrets copies the secrets referenced by the HostClaim in the
+// namespace of the BareMetalHost and makes the BareMetalHost point to those
+// copies. On failure, it also returns the reason to use in the Synchronized
+// condition.
+func (m *HostManager) synchronizeSecrets(ctx context.Context, bmh *metal3api.BareMetalHost) (string, error) {
+	var err error
+	bmh.Spec.UserData, err = m.synchronizeSecret(ctx, bmh, m.HostClaim.Spec.UserData, userDataSuffix)
+	if err != nil {
+		return metal3api.BadUserDataSecretReason, err
+	}
+	bmh.Spec.MetaData, err = m.synchronizeSecret(ctx, bmh, m.HostClaim.Spec.MetaData, metaDataSuffix)
+	if err != nil {
+		return metal3api.BadMetaDataSecretReason, err
+	}
+	bmh.Spec.NetworkData, err = m.synchronizeSecret(ctx, bmh, m.HostClaim.Spec.NetworkData, networkDataSuffix)
+	if err != nil {
+		return metal3api.BadNetworkDataSecretReason, err
+	}
+	return metal3api.ConfigurationSyncedReason, nil
+}
+
+// synchronizeSecret copies a single secret of the HostClaim in the namespace
+// of the BareMetalHost. When the HostClaim does not reference a secret, a
+// previous copy is removed.
+func (m *HostManager) synchronizeSecret(
+	ctx context.Context, bmh *metal3api.BareMetalHost, ref *corev1.SecretReference, suffix string,
+) (*corev1.SecretReference, error) {
+	name := copiedSecretName(bmh, suffix)
+	if ref == nil {
+		return nil, m.deleteSecret(ctx, bmh.Namespace, name)
+	}
+
+	// Secrets are always read in the namespace of the HostClaim. Otherwise a
+	// tenant could get a copy of any secret of the cluster.
+	if ref.Namespace != "" && ref.Namespace != m.HostClaim.Namespace {
+		return nil, fmt.Errorf("secret %s must be in the namespace of the HostClaim", ref.Name)
+	}
+
+	secretManager := secretutils.NewSecretManager(m.Log, m.client, m.APIReader)
+	source, err := secretManager.ObtainSecret(ctx, types.NamespacedName{Namespace: m.HostClaim.Namespace, Name: ref.Name})
+	if err != nil {
+		return nil, err
+	}
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: bmh.Namespace, Name: name}}
+	_, err = controllerutil.CreateOrUpdate(ctx, m.client, secret, func() error {
+		if !secret.CreationTimestamp.IsZero() && !m.ownsSecret(secret) {
+			return fmt.Errorf("secret %s/%s already exists and is not managed by the HostClaim", secret.Namespace, secret.Name)
+		}
+		metav1.SetMetaDataLabel(&secret.ObjectMeta, secretutils.LabelEnvironmentName, secretutils.LabelEnvironmentValue)
+		metav1.SetMetaDataLabel(&secret.ObjectMeta, HostClaimNameLabel, m.HostClaim.Name)
+		metav1.SetMetaDataLabel(&secret.ObjectMeta, HostClaimNamespaceLabel, m.HostClaim.Namespace)
+		secret.Type = source.Type
+		secret.Data = source.Data
+		return controllerutil.SetOwnerReference(bmh, secret, m.client.Scheme())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.SecretReference{Namespace: bmh.Namespace, Name: name}, nil
+}
+
+// ownsSecret checks that a secret was copied for the HostClaim.
+func (m *HostManager) ownsSecret(secret *corev1.Secret) bool {
+	return secret.Labels[HostClaimNameLabel] == m.HostClaim.Name &&
+		secret.Labels[HostClaimNamespaceLabel] == m.HostClaim.Namespace
+}
+
+// deleteSecret deletes a copied secret if it exists and belongs to the HostClaim.
+func (m *HostManager) deleteSecret(ctx context.Context, namespace, name string) error {
+	secret := &corev1.Secret{}
+	err := m.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, secret)
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !m.ownsSecret(secret) {
+		return nil
+	}
+	m.Log.Info("Deleting copied secret", "secret", name, "secretNamespace", namespace)
+	err = m.client.Delete(ctx, secret)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// deleteSecrets deletes all the secrets copied for the BareMetalHost.
+func (m *HostManager) deleteSecrets(ctx context.Context, bmh *metal3api.BareMetalHost) error {
+	for _, suffix := range []string{userDataSuffix, metaDataSuffix, networkDataSuffix} {
+		if err := m.deleteSecret(ctx, bmh.Namespace, copiedSecretName(bmh, suffix)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
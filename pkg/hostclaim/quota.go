@@ -0,0 +1,165 @@
+/*
+Copyright 2025 The Metal3 Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostclaim
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// An error used when the quota of the HostDeployPolicies prevents the binding
+// of any BareMetalHost.
+var ErrQuotaExceeded = errors.New("HostDeployPolicy quota exceeded")
+
+// NamespaceResources describes the BareMetalHosts of a namespace and the
+// resources bound by HostClaims.
+type NamespaceResources struct {
+	// Capacity is the total of the BareMetalHosts of the namespace.
+	Capacity metal3api.HostResources
+	// Free is the total of the BareMetalHosts without consumer.
+	Free metal3api.HostResources
+	// Usage is the total of the BareMetalHosts bound by the HostClaims of
+	// each namespace.
+	Usage map[string]metal3api.HostResources
+}
+
+// addHost adds a BareMetalHost with its hardware details to the resources.
+func addHost(resources *metal3api.HostResources, hardware *metal3api.HardwareDetails) {
+	resources.Hosts++
+	if hardware != nil {
+		resources.RAMMebibytes += hardware.RAMMebibytes
+		resources.CPUs += hardware.CPU.Count
+	}
+}
+
+// ComputeNamespaceResources computes the capacity of the BareMetalHosts of a
+// namespace and the resources bound by the HostClaims of each namespace.
+func ComputeNamespaceResources(ctx context.Context, c client.Client, namespace string) (*NamespaceResources, error) {
+	return computeNamespaceResources(ctx, c, namespace, nil, "")
+}
+
+// computeNamespaceResources is ComputeNamespaceResources with the hosts just
+// reserved for the HostClaims of reservedNamespace. They are counted as
+// bound even if the cache does not show their consumer yet.
+func computeNamespaceResources(
+	ctx context.Context, c client.Client, namespace string, reserved Set[client.ObjectKey], reservedNamespace string,
+) (*NamespaceResources, error) {
+	hosts := metal3api.BareMetalHostList{}
+	if err := c.List(ctx, &hosts, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	hardwareDataList := metal3api.HardwareDataList{}
+	if err := c.List(ctx, &hardwareDataList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	hardware := make(map[string]*metal3api.HardwareDetails, len(hardwareDataList.Items))
+	for i := range hardwareDataList.Items {
+		hardware[hardwareDataList.Items[i].Name] = hardwareDataList.Items[i].Spec.HardwareDetails
+	}
+
+	resources := &NamespaceResources{Usage: map[string]metal3api.HostResources{}}
+	for _, host := range hosts.Items {
+		details := hardware[host.Name]
+		addHost(&resources.Capacity, details)
+		consumer := host.Spec.ConsumerRef
+		switch {
+		case consumer == nil && SetContains(reserved, client.ObjectKeyFromObject(&host)):
+			usage := resources.Usage[reservedNamespace]
+			addHost(&usage, details)
+			resources.Usage[reservedNamespace] = usage
+		case consumer == nil:
+			addHost(&resources.Free, details)
+		case consumer.Kind == HostClaimKind && consumer.GroupVersionKind().Group == metal3api.GroupVersion.Group:
+			usage := resources.Usage[consumer.Namespace]
+			addHost(&usage, details)
+			resources.Usage[consumer.Namespace] = usage
+		default:
+		}
+	}
+	return resources, nil
+}
+
+// quotaState tracks the resources still available to a HostClaim namespace
+// under the quotas of the HostDeployPolicies accepting it in a namespace.
+type quotaState struct {
+	quotas []*metal3api.HostClaimQuota
+	used   metal3api.HostResources
+}
+
+// newQuotaState computes the resources already bound by the HostClaims of
+// claimNamespace in the namespace of the policies. Every policy accepting
+// the claim namespace is enforced, so that a policy without quota cannot be
+// used to escape the quota of another one. The reserved hosts are counted
+// as bound to claimNamespace. It returns nil when none of the policies has
+// a quota.
+func newQuotaState(
+	ctx context.Context, c client.Client, policies []*metal3api.HostDeployPolicy,
+	claimNamespace string, reserved Set[client.ObjectKey],
+) (*quotaState, error) {
+	state := &quotaState{}
+	namespace := ""
+	for _, policy := range policies {
+		if policy.Spec.Quota != nil {
+			state.quotas = append(state.quotas, policy.Spec.Quota)
+			namespace = policy.Namespace
+		}
+	}
+	if len(state.quotas) == 0 {
+		return nil, nil //nolint:nilnil
+	}
+	resources, err := computeNamespaceResources(ctx, c, namespace, reserved, claimNamespace)
+	if err != nil {
+		return nil, err
+	}
+	state.used = resources.Usage[claimNamespace]
+	return state, nil
+}
+
+// full checks if no more host can be bound.
+func (q *quotaState) full() bool {
+	return slices.ContainsFunc(q.quotas, func(quota *metal3api.HostClaimQuota) bool {
+		return quota.MaxHosts > 0 && q.used.Hosts >= quota.MaxHosts
+	})
+}
+
+// fits checks if a host with the given hardware can be bound without
+// exceeding any of the quotas. Hosts without hardware details never fit a
+// quota on memory or CPUs as their resources are unknown.
+func (q *quotaState) fits(hardware *metal3api.HardwareDetails) bool {
+	if q.full() {
+		return false
+	}
+	for _, quota := range q.quotas {
+		if quota.MaxRAMMebibytes == 0 && quota.MaxCPUs == 0 {
+			continue
+		}
+		if hardware == nil {
+			return false
+		}
+		if quota.MaxRAMMebibytes > 0 && q.used.RAMMebibytes+hardware.RAMMebibytes > quota.MaxRAMMebibytes {
+			return false
+		}
+		if quota.MaxCPUs > 0 && q.used.CPUs+hardware.CPU.Count > quota.MaxCPUs {
+			return false
+		}
+	}
+	return true
+}
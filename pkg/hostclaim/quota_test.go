@@ -0,0 +1,160 @@
+/*
+Copyright 2025 The Metal3 Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostclaim
+
+import (
+	"context"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	. "github.com/metal3-io/baremetal-operator/internal/testutil"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("HostDeployPolicy quota", func() {
+	consumer := func(namespace, name string) corev1.ObjectReference {
+		return corev1.ObjectReference{Kind: HostClaimKind, Namespace: namespace,
+			APIVersion: metal3api.GroupVersion.String(), Name: name}
+	}
+
+	hardwareData := func(bmh *metal3api.BareMetalHost, ramMiB, cpus int) *metal3api.HardwareData {
+		hd := NewHardwareData(bmh).Build()
+		hd.Spec.HardwareDetails.RAMMebibytes = ramMiB
+		hd.Spec.HardwareDetails.CPU.Count = cpus
+		return hd
+	}
+
+	// hosts returns a pool with one host used by the claim namespace, one
+	// host used by another namespace and two free hosts.
+	hosts := func() []client.Object {
+		used := NewBaremetalhost("used", "ns", metal3api.StateProvisioned).
+			SetConsumerRef(consumer(HostclaimNamespace, "other")).Build()
+		foreign := NewBaremetalhost("foreign", "ns", metal3api.StateProvisioned).
+			SetConsumerRef(consumer("tenant", "claim")).Build()
+		small := NewBaremetalhost("small", "ns", metal3api.StateAvailable).Build()
+		big := NewBaremetalhost("big", "ns", metal3api.StateAvailable).Build()
+		return []client.Object{
+			used, foreign, small, big,
+			hardwareData(used, 64*1024, 16), hardwareData(foreign, 128*1024, 32),
+			hardwareData(small, 64*1024, 16), hardwareData(big, 512*1024, 64),
+		}
+	}
+
+	It("Computes the capacity and usage of a namespace", func() {
+		fakeClient := fake.NewClientBuilder().WithScheme(setupScheme()).WithObjects(hosts()...).Build()
+		resources, err := ComputeNamespaceResources(context.TODO(), fakeClient, "ns")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resources.Capacity).To(Equal(metal3api.HostResources{Hosts: 4, RAMMebibytes: 768 * 1024, CPUs: 128}))
+		Expect(resources.Free).To(Equal(metal3api.HostResources{Hosts: 2, RAMMebibytes: 576 * 1024, CPUs: 80}))
+		Expect(resources.Usage).To(Equal(map[string]metal3api.HostResources{
+			HostclaimNamespace: {Hosts: 1, RAMMebibytes: 64 * 1024, CPUs: 16},
+			"tenant":           {Hosts: 1, RAMMebibytes: 128 * 1024, CPUs: 32},
+		}))
+	})
+
+	It("Counts the hosts reserved but not yet bound in the cache", func() {
+		fakeClient := fake.NewClientBuilder().WithScheme(setupScheme()).WithObjects(hosts()...).Build()
+		reserved := NewSet[client.ObjectKey]()
+		AddSet(reserved, client.ObjectKey{Namespace: "ns", Name: "small"})
+		resources, err := computeNamespaceResources(context.TODO(), fakeClient, "ns", reserved, HostclaimNamespace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resources.Free).To(Equal(metal3api.HostResources{Hosts: 1, RAMMebibytes: 512 * 1024, CPUs: 64}))
+		Expect(resources.Usage[HostclaimNamespace]).To(Equal(metal3api.HostResources{Hosts: 2, RAMMebibytes: 128 * 1024, CPUs: 32}))
+	})
+
+	type testCaseQuota struct {
+		Quota         *metal3api.HostClaimQuota
+		OtherQuota    *metal3api.HostClaimQuota
+		ExpectedHost  string
+		ExpectedError error
+	}
+
+	DescribeTable("Test chooseBMH with quota",
+		func(tc testCaseQuota) {
+			claim := NewHostclaim(HostclaimName).Build()
+			claim.Spec.Scoring = &metal3api.HostScoring{Profile: "binpack"}
+			policy := NewHostdeploypolicy("hdp", "ns").AcceptNames([]string{HostclaimNamespace}).Build()
+			policy.Spec.Quota = tc.Quota
+			// A second policy accepting the same namespace
+			otherPolicy := NewHostdeploypolicy("hdp-other", "ns").AcceptNames([]string{HostclaimNamespace}).Build()
+			otherPolicy.Spec.Quota = tc.OtherQuota
+			objects := append(hosts(), claim, policy, otherPolicy,
+				NewNamespace(HostclaimNamespace).Build(), NewNamespace("ns").Build())
+			fakeClient := fake.NewClientBuilder().WithScheme(setupScheme()).WithObjects(objects...).Build()
+			hostMgr, err := NewHostManager(fakeClient, GinkgoLogr, claim, fakeClient)
+			Expect(err).NotTo(HaveOccurred())
+			bmh, err := hostMgr.chooseBMH(context.TODO())
+			if tc.ExpectedError != nil {
+				Expect(err).To(MatchError(tc.ExpectedError))
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bmh.Name).To(Equal(tc.ExpectedHost))
+		},
+		Entry("no quota", testCaseQuota{
+			ExpectedHost: "small",
+		}),
+		Entry("host quota not reached", testCaseQuota{
+			Quota:        &metal3api.HostClaimQuota{MaxHosts: 2},
+			ExpectedHost: "small",
+		}),
+		Entry("host quota reached", testCaseQuota{
+			Quota:         &metal3api.HostClaimQuota{MaxHosts: 1},
+			ExpectedError: ErrQuotaExceeded,
+		}),
+		Entry("RAM quota only fits the small host", testCaseQuota{
+			Quota:        &metal3api.HostClaimQuota{MaxRAMMebibytes: 256 * 1024},
+			ExpectedHost: "small",
+		}),
+		Entry("CPU quota fits no host", testCaseQuota{
+			Quota:         &metal3api.HostClaimQuota{MaxCPUs: 24},
+			ExpectedError: ErrQuotaExceeded,
+		}),
+		Entry("quota of a policy listed after a policy without quota", testCaseQuota{
+			OtherQuota:    &metal3api.HostClaimQuota{MaxHosts: 1},
+			ExpectedError: ErrQuotaExceeded,
+		}),
+		Entry("most restrictive quota of the policies", testCaseQuota{
+			Quota:         &metal3api.HostClaimQuota{MaxHosts: 3},
+			OtherQuota:    &metal3api.HostClaimQuota{MaxCPUs: 24},
+			ExpectedError: ErrQuotaExceeded,
+		}),
+	)
+
+	It("Sets the QuotaExceeded reason when associating", func() {
+		claim := NewHostclaim(HostclaimName).Build()
+		policy := NewHostdeploypolicy("hdp", "ns").AcceptNames([]string{HostclaimNamespace}).Build()
+		policy.Spec.Quota = &metal3api.HostClaimQuota{MaxHosts: 1}
+		objects := append(hosts(), claim, policy,
+			NewNamespace(HostclaimNamespace).Build(), NewNamespace("ns").Build())
+		fakeClient := fake.NewClientBuilder().WithScheme(setupScheme()).WithObjects(objects...).Build()
+		hostMgr, err := NewHostManager(fakeClient, GinkgoLogr, claim, fakeClient)
+		Expect(err).NotTo(HaveOccurred())
+		err = hostMgr.Associate(context.TODO())
+		ok, _ := IsRequeueAfterError(err)
+		Expect(ok).To(BeTrue())
+		condition := conditions.Get(claim, metal3api.AssociatedCondition)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(metal3api.QuotaExceededReason))
+	})
+})
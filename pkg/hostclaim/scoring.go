@@ -0,0 +1,368 @@
+/*
+Copyright 2025 The Metal3 Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostclaim
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+)
+
+const (
+	// MaxHostScore is the highest score a scoring plugin can give to a host.
+	MaxHostScore int64 = 100
+
+	// DefaultScoringProfile is the profile used when the HostClaim does not
+	// specify one.
+	DefaultScoringProfile = "default"
+)
+
+// CandidateHost is a BareMetalHost that can be bound to a HostClaim
+// together with the hardware details found during its inspection.
+type CandidateHost struct {
+	Host *metal3api.BareMetalHost
+	// Hardware is nil if the host has no HardwareData.
+	Hardware *metal3api.HardwareDetails
+}
+
+// ScoringState holds the information shared by the scoring plugins while
+// ranking the candidates of a HostClaim.
+type ScoringState struct {
+	// HostClaim is the claim being bound.
+	HostClaim *metal3api.HostClaim
+	// FailureDomainUsage is the number of BareMetalHosts bound to HostClaims
+	// of the namespace of the claim, per failure domain.
+	FailureDomainUsage map[string]int
+	// Now is the reference time used by time based plugins.
+	Now time.Time
+}
+
+// ScorePlugin ranks the candidate hosts of a HostClaim. Score returns one
+// score per candidate, in the same order, between 0 and MaxHostScore. The
+// whole list is given to the plugin so that it can normalize its scores.
+type ScorePlugin interface {
+	Name() string
+	Score(state *ScoringState, candidates []CandidateHost) []int64
+}
+
+// WeightedPlugin is a scoring plugin with its weight in a profile.
+type WeightedPlugin struct {
+	Plugin ScorePlugin
+	Weight int64
+}
+
+// ScoringProfile is a named list of weighted scoring plugins.
+type ScoringProfile []WeightedPlugin
+
+var (
+	scoringProfilesLock sync.RWMutex
+	scoringProfiles     = map[string]ScoringProfile{}
+)
+
+// RegisterScoringProfile makes a scoring profile available to HostClaims
+// under the given name.
+func RegisterScoringProfile(name string, profile ScoringProfile) {
+	scoringProfilesLock.Lock()
+	defer scoringProfilesLock.Unlock()
+	scoringProfiles[name] = profile
+}
+
+// GetScoringProfile returns the scoring profile registered under the given
+// name. An empty name selects the default profile.
+func GetScoringProfile(name string) (ScoringProfile, error) {
+	if name == "" {
+		name = DefaultScoringProfile
+	}
+	scoringProfilesLock.RLock()
+	defer scoringProfilesLock.RUnlock()
+	profile, ok := scoringProfiles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown scoring profile %q", name)
+	}
+	return profile, nil
+}
+
+func init() {
+	RegisterScoringProfile(DefaultScoringProfile, ScoringProfile{
+		{Plugin: leastExcessRAM{}, Weight: 1},
+		{Plugin: leastExcessCPU{}, Weight: 1},
+		{Plugin: preferredHardware{}, Weight: 2},
+		{Plugin: spreadFailureDomains{}, Weight: 1},
+		{Plugin: leastRecentlyCleaned{}, Weight: 1},
+	})
+	RegisterScoringProfile("binpack", ScoringProfile{
+		{Plugin: leastExcessRAM{}, Weight: 2},
+		{Plugin: leastExcessCPU{}, Weight: 2},
+		{Plugin: preferredHardware{}, Weight: 1},
+	})
+	RegisterScoringProfile("spread", ScoringProfile{
+		{Plugin: spreadFailureDomains{}, Weight: 3},
+		{Plugin: leastRecentlyCleaned{}, Weight: 1},
+		{Plugin: preferredHardware{}, Weight: 1},
+	})
+	RegisterScoringProfile("random", ScoringProfile{})
+}
+
+// HostScore is the result of the ranking of a candidate host.
+type HostScore struct {
+	Candidate CandidateHost
+	Total     int64
+	// Breakdown records the weighted score of each plugin.
+	Breakdown map[string]int64
+}
+
+// String gives a human readable breakdown of the score.
+func (s HostScore) String() string {
+	names := make([]string, 0, len(s.Breakdown))
+	for name := range s.Breakdown {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s=%d", name, s.Breakdown[name]))
+	}
+	return fmt.Sprintf("%d (%s)", s.Total, strings.Join(parts, ", "))
+}
+
+// scoreHosts ranks the candidates with the plugins of the profile and returns
+// the best one. Ties are broken randomly.
+func scoreHosts(profile ScoringProfile, state *ScoringState, candidates []CandidateHost) (*HostScore, error) {
+	if len(candidates) == 0 {
+		return nil, ErrNoAvailableBMH
+	}
+	scores := make([]HostScore, len(candidates))
+	for i, candidate := range candidates {
+		scores[i] = HostScore{Candidate: candidate, Breakdown: map[string]int64{}}
+	}
+	for _, weighted := range profile {
+		pluginScores := weighted.Plugin.Score(state, candidates)
+		if len(pluginScores) != len(candidates) {
+			return nil, fmt.Errorf("scoring plugin %s returned %d scores for %d hosts",
+				weighted.Plugin.Name(), len(pluginScores), len(candidates))
+		}
+		for i, score := range pluginScores {
+			score = max(0, min(score, MaxHostScore)) * weighted.Weight
+			scores[i].Breakdown[weighted.Plugin.Name()] = score
+			scores[i].Total += score
+		}
+	}
+
+	best := []int{}
+	for i := range scores {
+		switch {
+		case len(best) == 0 || scores[i].Total > scores[best[0]].Total:
+			best = []int{i}
+		case scores[i].Total == scores[best[0]].Total:
+			best = append(best, i)
+		default:
+		}
+	}
+	pick, err := rand.Int(rand.Reader, big.NewInt(int64(len(best))))
+	if err != nil {
+		return nil, err
+	}
+	return &scores[best[pick.Int64()]], nil
+}
+
+// normalizeLowest gives MaxHostScore to the lowest values and 0 to the highest.
+// Candidates without a value get 0.
+func normalizeLowest(values []int64, known []bool) []int64 {
+	scores := make([]int64, len(values))
+	lowest, highest := int64(0), int64(0)
+	first := true
+	for i, value := range values {
+		if !known[i] {
+			continue
+		}
+		if first || value < lowest {
+			lowest = value
+		}
+		if first || value > highest {
+			highest = value
+		}
+		first = false
+	}
+	for i, value := range values {
+		switch {
+		case !known[i]:
+			scores[i] = 0
+		case highest == lowest:
+			scores[i] = MaxHostScore
+		default:
+			scores[i] = MaxHostScore * (highest - value) / (highest - lowest)
+		}
+	}
+	return scores
+}
+
+// scoreExcess scores the excess of each candidate over the amount required
+// by the claim. A candidate with exactly the required amount gets
+// MaxHostScore, one with twice the required amount gets half of it. Without
+// requirement, the excess is the whole amount and the candidates are ranked
+// against each other. Candidates without a value get 0.
+func scoreExcess(values []int64, known []bool, required int64) []int64 {
+	if required <= 0 {
+		return normalizeLowest(values, known)
+	}
+	scores := make([]int64, len(values))
+	for i, value := range values {
+		if !known[i] {
+			continue
+		}
+		excess := max(value-required, 0)
+		scores[i] = MaxHostScore * required / (required + excess)
+	}
+	return scores
+}
+
+// hardwareRequirements returns the hardware requirements of the claim of
+// the scoring state, or empty requirements.
+func hardwareRequirements(state *ScoringState) metal3api.HardwareRequirements {
+	if state == nil || state.HostClaim == nil || state.HostClaim.Spec.HostSelector.Hardware == nil {
+		return metal3api.HardwareRequirements{}
+	}
+	return *state.HostClaim.Spec.HostSelector.Hardware
+}
+
+// leastExcessRAM favors the hosts with the least excess memory over what
+// the claim requires.
+type leastExcessRAM struct{}
+
+func (leastExcessRAM) Name() string { return "leastExcessRAM" }
+
+func (leastExcessRAM) Score(state *ScoringState, candidates []CandidateHost) []int64 {
+	values := make([]int64, len(candidates))
+	known := make([]bool, len(candidates))
+	for i, candidate := range candidates {
+		if candidate.Hardware != nil && candidate.Hardware.RAMMebibytes > 0 {
+			values[i] = int64(candidate.Hardware.RAMMebibytes)
+			known[i] = true
+		}
+	}
+	return scoreExcess(values, known, int64(hardwareRequirements(state).MinRAMMebibytes))
+}
+
+// leastExcessCPU favors the hosts with the least excess CPUs over what the
+// claim requires.
+type leastExcessCPU struct{}
+
+func (leastExcessCPU) Name() string { return "leastExcessCPU" }
+
+func (leastExcessCPU) Score(state *ScoringState, candidates []CandidateHost) []int64 {
+	values := make([]int64, len(candidates))
+	known := make([]bool, len(candidates))
+	for i, candidate := range candidates {
+		if candidate.Hardware != nil && candidate.Hardware.CPU.Count > 0 {
+			values[i] = int64(candidate.Hardware.CPU.Count)
+			known[i] = true
+		}
+	}
+	return scoreExcess(values, known, int64(hardwareRequirements(state).MinCPUCount))
+}
+
+// preferredHardware favors the hosts whose vendor and product match the
+// preferences of the HostClaim.
+type preferredHardware struct{}
+
+func (preferredHardware) Name() string { return "preferredHardware" }
+
+func (preferredHardware) Score(state *ScoringState, candidates []CandidateHost) []int64 {
+	scores := make([]int64, len(candidates))
+	scoring := state.HostClaim.Spec.Scoring
+	if scoring == nil {
+		return scores
+	}
+	for i, candidate := range candidates {
+		if candidate.Hardware == nil {
+			continue
+		}
+		vendor := candidate.Hardware.SystemVendor
+		if matchesAny(vendor.Manufacturer, scoring.PreferredVendors) {
+			scores[i] += MaxHostScore / 2
+		}
+		if matchesAny(vendor.ProductName, scoring.PreferredProducts) {
+			scores[i] += MaxHostScore / 2
+		}
+	}
+	return scores
+}
+
+// matchesAny does a case insensitive substring match of value against patterns.
+func matchesAny(value string, patterns []string) bool {
+	if value == "" {
+		return false
+	}
+	value = strings.ToLower(value)
+	return slices.ContainsFunc(patterns, func(pattern string) bool {
+		return pattern != "" && strings.Contains(value, strings.ToLower(pattern))
+	})
+}
+
+// spreadFailureDomains favors the failure domains with the fewest hosts
+// already bound to HostClaims of the same namespace.
+type spreadFailureDomains struct{}
+
+func (spreadFailureDomains) Name() string { return "spreadFailureDomains" }
+
+func (spreadFailureDomains) Score(state *ScoringState, candidates []CandidateHost) []int64 {
+	values := make([]int64, len(candidates))
+	known := make([]bool, len(candidates))
+	for i, candidate := range candidates {
+		values[i] = int64(state.FailureDomainUsage[candidate.Host.Labels[FailureDomainLabelName]])
+		known[i] = true
+	}
+	return normalizeLowest(values, known)
+}
+
+// leastRecentlyCleaned keeps the hosts that were recently deprovisioned
+// for last. Hosts never deprovisioned get the best score.
+type leastRecentlyCleaned struct{}
+
+func (leastRecentlyCleaned) Name() string { return "leastRecentlyCleaned" }
+
+func (leastRecentlyCleaned) Score(state *ScoringState, candidates []CandidateHost) []int64 {
+	values := make([]int64, len(candidates))
+	known := make([]bool, len(candidates))
+	anyCleaned := false
+	for i, candidate := range candidates {
+		known[i] = true
+		end := candidate.Host.Status.OperationHistory.Deprovision.End
+		if !end.IsZero() {
+			values[i] = int64(state.Now.Sub(end.Time).Seconds())
+			anyCleaned = true
+		}
+	}
+	if !anyCleaned {
+		return normalizeLowest(values, known)
+	}
+	// Hosts never cleaned are considered older than all the others.
+	oldest := slices.Max(values) + 1
+	for i, candidate := range candidates {
+		if candidate.Host.Status.OperationHistory.Deprovision.End.IsZero() {
+			values[i] = oldest
+		}
+		values[i] = -values[i]
+	}
+	return normalizeLowest(values, known)
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2025 The Metal3 Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostclaim
+
+import (
+	"context"
+	"time"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	. "github.com/metal3-io/baremetal-operator/internal/testutil"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Host scoring", func() {
+	now := time.Now()
+
+	candidate := func(name string, ramMiB, cpus int, vendor, zone string, cleaned time.Duration) CandidateHost {
+		builder := NewBaremetalhost(name, "ns", metal3api.StateAvailable)
+		if zone != "" {
+			builder.SetLabels(map[string]string{FailureDomainLabelName: zone})
+		}
+		host := builder.Build()
+		if cleaned != 0 {
+			host.Status.OperationHistory.Deprovision.End = metav1.NewTime(now.Add(-cleaned))
+		}
+		return CandidateHost{
+			Host: host,
+			Hardware: &metal3api.HardwareDetails{
+				RAMMebibytes: ramMiB,
+				CPU:          metal3api.CPU{Count: cpus},
+				SystemVendor: metal3api.HardwareSystemVendor{Manufacturer: vendor, ProductName: vendor + " server"},
+			},
+		}
+	}
+
+	type testCaseScore struct {
+		Profile       string
+		Scoring       *metal3api.HostScoring
+		Hardware      *metal3api.HardwareRequirements
+		Candidates    []CandidateHost
+		Usage         map[string]int
+		ExpectedHost  string
+		ExpectedError bool
+	}
+
+	DescribeTable("Test scoreHosts",
+		func(tc testCaseScore) {
+			claim := NewHostclaim(HostclaimName).Build()
+			claim.Spec.Scoring = tc.Scoring
+			claim.Spec.HostSelector.Hardware = tc.Hardware
+			profile, err := GetScoringProfile(tc.Profile)
+			if tc.ExpectedError {
+				Expect(err).To(HaveOccurred())
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			state := &ScoringState{HostClaim: claim, FailureDomainUsage: tc.Usage, Now: now}
+			best, err := scoreHosts(profile, state, tc.Candidates)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(best.Candidate.Host.Name).To(Equal(tc.ExpectedHost))
+		},
+		Entry("binpack chooses the smallest host", testCaseScore{
+			Profile: "binpack",
+			Candidates: []CandidateHost{
+				candidate("big", 512*1024, 64, "", "", 0),
+				candidate("small", 64*1024, 16, "", "", 0),
+				candidate("medium", 128*1024, 32, "", "", 0),
+			},
+			ExpectedHost: "small",
+		}),
+		Entry("excess is measured over the hardware requirements", testCaseScore{
+			Profile:  "binpack",
+			Hardware: &metal3api.HardwareRequirements{MinRAMMebibytes: 64 * 1024, MinCPUCount: 16},
+			Candidates: []CandidateHost{
+				// Exact RAM but four times the CPUs
+				candidate("many-cpus", 64*1024, 64, "", "", 0),
+				// Slightly more RAM and exact CPUs
+				candidate("more-ram", 72*1024, 16, "", "", 0),
+			},
+			ExpectedHost: "more-ram",
+		}),
+		Entry("preferred vendor wins", testCaseScore{
+			Profile: "default",
+			Scoring: &metal3api.HostScoring{PreferredVendors: []string{"dell"}, PreferredProducts: []string{"Dell Server"}},
+			Candidates: []CandidateHost{
+				candidate("hpe", 64*1024, 16, "HPE", "", 0),
+				candidate("dell", 64*1024, 16, "Dell Inc.", "", 0),
+			},
+			ExpectedHost: "dell",
+		}),
+		Entry("spread chooses the least used failure domain", testCaseScore{
+			Profile: "spread",
+			Candidates: []CandidateHost{
+				candidate("zone-a", 64*1024, 16, "", "a", 0),
+				candidate("zone-b", 64*1024, 16, "", "b", 0),
+				candidate("zone-c", 64*1024, 16, "", "c", 0),
+			},
+			Usage:        map[string]int{"a": 2, "b": 0, "c": 1},
+			ExpectedHost: "zone-b",
+		}),
+		Entry("recently cleaned hosts come last", testCaseScore{
+			Profile: "spread",
+			Candidates: []CandidateHost{
+				candidate("just-cleaned", 64*1024, 16, "", "", time.Minute),
+				candidate("cleaned-long-ago", 64*1024, 16, "", "", 24*time.Hour),
+				candidate("recently-cleaned", 64*1024, 16, "", "", time.Hour),
+			},
+			ExpectedHost: "cleaned-long-ago",
+		}),
+		Entry("never cleaned hosts come first", testCaseScore{
+			Profile: "spread",
+			Candidates: []CandidateHost{
+				candidate("cleaned", 64*1024, 16, "", "", time.Hour),
+				candidate("never-cleaned", 64*1024, 16, "", "", 0),
+			},
+			ExpectedHost: "never-cleaned",
+		}),
+		Entry("unknown profile", testCaseScore{
+			Profile:       "unknown",
+			ExpectedError: true,
+		}),
+	)
+
+	It("Records the score of the chosen host in a condition", func() {
+		bmh1 := NewBaremetalhost("bmh1", "ns", metal3api.StateAvailable).Build()
+		bmh2 := NewBaremetalhost("bmh2", "ns", metal3api.StateAvailable).Build()
+		hd1 := NewHardwareData(bmh1).Build()
+		hd1.Spec.HardwareDetails.RAMMebibytes = 128 * 1024
+		hd2 := NewHardwareData(bmh2).Build()
+		hd2.Spec.HardwareDetails.RAMMebibytes = 64 * 1024
+		claim := NewHostclaim(HostclaimName).Build()
+		claim.Spec.Scoring = &metal3api.HostScoring{Profile: "binpack"}
+		objects := []client.Object{
+			claim, bmh1, bmh2, hd1, hd2,
+			NewHostdeploypolicy("hdp", "ns").AcceptNames([]string{HostclaimNamespace}).Build(),
+			NewNamespace(HostclaimNamespace).Build(), NewNamespace("ns").Build(),
+		}
+		fakeClient := fake.NewClientBuilder().WithScheme(setupScheme()).WithObjects(objects...).Build()
+		hostMgr, err := NewHostManager(fakeClient, GinkgoLogr, claim, fakeClient)
+		Expect(err).NotTo(HaveOccurred())
+		bmh, err := hostMgr.chooseBMH(context.TODO())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bmh.Name).To(Equal("bmh2"))
+		condition := conditions.Get(claim, metal3api.HostScoredCondition)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Message).To(ContainSubstring("ns/bmh2"))
+		Expect(condition.Message).To(ContainSubstring("leastExcessRAM=200"))
+	})
+
+	It("Counts the hosts bound in each failure domain", func() {
+		zoneA := map[string]string{FailureDomainLabelName: "a"}
+		zoneB := map[string]string{FailureDomainLabelName: "b"}
+		consumer := corev1.ObjectReference{Kind: HostClaimKind, Namespace: HostclaimNamespace,
+			APIVersion: metal3api.GroupVersion.String(), Name: "other"}
+		claim := NewHostclaim(HostclaimName).Build()
+		claim.Spec.Scoring = &metal3api.HostScoring{Profile: "spread"}
+		objects := []client.Object{
+			claim,
+			NewBaremetalhost("used-a", "ns", metal3api.StateProvisioned).SetLabels(zoneA).SetConsumerRef(consumer).Build(),
+			NewBaremetalhost("free-a", "ns", metal3api.StateAvailable).SetLabels(zoneA).Build(),
+			NewBaremetalhost("free-b", "ns", metal3api.StateAvailable).SetLabels(zoneB).Build(),
+			NewHostdeploypolicy("hdp", "ns").AcceptNames([]string{HostclaimNamespace}).Build(),
+			NewNamespace(HostclaimNamespace).Build(), NewNamespace("ns").Build(),
+		}
+		fakeClient := fake.NewClientBuilder().WithScheme(setupScheme()).WithObjects(objects...).Build()
+		hostMgr, err := NewHostManager(fakeClient, GinkgoLogr, claim, fakeClient)
+		Expect(err).NotTo(HaveOccurred())
+		bmh, err := hostMgr.chooseBMH(context.TODO())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bmh.Name).To(Equal("free-b"))
+	})
+})
@@ -0,0 +1,206 @@
+/*
+Copyright 2025 The Metal3 Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package maintenancewindow computes when the recurring maintenance
+// windows of a HostUpdatePolicy are open. It is shared by the controllers
+// holding servicing and the webhook validating the policies.
+package maintenancewindow
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	// Time zones of maintenance windows must be available in minimal images.
+	_ "time/tzdata"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+)
+
+// cronSchedule is a parsed cron expression. Each field holds the allowed
+// values of the minute, hour, day of month, month and day of week.
+type cronSchedule struct {
+	minute, hour, dom, month, dow map[int]bool
+	// Like cron, days match on either field when both are restricted.
+	domAny, dowAny bool
+	location       *time.Location
+}
+
+var cronFields = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 6},
+}
+
+// parseCronSchedule parses a schedule in the standard cron format in the
+// given time zone, UTC when empty.
+func parseCronSchedule(schedule, timeZone string) (*cronSchedule, error) {
+	fields := strings.Fields(schedule)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("schedule %q must have %d fields", schedule, len(cronFields))
+	}
+
+	values := make([]map[int]bool, len(fields))
+	for i, field := range fields {
+		var err error
+		values[i], err = parseCronField(field, cronFields[i].min, cronFields[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s in schedule %q: %w", cronFields[i].name, schedule, err)
+		}
+	}
+
+	location := time.UTC
+	if timeZone != "" {
+		var err error
+		if location, err = time.LoadLocation(timeZone); err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %w", timeZone, err)
+		}
+	}
+
+	return &cronSchedule{
+		minute:   values[0],
+		hour:     values[1],
+		dom:      values[2],
+		month:    values[3],
+		dow:      values[4],
+		domAny:   fields[2] == "*",
+		dowAny:   fields[4] == "*",
+		location: location,
+	}, nil
+}
+
+// parseCronField reads a comma-separated list of values, ranges and
+// steps, e.g. "1,15", "1-5" or "*/10".
+func parseCronField(field string, minValue, maxValue int) (map[int]bool, error) {
+	values := map[int]bool{}
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step < 1 {
+				return nil, fmt.Errorf("invalid step %q", stepPart)
+			}
+		}
+
+		first, last := minValue, maxValue
+		if rangePart != "*" {
+			firstPart, lastPart, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if first, err = strconv.Atoi(firstPart); err != nil {
+				return nil, fmt.Errorf("invalid value %q", firstPart)
+			}
+			last = first
+			if isRange {
+				if last, err = strconv.Atoi(lastPart); err != nil {
+					return nil, fmt.Errorf("invalid value %q", lastPart)
+				}
+			} else if hasStep {
+				last = maxValue
+			}
+		}
+		if first < minValue || last > maxValue || first > last {
+			return nil, fmt.Errorf("%q is out of the range %d-%d", rangePart, minValue, maxValue)
+		}
+
+		for value := first; value <= last; value += step {
+			values[value] = true
+		}
+	}
+	return values, nil
+}
+
+func (s *cronSchedule) matchesDay(t time.Time) bool {
+	domMatch := s.dom[t.Day()]
+	dowMatch := s.dow[int(t.Weekday())]
+	switch {
+	case s.domAny:
+		return dowMatch
+	case s.dowAny:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
+
+// next returns the first time matching the schedule at or after t, or
+// the zero time if there is none within five years.
+func (s *cronSchedule) next(t time.Time) time.Time {
+	t = t.In(s.location)
+	if rounded := t.Truncate(time.Minute); !rounded.Equal(t) {
+		t = rounded.Add(time.Minute)
+	}
+
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if !s.month[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.location)
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.location)
+			continue
+		}
+		if !s.hour[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, s.location)
+			continue
+		}
+		if !s.minute[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// Status tells whether one of the windows is open at the given time and,
+// if not, when the next one opens. Servicing may start at any time
+// without windows.
+func Status(windows []metal3api.MaintenanceWindow, now time.Time) (open bool, nextOpen time.Time, err error) {
+	if len(windows) == 0 {
+		return true, time.Time{}, nil
+	}
+
+	for _, window := range windows {
+		schedule, err := parseCronSchedule(window.Schedule, window.TimeZone)
+		if err != nil {
+			return false, time.Time{}, err
+		}
+
+		// A window is open if it started less than its duration ago.
+		start := schedule.next(now.Add(-window.Duration.Duration).Add(time.Nanosecond))
+		if !start.IsZero() && !start.After(now) {
+			return true, time.Time{}, nil
+		}
+		if start = schedule.next(now); !start.IsZero() && (nextOpen.IsZero() || start.Before(nextOpen)) {
+			nextOpen = start
+		}
+	}
+	return false, nextOpen, nil
+}
+
+// Validate checks the schedule and time zone of a window.
+func Validate(window metal3api.MaintenanceWindow) error {
+	_, err := parseCronSchedule(window.Schedule, window.TimeZone)
+	return err
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2025 The Metal3 Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenancewindow
+
+import (
+	"testing"
+	"time"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	// A Wednesday
+	start := time.Date(2025, time.January, 1, 10, 30, 15, 0, time.UTC)
+
+	testCases := []struct {
+		Scenario string
+		Schedule string
+		TimeZone string
+		Expected time.Time
+	}{
+		{
+			Scenario: "every minute",
+			Schedule: "* * * * *",
+			Expected: time.Date(2025, time.January, 1, 10, 31, 0, 0, time.UTC),
+		},
+		{
+			Scenario: "daily",
+			Schedule: "0 2 * * *",
+			Expected: time.Date(2025, time.January, 2, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			Scenario: "weekly on Saturday",
+			Schedule: "30 22 * * 6",
+			Expected: time.Date(2025, time.January, 4, 22, 30, 0, 0, time.UTC),
+		},
+		{
+			Scenario: "steps and ranges",
+			Schedule: "*/20 9-17 * * 1-5",
+			Expected: time.Date(2025, time.January, 1, 10, 40, 0, 0, time.UTC),
+		},
+		{
+			Scenario: "day of month or day of week",
+			Schedule: "0 0 15 * 5",
+			Expected: time.Date(2025, time.January, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Scenario: "month list",
+			Schedule: "0 0 1 3,6 *",
+			Expected: time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Scenario: "time zone",
+			Schedule: "0 2 * * *",
+			TimeZone: "Asia/Kolkata",
+			Expected: time.Date(2025, time.January, 1, 20, 30, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			schedule, err := parseCronSchedule(tc.Schedule, tc.TimeZone)
+			require.NoError(t, err)
+			assert.Equal(t, tc.Expected, schedule.next(start).UTC())
+		})
+	}
+}
+
+func TestParseCronScheduleInvalid(t *testing.T) {
+	for _, schedule := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "a * * * *"} {
+		_, err := parseCronSchedule(schedule, "")
+		assert.Error(t, err, schedule)
+	}
+	_, err := parseCronSchedule("* * * * *", "Mars/Olympus_Mons")
+	assert.Error(t, err)
+}
+
+func TestMaintenanceWindowStatus(t *testing.T) {
+	windows := []metal3api.MaintenanceWindow{
+		{Schedule: "0 2 * * 6", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+		{Schedule: "0 22 * * 3", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "Europe/Paris"},
+	}
+
+	testCases := []struct {
+		Scenario         string
+		Now              time.Time
+		ExpectedOpen     bool
+		ExpectedNextOpen time.Time
+	}{
+		{
+			Scenario:         "before the windows",
+			Now:              time.Date(2025, time.January, 1, 10, 0, 0, 0, time.UTC),
+			ExpectedNextOpen: time.Date(2025, time.January, 1, 21, 0, 0, 0, time.UTC),
+		},
+		{
+			Scenario:     "in the window of another time zone",
+			Now:          time.Date(2025, time.January, 1, 21, 30, 0, 0, time.UTC),
+			ExpectedOpen: true,
+		},
+		{
+			Scenario:     "in a window",
+			Now:          time.Date(2025, time.January, 4, 5, 59, 0, 0, time.UTC),
+			ExpectedOpen: true,
+		},
+		{
+			Scenario:         "after a window",
+			Now:              time.Date(2025, time.January, 4, 6, 0, 0, 0, time.UTC),
+			ExpectedNextOpen: time.Date(2025, time.January, 8, 21, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			open, nextOpen, err := Status(windows, tc.Now)
+			require.NoError(t, err)
+			assert.Equal(t, tc.ExpectedOpen, open)
+			assert.True(t, tc.ExpectedNextOpen.Equal(nextOpen), "next window at %s", nextOpen)
+		})
+	}
+}
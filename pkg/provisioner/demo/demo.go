@@ -324,6 +324,10 @@ func (p *demoProvisioner) RemoveBMCEventSubscriptionForNode(_ context.Context, _
 	return result, nil
 }
 
+func (p *demoProvisioner) CheckBMCEventSubscriptionForNode(_ context.Context, _ metal3api.BMCEventSubscription) (exists bool, err error) {
+	return true, nil
+}
+
 func (p *demoProvisioner) GetFirmwareComponents(_ context.Context) (components []metal3api.FirmwareComponentStatus, err error) {
 	return components, nil
 }
@@ -344,6 +348,10 @@ func (p *demoProvisioner) HasPowerFailure(_ context.Context) bool {
 	return false
 }
 
+func (p *demoProvisioner) GetDiskErasure(_ context.Context) (*metal3api.DiskErasure, error) {
+	return nil, nil
+}
+
 func (p *demoProvisioner) GetHealth(_ context.Context) string {
 	return ""
 }
@@ -2,6 +2,8 @@ package fixture
 
 import (
 	"context"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -105,6 +107,13 @@ type Fixture struct {
 	PowerFailed bool
 
 	Health string
+
+	// BMCEventSubscriptions lists the IDs of the subscriptions existing on the BMC
+	BMCEventSubscriptions []string
+	// BMCEventSubscriptionRemoveError is returned when removing a subscription
+	BMCEventSubscriptionRemoveError error
+
+	subscriptionCounter int
 }
 
 // NewProvisioner returns a new Fixture Provisioner.
@@ -409,14 +418,27 @@ func (p *fixtureProvisioner) GetFirmwareSettings(_ context.Context, _ bool) (set
 	return p.state.HostFirmwareSettings.Settings, p.state.HostFirmwareSettings.Schema, nil
 }
 
-func (p *fixtureProvisioner) AddBMCEventSubscriptionForNode(_ context.Context, _ *metal3api.BMCEventSubscription, _ provisioner.HTTPHeaders) (result provisioner.Result, err error) {
+func (p *fixtureProvisioner) AddBMCEventSubscriptionForNode(_ context.Context, subscription *metal3api.BMCEventSubscription, _ provisioner.HTTPHeaders) (result provisioner.Result, err error) {
+	p.state.subscriptionCounter++
+	subscription.Status.SubscriptionID = fmt.Sprintf("subscription-%d", p.state.subscriptionCounter)
+	p.state.BMCEventSubscriptions = append(p.state.BMCEventSubscriptions, subscription.Status.SubscriptionID)
 	return result, nil
 }
 
-func (p *fixtureProvisioner) RemoveBMCEventSubscriptionForNode(_ context.Context, _ metal3api.BMCEventSubscription) (result provisioner.Result, err error) {
+func (p *fixtureProvisioner) RemoveBMCEventSubscriptionForNode(_ context.Context, subscription metal3api.BMCEventSubscription) (result provisioner.Result, err error) {
+	if p.state.BMCEventSubscriptionRemoveError != nil {
+		return result, p.state.BMCEventSubscriptionRemoveError
+	}
+	p.state.BMCEventSubscriptions = slices.DeleteFunc(p.state.BMCEventSubscriptions, func(id string) bool {
+		return id == subscription.Status.SubscriptionID
+	})
 	return result, nil
 }
 
+func (p *fixtureProvisioner) CheckBMCEventSubscriptionForNode(_ context.Context, subscription metal3api.BMCEventSubscription) (exists bool, err error) {
+	return slices.Contains(p.state.BMCEventSubscriptions, subscription.Status.SubscriptionID), nil
+}
+
 func (p *fixtureProvisioner) GetFirmwareComponents(_ context.Context) (components []metal3api.FirmwareComponentStatus, err error) {
 	p.log.Info("getting Firmware components")
 	return p.state.HostFirmwareComponents.Components, nil
@@ -438,6 +460,10 @@ func (p *fixtureProvisioner) HasPowerFailure(_ context.Context) bool {
 	return p.state != nil && p.state.PowerFailed
 }
 
+func (p *fixtureProvisioner) GetDiskErasure(_ context.Context) (*metal3api.DiskErasure, error) {
+	return nil, nil
+}
+
 func (p *fixtureProvisioner) GetHealth(_ context.Context) string {
 	if p.state == nil {
 		return ""
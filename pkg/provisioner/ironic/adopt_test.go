@@ -115,6 +115,17 @@ func TestAdopt(t *testing.T) {
 				Fault:          "power fault",
 			}),
 
+			expectedDirty: false,
+		},
+		{
+			name: "node-in-network-Maintenance",
+			ironic: testserver.NewIronic(t).Node(nodes.Node{
+				ProvisionState:    string(nodes.Active),
+				UUID:              nodeUUID,
+				Maintenance:       true,
+				MaintenanceReason: networkMaintenanceReason,
+			}),
+
 			expectedDirty: false,
 		},
 	}
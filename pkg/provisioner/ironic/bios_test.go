@@ -77,6 +77,7 @@ func TestGetFirmwareSettings(t *testing.T) {
 					MaxLength:       nil,
 					ReadOnly:        &iFalse,
 					Unique:          nil,
+					ResetRequired:   &iTrue,
 				},
 			},
 			ironic:        testserver.NewIronic(t).BIOSDetailSettings(nodeUUID),
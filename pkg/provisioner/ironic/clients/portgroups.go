@@ -0,0 +1,65 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack/baremetal/v1/ports"
+)
+
+// PortGroup is an Ironic port group, aggregating ports of a node. The
+// baremetal API of gophercloud has no support for port groups yet.
+type PortGroup struct {
+	UUID                     string         `json:"uuid,omitempty"`
+	Name                     string         `json:"name,omitempty"`
+	NodeUUID                 string         `json:"node_uuid,omitempty"`
+	Address                  string         `json:"address,omitempty"`
+	Mode                     string         `json:"mode,omitempty"`
+	StandalonePortsSupported *bool          `json:"standalone_ports_supported,omitempty"`
+	Extra                    map[string]any `json:"extra,omitempty"`
+}
+
+// ListPortGroups returns the port groups of a node.
+func ListPortGroups(ctx context.Context, client *gophercloud.ServiceClient, nodeUUID string) ([]PortGroup, error) {
+	var result struct {
+		PortGroups []PortGroup `json:"portgroups"`
+	}
+	query := url.Values{"node": {nodeUUID}}
+	_, err := client.Get(ctx, client.ServiceURL("portgroups", "detail")+"?"+query.Encode(), &result, nil)
+	return result.PortGroups, err
+}
+
+// CreatePortGroup creates a port group and returns it.
+func CreatePortGroup(ctx context.Context, client *gophercloud.ServiceClient, group PortGroup) (*PortGroup, error) {
+	var result PortGroup
+	_, err := client.Post(ctx, client.ServiceURL("portgroups"), group, &result, &gophercloud.RequestOpts{
+		OkCodes: []int{http.StatusCreated},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// UpdatePortGroup applies a JSON patch to a port group. The operations are
+// the same as for ports.
+func UpdatePortGroup(ctx context.Context, client *gophercloud.ServiceClient, uuid string, updates ports.UpdateOpts) error {
+	body := make([]map[string]any, len(updates))
+	for i, patch := range updates {
+		body[i] = patch.ToPortUpdateMap()
+	}
+	_, err := client.Patch(ctx, client.ServiceURL("portgroups", uuid), body, nil, &gophercloud.RequestOpts{
+		OkCodes: []int{http.StatusOK},
+	})
+	return err
+}
+
+// DeletePortGroup deletes a port group.
+func DeletePortGroup(ctx context.Context, client *gophercloud.ServiceClient, uuid string) error {
+	_, err := client.Delete(ctx, client.ServiceURL("portgroups", uuid), &gophercloud.RequestOpts{
+		OkCodes: []int{http.StatusNoContent},
+	})
+	return err
+}
@@ -195,3 +195,8 @@ func (nu *NodeUpdater) SetDriverInfoOpts(settings UpdateOptsData, node *nodes.No
 	nu.setSectionUpdateOpts(node.DriverInfo, settings, "/driver_info")
 	return nu
 }
+
+func (nu *NodeUpdater) SetExtraOpts(settings UpdateOptsData, node *nodes.Node) *NodeUpdater {
+	nu.setSectionUpdateOpts(node.Extra, settings, "/extra")
+	return nu
+}
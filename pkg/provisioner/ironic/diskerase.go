@@ -0,0 +1,269 @@
+package ironic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack/baremetal/v1/nodes"
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner/ironic/clients"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner/ironic/hardwaredetails"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// diskEraseKey is the key of the extra field of the node recording the
+// progress of the disk erasure.
+const diskEraseKey = "metal3_disk_erase"
+
+// diskEraseMethod is how a cleaning mode erases the disks of a node.
+type diskEraseMethod struct {
+	// steps are the manual clean steps erasing the disks.
+	steps []nodes.CleanStep
+	// diskTypes are the types of disks the steps erase with the method,
+	// all of them when empty. Nodes with other disks are not erased.
+	diskTypes []metal3api.DiskType
+}
+
+// diskEraseMethods are the methods of the cleaning modes erasing the disks.
+var diskEraseMethods = map[metal3api.AutomatedCleaningMode]diskEraseMethod{
+	// The agent overwrites the disks, unless Ironic is configured to use
+	// their secure erase.
+	metal3api.CleaningModeFull: {
+		steps: []nodes.CleanStep{{Interface: nodes.InterfaceDeploy, Step: "erase_devices"}},
+	},
+	// The hardware-assisted erase of the agent uses the ATA secure erase
+	// of the SSDs and the NVMe format of the NVMe drives. It only erases
+	// the metadata of the rotational disks.
+	metal3api.CleaningModeSecure: {
+		steps:     []nodes.CleanStep{{Interface: nodes.InterfaceDeploy, Step: "erase_devices_express"}},
+		diskTypes: []metal3api.DiskType{metal3api.SSD, metal3api.NVME},
+	},
+	// The NVMe format of the agent uses the cryptographic erase of the
+	// drives, the other disks have no cryptographic erase.
+	metal3api.CleaningModeCrypto: {
+		steps:     []nodes.CleanStep{{Interface: nodes.InterfaceDeploy, Step: "erase_devices_express"}},
+		diskTypes: []metal3api.DiskType{metal3api.NVME},
+	},
+}
+
+// diskErase is the progress of the disk erasure of a node.
+type diskErase struct {
+	// Method is the cleaning mode erasing the disks.
+	Method string
+	// Since is the time of the last provision state change of the node
+	// when the erasure was requested.
+	Since string
+	// Steps are the clean steps requested to erase the disks.
+	Steps []string
+	// Disks are the inspected disks of the node when the erasure was
+	// requested.
+	Disks []metal3api.ErasedDisk
+	// Completed is set once the disks are erased.
+	Completed bool
+	// CompletedAt is the time the node came back from the manual
+	// cleaning.
+	CompletedAt string
+}
+
+func getDiskErase(ironicNode *nodes.Node) (erase diskErase, found bool) {
+	value, found := ironicNode.Extra[diskEraseKey].(map[string]any)
+	if !found {
+		return erase, false
+	}
+	erase.Method, _ = value["method"].(string)
+	erase.Since, _ = value["since"].(string)
+	erase.Completed, _ = value["completed"].(bool)
+	erase.CompletedAt, _ = value["completedAt"].(string)
+	steps, _ := value["steps"].([]any)
+	for _, step := range steps {
+		if name, ok := step.(string); ok {
+			erase.Steps = append(erase.Steps, name)
+		}
+	}
+	disks, _ := value["disks"].([]any)
+	for _, disk := range disks {
+		if fields, ok := disk.(map[string]any); ok {
+			erased := metal3api.ErasedDisk{}
+			erased.Name, _ = fields["name"].(string)
+			diskType, _ := fields["type"].(string)
+			erased.Type = metal3api.DiskType(diskType)
+			erased.SerialNumber, _ = fields["serialNumber"].(string)
+			erase.Disks = append(erase.Disks, erased)
+		}
+	}
+	return erase, true
+}
+
+// setDiskErase records the progress of the disk erasure in the node, or
+// removes it when erase is nil.
+func (p *ironicProvisioner) setDiskErase(ctx context.Context, ironicNode *nodes.Node, erase *diskErase) (result provisioner.Result, err error) {
+	var value any
+	if erase != nil {
+		fields := map[string]any{
+			"method":    erase.Method,
+			"since":     erase.Since,
+			"steps":     erase.Steps,
+			"completed": erase.Completed,
+		}
+		if erase.CompletedAt != "" {
+			fields["completedAt"] = erase.CompletedAt
+		}
+		if len(erase.Disks) > 0 {
+			disks := make([]map[string]any, 0, len(erase.Disks))
+			for _, disk := range erase.Disks {
+				disks = append(disks, map[string]any{
+					"name":         disk.Name,
+					"type":         string(disk.Type),
+					"serialNumber": disk.SerialNumber,
+				})
+			}
+			fields["disks"] = disks
+		}
+		value = fields
+	}
+
+	updater := clients.UpdateOptsBuilder(p.log)
+	updater.SetExtraOpts(clients.UpdateOptsData{diskEraseKey: value}, ironicNode)
+	_, success, result, err := p.tryUpdateNode(ctx, ironicNode, updater)
+	if !success {
+		return result, err
+	}
+	return operationContinuing(0)
+}
+
+// diskEraseCompleted returns whether the disks of the node have been erased
+// with the cleaning mode.
+func diskEraseCompleted(ironicNode *nodes.Node, automatedCleaningMode metal3api.AutomatedCleaningMode) bool {
+	erase, found := getDiskErase(ironicNode)
+	return found && erase.Completed && erase.Method == string(automatedCleaningMode)
+}
+
+// eraseDisks erases the disks of a manageable node with a manual cleaning.
+// The time of the last provision state change is recorded when the erasure
+// is requested, so that a node coming back to manageable after the cleaning
+// can be told apart from one which has not been cleaned yet.
+func (p *ironicProvisioner) eraseDisks(ctx context.Context, ironicNode *nodes.Node, automatedCleaningMode metal3api.AutomatedCleaningMode) (done bool, result provisioner.Result, err error) {
+	since := ironicNode.ProvisionUpdatedAt.UTC().Format(time.RFC3339Nano)
+	erase, found := getDiskErase(ironicNode)
+
+	switch {
+	case found && erase.Method == string(automatedCleaningMode) && erase.Completed:
+		return true, result, nil
+
+	case !found || erase.Method != string(automatedCleaningMode):
+		method := diskEraseMethods[automatedCleaningMode]
+		var disks []metal3api.ErasedDisk
+		disks, err = p.inventoryDisks(ctx, ironicNode)
+		if err != nil {
+			result, err = transientError(err)
+			return false, result, err
+		}
+		if message := unsupportedDisksMessage(method, disks, automatedCleaningMode); message != "" {
+			p.log.Info("cannot erase disks", "method", automatedCleaningMode, "reason", message)
+			result, err = operationFailed(message)
+			return false, result, err
+		}
+		p.log.Info("requesting disk erasure", "method", automatedCleaningMode)
+		erase = diskErase{
+			Method: string(automatedCleaningMode),
+			Since:  since,
+			Disks:  disks,
+		}
+		for _, step := range method.steps {
+			erase.Steps = append(erase.Steps, step.Step)
+		}
+		result, err = p.setDiskErase(ctx, ironicNode, &erase)
+
+	case erase.Since == since:
+		p.log.Info("erasing disks", "method", automatedCleaningMode)
+		p.publisher("DiskErasureStarted", fmt.Sprintf("Disk erasure started with method %s", automatedCleaningMode))
+		result, err = p.changeNodeProvisionState(ctx, ironicNode,
+			nodes.ProvisionStateOpts{
+				Target:     nodes.TargetClean,
+				CleanSteps: diskEraseMethods[automatedCleaningMode].steps,
+			},
+		)
+
+	default:
+		// The node went through the manual cleaning and is manageable
+		// again, a failed clean step would have left it in clean failed.
+		p.log.Info("disk erasure completed", "method", automatedCleaningMode, "steps", erase.Steps, "disks", len(erase.Disks))
+		p.publisher("DiskErasureComplete", fmt.Sprintf("Disk erasure completed with method %s", automatedCleaningMode))
+		erase.Completed = true
+		erase.CompletedAt = since
+		result, err = p.setDiskErase(ctx, ironicNode, &erase)
+	}
+	return false, result, err
+}
+
+// GetDiskErasure returns the last disk erasure completed by the manual
+// cleaning of the node, or nil if there is none.
+func (p *ironicProvisioner) GetDiskErasure(ctx context.Context) (*metal3api.DiskErasure, error) {
+	ironicNode, err := p.getNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	erase, found := getDiskErase(ironicNode)
+	if !found || !erase.Completed {
+		return nil, nil
+	}
+	completedAt, err := time.Parse(time.RFC3339Nano, erase.CompletedAt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid completion time of the disk erasure: %w", err)
+	}
+	return &metal3api.DiskErasure{
+		Method:      metal3api.AutomatedCleaningMode(erase.Method),
+		Steps:       erase.Steps,
+		Disks:       erase.Disks,
+		CompletedAt: metav1.NewTime(completedAt),
+	}, nil
+}
+
+// inventoryDisks returns the disks of the last inspection of the node, or
+// none if it was not inspected.
+func (p *ironicProvisioner) inventoryDisks(ctx context.Context, ironicNode *nodes.Node) ([]metal3api.ErasedDisk, error) {
+	inventory, err := nodes.GetInventory(ctx, p.client, ironicNode.UUID).Extract()
+	if err != nil {
+		if gophercloud.ResponseCodeIs(err, http.StatusNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to retrieve the inventory of the disks: %w", err)
+	}
+	details := hardwaredetails.GetHardwareDetails(inventory, p.log)
+	disks := make([]metal3api.ErasedDisk, 0, len(details.Storage))
+	for _, storage := range details.Storage {
+		disks = append(disks, metal3api.ErasedDisk{
+			Name:         storage.Name,
+			Type:         storage.Type,
+			SerialNumber: storage.SerialNumber,
+		})
+	}
+	return disks, nil
+}
+
+// unsupportedDisksMessage explains why the method cannot erase the disks
+// of a node, or returns an empty string if it can.
+func unsupportedDisksMessage(method diskEraseMethod, disks []metal3api.ErasedDisk, automatedCleaningMode metal3api.AutomatedCleaningMode) string {
+	if len(method.diskTypes) == 0 {
+		return ""
+	}
+	if len(disks) == 0 {
+		return fmt.Sprintf("the disks of the host must be inspected to erase them with method %s", automatedCleaningMode)
+	}
+	var unsupported []string
+	for _, disk := range disks {
+		if !slices.Contains(method.diskTypes, disk.Type) {
+			unsupported = append(unsupported, fmt.Sprintf("%s (%s)", disk.Name, disk.Type))
+		}
+	}
+	if len(unsupported) > 0 {
+		return fmt.Sprintf("method %s cannot erase disks %s", automatedCleaningMode, strings.Join(unsupported, ", "))
+	}
+	return ""
+}
@@ -0,0 +1,287 @@
+package ironic
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2/openstack/baremetal/inventory"
+	"github.com/gophercloud/gophercloud/v2/openstack/baremetal/v1/nodes"
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/hardwareutils/bmc"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner/ironic/clients"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner/ironic/testserver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestDeprovisionDiskErase(t *testing.T) {
+	nodeUUID := "33ce8659-7400-4c68-9535-d10766f07a58"
+	provisionUpdatedAt := time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC)
+	since := provisionUpdatedAt.Format(time.RFC3339Nano)
+	earlier := provisionUpdatedAt.Add(-time.Hour).Format(time.RFC3339Nano)
+
+	cases := []struct {
+		name                  string
+		provisionState        nodes.ProvisionState
+		automatedCleaningMode metal3api.AutomatedCleaningMode
+		restartOnFailure      bool
+		extra                 map[string]any
+		disks                 []inventory.RootDiskType
+		expectedDirty         bool
+		expectedError         string
+		expectedTarget        nodes.TargetProvisionState
+		expectedCleanSteps    []nodes.CleanStep
+		expectedErase         any
+		expectedEraseRemoved  bool
+	}{
+		{
+			name:                  "available without erasure",
+			provisionState:        nodes.Available,
+			automatedCleaningMode: metal3api.CleaningModeFull,
+			expectedDirty:         true,
+			expectedTarget:        nodes.TargetManage,
+		},
+		{
+			name:                  "available with erasure completed",
+			provisionState:        nodes.Available,
+			automatedCleaningMode: metal3api.CleaningModeFull,
+			extra: map[string]any{
+				diskEraseKey: map[string]any{"method": "full", "since": since, "completed": true},
+			},
+			expectedDirty: false,
+		},
+		{
+			name:                  "available with erasure completed by another method",
+			provisionState:        nodes.Available,
+			automatedCleaningMode: metal3api.CleaningModeSecure,
+			extra: map[string]any{
+				diskEraseKey: map[string]any{"method": "full", "since": since, "completed": true},
+			},
+			expectedDirty:  true,
+			expectedTarget: nodes.TargetManage,
+		},
+		{
+			name:                  "available with metadata cleaning",
+			provisionState:        nodes.Available,
+			automatedCleaningMode: metal3api.CleaningModeMetadata,
+			expectedDirty:         false,
+		},
+		{
+			name:                  "manageable requests erasure",
+			provisionState:        nodes.Manageable,
+			automatedCleaningMode: metal3api.CleaningModeSecure,
+			disks: []inventory.RootDiskType{
+				{Name: "/dev/nvme0n1", Serial: "nvme-1"},
+				{Name: "/dev/sda", Serial: "ssd-1"},
+			},
+			expectedDirty: true,
+			expectedErase: map[string]any{
+				"method": "secure", "since": since, "steps": []any{"erase_devices_express"}, "completed": false,
+				"disks": []any{
+					map[string]any{"name": "/dev/nvme0n1", "type": "NVME", "serialNumber": "nvme-1"},
+					map[string]any{"name": "/dev/sda", "type": "SSD", "serialNumber": "ssd-1"},
+				},
+			},
+		},
+		{
+			name:                  "manageable requests full erasure without inspection",
+			provisionState:        nodes.Manageable,
+			automatedCleaningMode: metal3api.CleaningModeFull,
+			expectedDirty:         true,
+			expectedErase: map[string]any{
+				"method": "full", "since": since, "steps": []any{"erase_devices"}, "completed": false,
+			},
+		},
+		{
+			name:                  "manageable rejects secure erasure without inspection",
+			provisionState:        nodes.Manageable,
+			automatedCleaningMode: metal3api.CleaningModeSecure,
+			expectedError:         "the disks of the host must be inspected to erase them with method secure",
+		},
+		{
+			name:                  "manageable rejects crypto erasure of other disks",
+			provisionState:        nodes.Manageable,
+			automatedCleaningMode: metal3api.CleaningModeCrypto,
+			disks: []inventory.RootDiskType{
+				{Name: "/dev/nvme0n1"},
+				{Name: "/dev/sda", Rotational: true},
+				{Name: "/dev/sdb"},
+			},
+			expectedError: "method crypto cannot erase disks /dev/sda (HDD), /dev/sdb (SSD)",
+		},
+		{
+			name:                  "manageable starts erasure",
+			provisionState:        nodes.Manageable,
+			automatedCleaningMode: metal3api.CleaningModeSecure,
+			extra: map[string]any{
+				diskEraseKey: map[string]any{"method": "secure", "since": since, "completed": false},
+			},
+			expectedDirty:  true,
+			expectedTarget: nodes.TargetClean,
+			expectedCleanSteps: []nodes.CleanStep{
+				{Interface: nodes.InterfaceDeploy, Step: "erase_devices_express"},
+			},
+		},
+		{
+			name:                  "manageable after erasure",
+			provisionState:        nodes.Manageable,
+			automatedCleaningMode: metal3api.CleaningModeFull,
+			extra: map[string]any{
+				diskEraseKey: map[string]any{"method": "full", "since": earlier, "steps": []any{"erase_devices"}, "completed": false},
+			},
+			expectedDirty: true,
+			expectedErase: map[string]any{
+				"method": "full", "since": earlier, "steps": []any{"erase_devices"}, "completed": true, "completedAt": since,
+			},
+		},
+		{
+			name:                  "manageable with erasure completed",
+			provisionState:        nodes.Manageable,
+			automatedCleaningMode: metal3api.CleaningModeFull,
+			extra: map[string]any{
+				diskEraseKey: map[string]any{"method": "full", "since": earlier, "completed": true},
+			},
+			expectedDirty:  true,
+			expectedTarget: nodes.TargetProvide,
+		},
+		{
+			name:                  "active forgets previous erasure",
+			provisionState:        nodes.Active,
+			automatedCleaningMode: metal3api.CleaningModeFull,
+			extra: map[string]any{
+				diskEraseKey: map[string]any{"method": "full", "since": earlier, "completed": true},
+			},
+			expectedDirty:        true,
+			expectedEraseRemoved: true,
+		},
+		{
+			name:                  "clean fail forgets failed erasure",
+			provisionState:        nodes.CleanFail,
+			automatedCleaningMode: metal3api.CleaningModeFull,
+			restartOnFailure:      true,
+			extra: map[string]any{
+				diskEraseKey: map[string]any{"method": "full", "since": earlier, "completed": false},
+			},
+			expectedDirty:        true,
+			expectedEraseRemoved: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ironic := testserver.NewIronic(t).WithDefaultResponses().Node(nodes.Node{
+				ProvisionState:     string(tc.provisionState),
+				UUID:               nodeUUID,
+				AutomatedClean:     ptr.To(true),
+				Extra:              tc.extra,
+				ProvisionUpdatedAt: provisionUpdatedAt,
+			})
+			if tc.disks != nil {
+				ironic.WithInventory(nodeUUID, nodes.InventoryData{
+					Inventory: inventory.InventoryType{Disks: tc.disks},
+				})
+			} else {
+				ironic.WithInventoryFailed(nodeUUID, http.StatusNotFound)
+			}
+			ironic.Start()
+			defer ironic.Stop()
+
+			host := makeHost()
+			host.Status.Provisioning.ID = nodeUUID
+			publisher := func(reason, message string) {}
+			auth := clients.AuthConfig{Type: clients.NoAuth}
+			prov, err := newProvisionerWithSettings(host, bmc.Credentials{}, publisher, ironic.Endpoint(), auth)
+			require.NoError(t, err)
+
+			result, err := prov.Deprovision(t.Context(), tc.restartOnFailure, tc.automatedCleaningMode)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedDirty, result.Dirty)
+			assert.Equal(t, tc.expectedError, result.ErrorMessage)
+
+			stateUpdate := ironic.GetLastNodeStatesProvisionUpdateRequestFor(nodeUUID)
+			assert.Equal(t, tc.expectedTarget, stateUpdate.Target)
+			assert.Equal(t, tc.expectedCleanSteps, stateUpdate.CleanSteps)
+
+			updates := ironic.GetLastNodeUpdateRequestFor(nodeUUID)
+			switch {
+			case tc.expectedErase != nil:
+				require.Len(t, updates, 1)
+				assert.Equal(t, "/extra/"+diskEraseKey, updates[0].Path)
+				assert.Equal(t, tc.expectedErase, updates[0].Value)
+			case tc.expectedEraseRemoved:
+				require.Len(t, updates, 1)
+				assert.Equal(t, "/extra/"+diskEraseKey, updates[0].Path)
+				assert.Equal(t, nodes.RemoveOp, updates[0].Op)
+			default:
+				assert.Empty(t, updates)
+			}
+		})
+	}
+}
+
+func TestGetDiskErasure(t *testing.T) {
+	nodeUUID := "33ce8659-7400-4c68-9535-d10766f07a58"
+	completedAt := time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		extra    map[string]any
+		expected *metal3api.DiskErasure
+	}{
+		{
+			name: "no erasure",
+		},
+		{
+			name: "erasure running",
+			extra: map[string]any{
+				diskEraseKey: map[string]any{"method": "secure", "steps": []any{"erase_devices"}, "completed": false},
+			},
+		},
+		{
+			name: "erasure completed",
+			extra: map[string]any{
+				diskEraseKey: map[string]any{
+					"method": "secure", "steps": []any{"erase_devices_express"}, "completed": true,
+					"completedAt": completedAt.Format(time.RFC3339Nano),
+					"disks": []any{
+						map[string]any{"name": "/dev/nvme0n1", "type": "NVME", "serialNumber": "nvme-1"},
+					},
+				},
+			},
+			expected: &metal3api.DiskErasure{
+				Method: metal3api.CleaningModeSecure,
+				Steps:  []string{"erase_devices_express"},
+				Disks: []metal3api.ErasedDisk{
+					{Name: "/dev/nvme0n1", Type: metal3api.NVME, SerialNumber: "nvme-1"},
+				},
+				CompletedAt: metav1.NewTime(completedAt),
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ironic := testserver.NewIronic(t).WithDefaultResponses().Node(nodes.Node{
+				ProvisionState: string(nodes.Available),
+				UUID:           nodeUUID,
+				Extra:          tc.extra,
+			})
+			ironic.Start()
+			defer ironic.Stop()
+
+			host := makeHost()
+			host.Status.Provisioning.ID = nodeUUID
+			publisher := func(reason, message string) {}
+			auth := clients.AuthConfig{Type: clients.NoAuth}
+			prov, err := newProvisionerWithSettings(host, bmc.Credentials{}, publisher, ironic.Endpoint(), auth)
+			require.NoError(t, err)
+
+			erasure, err := prov.GetDiskErasure(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, erasure)
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/gophercloud/gophercloud/v2"
@@ -36,6 +37,9 @@ type ironicProvisionerFactory struct {
 	// Ironic CR configuration
 	ironicName      string
 	ironicNamespace string
+
+	// Node cache shared by all the provisioners, nil when disabled.
+	nodeCache *nodeCache
 }
 
 func NewProvisionerFactory(logger logr.Logger, havePreprovImgBuilder bool) (provisioner.Factory, error) {
@@ -67,6 +71,10 @@ func (f *ironicProvisionerFactory) init(havePreprovImgBuilder bool) error {
 		return err
 	}
 
+	if f.config.nodeCacheRefreshInterval > 0 {
+		f.nodeCache = newNodeCache(f.log, f.config.nodeCacheRefreshInterval)
+	}
+
 	if f.ironicName != "" && f.ironicNamespace != "" {
 		f.log.Info("will use Ironic resource configuration",
 			"ironicName", f.ironicName,
@@ -157,6 +165,7 @@ func (f ironicProvisionerFactory) ironicProvisioner(ctx context.Context, hostDat
 		log:                     provisionerLogger,
 		debugLog:                provisionerLogger.V(1),
 		publisher:               publisher,
+		nodeCache:               f.nodeCache,
 	}
 
 	return p, nil
@@ -197,6 +206,15 @@ func loadConfigFromEnv(havePreprovImgBuilder bool) (ironicConfig, error) {
 		c.maxBusyHosts = value
 	}
 
+	c.nodeCacheRefreshInterval = 10 * time.Second
+	if intervalStr := os.Getenv("NODE_CACHE_REFRESH_INTERVAL"); intervalStr != "" {
+		value, err := time.ParseDuration(intervalStr)
+		if err != nil || value < 0 {
+			return c, fmt.Errorf("invalid value set for variable NODE_CACHE_REFRESH_INTERVAL=%s", intervalStr)
+		}
+		c.nodeCacheRefreshInterval = value
+	}
+
 	if liveISOForcePersistentBootDevice := os.Getenv("LIVE_ISO_FORCE_PERSISTENT_BOOT_DEVICE"); liveISOForcePersistentBootDevice != "" {
 		if liveISOForcePersistentBootDevice != "Default" && liveISOForcePersistentBootDevice != "Always" && liveISOForcePersistentBootDevice != "Never" {
 			return c, errors.New("invalid value for variable LIVE_ISO_FORCE_PERSISTENT_BOOT_DEVICE, must be one of Default, Always or Never")
@@ -3,6 +3,7 @@ package ironic
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/metal3-io/baremetal-operator/pkg/provisioner/ironic/clients"
 	"github.com/stretchr/testify/assert"
@@ -21,6 +22,7 @@ type EnvFixture struct {
 	ironicClientPrivateKeyFile            string
 	ironicInsecure                        string
 	ironicSkipClientSANVerify             string
+	nodeCacheRefreshInterval              string
 
 	origEnv map[string]string
 }
@@ -57,6 +59,7 @@ func (f *EnvFixture) SetUp() {
 	f.replace("IRONIC_CLIENT_PRIVATE_KEY_FILE", f.ironicClientPrivateKeyFile)
 	f.replace("IRONIC_INSECURE", f.ironicInsecure)
 	f.replace("IRONIC_SKIP_CLIENT_SAN_VERIFY", f.ironicSkipClientSANVerify)
+	f.replace("NODE_CACHE_REFRESH_INTERVAL", f.nodeCacheRefreshInterval)
 }
 func (f EnvFixture) VerifyConfig(t *testing.T, c ironicConfig, _ string) {
 	t.Helper()
@@ -65,6 +68,11 @@ func (f EnvFixture) VerifyConfig(t *testing.T, c ironicConfig, _ string) {
 	assert.Equal(t, f.isoURL, c.deployISOURL)
 	assert.Equal(t, f.liveISOForcePersistentBootDevice, c.liveISOForcePersistentBootDevice)
 	assert.Equal(t, f.directDeployForcePersistentBootDevice, c.directDeployForcePersistentBootDevice)
+	expectedInterval := 10 * time.Second
+	if f.nodeCacheRefreshInterval != "" {
+		expectedInterval, _ = time.ParseDuration(f.nodeCacheRefreshInterval)
+	}
+	assert.Equal(t, expectedInterval, c.nodeCacheRefreshInterval)
 }
 
 func (f EnvFixture) VerifyEndpoints(t *testing.T, ironic string) {
@@ -203,6 +211,29 @@ func TestLoadConfigFromEnv(t *testing.T) {
 			expectedError:         "invalid value for variable DIRECT_DEPLOY_FORCE_PERSISTENT_BOOT_DEVICE",
 			expectedImgBuildError: "invalid value for variable DIRECT_DEPLOY_FORCE_PERSISTENT_BOOT_DEVICE",
 		},
+		{
+			name: "node cache refresh interval",
+			env: EnvFixture{
+				isoURL:                   "http://iso",
+				nodeCacheRefreshInterval: "1m",
+			},
+		},
+		{
+			name: "node cache disabled",
+			env: EnvFixture{
+				isoURL:                   "http://iso",
+				nodeCacheRefreshInterval: "0",
+			},
+		},
+		{
+			name: "node cache refresh interval invalid",
+			env: EnvFixture{
+				isoURL:                   "http://iso",
+				nodeCacheRefreshInterval: "-1s",
+			},
+			expectedError:         "invalid value set for variable NODE_CACHE_REFRESH_INTERVAL",
+			expectedImgBuildError: "invalid value set for variable NODE_CACHE_REFRESH_INTERVAL",
+		},
 	}
 
 	for _, tt := range []string{"", " (with img builder)"} {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"path"
 	"strings"
 	"time"
 
@@ -69,6 +70,7 @@ type ironicConfig struct {
 	liveISOForcePersistentBootDevice      string
 	directDeployForcePersistentBootDevice string
 	maxBusyHosts                          int
+	nodeCacheRefreshInterval              time.Duration
 	externalURL                           string
 	provNetDisabled                       bool
 }
@@ -100,6 +102,8 @@ type ironicProvisioner struct {
 	publisher provisioner.EventPublisher
 	// available API features
 	availableFeatures clients.AvailableFeatures
+	// the node cache shared by the provisioners of the factory, if enabled
+	nodeCache *nodeCache
 }
 
 // FIXME(hroyrh) : move this to gophercloud when implementing
@@ -170,7 +174,7 @@ func (p *ironicProvisioner) getNode(ctx context.Context) (*nodes.Node, error) {
 		return nil, provisioner.ErrNeedsRegistration
 	}
 
-	ironicNode, err := nodes.Get(ctx, p.client, p.nodeID).Extract()
+	ironicNode, err := p.loadNode(ctx, p.nodeID)
 	if err == nil {
 		p.debugLog.Info("found existing node by ID")
 		return ironicNode, nil
@@ -246,7 +250,7 @@ func (p *ironicProvisioner) findExistingHost(ctx context.Context, bootMACAddress
 
 	for _, nodeName := range nodeSearchList {
 		p.debugLog.Info("looking for existing node by name", "name", nodeName)
-		ironicNode, err = nodes.Get(ctx, p.client, nodeName).Extract()
+		ironicNode, err = p.loadNode(ctx, nodeName)
 		if err == nil {
 			p.debugLog.Info("found existing node by name", "name", nodeName, "node", ironicNode.UUID)
 			return ironicNode, nil
@@ -272,7 +276,7 @@ func (p *ironicProvisioner) findExistingHost(ctx context.Context, bootMACAddress
 
 		if len(allPorts) > 0 {
 			nodeUUID := allPorts[0].NodeUUID
-			ironicNode, err = nodes.Get(ctx, p.client, nodeUUID).Extract()
+			ironicNode, err = p.loadNode(ctx, nodeUUID)
 			if err == nil {
 				p.debugLog.Info("found existing node by MAC", "MAC", bootMACAddress, "node", ironicNode.UUID, "name", ironicNode.Name)
 
@@ -511,6 +515,7 @@ func (p *ironicProvisioner) tryUpdateNode(ctx context.Context, ironicNode *nodes
 	p.log.Info("updating node settings in ironic", "updateCount", len(updater.Updates))
 	updatedNode, err = nodes.Update(ctx, p.client, ironicNode.UUID, updater.Updates).Extract()
 	if err == nil {
+		p.invalidateNode(ironicNode.UUID)
 		success = true
 	} else if gophercloud.ResponseCodeIs(err, http.StatusConflict) {
 		p.log.Info("could not update node settings in ironic, busy or update cannot be applied in the current state")
@@ -543,6 +548,7 @@ func (p *ironicProvisioner) tryChangeNodeProvisionState(ctx context.Context, iro
 
 	changeResult := nodes.ChangeProvisionState(ctx, p.client, ironicNode.UUID, opts)
 	if changeResult.Err == nil {
+		p.invalidateNode(ironicNode.UUID)
 		success = true
 	} else if gophercloud.ResponseCodeIs(changeResult.Err, http.StatusConflict) {
 		p.log.Info("could not change state of host, busy")
@@ -776,6 +782,7 @@ func (p *ironicProvisioner) GetFirmwareSettings(ctx context.Context, includeSche
 				MaxLength:       v.MaxLength,
 				ReadOnly:        v.ReadOnly,
 				Unique:          v.Unique,
+				ResetRequired:   v.ResetRequired,
 			}
 		}
 	}
@@ -912,7 +919,9 @@ func (p *ironicProvisioner) Adopt(ctx context.Context, data provisioner.AdoptDat
 			ironicNode.LastError)
 	case nodes.Active:
 		// Empty Fault means that maintenance was set manually, not by Ironic
-		if ironicNode.Maintenance && ironicNode.Fault == "" && data.State != metal3api.StateDeleting {
+		// or around the changes of the network
+		if ironicNode.Maintenance && ironicNode.Fault == "" && ironicNode.MaintenanceReason != networkMaintenanceReason &&
+			data.State != metal3api.StateDeleting {
 			p.log.Info("active node was found to be in maintenance, updating", "state", data.State)
 			return p.setMaintenanceFlag(ctx, ironicNode, false, "")
 		}
@@ -1348,6 +1357,11 @@ func (p *ironicProvisioner) Provision(ctx context.Context, data provisioner.Prov
 		)
 
 	case nodes.Active:
+		// Bond the NICs now that the deploy ramdisk is gone
+		if done, result, err := p.attachNetwork(ctx, ironicNode, data.Network); !done {
+			return result, err
+		}
+
 		// provisioning is done
 		p.publisher("ProvisioningComplete",
 			"Image provisioning completed for "+data.Image.URL)
@@ -1383,6 +1397,7 @@ func (p *ironicProvisioner) setMaintenanceFlag(ctx context.Context, ironicNode *
 	}
 
 	if err == nil {
+		p.invalidateNode(ironicNode.UUID)
 		result, err = operationContinuing(0)
 	} else if gophercloud.ResponseCodeIs(err, http.StatusConflict) {
 		p.log.Info("could not update maintenance in ironic, busy")
@@ -1415,6 +1430,7 @@ func (p *ironicProvisioner) syncAutomatedClean(ctx context.Context, ironicNode *
 	if err != nil {
 		return false, fmt.Errorf("failed to update automatedClean: %w", err)
 	}
+	p.invalidateNode(ironicNode.UUID)
 
 	return true, nil
 }
@@ -1466,6 +1482,10 @@ func (p *ironicProvisioner) Deprovision(ctx context.Context, restartOnFailure bo
 			p.log.Info("clearing maintenance flag", "maintenanceReason", ironicNode.MaintenanceReason)
 			return p.setMaintenanceFlag(ctx, ironicNode, false, "")
 		}
+		// A failed disk erasure has to be requested again.
+		if erase, found := getDiskErase(ironicNode); found && !erase.Completed {
+			return p.setDiskErase(ctx, ironicNode, nil)
+		}
 		// Move to manageable for retrying.
 		return p.changeNodeProvisionState(ctx, ironicNode,
 			nodes.ProvisionStateOpts{Target: nodes.TargetManage},
@@ -1477,12 +1497,26 @@ func (p *ironicProvisioner) Deprovision(ctx context.Context, restartOnFailure bo
 		// automatedCleaningMode: disabled.
 		// We also end up here if the node has to be re-registered, because we
 		// don't attempt to adopt once deprovisioning has started.
+		// The disks are erased from here as well, before cleaning again.
+		if automatedCleaningMode.ErasesDisks() {
+			done, result, err := p.eraseDisks(ctx, ironicNode, automatedCleaningMode)
+			if !done {
+				return result, err
+			}
+		}
 		p.log.Info("deprovisioning node is in manageable state", "automatedClean", ironicNode.AutomatedClean)
 		return p.changeNodeProvisionState(ctx, ironicNode,
 			nodes.ProvisionStateOpts{Target: nodes.TargetProvide},
 		)
 
 	case nodes.Available:
+		// The disks are erased by a manual cleaning after the automated one.
+		if automatedCleaningMode.ErasesDisks() && !diskEraseCompleted(ironicNode, automatedCleaningMode) {
+			p.log.Info("moving node to manageable to erase disks", "method", automatedCleaningMode)
+			return p.changeNodeProvisionState(ctx, ironicNode,
+				nodes.ProvisionStateOpts{Target: nodes.TargetManage},
+			)
+		}
 		p.publisher("DeprovisioningComplete", "Image deprovisioning completed")
 		return operationComplete()
 
@@ -1516,6 +1550,14 @@ func (p *ironicProvisioner) Deprovision(ctx context.Context, restartOnFailure bo
 		if updated {
 			return operationContinuing(0)
 		}
+		// Forget the disk erasure of a previous deprovisioning.
+		if _, found := getDiskErase(ironicNode); found {
+			return p.setDiskErase(ctx, ironicNode, nil)
+		}
+		// Make the NICs of the bonds standalone again for cleaning.
+		if done, result, err := p.detachNetwork(ctx, ironicNode); !done {
+			return result, err
+		}
 
 		p.log.Info("starting deprovisioning", "automatedClean", ironicNode.AutomatedClean)
 		p.publisher("DeprovisioningStarted", "Image deprovisioning started")
@@ -1631,6 +1673,7 @@ func (p *ironicProvisioner) realDelete(ctx context.Context, ironicNode *nodes.No
 	p.log.Info("host ready to be removed")
 	err = nodes.Delete(ctx, p.client, ironicNode.UUID).ExtractErr()
 	if err == nil {
+		p.invalidateNode(ironicNode.UUID)
 		p.log.Info("removed")
 	} else if gophercloud.ResponseCodeIs(err, http.StatusConflict) {
 		p.log.Info("could not remove host, busy")
@@ -1711,6 +1754,7 @@ func (p *ironicProvisioner) changePower(ctx context.Context, ironicNode *nodes.N
 		powerStateOpts)
 
 	if changeResult.Err == nil {
+		p.invalidateNode(ironicNode.UUID)
 		p.log.Info("power change OK")
 		event := map[nodes.TargetPowerState]struct{ Event, Reason string }{
 			nodes.PowerOn:      {Event: "PowerOn", Reason: "Host powered on"},
@@ -1881,6 +1925,41 @@ func (p *ironicProvisioner) PowerOff(ctx context.Context, rebootMode metal3api.R
 	return result, nil
 }
 
+// loadNode returns the node with the given UUID or name, from the node
+// cache when it is enabled.
+func (p *ironicProvisioner) loadNode(ctx context.Context, id string) (*nodes.Node, error) {
+	if p.nodeCache != nil {
+		return p.nodeCache.getNode(ctx, p.client, id)
+	}
+	return nodes.Get(ctx, p.client, id).Extract()
+}
+
+// listNodes returns the provision state and boot interface of all the
+// nodes, from the node cache when it is enabled.
+func (p *ironicProvisioner) listNodes(ctx context.Context) ([]nodes.Node, error) {
+	if p.nodeCache != nil {
+		return p.nodeCache.listNodes(ctx, p.client)
+	}
+
+	pager := nodes.List(p.client, nodes.ListOpts{
+		Fields: nodeListFields,
+	})
+
+	page, err := pager.AllPages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return nodes.ExtractNodes(page)
+}
+
+// invalidateNode drops a node changed by the operator from the node cache.
+func (p *ironicProvisioner) invalidateNode(uuid string) {
+	if p.nodeCache != nil {
+		p.nodeCache.invalidate(uuid)
+	}
+}
+
 func ironicNodeName(objMeta metav1.ObjectMeta) string {
 	return objMeta.Namespace + nameSeparator + objMeta.Name
 }
@@ -1913,16 +1992,7 @@ func (p *ironicProvisioner) HasCapacity(ctx context.Context) (result bool, err e
 
 func (p *ironicProvisioner) loadBusyHosts(ctx context.Context) (hosts map[string]struct{}, err error) {
 	hosts = make(map[string]struct{})
-	pager := nodes.List(p.client, nodes.ListOpts{
-		Fields: []string{"uuid,name,provision_state,boot_interface"},
-	})
-
-	page, err := pager.AllPages(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	allNodes, err := nodes.ExtractNodes(page)
+	allNodes, err := p.listNodes(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -1981,6 +2051,29 @@ func (p *ironicProvisioner) RemoveBMCEventSubscriptionForNode(ctx context.Contex
 	return operationComplete()
 }
 
+// CheckBMCEventSubscriptionForNode lists the subscriptions of the Redfish
+// event service of the node, which BMCs drop after a reset or a firmware
+// update.
+func (p *ironicProvisioner) CheckBMCEventSubscriptionForNode(ctx context.Context, subscription metal3api.BMCEventSubscription) (exists bool, err error) {
+	all, err := nodes.GetAllSubscriptions(
+		ctx,
+		p.client,
+		p.nodeID,
+		nodes.CallVendorPassthruOpts{
+			Method: "get_all_subscriptions",
+		}).Extract()
+	if err != nil {
+		return false, fmt.Errorf("failed to list the subscriptions: %w", err)
+	}
+
+	for _, member := range all.Members {
+		if path.Base(member["@odata.id"]) == subscription.Status.SubscriptionID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // Uses the Ironic Virtual Media Get API which synchronously fetches the
 // virtual media details for the given node.
 // We return only the bool isImageAttached because the url in the response
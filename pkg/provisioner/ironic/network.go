@@ -0,0 +1,380 @@
+package ironic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack/baremetal/v1/nodes"
+	"github.com/gophercloud/gophercloud/v2/openstack/baremetal/v1/ports"
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner/ironic/clients"
+)
+
+const (
+	// networkKey is the key of the extra field of the node recording that
+	// its ports are attached, with the names of the port groups created
+	// for its bonds and the provisioning VLAN to move the ports back to.
+	networkKey = "metal3_network"
+
+	// switchPortKey is the key of the extra field of the ports and port
+	// groups holding the VLAN configuration of their switch ports.
+	switchPortKey = "switchport"
+
+	// networkMaintenanceReason is the reason of the maintenance around the
+	// changes of the ports and port groups, which Ironic refuses for a
+	// deployed node otherwise.
+	networkMaintenanceReason = "configuring the network in baremetal-operator"
+
+	// bondMode is the Linux bonding mode of the port groups.
+	bondMode = "802.3ad"
+)
+
+// jsonValue returns the value as decoded from JSON, so that it can be
+// compared with the fields returned by Ironic.
+func jsonValue(value any) any {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var result any
+	_ = json.Unmarshal(content, &result)
+	return result
+}
+
+func portGroupName(ironicNode *nodes.Node, bond string) string {
+	name := ironicNode.Name
+	if name == "" {
+		name = ironicNode.UUID
+	}
+	return name + "-" + bond
+}
+
+// switchPortExtra returns the VLAN configuration of a switch port as
+// recorded in the extra field of its Ironic port.
+func switchPortExtra(config metal3api.SwitchPortConfig) any {
+	mode := config.Mode
+	if mode == "" {
+		mode = metal3api.SwitchPortModeAccess
+	}
+	extra := map[string]any{"mode": mode}
+	if config.VLAN != nil {
+		extra["native_vlan"] = *config.VLAN
+	}
+	if len(config.AllowedVLANs) > 0 {
+		extra["allowed_vlans"] = config.AllowedVLANs
+	}
+	return jsonValue(extra)
+}
+
+// enterNetworkMaintenance puts the node in maintenance before its ports and
+// port groups are changed. It returns true once the node is in maintenance.
+func (p *ironicProvisioner) enterNetworkMaintenance(ctx context.Context, ironicNode *nodes.Node) (bool, provisioner.Result, error) {
+	if ironicNode.Maintenance {
+		return true, provisioner.Result{}, nil
+	}
+	result, err := p.setMaintenanceFlag(ctx, ironicNode, true, networkMaintenanceReason)
+	return false, result, err
+}
+
+// leaveNetworkMaintenance ends the maintenance set to change the ports and
+// port groups of the node. It returns true once the node is out of it.
+func (p *ironicProvisioner) leaveNetworkMaintenance(ctx context.Context, ironicNode *nodes.Node) (bool, provisioner.Result, error) {
+	if !ironicNode.Maintenance || ironicNode.MaintenanceReason != networkMaintenanceReason {
+		return true, provisioner.Result{}, nil
+	}
+	result, err := p.setMaintenanceFlag(ctx, ironicNode, false, "")
+	return false, result, err
+}
+
+// networkUpdateResult returns the result of a failed update of a port or a
+// port group.
+func networkUpdateResult(err error) (provisioner.Result, error) {
+	if gophercloud.ResponseCodeIs(err, http.StatusConflict) {
+		return retryAfterDelay(provisionRequeueDelay)
+	}
+	return transientError(fmt.Errorf("failed to configure the network of the node: %w", err))
+}
+
+func (p *ironicProvisioner) listNodePorts(ctx context.Context, ironicNode *nodes.Node) ([]ports.Port, error) {
+	allPages, err := ports.ListDetail(p.client, ports.ListOpts{NodeUUID: ironicNode.UUID}).AllPages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the ports of the node: %w", err)
+	}
+	return ports.ExtractPorts(allPages)
+}
+
+// attachNetwork records the switch port of each NIC in the local link
+// connection of its port with the VLAN configuration of the switch port in
+// the switchport extra field, and aggregates the NICs of each bond in a port
+// group. It runs once the node is deployed, so that the deploy ramdisk is
+// not booted on bonded NICs, with the node in maintenance while the ports
+// and port groups change. It returns true once the ports are all
+// configured.
+func (p *ironicProvisioner) attachNetwork(ctx context.Context, ironicNode *nodes.Node, network *provisioner.NetworkData) (done bool, result provisioner.Result, err error) {
+	if network == nil {
+		return true, result, nil
+	}
+
+	nodePorts, err := p.listNodePorts(ctx, ironicNode)
+	if err != nil {
+		result, err = transientError(err)
+		return false, result, err
+	}
+	groups, err := clients.ListPortGroups(ctx, p.client, ironicNode.UUID)
+	if err != nil {
+		result, err = transientError(fmt.Errorf("failed to list the port groups of the node: %w", err))
+		return false, result, err
+	}
+
+	changed := false
+
+	bondConfigs := make(map[string]metal3api.SwitchPortConfig, len(network.Bonds))
+	groupUUIDs := make(map[string]string, len(network.Bonds))
+	groupNames := make([]string, 0, len(network.Bonds))
+	for _, bond := range network.Bonds {
+		name := portGroupName(ironicNode, bond.Name)
+		groupNames = append(groupNames, name)
+		bondConfigs[bond.Name] = bond.SwitchPortConfig
+		switchPort := switchPortExtra(bond.SwitchPortConfig)
+
+		var group *clients.PortGroup
+		for i := range groups {
+			if groups[i].Name == name {
+				group = &groups[i]
+				break
+			}
+		}
+
+		if group != nil && reflect.DeepEqual(group.Extra[switchPortKey], switchPort) {
+			groupUUIDs[bond.Name] = group.UUID
+			continue
+		}
+		if ok, result, err := p.enterNetworkMaintenance(ctx, ironicNode); !ok {
+			return false, result, err
+		}
+
+		if group == nil {
+			p.log.Info("creating port group", "bond", bond.Name)
+			standalone := false
+			group, err = clients.CreatePortGroup(ctx, p.client, clients.PortGroup{
+				Name:                     name,
+				NodeUUID:                 ironicNode.UUID,
+				Address:                  strings.ToLower(bond.MACAddresses[0]),
+				Mode:                     bondMode,
+				StandalonePortsSupported: &standalone,
+				Extra:                    map[string]any{switchPortKey: switchPort},
+			})
+		} else {
+			p.log.Info("updating port group", "bond", bond.Name)
+			err = clients.UpdatePortGroup(ctx, p.client, group.UUID, ports.UpdateOpts{
+				ports.UpdateOperation{Op: ports.AddOp, Path: "/extra/" + switchPortKey, Value: switchPort},
+			})
+		}
+		if err != nil {
+			result, err = networkUpdateResult(err)
+			return false, result, err
+		}
+		changed = true
+		groupUUIDs[bond.Name] = group.UUID
+	}
+
+	for _, attachment := range network.Interfaces {
+		localLink := map[string]any{
+			"switch_id":   attachment.SwitchID,
+			"port_id":     attachment.PortID,
+			"switch_info": attachment.SwitchInfo,
+		}
+		groupUUID := groupUUIDs[attachment.Bond]
+		config := attachment.SwitchPortConfig
+		if attachment.Bond != "" {
+			config = bondConfigs[attachment.Bond]
+		}
+		switchPort := switchPortExtra(config)
+
+		var port *ports.Port
+		for i := range nodePorts {
+			if strings.EqualFold(nodePorts[i].Address, attachment.MACAddress) {
+				port = &nodePorts[i]
+				break
+			}
+		}
+
+		if port == nil {
+			if ok, result, err := p.enterNetworkMaintenance(ctx, ironicNode); !ok {
+				return false, result, err
+			}
+			p.log.Info("creating port", "MAC", attachment.MACAddress)
+			opts := ports.CreateOpts{
+				NodeUUID:            ironicNode.UUID,
+				Address:             attachment.MACAddress,
+				LocalLinkConnection: localLink,
+				PortGroupUUID:       groupUUID,
+				Extra:               map[string]any{switchPortKey: switchPort},
+			}
+			if _, err = ports.Create(ctx, p.client, opts).Extract(); err != nil {
+				result, err = networkUpdateResult(err)
+				return false, result, err
+			}
+			changed = true
+			continue
+		}
+
+		var updates ports.UpdateOpts
+		if !reflect.DeepEqual(port.LocalLinkConnection, localLink) {
+			updates = append(updates, ports.UpdateOperation{Op: ports.AddOp, Path: "/local_link_connection", Value: localLink})
+		}
+		if port.PortGroupUUID != groupUUID {
+			if groupUUID == "" {
+				updates = append(updates, ports.UpdateOperation{Op: ports.RemoveOp, Path: "/portgroup_uuid"})
+			} else {
+				updates = append(updates, ports.UpdateOperation{Op: ports.AddOp, Path: "/portgroup_uuid", Value: groupUUID})
+			}
+		}
+		if !reflect.DeepEqual(port.Extra[switchPortKey], switchPort) {
+			updates = append(updates, ports.UpdateOperation{Op: ports.AddOp, Path: "/extra/" + switchPortKey, Value: switchPort})
+		}
+
+		if len(updates) > 0 {
+			if ok, result, err := p.enterNetworkMaintenance(ctx, ironicNode); !ok {
+				return false, result, err
+			}
+			p.log.Info("updating port", "MAC", attachment.MACAddress)
+			if _, err = ports.Update(ctx, p.client, port.UUID, updates).Extract(); err != nil {
+				result, err = networkUpdateResult(err)
+				return false, result, err
+			}
+			changed = true
+		}
+	}
+
+	if changed {
+		result, err = operationContinuing(0)
+		return false, result, err
+	}
+	if ok, result, err := p.leaveNetworkMaintenance(ctx, ironicNode); !ok {
+		return false, result, err
+	}
+
+	// Record the port groups to remove and the VLAN to move the ports back
+	// to on deprovisioning
+	record := map[string]any{"portgroups": groupNames}
+	if network.ProvisioningVLAN != nil {
+		record["provisioning_vlan"] = *network.ProvisioningVLAN
+	}
+	value := jsonValue(record)
+	if current, found := ironicNode.Extra[networkKey]; found && reflect.DeepEqual(current, value) {
+		return true, result, nil
+	}
+	p.log.Info("network of the node configured", "portGroups", groupNames)
+	updater := clients.UpdateOptsBuilder(p.log)
+	updater.SetExtraOpts(clients.UpdateOptsData{networkKey: value}, ironicNode)
+	_, success, result, err := p.tryUpdateNode(ctx, ironicNode, updater)
+	if !success {
+		return false, result, err
+	}
+	result, err = operationContinuing(0)
+	return false, result, err
+}
+
+// detachNetwork releases the NICs of the bonds from their port groups,
+// deletes the port groups and moves the switch ports back to the
+// provisioning VLAN, so that the NICs are standalone again for cleaning and
+// the next provisioning. The node is in maintenance while the ports and
+// port groups change. It returns true once the port groups are all deleted.
+func (p *ironicProvisioner) detachNetwork(ctx context.Context, ironicNode *nodes.Node) (done bool, result provisioner.Result, err error) {
+	network, found := ironicNode.Extra[networkKey].(map[string]any)
+	if !found {
+		return true, result, nil
+	}
+	names, _ := network["portgroups"].([]any)
+
+	// Ports without a provisioning VLAN are left to the default of the switch
+	var switchPort any
+	if vlan, found := network["provisioning_vlan"]; found {
+		switchPort = jsonValue(map[string]any{"mode": metal3api.SwitchPortModeAccess, "native_vlan": vlan})
+	}
+
+	groups, err := clients.ListPortGroups(ctx, p.client, ironicNode.UUID)
+	if err != nil {
+		result, err = transientError(fmt.Errorf("failed to list the port groups of the node: %w", err))
+		return false, result, err
+	}
+	groups = slices.DeleteFunc(groups, func(group clients.PortGroup) bool {
+		return !slices.Contains(names, any(group.Name))
+	})
+
+	nodePorts, err := p.listNodePorts(ctx, ironicNode)
+	if err != nil {
+		result, err = transientError(err)
+		return false, result, err
+	}
+	changed := false
+	for _, port := range nodePorts {
+		var updates ports.UpdateOpts
+		if port.PortGroupUUID != "" && slices.ContainsFunc(groups, func(group clients.PortGroup) bool {
+			return group.UUID == port.PortGroupUUID
+		}) {
+			updates = append(updates, ports.UpdateOperation{Op: ports.RemoveOp, Path: "/portgroup_uuid"})
+		}
+		current, found := port.Extra[switchPortKey]
+		switch {
+		case switchPort == nil && found:
+			updates = append(updates, ports.UpdateOperation{Op: ports.RemoveOp, Path: "/extra/" + switchPortKey})
+		case switchPort != nil && found && !reflect.DeepEqual(current, switchPort):
+			updates = append(updates, ports.UpdateOperation{Op: ports.AddOp, Path: "/extra/" + switchPortKey, Value: switchPort})
+		}
+		if len(updates) == 0 {
+			continue
+		}
+
+		if ok, result, err := p.enterNetworkMaintenance(ctx, ironicNode); !ok {
+			return false, result, err
+		}
+		p.log.Info("detaching port from the tenant networks", "MAC", port.Address)
+		if _, err = ports.Update(ctx, p.client, port.UUID, updates).Extract(); err != nil {
+			result, err = networkUpdateResult(err)
+			return false, result, err
+		}
+		changed = true
+	}
+	if changed {
+		result, err = operationContinuing(0)
+		return false, result, err
+	}
+
+	if len(groups) > 0 {
+		if ok, result, err := p.enterNetworkMaintenance(ctx, ironicNode); !ok {
+			return false, result, err
+		}
+		for _, group := range groups {
+			p.log.Info("deleting port group", "name", group.Name)
+			if err = clients.DeletePortGroup(ctx, p.client, group.UUID); err != nil && !gophercloud.ResponseCodeIs(err, http.StatusNotFound) {
+				result, err = networkUpdateResult(err)
+				return false, result, err
+			}
+		}
+		result, err = operationContinuing(0)
+		return false, result, err
+	}
+	if ok, result, err := p.leaveNetworkMaintenance(ctx, ironicNode); !ok {
+		return false, result, err
+	}
+
+	p.log.Info("port groups of the node deleted")
+	p.publisher("NetworkDetached", "Port groups of the bonds deleted")
+	updater := clients.UpdateOptsBuilder(p.log)
+	updater.SetExtraOpts(clients.UpdateOptsData{networkKey: nil}, ironicNode)
+	_, success, result, err := p.tryUpdateNode(ctx, ironicNode, updater)
+	if !success {
+		return false, result, err
+	}
+	result, err = operationContinuing(0)
+	return false, result, err
+}
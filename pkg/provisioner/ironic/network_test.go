@@ -0,0 +1,479 @@
+package ironic
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/v2/openstack/baremetal/v1/nodes"
+	"github.com/gophercloud/gophercloud/v2/openstack/baremetal/v1/ports"
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/hardwareutils/bmc"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner/ironic/clients"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner/ironic/testserver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	networkNodeUUID = "33ce8659-7400-4c68-9535-d10766f07a58"
+	bondGroupUUID   = "4a6ff7a5-4d3d-4bbf-9ad1-8b1f3f1c6b8f"
+	firstPortUUID   = "c6a1a8f1-2e8e-4c1a-9c0d-1b5d7b6f0e01"
+	secondPortUUID  = "c6a1a8f1-2e8e-4c1a-9c0d-1b5d7b6f0e02"
+	thirdPortUUID   = "c6a1a8f1-2e8e-4c1a-9c0d-1b5d7b6f0e03"
+)
+
+func testNetworkData() *provisioner.NetworkData {
+	return &provisioner.NetworkData{
+		ProvisioningVLAN: ptr.To(metal3api.VLANID(5)),
+		Interfaces: []provisioner.NetworkAttachment{
+			{
+				MACAddress: "00:00:00:00:00:01",
+				SwitchID:   "aa:bb:cc:dd:ee:ff",
+				SwitchInfo: "tor-1",
+				PortID:     "Ethernet1/1",
+				Bond:       "bond0",
+			},
+			{
+				MACAddress: "00:00:00:00:00:02",
+				SwitchID:   "aa:bb:cc:dd:ee:ff",
+				SwitchInfo: "tor-1",
+				PortID:     "Ethernet1/2",
+				Bond:       "bond0",
+			},
+			{
+				MACAddress: "00:00:00:00:00:03",
+				SwitchID:   "aa:bb:cc:dd:ee:ff",
+				SwitchInfo: "tor-1",
+				PortID:     "Ethernet1/3",
+				SwitchPortConfig: metal3api.SwitchPortConfig{
+					VLAN: ptr.To(metal3api.VLANID(100)),
+				},
+			},
+		},
+		Bonds: []metal3api.NetworkBond{
+			{
+				Name:         "bond0",
+				MACAddresses: []string{"00:00:00:00:00:01", "00:00:00:00:00:02"},
+				SwitchPortConfig: metal3api.SwitchPortConfig{
+					Mode:         metal3api.SwitchPortModeTrunk,
+					VLAN:         ptr.To(metal3api.VLANID(10)),
+					AllowedVLANs: []metal3api.VLANID{20, 30},
+				},
+			},
+		},
+	}
+}
+
+func localLink(port string) map[string]any {
+	return map[string]any{
+		"switch_id":   "aa:bb:cc:dd:ee:ff",
+		"port_id":     port,
+		"switch_info": "tor-1",
+	}
+}
+
+func bondSwitchPort() any {
+	return jsonValue(map[string]any{"mode": "trunk", "native_vlan": 10, "allowed_vlans": []int{20, 30}})
+}
+
+func accessSwitchPort(vlan int) any {
+	return jsonValue(map[string]any{"mode": "access", "native_vlan": vlan})
+}
+
+func configuredPorts() []ports.Port {
+	return []ports.Port{
+		{
+			UUID:                firstPortUUID,
+			Address:             "00:00:00:00:00:01",
+			NodeUUID:            networkNodeUUID,
+			LocalLinkConnection: localLink("Ethernet1/1"),
+			PortGroupUUID:       bondGroupUUID,
+			Extra:               map[string]any{switchPortKey: bondSwitchPort()},
+		},
+		{
+			UUID:                secondPortUUID,
+			Address:             "00:00:00:00:00:02",
+			NodeUUID:            networkNodeUUID,
+			LocalLinkConnection: localLink("Ethernet1/2"),
+			PortGroupUUID:       bondGroupUUID,
+			Extra:               map[string]any{switchPortKey: bondSwitchPort()},
+		},
+		{
+			UUID:                thirdPortUUID,
+			Address:             "00:00:00:00:00:03",
+			NodeUUID:            networkNodeUUID,
+			LocalLinkConnection: localLink("Ethernet1/3"),
+			Extra:               map[string]any{switchPortKey: accessSwitchPort(100)},
+		},
+	}
+}
+
+func configuredPortGroups() []clients.PortGroup {
+	return []clients.PortGroup{
+		{
+			UUID:     bondGroupUUID,
+			Name:     "node-0-bond0",
+			NodeUUID: networkNodeUUID,
+			Mode:     bondMode,
+			Extra:    map[string]any{switchPortKey: bondSwitchPort()},
+		},
+	}
+}
+
+// maintenanceRequests returns whether the maintenance of the node was set
+// and unset.
+func maintenanceRequests(ironic *testserver.IronicMock) (set bool, unset bool) {
+	_, set = ironic.GetLastRequestFor("/v1/nodes/"+networkNodeUUID+"/maintenance", http.MethodPut)
+	_, unset = ironic.GetLastRequestFor("/v1/nodes/"+networkNodeUUID+"/maintenance", http.MethodDelete)
+	return
+}
+
+func TestAttachNetwork(t *testing.T) {
+	networkRecord := map[string]any{"portgroups": []any{"node-0-bond0"}, "provisioning_vlan": float64(5)}
+	unconfiguredPorts := []ports.Port{
+		{UUID: firstPortUUID, Address: "00:00:00:00:00:01", NodeUUID: networkNodeUUID},
+		{UUID: secondPortUUID, Address: "00:00:00:00:00:02", NodeUUID: networkNodeUUID},
+		{UUID: thirdPortUUID, Address: "00:00:00:00:00:03", NodeUUID: networkNodeUUID, PortGroupUUID: bondGroupUUID},
+	}
+
+	cases := []struct {
+		name                string
+		network             *provisioner.NetworkData
+		extra               map[string]any
+		maintenance         bool
+		ports               []ports.Port
+		portUpdateError     int
+		groups              []clients.PortGroup
+		expectedDone        bool
+		expectedRequeue     bool
+		expectedMaintenance bool
+		expectedCleared     bool
+		expectedGroup       *clients.PortGroup
+		expectedUpdates     map[string][]ports.UpdateOperation
+		expectedCreate      *ports.CreateOpts
+		expectedRecord      any
+	}{
+		{
+			name:         "no network",
+			expectedDone: true,
+		},
+		{
+			name:                "sets maintenance before changing the ports",
+			network:             testNetworkData(),
+			ports:               unconfiguredPorts,
+			groups:              configuredPortGroups(),
+			expectedMaintenance: true,
+		},
+		{
+			name:        "creates the bond and the missing port",
+			network:     testNetworkData(),
+			maintenance: true,
+			ports:       configuredPorts()[:2],
+			groups:      nil,
+			expectedGroup: &clients.PortGroup{
+				Name:                     "node-0-bond0",
+				NodeUUID:                 networkNodeUUID,
+				Address:                  "00:00:00:00:00:01",
+				Mode:                     bondMode,
+				StandalonePortsSupported: ptr.To(false),
+				Extra:                    map[string]any{switchPortKey: bondSwitchPort()},
+			},
+			expectedCreate: &ports.CreateOpts{
+				NodeUUID:            networkNodeUUID,
+				Address:             "00:00:00:00:00:03",
+				LocalLinkConnection: localLink("Ethernet1/3"),
+				Extra:               map[string]any{switchPortKey: accessSwitchPort(100)},
+			},
+		},
+		{
+			name:        "configures the existing ports",
+			network:     testNetworkData(),
+			maintenance: true,
+			ports:       unconfiguredPorts,
+			groups:      configuredPortGroups(),
+			expectedUpdates: map[string][]ports.UpdateOperation{
+				firstPortUUID: {
+					{Op: ports.AddOp, Path: "/local_link_connection", Value: localLink("Ethernet1/1")},
+					{Op: ports.AddOp, Path: "/portgroup_uuid", Value: bondGroupUUID},
+					{Op: ports.AddOp, Path: "/extra/switchport", Value: bondSwitchPort()},
+				},
+				secondPortUUID: {
+					{Op: ports.AddOp, Path: "/local_link_connection", Value: localLink("Ethernet1/2")},
+					{Op: ports.AddOp, Path: "/portgroup_uuid", Value: bondGroupUUID},
+					{Op: ports.AddOp, Path: "/extra/switchport", Value: bondSwitchPort()},
+				},
+				thirdPortUUID: {
+					{Op: ports.AddOp, Path: "/local_link_connection", Value: localLink("Ethernet1/3")},
+					{Op: ports.RemoveOp, Path: "/portgroup_uuid"},
+					{Op: ports.AddOp, Path: "/extra/switchport", Value: accessSwitchPort(100)},
+				},
+			},
+		},
+		{
+			name:    "updates the VLANs of the bond",
+			network: testNetworkData(),
+			ports:   configuredPorts(),
+			groups: []clients.PortGroup{
+				{UUID: bondGroupUUID, Name: "node-0-bond0", NodeUUID: networkNodeUUID, Mode: bondMode},
+			},
+			maintenance: true,
+			expectedUpdates: map[string][]ports.UpdateOperation{
+				bondGroupUUID: {{Op: ports.AddOp, Path: "/extra/switchport", Value: bondSwitchPort()}},
+			},
+		},
+		{
+			name:            "retries when Ironic refuses the change",
+			network:         testNetworkData(),
+			maintenance:     true,
+			ports:           unconfiguredPorts,
+			portUpdateError: http.StatusConflict,
+			groups:          configuredPortGroups(),
+			expectedRequeue: true,
+		},
+		{
+			name:            "clears maintenance once configured",
+			network:         testNetworkData(),
+			maintenance:     true,
+			ports:           configuredPorts(),
+			groups:          configuredPortGroups(),
+			expectedCleared: true,
+		},
+		{
+			name:           "records the port groups",
+			network:        testNetworkData(),
+			ports:          configuredPorts(),
+			groups:         configuredPortGroups(),
+			expectedRecord: networkRecord,
+		},
+		{
+			name:         "configured",
+			network:      testNetworkData(),
+			extra:        map[string]any{networkKey: networkRecord},
+			ports:        configuredPorts(),
+			groups:       configuredPortGroups(),
+			expectedDone: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ironicNode := nodes.Node{
+				UUID:           networkNodeUUID,
+				Name:           "node-0",
+				ProvisionState: string(nodes.Active),
+				Extra:          tc.extra,
+			}
+			if tc.maintenance {
+				ironicNode.Maintenance = true
+				ironicNode.MaintenanceReason = networkMaintenanceReason
+			}
+			ironic := testserver.NewIronic(t).WithDefaultResponses().Node(ironicNode).
+				NodeUpdate(ironicNode).
+				NodeMaintenance(nodes.Node{UUID: networkNodeUUID}, true).
+				NodeMaintenance(nodes.Node{UUID: networkNodeUUID}, false).
+				PortGroups(tc.groups, clients.PortGroup{UUID: bondGroupUUID})
+			if tc.portUpdateError != 0 {
+				ironic.NodePortsUpdateError(tc.ports, tc.portUpdateError)
+			} else {
+				ironic.NodePorts(tc.ports)
+			}
+			ironic.Start()
+			defer ironic.Stop()
+
+			host := makeHost()
+			host.Status.Provisioning.ID = networkNodeUUID
+			publisher := func(reason, message string) {}
+			auth := clients.AuthConfig{Type: clients.NoAuth}
+			prov, err := newProvisionerWithSettings(host, bmc.Credentials{}, publisher, ironic.Endpoint(), auth)
+			require.NoError(t, err)
+
+			done, result, err := prov.attachNetwork(t.Context(), &ironicNode, tc.network)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedDone, done)
+			assert.Equal(t, !tc.expectedDone, result.Dirty)
+			assert.Equal(t, tc.expectedRequeue, result.RequeueAfter > 0)
+
+			set, unset := maintenanceRequests(ironic)
+			assert.Equal(t, tc.expectedMaintenance, set)
+			assert.Equal(t, tc.expectedCleared, unset)
+			if tc.expectedMaintenance {
+				assert.Empty(t, ironic.GetLastPortGroupCreateRequest())
+				assert.Empty(t, ironic.GetLastPortCreateRequest())
+			}
+
+			if tc.expectedGroup != nil {
+				assert.Equal(t, *tc.expectedGroup, ironic.GetLastPortGroupCreateRequest())
+			}
+			if tc.expectedCreate != nil {
+				assert.Equal(t, *tc.expectedCreate, ironic.GetLastPortCreateRequest())
+			}
+			for uuid, expected := range tc.expectedUpdates {
+				if uuid == bondGroupUUID {
+					assert.Equal(t, expected, ironic.GetLastPortGroupUpdateRequestFor(uuid))
+				} else {
+					assert.Equal(t, expected, ironic.GetLastPortUpdateRequestFor(uuid))
+				}
+			}
+
+			updates := ironic.GetLastNodeUpdateRequestFor(networkNodeUUID)
+			if tc.expectedRecord != nil {
+				require.Len(t, updates, 1)
+				assert.Equal(t, "/extra/"+networkKey, updates[0].Path)
+				assert.Equal(t, tc.expectedRecord, updates[0].Value)
+			} else {
+				assert.Empty(t, updates)
+			}
+		})
+	}
+}
+
+func TestDetachNetwork(t *testing.T) {
+	networkRecord := map[string]any{"portgroups": []any{"node-0-bond0"}, "provisioning_vlan": float64(5)}
+	detachedPorts := configuredPorts()
+	for i := range detachedPorts {
+		detachedPorts[i].PortGroupUUID = ""
+		detachedPorts[i].Extra = map[string]any{switchPortKey: accessSwitchPort(5)}
+	}
+
+	cases := []struct {
+		name                string
+		extra               map[string]any
+		maintenance         bool
+		ports               []ports.Port
+		groups              []clients.PortGroup
+		expectedDone        bool
+		expectedMaintenance bool
+		expectedCleared     bool
+		expectedUpdates     map[string][]ports.UpdateOperation
+		expectedDeleted     bool
+		expectedRemoved     bool
+	}{
+		{
+			name:         "not attached",
+			ports:        configuredPorts(),
+			groups:       configuredPortGroups(),
+			expectedDone: true,
+		},
+		{
+			name:                "sets maintenance before changing the ports",
+			extra:               map[string]any{networkKey: networkRecord},
+			ports:               configuredPorts(),
+			groups:              configuredPortGroups(),
+			expectedMaintenance: true,
+		},
+		{
+			name:        "moves the ports back to the provisioning VLAN",
+			extra:       map[string]any{networkKey: networkRecord},
+			maintenance: true,
+			ports:       configuredPorts(),
+			groups:      configuredPortGroups(),
+			expectedUpdates: map[string][]ports.UpdateOperation{
+				firstPortUUID: {
+					{Op: ports.RemoveOp, Path: "/portgroup_uuid"},
+					{Op: ports.AddOp, Path: "/extra/switchport", Value: accessSwitchPort(5)},
+				},
+				secondPortUUID: {
+					{Op: ports.RemoveOp, Path: "/portgroup_uuid"},
+					{Op: ports.AddOp, Path: "/extra/switchport", Value: accessSwitchPort(5)},
+				},
+				thirdPortUUID: {
+					{Op: ports.AddOp, Path: "/extra/switchport", Value: accessSwitchPort(5)},
+				},
+			},
+		},
+		{
+			name:        "leaves the ports to the switch without a provisioning VLAN",
+			extra:       map[string]any{networkKey: map[string]any{"portgroups": []any{}}},
+			maintenance: true,
+			ports:       configuredPorts()[2:],
+			expectedUpdates: map[string][]ports.UpdateOperation{
+				thirdPortUUID: {{Op: ports.RemoveOp, Path: "/extra/switchport"}},
+			},
+		},
+		{
+			name:            "deletes the port groups",
+			extra:           map[string]any{networkKey: networkRecord},
+			maintenance:     true,
+			ports:           detachedPorts,
+			groups:          configuredPortGroups(),
+			expectedDeleted: true,
+		},
+		{
+			name:   "keeps the other port groups",
+			extra:  map[string]any{networkKey: map[string]any{"portgroups": []any{}, "provisioning_vlan": float64(5)}},
+			ports:  detachedPorts,
+			groups: configuredPortGroups(),
+			// The record is removed once no port group is left
+			expectedRemoved: true,
+		},
+		{
+			name:            "clears maintenance once detached",
+			extra:           map[string]any{networkKey: networkRecord},
+			maintenance:     true,
+			ports:           detachedPorts,
+			expectedCleared: true,
+		},
+		{
+			name:            "forgets the network once detached",
+			extra:           map[string]any{networkKey: networkRecord},
+			ports:           detachedPorts,
+			expectedRemoved: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ironicNode := nodes.Node{
+				UUID:           networkNodeUUID,
+				Name:           "node-0",
+				ProvisionState: string(nodes.Active),
+				Extra:          tc.extra,
+			}
+			if tc.maintenance {
+				ironicNode.Maintenance = true
+				ironicNode.MaintenanceReason = networkMaintenanceReason
+			}
+			ironic := testserver.NewIronic(t).WithDefaultResponses().Node(ironicNode).
+				NodeUpdate(ironicNode).
+				NodeMaintenance(nodes.Node{UUID: networkNodeUUID}, true).
+				NodeMaintenance(nodes.Node{UUID: networkNodeUUID}, false).
+				NodePorts(tc.ports).
+				PortGroups(tc.groups, clients.PortGroup{})
+			ironic.Start()
+			defer ironic.Stop()
+
+			host := makeHost()
+			host.Status.Provisioning.ID = networkNodeUUID
+			publisher := func(reason, message string) {}
+			auth := clients.AuthConfig{Type: clients.NoAuth}
+			prov, err := newProvisionerWithSettings(host, bmc.Credentials{}, publisher, ironic.Endpoint(), auth)
+			require.NoError(t, err)
+
+			done, result, err := prov.detachNetwork(t.Context(), &ironicNode)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedDone, done)
+			assert.Equal(t, !tc.expectedDone, result.Dirty)
+
+			set, unset := maintenanceRequests(ironic)
+			assert.Equal(t, tc.expectedMaintenance, set)
+			assert.Equal(t, tc.expectedCleared, unset)
+
+			for _, uuid := range []string{firstPortUUID, secondPortUUID, thirdPortUUID} {
+				assert.Equal(t, tc.expectedUpdates[uuid], ironic.GetLastPortUpdateRequestFor(uuid), uuid)
+			}
+			_, deleted := ironic.GetLastRequestFor("/v1/portgroups/"+bondGroupUUID, http.MethodDelete)
+			assert.Equal(t, tc.expectedDeleted, deleted)
+
+			updates := ironic.GetLastNodeUpdateRequestFor(networkNodeUUID)
+			if tc.expectedRemoved {
+				require.Len(t, updates, 1)
+				assert.Equal(t, "/extra/"+networkKey, updates[0].Path)
+				assert.Equal(t, nodes.RemoveOp, updates[0].Op)
+			} else {
+				assert.Empty(t, updates)
+			}
+		})
+	}
+}
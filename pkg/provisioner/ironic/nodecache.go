@@ -0,0 +1,307 @@
+package ironic
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack/baremetal/v1/nodes"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	labelCacheResult = "result"
+	cacheHit         = "hit"
+	cacheMiss        = "miss"
+)
+
+var nodeCacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "metal3_ironic_node_cache_requests_total",
+	Help: "Number of Ironic node lookups served from the node cache (hit) or from Ironic (miss)",
+}, []string{labelCacheResult})
+
+var nodeCacheAge = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "metal3_ironic_node_cache_age_seconds",
+	Help: "Time since the list of Ironic nodes was last reloaded, as of the last lookup",
+})
+
+func init() {
+	metrics.Registry.MustRegister(
+		nodeCacheRequests,
+		nodeCacheAge)
+}
+
+// nodeListFields are the fields of the nodes returned by listNodes.
+var nodeListFields = []string{"uuid,name,provision_state,target_provision_state,boot_interface,updated_at"}
+
+// nodeCache holds the Ironic nodes for all the provisioners created by a
+// factory, so that looking up a node does not cost a request to Ironic on
+// every reconcile.
+//
+// The cache keeps a list of all the nodes with a few fields, reloaded once
+// it gets older than the refresh interval, and the details of the nodes
+// looked up individually. The details of a node stay valid as long as the
+// list shows that the node has not been updated in Ironic. Nodes with a
+// provision or power state transition in progress are not kept, since
+// Ironic changes them on its own. The operator drops the entry of a node
+// when it changes the node, which is then loaded again on its own.
+type nodeCache struct {
+	refreshInterval time.Duration
+	log             logr.Logger
+
+	// listLock serializes the reloads of the list. lock protects the
+	// content of the cache and is never held during a request to Ironic.
+	listLock sync.Mutex
+	lock     sync.Mutex
+	listed   map[string]nodes.Node
+	details  map[string]cachedNode
+	byName   map[string]string
+	// dropped holds the sequence number of the invalidation of the nodes
+	// not loaded again since, so that the result of a request started
+	// before an invalidation is not cached.
+	dropped   map[string]uint64
+	sequence  uint64
+	refreshed time.Time
+}
+
+type cachedNode struct {
+	node   nodes.Node
+	loaded time.Time
+}
+
+func newNodeCache(logger logr.Logger, refreshInterval time.Duration) *nodeCache {
+	return &nodeCache{
+		refreshInterval: refreshInterval,
+		log:             logger.WithName("node-cache"),
+		listed:          map[string]nodes.Node{},
+		details:         map[string]cachedNode{},
+		byName:          map[string]string{},
+		dropped:         map[string]uint64{},
+	}
+}
+
+// expired returns whether the list of nodes must be reloaded, and the
+// current sequence number.
+func (c *nodeCache) expired() (bool, uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return time.Since(c.refreshed) >= c.refreshInterval, c.sequence
+}
+
+// refresh reloads the list of nodes if it has expired, and reports
+// whether it did.
+func (c *nodeCache) refresh(ctx context.Context, client *gophercloud.ServiceClient) (bool, error) {
+	if expired, _ := c.expired(); !expired {
+		return false, nil
+	}
+
+	c.listLock.Lock()
+	defer c.listLock.Unlock()
+
+	// Another caller may have reloaded the list in the meantime
+	expired, sequence := c.expired()
+	if !expired {
+		return false, nil
+	}
+
+	page, err := nodes.List(client, nodes.ListOpts{Fields: nodeListFields}).AllPages(ctx)
+	if err != nil {
+		return false, err
+	}
+	allNodes, err := nodes.ExtractNodes(page)
+	if err != nil {
+		return false, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := time.Now()
+	c.listed = make(map[string]nodes.Node, len(allNodes))
+	for _, node := range allNodes {
+		c.listed[node.UUID] = node
+		if c.dropped[node.UUID] > sequence {
+			// Changed by the operator during the request
+			continue
+		}
+		delete(c.dropped, node.UUID)
+		cached, found := c.details[node.UUID]
+		switch {
+		case !found:
+		case cached.node.UpdatedAt.Equal(node.UpdatedAt):
+			cached.loaded = now
+			c.details[node.UUID] = cached
+		default:
+			c.forget(node.UUID)
+		}
+	}
+	for uuid, seq := range c.dropped {
+		if _, found := c.listed[uuid]; !found && seq <= sequence {
+			delete(c.dropped, uuid)
+		}
+	}
+	for uuid := range c.details {
+		if _, found := c.listed[uuid]; !found {
+			c.forget(uuid)
+		}
+	}
+	c.refreshed = now
+	c.log.V(1).Info("reloaded the list of nodes", "count", len(allNodes))
+	return true, nil
+}
+
+// store records a node loaded from Ironic by a request started at the
+// given sequence number.
+func (c *nodeCache) store(node nodes.Node, sequence uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.dropped[node.UUID] > sequence {
+		// Changed by the operator during the request
+		return
+	}
+	delete(c.dropped, node.UUID)
+	c.listed[node.UUID] = node
+
+	c.forget(node.UUID)
+	if node.TargetProvisionState != "" || node.TargetPowerState != "" {
+		return
+	}
+	c.details[node.UUID] = cachedNode{node: node, loaded: time.Now()}
+	if node.Name != "" {
+		c.byName[node.Name] = node.UUID
+	}
+}
+
+// forget drops the details of a node. The lock must be held.
+func (c *nodeCache) forget(uuid string) {
+	if cached, found := c.details[uuid]; found {
+		if c.byName[cached.node.Name] == uuid {
+			delete(c.byName, cached.node.Name)
+		}
+		delete(c.details, uuid)
+	}
+}
+
+// getNode returns the node with the given UUID or name. Nodes that are
+// not in the cache are loaded from Ironic, and errors are returned
+// unchanged so that the caller can check for a missing node.
+func (c *nodeCache) getNode(ctx context.Context, client *gophercloud.ServiceClient, id string) (*nodes.Node, error) {
+	if _, err := c.refresh(ctx, client); err != nil {
+		// The node can still be loaded on its own.
+		c.log.Error(err, "failed to reload the list of nodes")
+	}
+
+	if node, found := c.lookup(id); found {
+		nodeCacheRequests.WithLabelValues(cacheHit).Inc()
+		return node, nil
+	}
+
+	nodeCacheRequests.WithLabelValues(cacheMiss).Inc()
+	_, sequence := c.expired()
+	node, err := nodes.Get(ctx, client, id).Extract()
+	if err != nil {
+		return nil, err
+	}
+	c.store(*node, sequence)
+	return node, nil
+}
+
+func (c *nodeCache) lookup(id string) (*nodes.Node, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	nodeCacheAge.Set(time.Since(c.refreshed).Seconds())
+
+	uuid, found := c.byName[id]
+	if !found {
+		uuid = id
+	}
+	cached, found := c.details[uuid]
+	if !found || time.Since(cached.loaded) >= c.refreshInterval {
+		return nil, false
+	}
+	node := cached.node
+	return &node, true
+}
+
+// listNodes returns all the nodes with the fields in nodeListFields. The
+// nodes dropped since the last reload of the list are loaded on their own
+// so that their provision states are up to date.
+func (c *nodeCache) listNodes(ctx context.Context, client *gophercloud.ServiceClient) ([]nodes.Node, error) {
+	reloaded, err := c.refresh(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	loaded, err := c.loadDropped(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	if reloaded || loaded {
+		nodeCacheRequests.WithLabelValues(cacheMiss).Inc()
+	} else {
+		nodeCacheRequests.WithLabelValues(cacheHit).Inc()
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	nodeCacheAge.Set(time.Since(c.refreshed).Seconds())
+	allNodes := make([]nodes.Node, 0, len(c.listed))
+	for _, node := range c.listed {
+		allNodes = append(allNodes, node)
+	}
+	return allNodes, nil
+}
+
+// loadDropped loads the nodes dropped from the cache, and reports whether
+// there were any.
+func (c *nodeCache) loadDropped(ctx context.Context, client *gophercloud.ServiceClient) (bool, error) {
+	c.lock.Lock()
+	sequence := c.sequence
+	dropped := slices.Collect(maps.Keys(c.dropped))
+	c.lock.Unlock()
+
+	for _, uuid := range dropped {
+		node, err := nodes.Get(ctx, client, uuid).Extract()
+		if gophercloud.ResponseCodeIs(err, http.StatusNotFound) {
+			c.remove(uuid, sequence)
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		c.store(*node, sequence)
+	}
+	return len(dropped) > 0, nil
+}
+
+// remove drops a node deleted from Ironic by a request started at the
+// given sequence number.
+func (c *nodeCache) remove(uuid string, sequence uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.dropped[uuid] > sequence {
+		return
+	}
+	delete(c.dropped, uuid)
+	delete(c.listed, uuid)
+	c.forget(uuid)
+}
+
+// invalidate drops the entry of a node changed by the operator.
+func (c *nodeCache) invalidate(uuid string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.forget(uuid)
+	c.sequence++
+	c.dropped[uuid] = c.sequence
+}
@@ -0,0 +1,137 @@
+package ironic
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2/openstack/baremetal/v1/nodes"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner/ironic/clients"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner/ironic/testserver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestNodeCache(t *testing.T) {
+	updated := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	listed := nodes.Node{
+		UUID:           "33ce8659-7400-4c68-9535-d10766f07a58",
+		Name:           "myns" + nameSeparator + "listed",
+		ProvisionState: string(nodes.Available),
+		UpdatedAt:      updated,
+	}
+	created := nodes.Node{
+		UUID:           "1b8a5b4e-e1b6-4a3b-b1f9-a4f3e0d1c36e",
+		Name:           "myns" + nameSeparator + "created",
+		ProvisionState: string(nodes.Enroll),
+		UpdatedAt:      updated,
+	}
+	deploying := nodes.Node{
+		UUID:                 "8b5f0f4e-6f0e-4f44-9d4e-4b1f8f1e2a77",
+		Name:                 "myns" + nameSeparator + "deploying",
+		ProvisionState:       string(nodes.Deploying),
+		TargetProvisionState: string(nodes.TargetActive),
+		UpdatedAt:            updated,
+	}
+	ironic := testserver.NewIronic(t).Nodes([]nodes.Node{listed, deploying}).
+		Node(listed).Node(created).Node(deploying).Start()
+	defer ironic.Stop()
+
+	client, err := clients.IronicClient(ironic.Endpoint(), clients.AuthConfig{Type: clients.NoAuth}, clients.TLSConfig{})
+	require.NoError(t, err)
+
+	count := func(path string) int {
+		return strings.Count(ironic.Requests, path+";")
+	}
+	cache := newNodeCache(logf.Log, time.Hour)
+
+	// The list only holds a few fields, the details are loaded once
+	node, err := cache.getNode(t.Context(), client, listed.Name)
+	require.NoError(t, err)
+	assert.Equal(t, listed.UUID, node.UUID)
+	node, err = cache.getNode(t.Context(), client, listed.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, listed.Name, node.Name)
+	assert.Equal(t, 1, count("/v1/nodes"))
+	assert.Equal(t, 0, count("/v1/nodes/detail"))
+	assert.Equal(t, 1, count("/v1/nodes/"+listed.Name))
+	assert.Equal(t, 0, count("/v1/nodes/"+listed.UUID))
+
+	// A node missing from the list is loaded on its own, then cached
+	for range 2 {
+		node, err = cache.getNode(t.Context(), client, created.UUID)
+		require.NoError(t, err)
+		assert.Equal(t, created.Name, node.Name)
+	}
+	assert.Equal(t, 1, count("/v1/nodes/"+created.UUID))
+
+	// A node in transition is always loaded from Ironic
+	for range 2 {
+		_, err = cache.getNode(t.Context(), client, deploying.UUID)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, count("/v1/nodes/"+deploying.UUID))
+
+	// An invalidated node is loaded again
+	cache.invalidate(listed.UUID)
+	_, err = cache.getNode(t.Context(), client, listed.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count("/v1/nodes/"+listed.UUID))
+
+	allNodes, err := cache.listNodes(t.Context(), client)
+	require.NoError(t, err)
+	assert.Len(t, allNodes, 3)
+	assert.Equal(t, 1, count("/v1/nodes"))
+
+	// Listing the nodes after an invalidation only loads the dropped node
+	cache.invalidate(created.UUID)
+	allNodes, err = cache.listNodes(t.Context(), client)
+	require.NoError(t, err)
+	assert.Len(t, allNodes, 3)
+	assert.Equal(t, 1, count("/v1/nodes"))
+	assert.Equal(t, 2, count("/v1/nodes/"+created.UUID))
+
+	// Nodes not updated in Ironic stay cached when the list is reloaded
+	cache.refreshed = time.Now().Add(-2 * time.Hour)
+	_, err = cache.getNode(t.Context(), client, listed.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count("/v1/nodes"))
+	assert.Equal(t, 1, count("/v1/nodes/"+listed.UUID))
+}
+
+func TestNodeCacheInvalidatedDuringRequest(t *testing.T) {
+	node := nodes.Node{
+		UUID:           "33ce8659-7400-4c68-9535-d10766f07a58",
+		ProvisionState: string(nodes.Available),
+	}
+	cache := newNodeCache(logf.Log, time.Hour)
+
+	_, sequence := cache.expired()
+	cache.invalidate(node.UUID)
+	cache.store(node, sequence)
+	_, found := cache.lookup(node.UUID)
+	assert.False(t, found)
+
+	_, sequence = cache.expired()
+	cache.store(node, sequence)
+	_, found = cache.lookup(node.UUID)
+	assert.True(t, found)
+}
+
+func TestNodeCacheExpiry(t *testing.T) {
+	ironic := testserver.NewIronic(t).Nodes([]nodes.Node{}).Start()
+	defer ironic.Stop()
+
+	client, err := clients.IronicClient(ironic.Endpoint(), clients.AuthConfig{Type: clients.NoAuth}, clients.TLSConfig{})
+	require.NoError(t, err)
+
+	cache := newNodeCache(logf.Log, time.Hour)
+	_, err = cache.listNodes(t.Context(), client)
+	require.NoError(t, err)
+	cache.refreshed = time.Now().Add(-2 * time.Hour)
+	_, err = cache.listNodes(t.Context(), client)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, strings.Count(ironic.Requests, "/v1/nodes;"))
+}
@@ -0,0 +1,68 @@
+package ironic
+
+import (
+	"net/http"
+	"testing"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/hardwareutils/bmc"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner/ironic/clients"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner/ironic/testserver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckBMCEventSubscriptionForNode(t *testing.T) {
+	nodeUUID := "33ce8659-7400-4c68-9535-d10766f07a58"
+	cases := []struct {
+		name   string
+		ironic *testserver.IronicMock
+
+		expectedExists bool
+		expectedError  string
+	}{
+		{
+			name:           "subscription-exists",
+			ironic:         testserver.NewIronic(t).WithSubscriptions(nodeUUID, "other", "sub-1"),
+			expectedExists: true,
+		},
+		{
+			name:   "subscription-dropped",
+			ironic: testserver.NewIronic(t).WithSubscriptions(nodeUUID, "other"),
+		},
+		{
+			name:   "no-subscriptions",
+			ironic: testserver.NewIronic(t).WithSubscriptions(nodeUUID),
+		},
+		{
+			name:          "ironic-error",
+			ironic:        testserver.NewIronic(t).NodeError(nodeUUID+"/vendor_passthru", http.StatusInternalServerError),
+			expectedError: "failed to list the subscriptions",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.ironic.Start()
+			defer tc.ironic.Stop()
+
+			host := makeHost()
+			host.Status.Provisioning.ID = nodeUUID
+			publisher := func(reason, message string) {}
+			auth := clients.AuthConfig{Type: clients.NoAuth}
+			prov, err := newProvisionerWithSettings(host, bmc.Credentials{}, publisher, tc.ironic.Endpoint(), auth)
+			require.NoError(t, err)
+
+			subscription := metal3api.BMCEventSubscription{
+				Status: metal3api.BMCEventSubscriptionStatus{SubscriptionID: "sub-1"},
+			}
+			exists, err := prov.CheckBMCEventSubscriptionForNode(t.Context(), subscription)
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedExists, exists)
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/gophercloud/gophercloud/v2/openstack/baremetal/v1/nodes"
 	"github.com/gophercloud/gophercloud/v2/openstack/baremetal/v1/ports"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner/ironic/clients"
 )
 
 // IronicMock is a test server that implements Ironic's semantics.
@@ -322,7 +323,99 @@ func (m *IronicMock) Port(port ports.Port) *IronicMock {
 	return m
 }
 
-// Nodes configure the server with a valid response for /v1/nodes.
+// NodePorts configures the server with a valid response for
+//
+//	[GET] /v1/ports/detail
+//	[PATCH] /v1/ports/<port uuid>
+//	[POST] /v1/ports
+func (m *IronicMock) NodePorts(allPorts []ports.Port) *IronicMock {
+	m.ResponseJSON(m.buildURL("/v1/ports/detail", http.MethodGet), map[string][]ports.Port{
+		"ports": allPorts,
+	})
+	for _, port := range allPorts {
+		m.ResponseJSON(m.buildURL("/v1/ports/"+port.UUID, http.MethodPatch), port)
+	}
+	m.ResponseWithCode(m.buildURL("/v1/ports", http.MethodPost), "{}", http.StatusCreated)
+	return m
+}
+
+// NodePortsUpdateError configures the server with a valid response for
+//
+//	[GET] /v1/ports/detail
+//
+// and with an error response for
+//
+//	[PATCH] /v1/ports/<port uuid>
+func (m *IronicMock) NodePortsUpdateError(allPorts []ports.Port, errorCode int) *IronicMock {
+	m.ResponseJSON(m.buildURL("/v1/ports/detail", http.MethodGet), map[string][]ports.Port{
+		"ports": allPorts,
+	})
+	for _, port := range allPorts {
+		m.ResponseWithCode(m.buildURL("/v1/ports/"+port.UUID, http.MethodPatch), "", errorCode)
+	}
+	return m
+}
+
+// GetLastPortUpdateRequestFor returns the content of the last update request for the specified port.
+func (m *IronicMock) GetLastPortUpdateRequestFor(id string) (updates []ports.UpdateOperation) {
+	if bodyRaw, ok := m.GetLastRequestFor("/v1/ports/"+id, http.MethodPatch); ok {
+		_ = json.Unmarshal([]byte(bodyRaw), &updates)
+	}
+
+	return
+}
+
+// GetLastPortCreateRequest returns the content of the last port creation request.
+func (m *IronicMock) GetLastPortCreateRequest() (port ports.CreateOpts) {
+	if bodyRaw, ok := m.GetLastRequestFor("/v1/ports", http.MethodPost); ok {
+		_ = json.Unmarshal([]byte(bodyRaw), &port)
+	}
+
+	return
+}
+
+// PortGroups configures the server with a valid response for
+//
+//	[GET] /v1/portgroups/detail
+//	[PATCH] /v1/portgroups/<port group uuid>
+//	[DELETE] /v1/portgroups/<port group uuid>
+//	[POST] /v1/portgroups, returning the created port group
+func (m *IronicMock) PortGroups(groups []clients.PortGroup, created clients.PortGroup) *IronicMock {
+	m.ResponseJSON(m.buildURL("/v1/portgroups/detail", http.MethodGet), map[string][]clients.PortGroup{
+		"portgroups": groups,
+	})
+	for _, group := range groups {
+		m.ResponseJSON(m.buildURL("/v1/portgroups/"+group.UUID, http.MethodPatch), group)
+		m.ResponseWithCode(m.buildURL("/v1/portgroups/"+group.UUID, http.MethodDelete), "", http.StatusNoContent)
+	}
+	content, err := json.Marshal(created)
+	if err != nil {
+		m.t.Error(err)
+	}
+	m.ResponseWithCode(m.buildURL("/v1/portgroups", http.MethodPost), string(content), http.StatusCreated)
+	return m
+}
+
+// GetLastPortGroupUpdateRequestFor returns the content of the last update request for the specified port group.
+func (m *IronicMock) GetLastPortGroupUpdateRequestFor(id string) (updates []ports.UpdateOperation) {
+	if bodyRaw, ok := m.GetLastRequestFor("/v1/portgroups/"+id, http.MethodPatch); ok {
+		_ = json.Unmarshal([]byte(bodyRaw), &updates)
+	}
+
+	return
+}
+
+// GetLastPortGroupCreateRequest returns the content of the last port group creation request.
+func (m *IronicMock) GetLastPortGroupCreateRequest() (group clients.PortGroup) {
+	if bodyRaw, ok := m.GetLastRequestFor("/v1/portgroups", http.MethodPost); ok {
+		_ = json.Unmarshal([]byte(bodyRaw), &group)
+	}
+
+	return
+}
+
+// Nodes configure the server with a valid response for /v1/nodes and
+// /v1/nodes/detail.
 func (m *IronicMock) Nodes(allNodes []nodes.Node) *IronicMock {
 	resp := struct {
 		Nodes []nodes.Node `json:"nodes"`
@@ -331,6 +424,7 @@ func (m *IronicMock) Nodes(allNodes []nodes.Node) *IronicMock {
 	}
 
 	m.ResponseJSON(m.buildURL("/v1/nodes", http.MethodGet), resp)
+	m.ResponseJSON(m.buildURL("/v1/nodes/detail", http.MethodGet), resp)
 	return m
 }
 
@@ -445,7 +539,7 @@ func (m *IronicMock) BIOSDetailSettings(nodeUUID string) *IronicMock {
 			MinLength:       nil,
 			MaxLength:       nil,
 			ReadOnly:        &iFalse,
-			ResetRequired:   nil,
+			ResetRequired:   &iTrue,
 			Unique:          nil,
 		},
 	}
@@ -479,3 +573,20 @@ func (m *IronicMock) WithInventoryFailed(nodeUUID string, errorCode int) *Ironic
 	m.ErrorResponse(v1node+nodeUUID+"/inventory", errorCode)
 	return m
 }
+
+// WithSubscriptions configures the server with a valid response for
+// [GET] /v1/nodes/<node>/vendor_passthru listing the given Redfish event
+// subscriptions.
+func (m *IronicMock) WithSubscriptions(nodeUUID string, subscriptionIDs ...string) *IronicMock {
+	members := make([]map[string]string, 0, len(subscriptionIDs))
+	for _, id := range subscriptionIDs {
+		members = append(members, map[string]string{
+			"@odata.id": "/redfish/v1/EventService/Subscriptions/" + id,
+		})
+	}
+	m.ResponseJSON(m.buildURL(v1node+nodeUUID+"/vendor_passthru", http.MethodGet), nodes.GetAllSubscriptionsVendorPassthru{
+		Members:      members,
+		MembersCount: len(members),
+	})
+	return m
+}
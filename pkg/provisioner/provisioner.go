@@ -126,6 +126,27 @@ type ServicingData struct {
 	HasFirmwareSpec bool
 }
 
+// NetworkAttachment is a NIC of the host connected to a switch port.
+type NetworkAttachment struct {
+	MACAddress string
+	// SwitchID is the MAC address of the switch and SwitchInfo its name.
+	SwitchID   string
+	SwitchInfo string
+	PortID     string
+	// Bond is the name of the bond of the NIC, whose configuration is
+	// used for the switch port.
+	Bond string
+	metal3api.SwitchPortConfig
+}
+
+// NetworkData is the tenant network configuration of the host, with the
+// switch ports of its NICs resolved.
+type NetworkData struct {
+	ProvisioningVLAN *metal3api.VLANID
+	Interfaces       []NetworkAttachment
+	Bonds            []metal3api.NetworkBond
+}
+
 type ProvisionData struct {
 	Image           metal3api.Image
 	HostConfig      HostConfigData
@@ -134,6 +155,7 @@ type ProvisionData struct {
 	RootDeviceHints *metal3api.RootDeviceHints
 	CustomDeploy    *metal3api.CustomDeploy
 	ImagePullSecret string
+	Network         *NetworkData
 }
 
 type HTTPHeaders []map[string]string
@@ -230,6 +252,9 @@ type Provisioner interface {
 	// RemoveBMCEventSubscriptionForNode delete the subscription
 	RemoveBMCEventSubscriptionForNode(ctx context.Context, subscription metal3api.BMCEventSubscription) (result Result, err error)
 
+	// CheckBMCEventSubscriptionForNode tells whether the subscription still exists on the BMC
+	CheckBMCEventSubscriptionForNode(ctx context.Context, subscription metal3api.BMCEventSubscription) (exists bool, err error)
+
 	// GetFirmwareComponents gets all firmware components available from a note
 	GetFirmwareComponents(ctx context.Context) (components []metal3api.FirmwareComponentStatus, err error)
 
@@ -244,6 +269,10 @@ type Provisioner interface {
 
 	HasPowerFailure(ctx context.Context) bool
 
+	// GetDiskErasure returns the last disk erasure completed when
+	// deprovisioning the host, or nil if there is none.
+	GetDiskErasure(ctx context.Context) (*metal3api.DiskErasure, error)
+
 	// GetHealth returns the health status of the node from the provisioner.
 	// Possible values are HealthOK, HealthWarning, HealthCritical, or
 	// empty string if unavailable.
@@ -14,6 +14,14 @@ func FuzzGetParsedURL(f *testing.F) {
 	f.Add("idrac-virtualmedia://192.168.122.1:443")
 	f.Add("ilo5-redfish://192.168.122.1")
 	f.Add("ilo5-redfish://ilo.example.com")
+	f.Add("ilo4://192.168.122.1")
+	f.Add("ilo4-virtualmedia://ilo.example.com:443")
+	f.Add("ilo5://192.168.122.1")
+	f.Add("ilo5+https://ilo.example.com")
+	f.Add("irmc://192.168.122.1?port=443&authmethod=digest")
+	f.Add("irmc-virtualmedia://irmc.example.com")
+	f.Add("xcc-redfish://192.168.122.1/redfish/v1/Systems/1")
+	f.Add("xcc-virtualmedia+https://xcc.example.com")
 	f.Add("idrac-redfish://192.168.122.1")
 	f.Add("idrac-redfish://idrac.example.com:443")
 	f.Add("redfish+http://192.168.122.1")